@@ -0,0 +1,123 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/alert"
+)
+
+// Notifier delivers stock alerts to the purchasing team.
+type Notifier interface {
+	Notify(context.Context, *models.StockAlert) error
+}
+
+// LogNotifier delivers stock alerts to the log.
+type LogNotifier struct{}
+
+// Notify logs the alert.
+func (LogNotifier) Notify(_ context.Context, a *models.StockAlert) error {
+	log.Printf("low stock: book %d %q has %d left, reorder threshold is %d", a.BookID, a.Title, a.Stock, a.Threshold)
+	return nil
+}
+
+// WebhookNotifier delivers stock alerts by posting them as JSON to a URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting alerts to the given URL,
+// giving up on a delivery after timeout.
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify posts the alert as {"alert": ...}, any response but a 2xx fails the
+// delivery.
+func (n *WebhookNotifier) Notify(ctx context.Context, a *models.StockAlert) error {
+	body, err := json.Marshal(map[string]interface{}{"alert": a})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %v", err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// Evaluator opens and resolves stock alerts and delivers the new ones.
+type Evaluator struct {
+	alertStorage alert.AlertStorage
+	notifier     Notifier
+}
+
+// NewEvaluator creates an evaluator delivering alerts through notifier.
+func NewEvaluator(alertStorage alert.AlertStorage, notifier Notifier) *Evaluator {
+	return &Evaluator{
+		alertStorage: alertStorage,
+		notifier:     notifier,
+	}
+}
+
+// Evaluate opens an alert for every book whose stock fell to its reorder
+// threshold and delivers every alert not delivered yet. An alert is
+// delivered once, failed deliveries are retried on the next evaluation.
+func (e *Evaluator) Evaluate(ctx context.Context) error {
+	opened, resolved, err := e.alertStorage.Evaluate(ctx)
+	if err != nil {
+		return err
+	}
+	if opened > 0 || resolved > 0 {
+		log.Printf("opened %d and resolved %d stock alert(s)", opened, resolved)
+	}
+
+	alerts, err := e.alertStorage.GetUndeliveredAlerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	var lastErr error
+	for _, a := range alerts {
+		if err := e.notifier.Notify(ctx, a); err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		if err := e.alertStorage.MarkNotified(ctx, a.ID); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to deliver %d stock alert(s): %v", failed, lastErr)
+	}
+
+	return nil
+}
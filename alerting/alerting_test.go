@@ -0,0 +1,131 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	mock_storage_alert "github.com/wilsonangara/simple-online-book-store/storage/sqlite/alert/mock"
+)
+
+// notifierFunc lets a function be used as a Notifier.
+type notifierFunc func(context.Context, *models.StockAlert) error
+
+func (f notifierFunc) Notify(ctx context.Context, a *models.StockAlert) error {
+	return f(ctx, a)
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	alerts := []*models.StockAlert{
+		{ID: 1, BookID: 1, Title: "Atomic Habits", Threshold: 5, Stock: 3},
+		{ID: 2, BookID: 2, Title: "The Tipping Point", Threshold: 5, Stock: 0},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockAlertStorage := mock_storage_alert.NewMockAlertStorage(ctrl)
+		mockAlertStorage.EXPECT().Evaluate(gomock.Any()).Return(int64(2), int64(0), nil)
+		mockAlertStorage.EXPECT().GetUndeliveredAlerts(gomock.Any()).Return(alerts, nil)
+		mockAlertStorage.EXPECT().MarkNotified(gomock.Any(), int64(1)).Return(nil)
+		mockAlertStorage.EXPECT().MarkNotified(gomock.Any(), int64(2)).Return(nil)
+
+		delivered := []int64{}
+		e := NewEvaluator(mockAlertStorage, notifierFunc(func(_ context.Context, a *models.StockAlert) error {
+			delivered = append(delivered, a.ID)
+			return nil
+		}))
+
+		if err := e.Evaluate(context.Background()); err != nil {
+			t.Fatalf("Evaluate(_) expected nil error, got = %v", err)
+		}
+		if diff := cmp.Diff([]int64{1, 2}, delivered); diff != "" {
+			t.Fatalf("Evaluate(_) mismatch (-want+got):\n%s", diff)
+		}
+	})
+
+	t.Run("FailedDelivery", func(t *testing.T) {
+		t.Parallel()
+
+		// an alert that failed to be delivered is left undelivered to be
+		// retried, the others are still delivered.
+		ctrl := gomock.NewController(t)
+		mockAlertStorage := mock_storage_alert.NewMockAlertStorage(ctrl)
+		mockAlertStorage.EXPECT().Evaluate(gomock.Any()).Return(int64(0), int64(0), nil)
+		mockAlertStorage.EXPECT().GetUndeliveredAlerts(gomock.Any()).Return(alerts, nil)
+		mockAlertStorage.EXPECT().MarkNotified(gomock.Any(), int64(2)).Return(nil)
+
+		e := NewEvaluator(mockAlertStorage, notifierFunc(func(_ context.Context, a *models.StockAlert) error {
+			if a.ID == 1 {
+				return errors.New("failed to deliver")
+			}
+			return nil
+		}))
+
+		if err := e.Evaluate(context.Background()); err == nil {
+			t.Fatalf("Evaluate(_) expected error for a failed delivery")
+		}
+	})
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Parallel()
+
+	alert := &models.StockAlert{ID: 1, BookID: 1, Title: "Atomic Habits", Threshold: 5, Stock: 3, Status: models.StockAlertStatusOpen}
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:   "Success",
+			status: http.StatusNoContent,
+		},
+		{
+			name:    "ErrorStatus",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got struct {
+				Alert *models.StockAlert `json:"alert"`
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Notify(_, _) error, got content type = %v, want = %v", ct, "application/json")
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("unexpected error when decoding webhook body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(srv.Close)
+
+			err := NewWebhookNotifier(srv.URL, time.Second).Notify(context.Background(), alert)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify(_, _) error, got = %v, want error = %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(alert, got.Alert); diff != "" {
+				t.Fatalf("Notify(_, _) mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
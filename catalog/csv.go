@@ -0,0 +1,274 @@
+package catalog
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+)
+
+var ErrInvalidHeader = errors.New("invalid csv header")
+
+// csvColumns are the columns of an exported catalog, an imported catalog may
+// have any of them in any order. isbn_10 is accepted on import but converted
+// to ISBN-13, which is how books are matched.
+var csvColumns = []string{"id", "isbn_13", "isbn_10", "title", "author", "price", "description", "stock"}
+
+// authorSeparator separates the authors of a co-authored book in the author
+// column, names may contain commas such as "Martin, Robert C." but never a
+// semicolon.
+const authorSeparator = ";"
+
+// RowError is a row of an imported catalog that could not be upserted.
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Report sums up an import. Nothing is committed when any row fails, so
+// created and updated are what the import would do once its errors are fixed.
+type Report struct {
+	Created   int         `json:"created"`
+	Updated   int         `json:"updated"`
+	DryRun    bool        `json:"dry_run"`
+	Committed bool        `json:"committed"`
+	Errors    []*RowError `json:"errors"`
+}
+
+// ImportCSV upserts every row of the given CSV catalog in one transaction.
+// The first row is a header naming the columns, empty cells keep the current
+// value of an updated book.
+func ImportCSV(ctx context.Context, bookStorage book.BookStorage, r io.Reader, dryRun bool) (*Report, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: missing header", ErrInvalidHeader)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+	columns, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		DryRun: dryRun,
+		Errors: []*RowError{},
+	}
+
+	lines := []int{}
+	upserts := []*models.BookUpsert{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to read csv: %v", err)
+			}
+			report.Errors = append(report.Errors, &RowError{
+				Line:    parseErr.Line,
+				Message: parseErr.Err.Error(),
+			})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(columns) {
+			report.Errors = append(report.Errors, &RowError{
+				Line:    line,
+				Message: fmt.Sprintf("expected %d fields, got %d", len(columns), len(record)),
+			})
+			continue
+		}
+
+		upsert, err := parseRecord(columns, record)
+		if err != nil {
+			report.Errors = append(report.Errors, &RowError{
+				Line:    line,
+				Message: err.Error(),
+			})
+			continue
+		}
+
+		lines = append(lines, line)
+		upserts = append(upserts, upsert)
+	}
+
+	if err := upsert(ctx, bookStorage, upserts, lines, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// upsert upserts the parsed books, which were read from the given lines, and
+// fills in the report. Books are only committed when nothing failed so far.
+func upsert(ctx context.Context, bookStorage book.BookStorage, upserts []*models.BookUpsert, lines []int, report *Report) error {
+	if len(upserts) == 0 {
+		return nil
+	}
+
+	dryRun := report.DryRun || len(report.Errors) > 0
+	results, err := bookStorage.UpsertBooks(ctx, upserts, dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to upsert books: %v", err)
+	}
+
+	for i, result := range results {
+		switch {
+		case result.Err != nil:
+			report.Errors = append(report.Errors, &RowError{
+				Line:    lines[i],
+				Message: result.Err.Error(),
+			})
+		case result.Created:
+			report.Created++
+		default:
+			report.Updated++
+		}
+	}
+
+	report.Committed = !dryRun && len(report.Errors) == 0
+	return nil
+}
+
+// parseHeader maps every column of the header to its csv column, "isbn" is
+// accepted as an alias of either ISBN.
+func parseHeader(header []string) ([]string, error) {
+	known := map[string]bool{}
+	for _, column := range csvColumns {
+		known[column] = true
+	}
+
+	seen := map[string]bool{}
+	columns := []string{}
+	for _, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column == "isbn" {
+			column = "isbn_13"
+		}
+		if !known[column] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidHeader, column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidHeader, column)
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+// parseRecord validates a row of the catalog into a book upsert.
+func parseRecord(columns, record []string) (*models.BookUpsert, error) {
+	upsert := &models.BookUpsert{}
+	for i, column := range columns {
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		switch column {
+		case "id":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("invalid id %q", value)
+			}
+			upsert.ID = id
+		case "isbn_13", "isbn_10":
+			isbn13, err := isbn.Normalize(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid isbn %q: %v", value, err)
+			}
+			if upsert.ISBN13 != "" && upsert.ISBN13 != isbn13 {
+				return nil, fmt.Errorf("isbn_10 and isbn_13 do not match")
+			}
+			upsert.ISBN13 = isbn13
+		case "title":
+			upsert.Title = &value
+		case "author":
+			upsert.Authors = []string{}
+			for _, name := range strings.Split(value, authorSeparator) {
+				if name = strings.TrimSpace(name); name != "" {
+					upsert.Authors = append(upsert.Authors, name)
+				}
+			}
+		case "price":
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil || price < 0 {
+				return nil, fmt.Errorf("invalid price %q", value)
+			}
+			value = strconv.FormatFloat(price, 'f', 2, 64)
+			upsert.Price = &value
+		case "description":
+			upsert.Description = &value
+		case "stock":
+			stock, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || stock < 0 {
+				return nil, fmt.Errorf("invalid stock %q", value)
+			}
+			upsert.Stock = &stock
+		}
+	}
+
+	return upsert, nil
+}
+
+// ExportCSV writes every book of our catalog to w as CSV, which can be
+// imported back as it is. The authors of a book are listed in order.
+func ExportCSV(ctx context.Context, bookStorage book.BookStorage, w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvColumns); err != nil {
+		return fmt.Errorf("failed to write csv header: %v", err)
+	}
+
+	err := bookStorage.EachBook(ctx, func(b *models.Book) error {
+		return writer.Write([]string{
+			strconv.FormatInt(b.ID, 10),
+			b.ISBN13,
+			b.ISBN10,
+			b.Title,
+			exportAuthors(b),
+			b.Price,
+			b.Description,
+			strconv.FormatInt(b.Stock, 10),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write books: %v", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportAuthors joins the authors credited on a book, books not credited to
+// any author yet are exported with their author column.
+func exportAuthors(b *models.Book) string {
+	names := []string{}
+	for _, a := range b.Authors {
+		if a.Role == "author" {
+			names = append(names, a.Name)
+		}
+	}
+	if len(names) == 0 {
+		return b.Author
+	}
+	return strings.Join(names, authorSeparator+" ")
+}
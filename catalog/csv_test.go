@@ -0,0 +1,200 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	mock_books_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+)
+
+func TestImportCSV(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockBookStorage := mock_books_storage.NewMockBookStorage(ctrl)
+
+		title, price := "Clean Code", "32.50"
+		stock := int64(4)
+		wantUpserts := []*models.BookUpsert{
+			{ISBN13: "9780132350884", Title: &title, Authors: []string{"Robert C. Martin"}, Price: &price, Stock: &stock},
+			{ID: 1, Price: &price},
+			{ID: 2, Authors: []string{"Heath, Chip", "Heath, Dan"}},
+		}
+		mockBookStorage.
+			EXPECT().
+			UpsertBooks(gomock.Any(), wantUpserts, false).
+			Return([]*models.BookUpsertResult{
+				{BookID: 4, Created: true},
+				{BookID: 1},
+				{BookID: 2},
+			}, nil)
+
+		csv := "isbn,title,author,price,stock,id\n" +
+			"0-13-235088-2,Clean Code,Robert C. Martin,32.5,4,\n" +
+			",,,32.50,,1\n" +
+			",,\"Heath, Chip; Heath, Dan\",,,2\n"
+
+		report, err := ImportCSV(ctx, mockBookStorage, strings.NewReader(csv), false)
+		if err != nil {
+			t.Fatalf("ImportCSV(_, _, _, _) expected nil error, got = %v", err)
+		}
+
+		want := &Report{Created: 1, Updated: 2, Committed: true, Errors: []*RowError{}}
+		if diff := cmp.Diff(want, report); diff != "" {
+			t.Fatalf("ImportCSV(_, _, _, _) mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("RowErrors", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockBookStorage := mock_books_storage.NewMockBookStorage(ctrl)
+
+		// rows failing validation turn the import into a dry run.
+		mockBookStorage.
+			EXPECT().
+			UpsertBooks(gomock.Any(), gomock.Any(), true).
+			Return([]*models.BookUpsertResult{
+				{Err: errors.New("isbn already exist")},
+			}, nil)
+
+		csv := "id,price,stock\n" +
+			"1,-1,\n" +
+			"2,1.00,many\n" +
+			"3,1.00\n" +
+			"4,1.00,1\n"
+
+		report, err := ImportCSV(ctx, mockBookStorage, strings.NewReader(csv), false)
+		if err != nil {
+			t.Fatalf("ImportCSV(_, _, _, _) expected nil error, got = %v", err)
+		}
+
+		want := &Report{
+			Errors: []*RowError{
+				{Line: 2, Message: `invalid price "-1"`},
+				{Line: 3, Message: `invalid stock "many"`},
+				{Line: 4, Message: "expected 3 fields, got 2"},
+				{Line: 5, Message: "isbn already exist"},
+			},
+		}
+		if diff := cmp.Diff(want, report); diff != "" {
+			t.Fatalf("ImportCSV(_, _, _, _) mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("InvalidHeader", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockBookStorage := mock_books_storage.NewMockBookStorage(ctrl)
+
+		_, err := ImportCSV(ctx, mockBookStorage, strings.NewReader("id,publisher\n1,x\n"), true)
+		if !errors.Is(err, ErrInvalidHeader) {
+			t.Fatalf("ImportCSV(_, _, _, _) error, got = %v, want = %v", err, ErrInvalidHeader)
+		}
+	})
+}
+
+func TestExportCSV(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockBookStorage := mock_books_storage.NewMockBookStorage(ctrl)
+
+	mockBookStorage.
+		EXPECT().
+		EachBook(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*models.Book) error) error {
+			return fn(&models.Book{
+				ID:          1,
+				Title:       "Atomic Habits",
+				Author:      "James Clear",
+				Price:       "10.00",
+				Description: "Tiny changes, remarkable results",
+				ISBN10:      "0735211299",
+				ISBN13:      "9780735211292",
+				Stock:       10,
+				Authors: []*models.BookAuthor{
+					{Name: "James Clear", Role: "author"},
+					{Name: "Clear, Jim", Role: "author"},
+					{Name: "Ann Illustrator", Role: "illustrator"},
+				},
+			})
+		})
+
+	var buf bytes.Buffer
+	if err := ExportCSV(context.Background(), mockBookStorage, &buf); err != nil {
+		t.Fatalf("ExportCSV(_, _, _) expected nil error, got = %v", err)
+	}
+
+	want := "id,isbn_13,isbn_10,title,author,price,description,stock\n" +
+		"1,9780735211292,0735211299,Atomic Habits,\"James Clear; Clear, Jim\",10.00,\"Tiny changes, remarkable results\",10\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Fatalf("ExportCSV(_, _, _) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExportImportCSV(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+	ts, err := sqlite.NewStorage(filepath.Join(dir, uuid.New().String()), filepath.Join("..", "storage", "migrations"))
+	if err != nil {
+		t.Fatalf("failed to create new test storage: %v", err)
+	}
+	t.Cleanup(ts.Teardown)
+	bookStorage := book.NewStorage(ts.Database())
+
+	// a co-authored book exported and imported back keeps its authors.
+	authors := []string{"Chip Heath", "Dan Heath"}
+	if _, err := bookStorage.UpsertBooks(ctx, []*models.BookUpsert{{ID: 2, Authors: authors}}, false); err != nil {
+		t.Fatalf("unexpected error when crediting authors: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportCSV(ctx, bookStorage, &buf); err != nil {
+		t.Fatalf("ExportCSV(_, _, _) expected nil error, got = %v", err)
+	}
+	report, err := ImportCSV(ctx, bookStorage, &buf, false)
+	if err != nil {
+		t.Fatalf("ImportCSV(_, _, _, _) expected nil error, got = %v", err)
+	}
+	if !report.Committed || len(report.Errors) != 0 {
+		t.Fatalf("ImportCSV(_, _, _, _) error, got report = %+v", report)
+	}
+
+	b, err := bookStorage.GetBookByID(ctx, 2)
+	if err != nil {
+		t.Fatalf("unexpected error when getting book: %v", err)
+	}
+	got := []string{}
+	for _, a := range b.Authors {
+		got = append(got, a.Name)
+	}
+	if diff := cmp.Diff(authors, got); diff != "" {
+		t.Fatalf("ImportCSV(_, _, _, _) authors mismatch (-want +got):\n%s", diff)
+	}
+}
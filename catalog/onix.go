@@ -0,0 +1,335 @@
+package catalog
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+)
+
+// onixBatchSize is how many products are upserted per transaction, a feed is
+// applied incrementally so a large feed is never held in memory at once.
+const onixBatchSize = 100
+
+// ONIX code list values mapped to our books, see the ONIX 3.0 code lists
+// published by EDItEUR.
+const (
+	onixNotificationDelete = "05"  // list 1, delete
+	onixIDTypeISBN10       = "02"  // list 5, ISBN-10
+	onixIDTypeGTIN13       = "03"  // list 5, GTIN-13
+	onixIDTypeISBN13       = "15"  // list 5, ISBN-13
+	onixTitleTypeDistinct  = "01"  // list 15, distinctive title
+	onixTitleLevelProduct  = "01"  // list 149, product
+	onixRoleAuthor         = "A01" // list 17, by (author)
+	onixTextDescription    = "03"  // list 153, description
+	onixTextShortDesc      = "02"  // list 153, short description
+)
+
+var ErrMalformedONIX = errors.New("malformed onix")
+
+// tagPattern matches the markup of XHTML text content.
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// RejectedRecord is a product of an ONIX feed that could not be applied.
+type RejectedRecord struct {
+	RecordReference string `json:"record_reference"`
+	ISBN            string `json:"isbn,omitempty"`
+	Message         string `json:"message"`
+}
+
+// ONIXReport sums up the ingestion of an ONIX feed.
+type ONIXReport struct {
+	Created  int               `json:"created"`
+	Updated  int               `json:"updated"`
+	Rejected []*RejectedRecord `json:"rejected"`
+}
+
+// onixProduct is the part of an ONIX 3.0 product, in reference tags, that is
+// mapped to our books. Tags are matched regardless of their namespace.
+type onixProduct struct {
+	RecordReference    string `xml:"RecordReference"`
+	NotificationType   string `xml:"NotificationType"`
+	ProductIdentifiers []struct {
+		Type  string `xml:"ProductIDType"`
+		Value string `xml:"IDValue"`
+	} `xml:"ProductIdentifier"`
+	TitleDetails []struct {
+		Type     string `xml:"TitleType"`
+		Elements []struct {
+			Level              string `xml:"TitleElementLevel"`
+			TitleText          string `xml:"TitleText"`
+			TitlePrefix        string `xml:"TitlePrefix"`
+			TitleWithoutPrefix string `xml:"TitleWithoutPrefix"`
+		} `xml:"TitleElement"`
+	} `xml:"DescriptiveDetail>TitleDetail"`
+	Contributors []struct {
+		SequenceNumber int      `xml:"SequenceNumber"`
+		Roles          []string `xml:"ContributorRole"`
+		PersonName     string   `xml:"PersonName"`
+		NamesBeforeKey string   `xml:"NamesBeforeKey"`
+		KeyNames       string   `xml:"KeyNames"`
+		CorporateName  string   `xml:"CorporateName"`
+	} `xml:"DescriptiveDetail>Contributor"`
+	TextContents []struct {
+		Type string `xml:"TextType"`
+		Text struct {
+			Inner string `xml:",innerxml"`
+		} `xml:"Text"`
+	} `xml:"CollateralDetail>TextContent"`
+	Prices []struct {
+		Amount string `xml:"PriceAmount"`
+	} `xml:"ProductSupply>SupplyDetail>Price"`
+}
+
+// IngestONIX streams the products of an ONIX 3.0 feed in reference tags and
+// upserts them as books matched by their ISBN. Products are applied in
+// batches as they are read, a rejected product does not stop the others from
+// being applied. A feed that is not well-formed stops the ingestion, with
+// the products read so far already applied.
+func IngestONIX(ctx context.Context, bookStorage book.BookStorage, r io.Reader) (*ONIXReport, error) {
+	report := &ONIXReport{
+		Rejected: []*RejectedRecord{},
+	}
+
+	decoder := xml.NewDecoder(r)
+
+	records := []*RejectedRecord{}
+	upserts := []*models.BookUpsert{}
+	flush := func() error {
+		err := applyONIX(ctx, bookStorage, upserts, records, report)
+		records, upserts = records[:0], upserts[:0]
+		return err
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if flushErr := flush(); flushErr != nil {
+				return report, flushErr
+			}
+			return report, fmt.Errorf("%w: %v", ErrMalformedONIX, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Product" {
+			continue
+		}
+
+		var product onixProduct
+		if err := decoder.DecodeElement(&product, &start); err != nil {
+			if flushErr := flush(); flushErr != nil {
+				return report, flushErr
+			}
+			return report, fmt.Errorf("%w: %v", ErrMalformedONIX, err)
+		}
+
+		record := &RejectedRecord{RecordReference: strings.TrimSpace(product.RecordReference)}
+		upsert, err := product.toUpsert()
+		if err != nil {
+			if upsert != nil {
+				record.ISBN = upsert.ISBN13
+			}
+			record.Message = err.Error()
+			report.Rejected = append(report.Rejected, record)
+			continue
+		}
+		record.ISBN = upsert.ISBN13
+
+		records = append(records, record)
+		upserts = append(upserts, upsert)
+		if len(upserts) == onixBatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// applyONIX upserts a batch of products. Since a batch is only committed when
+// every product succeeds, the batch is upserted again without the rejected
+// products until it commits.
+func applyONIX(ctx context.Context, bookStorage book.BookStorage, upserts []*models.BookUpsert, records []*RejectedRecord, report *ONIXReport) error {
+	for len(upserts) > 0 {
+		results, err := bookStorage.UpsertBooks(ctx, upserts, false)
+		if err != nil {
+			return fmt.Errorf("failed to upsert books: %v", err)
+		}
+
+		var (
+			retryUpserts []*models.BookUpsert
+			retryRecords []*RejectedRecord
+			created      int
+			updated      int
+		)
+		for i, result := range results {
+			switch {
+			case result.Err != nil:
+				records[i].Message = result.Err.Error()
+				report.Rejected = append(report.Rejected, records[i])
+				continue
+			case result.Created:
+				created++
+			default:
+				updated++
+			}
+			retryUpserts = append(retryUpserts, upserts[i])
+			retryRecords = append(retryRecords, records[i])
+		}
+
+		if len(retryUpserts) == len(upserts) {
+			report.Created += created
+			report.Updated += updated
+			return nil
+		}
+		upserts, records = retryUpserts, retryRecords
+	}
+
+	return nil
+}
+
+// toUpsert maps the product to a book upsert, the upsert is returned along
+// with the error when the product has an ISBN but cannot be applied.
+func (p *onixProduct) toUpsert() (*models.BookUpsert, error) {
+	isbn13, err := p.isbn()
+	if err != nil {
+		return nil, err
+	}
+	upsert := &models.BookUpsert{ISBN13: isbn13}
+
+	if strings.TrimSpace(p.NotificationType) == onixNotificationDelete {
+		return upsert, errors.New("delete notifications are not supported")
+	}
+
+	if title := p.title(); title != "" {
+		upsert.Title = &title
+	}
+	if authors := p.authors(); len(authors) > 0 {
+		upsert.Authors = authors
+	}
+	if description := p.description(); description != "" {
+		upsert.Description = &description
+	}
+
+	if len(p.Prices) > 0 {
+		amount := strings.TrimSpace(p.Prices[0].Amount)
+		price, err := strconv.ParseFloat(amount, 64)
+		if err != nil || price < 0 {
+			return upsert, fmt.Errorf("invalid price %q", amount)
+		}
+		formatted := strconv.FormatFloat(price, 'f', 2, 64)
+		upsert.Price = &formatted
+	}
+
+	return upsert, nil
+}
+
+// isbn returns the ISBN-13 of the first ISBN identifying the product.
+func (p *onixProduct) isbn() (string, error) {
+	for _, id := range p.ProductIdentifiers {
+		switch strings.TrimSpace(id.Type) {
+		case onixIDTypeISBN13, onixIDTypeGTIN13, onixIDTypeISBN10:
+			isbn13, err := isbn.Normalize(id.Value)
+			if err != nil {
+				return "", fmt.Errorf("invalid isbn %q: %v", strings.TrimSpace(id.Value), err)
+			}
+			return isbn13, nil
+		}
+	}
+	return "", errors.New("missing isbn")
+}
+
+// title returns the distinctive title of the product itself.
+func (p *onixProduct) title() string {
+	for _, detail := range p.TitleDetails {
+		if strings.TrimSpace(detail.Type) != onixTitleTypeDistinct {
+			continue
+		}
+		for _, element := range detail.Elements {
+			if strings.TrimSpace(element.Level) != onixTitleLevelProduct {
+				continue
+			}
+			if text := strings.TrimSpace(element.TitleText); text != "" {
+				return text
+			}
+			return strings.TrimSpace(strings.TrimSpace(element.TitlePrefix) + " " + strings.TrimSpace(element.TitleWithoutPrefix))
+		}
+	}
+	return ""
+}
+
+// authors returns the names of the contributors credited as authors, ordered
+// by their sequence number.
+func (p *onixProduct) authors() []string {
+	contributors := p.Contributors
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].SequenceNumber < contributors[j].SequenceNumber
+	})
+
+	authors := []string{}
+	for _, contributor := range contributors {
+		isAuthor := false
+		for _, role := range contributor.Roles {
+			if strings.TrimSpace(role) == onixRoleAuthor {
+				isAuthor = true
+			}
+		}
+		if !isAuthor {
+			continue
+		}
+
+		name := strings.TrimSpace(contributor.PersonName)
+		if name == "" {
+			name = strings.TrimSpace(strings.TrimSpace(contributor.NamesBeforeKey) + " " + strings.TrimSpace(contributor.KeyNames))
+		}
+		if name == "" {
+			name = strings.TrimSpace(contributor.CorporateName)
+		}
+		if name != "" {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+// description returns the plain text of the product description, falling
+// back to its short description. XHTML descriptions are stripped of markup.
+func (p *onixProduct) description() string {
+	for _, textType := range []string{onixTextDescription, onixTextShortDesc} {
+		for _, content := range p.TextContents {
+			if strings.TrimSpace(content.Type) != textType {
+				continue
+			}
+			if text := plainText(content.Text.Inner); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// plainText strips the inner XML of a text of its markup, which is either
+// XHTML, escaped HTML or CDATA.
+func plainText(innerXML string) string {
+	text := strings.NewReplacer("<![CDATA[", "", "]]>", "").Replace(innerXML)
+	text = tagPattern.ReplaceAllString(html.UnescapeString(text), " ")
+	return strings.Join(strings.Fields(text), " ")
+}
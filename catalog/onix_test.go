@@ -0,0 +1,237 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	mock_books_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+)
+
+const testFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<ONIXMessage release="3.0" xmlns="http://ns.editeur.org/onix/3.0/reference">
+  <Header>
+    <Sender><SenderName>Example Publishing</SenderName></Sender>
+  </Header>
+  <Product>
+    <RecordReference>com.example.1</RecordReference>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>978-0-13-235088-4</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement>
+          <TitleElementLevel>01</TitleElementLevel>
+          <TitleText>Clean Code</TitleText>
+        </TitleElement>
+      </TitleDetail>
+      <Contributor>
+        <SequenceNumber>2</SequenceNumber>
+        <ContributorRole>B01</ContributorRole>
+        <PersonName>Some Editor</PersonName>
+      </Contributor>
+      <Contributor>
+        <SequenceNumber>1</SequenceNumber>
+        <ContributorRole>A01</ContributorRole>
+        <NamesBeforeKey>Robert C.</NamesBeforeKey>
+        <KeyNames>Martin</KeyNames>
+      </Contributor>
+    </DescriptiveDetail>
+    <CollateralDetail>
+      <TextContent>
+        <TextType>03</TextType>
+        <ContentAudience>00</ContentAudience>
+        <Text textformat="05"><p xmlns="http://www.w3.org/1999/xhtml">A handbook of <em>agile</em> software craftsmanship.</p></Text>
+      </TextContent>
+    </CollateralDetail>
+    <ProductSupply>
+      <SupplyDetail>
+        <Price><PriceType>02</PriceType><PriceAmount>32.5</PriceAmount><CurrencyCode>USD</CurrencyCode></Price>
+      </SupplyDetail>
+    </ProductSupply>
+  </Product>
+  <Product>
+    <RecordReference>com.example.2</RecordReference>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>01</ProductIDType><IDValue>EX-2</IDValue></ProductIdentifier>
+  </Product>
+  <Product>
+    <RecordReference>com.example.3</RecordReference>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>02</ProductIDType><IDValue>0316346624</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement>
+          <TitleElementLevel>01</TitleElementLevel>
+          <TitlePrefix>The</TitlePrefix>
+          <TitleWithoutPrefix>Tipping Point</TitleWithoutPrefix>
+        </TitleElement>
+      </TitleDetail>
+    </DescriptiveDetail>
+  </Product>
+  <Product>
+    <RecordReference>com.example.4</RecordReference>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>9780306406157</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement>
+          <TitleElementLevel>01</TitleElementLevel>
+          <TitleText>Missing Author</TitleText>
+        </TitleElement>
+      </TitleDetail>
+    </DescriptiveDetail>
+  </Product>
+</ONIXMessage>
+`
+
+func TestIngestONIX(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockBookStorage := mock_books_storage.NewMockBookStorage(ctrl)
+
+		title, description, price := "Clean Code", "A handbook of agile software craftsmanship.", "32.50"
+		tippingPoint := "The Tipping Point"
+		wantUpserts := []*models.BookUpsert{
+			{
+				ISBN13:      "9780132350884",
+				Title:       &title,
+				Authors:     []string{"Robert C. Martin"},
+				Price:       &price,
+				Description: &description,
+			},
+			{ISBN13: "9780316346627", Title: &tippingPoint},
+			{ISBN13: "9780306406157", Title: strPtr("Missing Author")},
+		}
+
+		gomock.InOrder(
+			// the rejected product fails the whole batch, which is then
+			// upserted again without it.
+			mockBookStorage.
+				EXPECT().
+				UpsertBooks(gomock.Any(), wantUpserts, false).
+				Return([]*models.BookUpsertResult{
+					{BookID: 4, Created: true},
+					{BookID: 2},
+					{Err: errors.New("title, author and price are required to create a book")},
+				}, nil),
+			mockBookStorage.
+				EXPECT().
+				UpsertBooks(gomock.Any(), wantUpserts[:2], false).
+				Return([]*models.BookUpsertResult{
+					{BookID: 4, Created: true},
+					{BookID: 2},
+				}, nil),
+		)
+
+		report, err := IngestONIX(ctx, mockBookStorage, strings.NewReader(testFeed))
+		if err != nil {
+			t.Fatalf("IngestONIX(_, _, _) expected nil error, got = %v", err)
+		}
+
+		want := &ONIXReport{
+			Created: 1,
+			Updated: 1,
+			Rejected: []*RejectedRecord{
+				{RecordReference: "com.example.2", Message: "missing isbn"},
+				{RecordReference: "com.example.4", ISBN: "9780306406157", Message: "title, author and price are required to create a book"},
+			},
+		}
+		if diff := cmp.Diff(want, report); diff != "" {
+			t.Fatalf("IngestONIX(_, _, _) mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockBookStorage := mock_books_storage.NewMockBookStorage(ctrl)
+
+		// the products read before the feed broke are still applied.
+		mockBookStorage.
+			EXPECT().
+			UpsertBooks(gomock.Any(), gomock.Len(1), false).
+			Return([]*models.BookUpsertResult{{BookID: 4, Created: true}}, nil)
+
+		feed := testFeed[:strings.Index(testFeed, "<RecordReference>com.example.3")] + "<Broken"
+		report, err := IngestONIX(ctx, mockBookStorage, strings.NewReader(feed))
+		if !errors.Is(err, ErrMalformedONIX) {
+			t.Fatalf("IngestONIX(_, _, _) error, got = %v, want = %v", err, ErrMalformedONIX)
+		}
+		if report.Created != 1 {
+			t.Fatalf("IngestONIX(_, _, _) error, got = %v created, want = %v", report.Created, 1)
+		}
+	})
+}
+
+func TestIngestONIXDir(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockBookStorage := mock_books_storage.NewMockBookStorage(ctrl)
+
+	mockBookStorage.
+		EXPECT().
+		UpsertBooks(gomock.Any(), gomock.Any(), false).
+		Return([]*models.BookUpsertResult{{BookID: 4, Created: true}}, nil)
+
+	dir := t.TempDir()
+	feed := `<ONIXMessage release="3.0"><Product><RecordReference>1</RecordReference>` +
+		`<ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>9780132350884</IDValue></ProductIdentifier>` +
+		`</Product></ONIXMessage>`
+
+	settled := filepath.Join(dir, "feed.xml")
+	if err := os.WriteFile(settled, []byte(feed), 0o644); err != nil {
+		t.Fatalf("unexpected error when writing feed: %v", err)
+	}
+	past := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(settled, past, past); err != nil {
+		t.Fatalf("unexpected error when changing feed times: %v", err)
+	}
+
+	// a feed still being written is left for the next run.
+	fresh := filepath.Join(dir, "fresh.xml")
+	if err := os.WriteFile(fresh, []byte(feed), 0o644); err != nil {
+		t.Fatalf("unexpected error when writing feed: %v", err)
+	}
+
+	if err := IngestONIXDir(context.Background(), mockBookStorage, dir); err != nil {
+		t.Fatalf("IngestONIXDir(_, _, _) expected nil error, got = %v", err)
+	}
+
+	if _, err := os.Stat(settled); !os.IsNotExist(err) {
+		t.Fatalf("IngestONIXDir(_, _, _) expected ingested feed to be moved, got = %v", err)
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Fatalf("IngestONIXDir(_, _, _) expected fresh feed to be kept, got = %v", err)
+	}
+
+	processed, err := filepath.Glob(filepath.Join(dir, processedDir, "*"))
+	if err != nil {
+		t.Fatalf("unexpected error when listing processed feeds: %v", err)
+	}
+	if len(processed) != 2 {
+		t.Fatalf("IngestONIXDir(_, _, _) error, got = %v, want feed and its report", processed)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+)
+
+// onixSettleTime is how long a feed must be left unmodified before it is
+// ingested, so a feed still being copied into the directory is not read.
+const onixSettleTime = 5 * time.Second
+
+const (
+	processedDir = "processed"
+	failedDir    = "failed"
+)
+
+// IngestONIXDir ingests every ONIX feed, a file with the .xml extension, found
+// in dir. An ingested feed is moved to the processed directory within dir
+// along with its report, a feed that is not well-formed is moved to the
+// failed directory instead. A feed is left in place when the storage fails so
+// it is ingested again on the next run.
+func IngestONIXDir(ctx context.Context, bookStorage book.BookStorage, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read onix directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".xml") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat onix feed: %v", err)
+		}
+		if time.Since(info.ModTime()) < onixSettleTime {
+			continue
+		}
+
+		if err := ingestONIXFile(ctx, bookStorage, dir, entry.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ingestONIXFile ingests a single feed of dir and moves it out of the way.
+func ingestONIXFile(ctx context.Context, bookStorage book.BookStorage, dir, name string) error {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to open onix feed: %v", err)
+	}
+	report, err := IngestONIX(ctx, bookStorage, f)
+	f.Close()
+
+	destDir := processedDir
+	feedErr := ""
+	if err != nil {
+		if !errors.Is(err, ErrMalformedONIX) {
+			return fmt.Errorf("failed to ingest onix feed %s: %v", name, err)
+		}
+		destDir = failedDir
+		feedErr = err.Error()
+	}
+	log.Printf("ingested onix feed %s: %d created, %d updated, %d rejected",
+		name, report.Created, report.Updated, len(report.Rejected))
+
+	destDir = filepath.Join(dir, destDir)
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create onix directory: %v", err)
+	}
+
+	// feeds are usually delivered under the same name, the time they were
+	// ingested keeps them apart.
+	dest := filepath.Join(destDir, time.Now().UTC().Format("20060102T150405")+"-"+name)
+
+	out, err := json.MarshalIndent(struct {
+		*ONIXReport
+		Error string `json:"error,omitempty"`
+	}{report, feedErr}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal onix report: %v", err)
+	}
+	if err := os.WriteFile(dest+".report.json", out, 0o644); err != nil {
+		return fmt.Errorf("failed to write onix report: %v", err)
+	}
+
+	if err := os.Rename(filepath.Join(dir, name), dest); err != nil {
+		return fmt.Errorf("failed to move onix feed: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+// Command catalog imports and exports the books of our catalog as CSV, the
+// same way as the admin endpoints do, and ingests ONIX feeds from a file or a
+// watched directory. It is run from the root of the repository so it finds the
+// same config and database as the server:
+//
+//	$ go run ./cmd/catalog import -file books.csv -dry-run
+//	$ go run ./cmd/catalog export > books.csv
+//	$ go run ./cmd/catalog onix -file feed.xml
+//	$ go run ./cmd/catalog onix -watch feeds/ -interval 1m
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/kenshaw/envcfg"
+
+	"github.com/wilsonangara/simple-online-book-store/catalog"
+	"github.com/wilsonangara/simple-online-book-store/scheduler"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	book_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+)
+
+const usage = `usage:
+	catalog import [-file books.csv] [-dry-run]
+	catalog export [-file books.csv]
+	catalog onix -file feed.xml | -watch dir [-interval 1m]`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "import":
+		fs := flag.NewFlagSet("import", flag.ExitOnError)
+		file := fs.String("file", "", "csv file to import, read from stdin when empty")
+		dryRun := fs.Bool("dry-run", false, "report what would be imported without committing")
+		fs.Parse(os.Args[2:])
+
+		in := io.Reader(os.Stdin)
+		if *file != "" {
+			f, err := os.Open(*file)
+			if err != nil {
+				log.Fatalf("failed to open csv file: %v", err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		report, err := catalog.ImportCSV(ctx, newBookStorage(), in, *dryRun)
+		if err != nil {
+			log.Fatalf("failed to import books: %v", err)
+		}
+
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("failed to marshal report: %v", err)
+		}
+		fmt.Println(string(out))
+
+		if len(report.Errors) > 0 {
+			os.Exit(1)
+		}
+	case "export":
+		fs := flag.NewFlagSet("export", flag.ExitOnError)
+		file := fs.String("file", "", "csv file to export to, written to stdout when empty")
+		fs.Parse(os.Args[2:])
+
+		out := io.Writer(os.Stdout)
+		if *file != "" {
+			f, err := os.Create(*file)
+			if err != nil {
+				log.Fatalf("failed to create csv file: %v", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if err := catalog.ExportCSV(ctx, newBookStorage(), out); err != nil {
+			log.Fatalf("failed to export books: %v", err)
+		}
+	case "onix":
+		fs := flag.NewFlagSet("onix", flag.ExitOnError)
+		file := fs.String("file", "", "onix feed to ingest")
+		watch := fs.String("watch", "", "directory to watch for onix feeds")
+		interval := fs.Duration("interval", time.Minute, "how often the watched directory is checked")
+		fs.Parse(os.Args[2:])
+
+		switch {
+		case *file != "":
+			f, err := os.Open(*file)
+			if err != nil {
+				log.Fatalf("failed to open onix feed: %v", err)
+			}
+			defer f.Close()
+
+			report, err := catalog.IngestONIX(ctx, newBookStorage(), f)
+			if report != nil {
+				out, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					log.Fatalf("failed to marshal report: %v", err)
+				}
+				fmt.Println(string(out))
+			}
+			if err != nil {
+				log.Fatalf("failed to ingest onix feed: %v", err)
+			}
+		case *watch != "":
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			defer stop()
+
+			bookStorage := newBookStorage()
+			job := func(ctx context.Context) error {
+				return catalog.IngestONIXDir(ctx, bookStorage, *watch)
+			}
+			if err := job(ctx); err != nil {
+				log.Printf("failed to ingest onix feeds: %v", err)
+			}
+			scheduler.Every(ctx, "ingest onix feeds", *interval, job)
+		default:
+			log.Fatal(usage)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
+
+// newBookStorage connects to the database configured for the server.
+func newBookStorage() *book_storage.Storage {
+	config, err := envcfg.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("failed to get working directory: %v", err)
+	}
+	dbName := filepath.Join(wd, "storage", "sqlite", "databases",
+		config.GetString("db.name"),
+	)
+	pathToMigrations := filepath.Join(wd, "storage", "migrations")
+
+	storage, err := sqlite.NewStorage(dbName, pathToMigrations)
+	if err != nil {
+		log.Fatalf("failed to initialized storage: %v", err)
+	}
+
+	return book_storage.NewStorage(storage.Database())
+}
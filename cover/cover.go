@@ -0,0 +1,162 @@
+package cover
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	// MaxSize is the largest cover accepted, in bytes.
+	MaxSize = 5 << 20
+
+	// maxPixels is the largest cover accepted once decoded, a small file can
+	// still decode into a huge image.
+	maxPixels = 25_000_000
+
+	// thumbnailQuality is the JPEG quality thumbnails are encoded with.
+	thumbnailQuality = 85
+)
+
+// Original is the size name of the image a cover was uploaded with.
+const Original = "original"
+
+// Sizes are the widths thumbnails are resized to, keeping the aspect ratio
+// of the cover. A cover narrower than a size is never enlarged.
+var Sizes = map[string]int{
+	"small":  100,
+	"medium": 300,
+	"large":  600,
+}
+
+var (
+	ErrTooLarge        = errors.New("cover is too large")
+	ErrUnsupportedType = errors.New("cover must be a jpeg, png or gif image")
+	ErrInvalidImage    = errors.New("cover is not a valid image")
+)
+
+// decoders are the image formats accepted as covers by their sniffed
+// content type.
+var decoders = map[string]func([]byte) (image.Image, error){
+	"image/jpeg": func(data []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(data)) },
+	"image/png":  func(data []byte) (image.Image, error) { return png.Decode(bytes.NewReader(data)) },
+	"image/gif":  func(data []byte) (image.Image, error) { return gif.Decode(bytes.NewReader(data)) },
+}
+
+// Cover is an uploaded cover with its thumbnails.
+type Cover struct {
+	// Hash identifies a cover by the content of its original image.
+	Hash        string
+	ContentType string
+	Original    []byte
+	Thumbnails  map[string][]byte
+}
+
+// Process validates the uploaded image and generates its thumbnails. The type
+// of the image is sniffed from its content rather than trusted from the
+// upload.
+func Process(data []byte) (*Cover, error) {
+	if len(data) > MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	decode, ok := decoders[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, err := decode(data)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	thumbnails := map[string][]byte{}
+	for size, width := range Sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(img, width), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return nil, err
+		}
+		thumbnails[size] = buf.Bytes()
+	}
+
+	sum := sha256.Sum256(data)
+	return &Cover{
+		Hash:        hex.EncodeToString(sum[:]),
+		ContentType: contentType,
+		Original:    data,
+		Thumbnails:  thumbnails,
+	}, nil
+}
+
+// Key returns the blob key of a cover in the given size.
+func Key(hash, size string) string {
+	return "covers/" + hash + "/" + size
+}
+
+// resize scales the image down to the given width by averaging the pixels
+// each thumbnail pixel covers. Transparent areas are laid over white as
+// thumbnails are JPEGs.
+func resize(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if width > bounds.Dx() {
+		width = bounds.Dx()
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, src, bounds.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * bounds.Dy() / height
+		y1 := (y + 1) * bounds.Dy() / height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := x * bounds.Dx() / width
+			x1 := (x + 1) * bounds.Dx() / width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := flat.PixOffset(bounds.Min.X+sx, bounds.Min.Y+sy)
+					r += uint64(flat.Pix[i])
+					g += uint64(flat.Pix[i+1])
+					b += uint64(flat.Pix[i+2])
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 255})
+		}
+	}
+
+	return dst
+}
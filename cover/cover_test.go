@@ -0,0 +1,86 @@
+package cover
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testPNG(t testing.TB, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("unexpected error when encoding png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		data := testPNG(t, 400, 600)
+
+		cover, err := Process(data)
+		if err != nil {
+			t.Fatalf("Process(_) expected nil error, got = %v", err)
+		}
+		if cover.ContentType != "image/png" || len(cover.Hash) != 64 {
+			t.Fatalf("Process(_) unexpected cover: %v, %v", cover.ContentType, cover.Hash)
+		}
+
+		// covers narrower than a size are not enlarged.
+		wantWidths := map[string]int{"small": 100, "medium": 300, "large": 400}
+		for size, width := range wantWidths {
+			img, err := jpeg.Decode(bytes.NewReader(cover.Thumbnails[size]))
+			if err != nil {
+				t.Fatalf("unexpected error when decoding %s thumbnail: %v", size, err)
+			}
+			if img.Bounds().Dx() != width || img.Bounds().Dy() != width*3/2 {
+				t.Fatalf("Process(_) %s thumbnail error, got = %v, want width = %v", size, img.Bounds(), width)
+			}
+		}
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Process([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Fatalf("Process(_) error, got = %v, want = %v", err, ErrUnsupportedType)
+		}
+	})
+
+	t.Run("InvalidImage", func(t *testing.T) {
+		t.Parallel()
+
+		data := testPNG(t, 10, 10)
+		_, err := Process(data[:len(data)/2])
+		if !errors.Is(err, ErrInvalidImage) {
+			t.Fatalf("Process(_) error, got = %v, want = %v", err, ErrInvalidImage)
+		}
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Process(make([]byte, MaxSize+1))
+		if !errors.Is(err, ErrTooLarge) {
+			t.Fatalf("Process(_) error, got = %v, want = %v", err, ErrTooLarge)
+		}
+	})
+}
@@ -12,6 +12,7 @@ require (
 	github.com/kenshaw/envcfg v0.5.0
 	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/pressly/goose v2.7.0+incompatible
+	golang.org/x/crypto v0.5.0
 )
 
 require (
@@ -47,7 +48,6 @@ require (
 	github.com/yookoala/realpath v1.0.0 // indirect
 	go.opencensus.io v0.22.5 // indirect
 	golang.org/x/arch v0.0.0-20210923205945-b76863e36670 // indirect
-	golang.org/x/crypto v0.5.0 // indirect
 	golang.org/x/net v0.7.0 // indirect
 	golang.org/x/oauth2 v0.0.0-20201203001011-0b49973bad19 // indirect
 	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4 // indirect
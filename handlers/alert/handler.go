@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/alert"
+)
+
+var (
+	errInternalServer   = errors.New("internal error")
+	errBookNotFound     = errors.New("book not found")
+	errInvalidID        = errors.New("invalid book id")
+	errInvalidThreshold = errors.New("reorder_threshold must not be negative")
+)
+
+type Handler struct {
+	alertStorage alert.AlertStorage
+}
+
+// NewHandler returns a wrapper for stock alert handler.
+func NewHandler(alertStorage alert.AlertStorage) *Handler {
+	return &Handler{alertStorage: alertStorage}
+}
+
+// GetOpenAlerts fetches the stock alerts of the books not restocked yet.
+func (h *Handler) GetOpenAlerts(c *gin.Context) {
+	alerts, err := h.alertStorage.GetOpenAlerts(c.Request.Context())
+	if err != nil {
+		log.Printf("failed to get open stock alerts: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+	})
+}
+
+type SetReorderThresholdRequest struct {
+	ReorderThreshold *int64 `json:"reorder_threshold"`
+}
+
+// SetReorderThreshold sets the stock a book is alerted on, a null threshold
+// stops alerting on the book.
+func (h *Handler) SetReorderThreshold(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return
+	}
+
+	r := &SetReorderThresholdRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.ReorderThreshold != nil && *r.ReorderThreshold < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidThreshold.Error(),
+		})
+		return
+	}
+
+	if err := h.alertStorage.SetReorderThreshold(c.Request.Context(), id, r.ReorderThreshold); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to set reorder threshold: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
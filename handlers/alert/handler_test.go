@@ -0,0 +1,191 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_alert "github.com/wilsonangara/simple-online-book-store/storage/sqlite/alert/mock"
+)
+
+func Test_GetOpenAlerts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(m *mock_storage_alert.MockAlertStorage)
+		wantCode int
+		wantBody gin.H
+	}{
+		{
+			name: "Success",
+			mock: func(m *mock_storage_alert.MockAlertStorage) {
+				m.EXPECT().GetOpenAlerts(gomock.Any()).Return([]*models.StockAlert{
+					{ID: 1, BookID: 1, Title: "Atomic Habits", Threshold: 5, Stock: 3, Status: models.StockAlertStatusOpen},
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "InternalServerError",
+			mock: func(m *mock_storage_alert.MockAlertStorage) {
+				m.EXPECT().GetOpenAlerts(gomock.Any()).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockAlertStorage := mock_storage_alert.NewMockAlertStorage(ctrl)
+			tt.mock(mockAlertStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				alertStorage: mockAlertStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8443/v1/alerts", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.GetOpenAlerts(testCtx)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GetOpenAlerts() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+
+			if tt.wantBody != nil {
+				if diff := cmp.Diff(tt.wantBody, getResponseBody(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("GetOpenAlerts() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_SetReorderThreshold(t *testing.T) {
+	t.Parallel()
+
+	threshold := int64(5)
+
+	tests := []struct {
+		name     string
+		id       string
+		body     string
+		mock     func(m *mock_storage_alert.MockAlertStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			body: `{"reorder_threshold": 5}`,
+			mock: func(m *mock_storage_alert.MockAlertStorage) {
+				m.EXPECT().SetReorderThreshold(gomock.Any(), int64(1), &threshold).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name: "Unset",
+			id:   "1",
+			body: `{"reorder_threshold": null}`,
+			mock: func(m *mock_storage_alert.MockAlertStorage) {
+				m.EXPECT().SetReorderThreshold(gomock.Any(), int64(1), nil).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "NegativeThreshold",
+			id:       "1",
+			body:     `{"reorder_threshold": -1}`,
+			mock:     func(m *mock_storage_alert.MockAlertStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidThreshold.Error()},
+		},
+		{
+			name:     "InvalidID",
+			id:       "abc",
+			body:     `{"reorder_threshold": 5}`,
+			mock:     func(m *mock_storage_alert.MockAlertStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidID.Error()},
+		},
+		{
+			name: "NotFound",
+			id:   "1000",
+			body: `{"reorder_threshold": 5}`,
+			mock: func(m *mock_storage_alert.MockAlertStorage) {
+				m.EXPECT().SetReorderThreshold(gomock.Any(), int64(1000), &threshold).Return(sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errBookNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockAlertStorage := mock_storage_alert.NewMockAlertStorage(ctrl)
+			tt.mock(mockAlertStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				alertStorage: mockAlertStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodPut, "http://localhost:8443/v1/books/"+tt.id+"/reorder-threshold", bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.SetReorderThreshold(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("SetReorderThreshold() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, getResponseBody(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("SetReorderThreshold() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
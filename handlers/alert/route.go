@@ -0,0 +1,15 @@
+package alert
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddAlertRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/alerts", m.Authenticate(), m.Admin())
+
+	r.GET("/", h.GetOpenAlerts)
+
+	rg.PUT("/books/:id/reorder-threshold", m.Authenticate(), m.Admin(), h.SetReorderThreshold)
+}
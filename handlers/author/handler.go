@@ -0,0 +1,281 @@
+package author
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/author"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+)
+
+var (
+	errInternalServer = errors.New("internal error")
+	errInvalidID      = errors.New("invalid author id")
+	errAuthorNotFound = errors.New("author not found")
+	errInvalidIntoID  = errors.New("into_id is required")
+	errInvalidBookID  = errors.New("invalid book id")
+	errNameIsRequired = errors.New("name is required")
+	errBookNotFound   = errors.New("book not found")
+	errCreditNotFound = errors.New("credit not found")
+)
+
+type Handler struct {
+	authorStorage author.AuthorStorage
+	bookStorage   book.BookStorage
+}
+
+// NewHandler returns a wrapper for author handler.
+func NewHandler(authorStorage author.AuthorStorage, bookStorage book.BookStorage) *Handler {
+	return &Handler{
+		authorStorage: authorStorage,
+		bookStorage:   bookStorage,
+	}
+}
+
+// GetAuthor fetches an author by the given id.
+func (h *Handler) GetAuthor(c *gin.Context) {
+	foundAuthor, ok := h.getAuthor(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"author": foundAuthor,
+	})
+}
+
+// GetAuthorBooks fetches all books an author is credited on, in any role.
+func (h *Handler) GetAuthorBooks(c *gin.Context) {
+	foundAuthor, ok := h.getAuthor(c)
+	if !ok {
+		return
+	}
+
+	credits, err := h.authorStorage.GetBookAuthorsByAuthorID(c.Request.Context(), foundAuthor.ID)
+	if err != nil {
+		log.Printf("failed to get credits of author %d: %v", foundAuthor.ID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	bookIDs := []int64{}
+	for _, credit := range credits {
+		bookIDs = append(bookIDs, credit.BookID)
+	}
+
+	books := []*models.Book{}
+	if len(bookIDs) > 0 {
+		books, err = h.bookStorage.GetBooksByIDs(c.Request.Context(), bookIDs)
+		if err != nil {
+			log.Printf("failed to get books by ids: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"author": foundAuthor,
+		"books":  books,
+	})
+}
+
+// MergeAuthorRequest names the author an author is merged into.
+type MergeAuthorRequest struct {
+	IntoID int64 `json:"into_id"`
+}
+
+// MergeAuthor lets an admin merge an author into another, such as an author
+// whose name was written differently on some books. The merged author is kept
+// as an alias of the author it was merged into.
+func (h *Handler) MergeAuthor(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return
+	}
+
+	r := &MergeAuthorRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.IntoID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidIntoID.Error(),
+		})
+		return
+	}
+
+	merged, err := h.authorStorage.Merge(c.Request.Context(), id, r.IntoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sqlite.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errAuthorNotFound.Error(),
+			})
+		case errors.Is(err, author.ErrMergeIntoItself):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		default:
+			log.Printf("failed to merge author %d into %d: %v", id, r.IntoID, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"author": merged,
+	})
+}
+
+// CreditBookRequest names the author credited on a book and their role.
+type CreditBookRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// CreditBook lets an admin credit a translator or an illustrator on a book,
+// the authors of a book are credited from its author instead.
+func (h *Handler) CreditBook(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || bookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	r := &CreditBookRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errNameIsRequired.Error(),
+		})
+		return
+	}
+
+	credit, err := h.authorStorage.CreditBook(c.Request.Context(), bookID, name, r.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, author.ErrInvalidRole):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		case errors.Is(err, author.ErrBookIDNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+		case errors.Is(err, author.ErrAlreadyCredited):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		default:
+			log.Printf("failed to credit %q on book %d: %v", name, bookID, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"credit": credit,
+	})
+}
+
+// UncreditBook lets an admin remove the credit of a translator or an
+// illustrator, given by the role query, from a book.
+func (h *Handler) UncreditBook(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || bookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	authorID, err := strconv.ParseInt(c.Param("author_id"), 10, 64)
+	if err != nil || authorID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return
+	}
+
+	if err := h.authorStorage.UncreditBook(c.Request.Context(), bookID, authorID, c.Query("role")); err != nil {
+		switch {
+		case errors.Is(err, author.ErrInvalidRole):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		case errors.Is(err, sqlite.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errCreditNotFound.Error(),
+			})
+		default:
+			log.Printf("failed to remove credit of author %d on book %d: %v", authorID, bookID, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// getAuthor fetches the author referenced by the id path parameter,
+// aborting the request when it cannot be found.
+func (h *Handler) getAuthor(c *gin.Context) (*models.Author, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return nil, false
+	}
+
+	foundAuthor, err := h.authorStorage.GetAuthorByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errAuthorNotFound.Error(),
+			})
+			return nil, false
+		}
+		log.Printf("failed to get author by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return nil, false
+	}
+	return foundAuthor, true
+}
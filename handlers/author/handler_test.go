@@ -0,0 +1,671 @@
+package author
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/author"
+	mock_storage_author "github.com/wilsonangara/simple-online-book-store/storage/sqlite/author/mock"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+)
+
+func Test_GetAuthor(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/authors/1"
+	)
+
+	mockGetAuthorByID := func(res *models.Author, err error) func(m *mock_storage_author.MockAuthorStorage) {
+		return func(m *mock_storage_author.MockAuthorStorage) {
+			m.
+				EXPECT().
+				GetAuthorByID(
+					gomock.Any(), // context
+					gomock.Any(), // author id
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		mockAuthor func(m *mock_storage_author.MockAuthorStorage)
+		wantCode   int
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			mockAuthor: mockGetAuthorByID(&models.Author{
+				ID:   1,
+				Name: genString(),
+			}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "InvalidID",
+			id:       "abc",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:       "AuthorNotFound",
+			id:         "1",
+			mockAuthor: mockGetAuthorByID(nil, sqlite.ErrNotFound),
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "GetAuthorDatabaseOperationFailed",
+			id:         "1",
+			mockAuthor: mockGetAuthorByID(nil, errors.New("failed to execute GetAuthorByID operation")),
+			wantCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageAuthor := mock_storage_author.NewMockAuthorStorage(ctrl)
+			if tt.mockAuthor != nil {
+				tt.mockAuthor(mockStorageAuthor)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				authorStorage: mockStorageAuthor,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.GetAuthor(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetAuthor() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_GetAuthorBooks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/authors/1/books"
+
+		validAuthorID = int64(1)
+		validBookID   = int64(1)
+	)
+
+	validAuthor := &models.Author{
+		ID:   validAuthorID,
+		Name: genString(),
+	}
+
+	// mock functions
+	mockGetAuthorByID := func(res *models.Author, err error) func(m *mock_storage_author.MockAuthorStorage) {
+		return func(m *mock_storage_author.MockAuthorStorage) {
+			m.
+				EXPECT().
+				GetAuthorByID(
+					gomock.Any(), // context
+					gomock.Any(), // author id
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetBookAuthorsByAuthorID := func(res []*models.BookAuthor, err error) func(m *mock_storage_author.MockAuthorStorage) {
+		return func(m *mock_storage_author.MockAuthorStorage) {
+			m.
+				EXPECT().
+				GetBookAuthorsByAuthorID(
+					gomock.Any(), // context
+					gomock.Any(), // author id
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetBooksByIDs := func(res []*models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+		return func(m *mock_storage_book.MockBookStorage) {
+			m.
+				EXPECT().
+				GetBooksByIDs(
+					gomock.Any(), // context
+					gomock.Any(), // book IDs
+				).
+				Return(res, err)
+		}
+	}
+
+	validCredits := []*models.BookAuthor{
+		{
+			BookID:   validBookID,
+			AuthorID: validAuthorID,
+			Name:     validAuthor.Name,
+			Role:     "author",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		mockAuthor []func(m *mock_storage_author.MockAuthorStorage)
+		mockBook   func(m *mock_storage_book.MockBookStorage)
+		wantCode   int
+	}{
+		{
+			name: "Success",
+			mockAuthor: []func(m *mock_storage_author.MockAuthorStorage){
+				mockGetAuthorByID(validAuthor, nil),
+				mockGetBookAuthorsByAuthorID(validCredits, nil),
+			},
+			mockBook: mockGetBooksByIDs([]*models.Book{
+				{
+					ID:     validBookID,
+					Title:  genString(),
+					Author: validAuthor.Name,
+					Price:  "1.10",
+				},
+			}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "AuthorNotFound",
+			mockAuthor: []func(m *mock_storage_author.MockAuthorStorage){
+				mockGetAuthorByID(nil, sqlite.ErrNotFound),
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "GetCreditsDatabaseOperationFailed",
+			mockAuthor: []func(m *mock_storage_author.MockAuthorStorage){
+				mockGetAuthorByID(validAuthor, nil),
+				mockGetBookAuthorsByAuthorID(nil, errors.New("failed to execute GetBookAuthorsByAuthorID operation")),
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "GetBooksDatabaseOperationFailed",
+			mockAuthor: []func(m *mock_storage_author.MockAuthorStorage){
+				mockGetAuthorByID(validAuthor, nil),
+				mockGetBookAuthorsByAuthorID(validCredits, nil),
+			},
+			mockBook: mockGetBooksByIDs(nil, errors.New("failed to execute GetBooksByIDs operation")),
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageAuthor := mock_storage_author.NewMockAuthorStorage(ctrl)
+			for _, mock := range tt.mockAuthor {
+				mock(mockStorageAuthor)
+			}
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			if tt.mockBook != nil {
+				tt.mockBook(mockStorageBook)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				authorStorage: mockStorageAuthor,
+				bookStorage:   mockStorageBook,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			h.GetAuthorBooks(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetAuthorBooks() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_MergeAuthor(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	validAuthor := &models.Author{
+		ID:      2,
+		Name:    "Malcolm Gladwell",
+		Aliases: []string{"M. Gladwell"},
+	}
+
+	mockMerge := func(res *models.Author, err error) func(m *mock_storage_author.MockAuthorStorage) {
+		return func(m *mock_storage_author.MockAuthorStorage) {
+			m.
+				EXPECT().
+				Merge(
+					gomock.Any(), // context
+					int64(4),     // author id
+					int64(2),     // into author id
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		body       any
+		mockAuthor func(m *mock_storage_author.MockAuthorStorage)
+		wantCode   int
+		wantErr    gin.H
+	}{
+		{
+			name:       "Success",
+			id:         "4",
+			body:       &MergeAuthorRequest{IntoID: 2},
+			mockAuthor: mockMerge(validAuthor, nil),
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:     "InvalidID",
+			id:       "abc",
+			body:     &MergeAuthorRequest{IntoID: 2},
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidID.Error(),
+			},
+		},
+		{
+			name:     "MissingIntoID",
+			id:       "4",
+			body:     gin.H{},
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidIntoID.Error(),
+			},
+		},
+		{
+			name:       "IntoItself",
+			id:         "4",
+			body:       &MergeAuthorRequest{IntoID: 2},
+			mockAuthor: mockMerge(nil, author.ErrMergeIntoItself),
+			wantCode:   http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": author.ErrMergeIntoItself.Error(),
+			},
+		},
+		{
+			name:       "AuthorNotFound",
+			id:         "4",
+			body:       &MergeAuthorRequest{IntoID: 2},
+			mockAuthor: mockMerge(nil, sqlite.ErrNotFound),
+			wantCode:   http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errAuthorNotFound.Error(),
+			},
+		},
+		{
+			name:       "MergeDatabaseOperationFailed",
+			id:         "4",
+			body:       &MergeAuthorRequest{IntoID: 2},
+			mockAuthor: mockMerge(nil, errors.New("failed to execute Merge operation")),
+			wantCode:   http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageAuthor := mock_storage_author.NewMockAuthorStorage(ctrl)
+			if tt.mockAuthor != nil {
+				tt.mockAuthor(mockStorageAuthor)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				authorStorage: mockStorageAuthor,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8443/v1/authors/"+tt.id+"/merge", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.MergeAuthor(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("MergeAuthor() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				var resBody gin.H
+				if err := json.Unmarshal(w.Body.Bytes(), &resBody); err != nil {
+					t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+				}
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("MergeAuthor() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+
+			var resBody struct {
+				Author *models.Author `json:"author"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resBody); err != nil {
+				t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+			}
+			if diff := cmp.Diff(validAuthor, resBody.Author); diff != "" {
+				t.Fatalf("MergeAuthor() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_CreditBook(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	validCredit := &models.BookAuthor{
+		BookID:   1,
+		AuthorID: 5,
+		Name:     "Anna Translator",
+		Role:     "translator",
+	}
+
+	mockCreditBook := func(res *models.BookAuthor, err error) func(m *mock_storage_author.MockAuthorStorage) {
+		return func(m *mock_storage_author.MockAuthorStorage) {
+			m.
+				EXPECT().
+				CreditBook(
+					gomock.Any(),      // context
+					int64(1),          // book id
+					"Anna Translator", // name
+					gomock.Any(),      // role
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		body       any
+		mockAuthor func(m *mock_storage_author.MockAuthorStorage)
+		wantCode   int
+		wantErr    gin.H
+	}{
+		{
+			name:       "Success",
+			id:         "1",
+			body:       &CreditBookRequest{Name: " Anna Translator ", Role: "translator"},
+			mockAuthor: mockCreditBook(validCredit, nil),
+			wantCode:   http.StatusCreated,
+		},
+		{
+			name:     "InvalidBookID",
+			id:       "abc",
+			body:     &CreditBookRequest{Name: "Anna Translator", Role: "translator"},
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidBookID.Error(),
+			},
+		},
+		{
+			name:     "MissingName",
+			id:       "1",
+			body:     &CreditBookRequest{Role: "translator"},
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errNameIsRequired.Error(),
+			},
+		},
+		{
+			name:       "InvalidRole",
+			id:         "1",
+			body:       &CreditBookRequest{Name: "Anna Translator", Role: "author"},
+			mockAuthor: mockCreditBook(nil, author.ErrInvalidRole),
+			wantCode:   http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": author.ErrInvalidRole.Error(),
+			},
+		},
+		{
+			name:       "BookNotFound",
+			id:         "1",
+			body:       &CreditBookRequest{Name: "Anna Translator", Role: "translator"},
+			mockAuthor: mockCreditBook(nil, author.ErrBookIDNotFound),
+			wantCode:   http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errBookNotFound.Error(),
+			},
+		},
+		{
+			name:       "AlreadyCredited",
+			id:         "1",
+			body:       &CreditBookRequest{Name: "Anna Translator", Role: "translator"},
+			mockAuthor: mockCreditBook(nil, author.ErrAlreadyCredited),
+			wantCode:   http.StatusConflict,
+			wantErr: gin.H{
+				"message": author.ErrAlreadyCredited.Error(),
+			},
+		},
+		{
+			name:       "CreditBookDatabaseOperationFailed",
+			id:         "1",
+			body:       &CreditBookRequest{Name: "Anna Translator", Role: "translator"},
+			mockAuthor: mockCreditBook(nil, errors.New("failed to execute CreditBook operation")),
+			wantCode:   http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageAuthor := mock_storage_author.NewMockAuthorStorage(ctrl)
+			if tt.mockAuthor != nil {
+				tt.mockAuthor(mockStorageAuthor)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				authorStorage: mockStorageAuthor,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8443/v1/books/"+tt.id+"/credits", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.CreditBook(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("CreditBook() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				var resBody gin.H
+				if err := json.Unmarshal(w.Body.Bytes(), &resBody); err != nil {
+					t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+				}
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("CreditBook() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_UncreditBook(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockUncreditBook := func(err error) func(m *mock_storage_author.MockAuthorStorage) {
+		return func(m *mock_storage_author.MockAuthorStorage) {
+			m.
+				EXPECT().
+				UncreditBook(
+					gomock.Any(),  // context
+					int64(1),      // book id
+					int64(5),      // author id
+					"illustrator", // role
+				).
+				Return(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		bookID     string
+		authorID   string
+		mockAuthor func(m *mock_storage_author.MockAuthorStorage)
+		wantCode   int
+	}{
+		{
+			name:       "Success",
+			bookID:     "1",
+			authorID:   "5",
+			mockAuthor: mockUncreditBook(nil),
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:     "InvalidBookID",
+			bookID:   "abc",
+			authorID: "5",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "InvalidAuthorID",
+			bookID:   "1",
+			authorID: "0",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:       "InvalidRole",
+			bookID:     "1",
+			authorID:   "5",
+			mockAuthor: mockUncreditBook(author.ErrInvalidRole),
+			wantCode:   http.StatusBadRequest,
+		},
+		{
+			name:       "CreditNotFound",
+			bookID:     "1",
+			authorID:   "5",
+			mockAuthor: mockUncreditBook(sqlite.ErrNotFound),
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "UncreditBookDatabaseOperationFailed",
+			bookID:     "1",
+			authorID:   "5",
+			mockAuthor: mockUncreditBook(errors.New("failed to execute UncreditBook operation")),
+			wantCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageAuthor := mock_storage_author.NewMockAuthorStorage(ctrl)
+			if tt.mockAuthor != nil {
+				tt.mockAuthor(mockStorageAuthor)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				authorStorage: mockStorageAuthor,
+			}
+
+			r, err := http.NewRequest(http.MethodDelete, "http://localhost:8443/v1/books/"+tt.bookID+"/credits/"+tt.authorID+"?role=illustrator", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{
+				{Key: "id", Value: tt.bookID},
+				{Key: "author_id", Value: tt.authorID},
+			}
+
+			h.UncreditBook(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("UncreditBook() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
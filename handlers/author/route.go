@@ -0,0 +1,18 @@
+package author
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddAuthorRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/authors")
+
+	r.GET("/:id", h.GetAuthor)
+	r.GET("/:id/books", h.GetAuthorBooks)
+	r.POST("/:id/merge", m.Authenticate(), m.Admin(), h.MergeAuthor)
+
+	rg.POST("/books/:id/credits", m.Authenticate(), m.Admin(), h.CreditBook)
+	rg.DELETE("/books/:id/credits/:author_id", m.Authenticate(), m.Admin(), h.UncreditBook)
+}
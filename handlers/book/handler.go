@@ -1,37 +1,551 @@
 package book
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/wilsonangara/simple-online-book-store/catalog"
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/locale"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
 )
 
-var errInternalServer = errors.New("internal error")
+// maxImportSize is the largest CSV catalog accepted by an import.
+const maxImportSize = 10 << 20
+
+const (
+	// defaultChangesLimit is how many changes are fetched at once unless
+	// asked otherwise, up to maxChangesLimit.
+	defaultChangesLimit = 100
+	maxChangesLimit     = 1000
+)
+
+// catalogCacheControl lets caches keep our listings but have them
+// revalidate every time, stock changes with every order.
+const catalogCacheControl = "public, no-cache"
+
+// releaseDateLayout is the layout of the release date of a book.
+const releaseDateLayout = "2006-01-02"
+
+var (
+	errInternalServer = errors.New("internal error")
+	errInvalidDryRun  = errors.New("invalid dry_run")
+	errMissingFile    = errors.New("missing csv file")
+	errBookNotFound   = errors.New("book not found")
+	errInvalidID      = errors.New("invalid book id")
+
+	errInvalidReleaseDate = errors.New("invalid release_date, expected YYYY-MM-DD")
+	errInvalidSince       = errors.New("invalid since")
+	errInvalidAuthorID    = errors.New("invalid author id")
+	errInvalidPriceBand   = errors.New("invalid price_band")
+	errInvalidLimit       = errors.New("invalid limit")
+)
 
 type Handler struct {
 	bookStorage book.BookStorage
+	localizer   *locale.Localizer
 }
 
 // NewHandler returns a wrapper for book handler.
-func NewHandler(bookStorage book.BookStorage) *Handler {
-	return &Handler{bookStorage: bookStorage}
+func NewHandler(bookStorage book.BookStorage, localizer *locale.Localizer) *Handler {
+	return &Handler{
+		bookStorage: bookStorage,
+		localizer:   localizer,
+	}
 }
 
-// GetBooks fetches all books that exist in our storage.
+// GetBooks fetches all books that exist in our storage in the locale of the
+// request, optionally only the books matching a search query q, an author,
+// a category, a price band or a publisher. The books are counted by author,
+// price band and category next to them.
 func (h *Handler) GetBooks(c *gin.Context) {
-	books, err := h.bookStorage.GetBooks(c.Request.Context())
+	filter := &models.BookFilter{
+		Publisher: c.Query("publisher"),
+		Query:     c.Query("q"),
+		Category:  c.Query("category"),
+		PriceBand: c.Query("price_band"),
+	}
+	if v := c.Query("author"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errInvalidAuthorID.Error(),
+			})
+			return
+		}
+		filter.AuthorID = id
+	}
+	if filter.PriceBand != "" {
+		if _, ok := models.GetPriceBand(filter.PriceBand); !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errInvalidPriceBand.Error(),
+			})
+			return
+		}
+	}
+
+	lang, ok := h.negotiateLocale(c)
+	if !ok {
+		return
+	}
+
+	if h.notModified(c, lang) {
+		return
+	}
+
+	books, err := h.bookStorage.GetBooks(c.Request.Context(), filter)
 	if err != nil {
 		log.Printf("failed to get books: %v", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"message": errInternalServer.Error(),
 		})
+		return
+	}
+
+	facets, err := h.bookStorage.GetFacets(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("failed to get book facets: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	if !h.localize(c, lang, books...) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"books":  books,
+		"facets": facets,
+	})
+}
+
+// GetBook fetches a book by the given id in the locale of the request.
+func (h *Handler) GetBook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return
+	}
+
+	lang, ok := h.negotiateLocale(c)
+	if !ok {
+		return
+	}
+
+	if h.notModified(c, lang) {
+		return
+	}
+
+	book, err := h.bookStorage.GetBookByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get book by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	if !h.localize(c, lang, book) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"book": book,
+	})
+}
+
+// GetBookByISBN fetches a book by either its ISBN-10 or ISBN-13 in the
+// locale of the request.
+func (h *Handler) GetBookByISBN(c *gin.Context) {
+	isbn13, err := isbn.Normalize(c.Param("isbn"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	lang, ok := h.negotiateLocale(c)
+	if !ok {
+		return
+	}
+
+	if h.notModified(c, lang) {
+		return
+	}
+
+	book, err := h.bookStorage.GetBookByISBN(c.Request.Context(), isbn13)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get book by isbn: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	if !h.localize(c, lang, book) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"book": book,
+	})
+}
+
+// GetChanges fetches the changes of our books sequenced after since, in
+// order, each with the book as it is now. Mirrors of our catalog resume from
+// the next_since of the last page until has_more is false.
+func (h *Handler) GetChanges(c *gin.Context) {
+	since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil || since < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidSince.Error(),
+		})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultChangesLimit)))
+	if err != nil || limit < 1 || limit > maxChangesLimit {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidLimit.Error(),
+		})
+		return
+	}
+
+	// one more change than asked for tells whether there are more.
+	changes, err := h.bookStorage.GetChanges(c.Request.Context(), since, limit+1)
+	if err != nil {
+		log.Printf("failed to get book changes: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	ids := []int64{}
+	seen := map[int64]bool{}
+	for _, change := range changes {
+		if change.Operation != models.BookChangeDelete && !seen[change.BookID] {
+			seen[change.BookID] = true
+			ids = append(ids, change.BookID)
+		}
+	}
+	if len(ids) > 0 {
+		books, err := h.bookStorage.GetBooksByIDs(c.Request.Context(), ids)
+		if err != nil {
+			log.Printf("failed to get books by ids: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+
+		// archived books are left out, mirrors drop them as deleted.
+		byID := make(map[int64]*models.Book, len(books))
+		for _, b := range books {
+			byID[b.ID] = b
+		}
+		for _, change := range changes {
+			if change.Operation != models.BookChangeDelete {
+				change.Book = byID[change.BookID]
+			}
+		}
+	}
+
+	nextSince := since
+	if len(changes) > 0 {
+		nextSince = changes[len(changes)-1].Seq
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes":    changes,
+		"next_since": nextSince,
+		"has_more":   hasMore,
+	})
+}
+
+// ImportBooks upserts the books of a CSV catalog sent either as the request
+// body or as the "file" of a multipart form. Nothing is committed when any
+// row fails or when dry_run is set, the report lists the failing rows.
+func (h *Handler) ImportBooks(c *gin.Context) {
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errInvalidDryRun.Error(),
+			})
+			return
+		}
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	var body io.Reader = c.Request.Body
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errMissingFile.Error(),
+			})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Printf("failed to open csv file: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	report, err := catalog.ImportCSV(c.Request.Context(), h.bookStorage, body, dryRun)
+	if err != nil {
+		if errors.Is(err, catalog.ErrInvalidHeader) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		log.Printf("failed to import books: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if len(report.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{
+		"report": report,
+	})
+}
+
+// ExportBooks streams every book of our catalog as CSV.
+func (h *Handler) ExportBooks(c *gin.Context) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="books.csv"`)
+	c.Status(http.StatusOK)
+
+	// the status is already sent once books are written, a failing export
+	// can only be logged.
+	if err := catalog.ExportCSV(c.Request.Context(), h.bookStorage, c.Writer); err != nil {
+		log.Printf("failed to export books: %v", err)
+	}
+}
+
+// GetArchivedBooks fetches all archived books.
+func (h *Handler) GetArchivedBooks(c *gin.Context) {
+	books, err := h.bookStorage.GetArchivedBooks(c.Request.Context())
+	if err != nil {
+		log.Printf("failed to get archived books: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"books": books,
 	})
 }
+
+// ArchiveBook hides a book from our listings and stops it from being
+// ordered, past orders of the book keep showing it.
+func (h *Handler) ArchiveBook(c *gin.Context) {
+	h.setArchived(c, h.bookStorage.Archive)
+}
+
+// RestoreBook brings an archived book back to our listings.
+func (h *Handler) RestoreBook(c *gin.Context) {
+	h.setArchived(c, h.bookStorage.Restore)
+}
+
+type SetReleaseDateRequest struct {
+	ReleaseDate string `json:"release_date"`
+}
+
+// SetReleaseDate lets an admin set the date a book is released on, books
+// are up for preorder until then. An empty date marks the book as released.
+func (h *Handler) SetReleaseDate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return
+	}
+
+	r := &SetReleaseDateRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.ReleaseDate != "" {
+		if _, err := time.Parse(releaseDateLayout, r.ReleaseDate); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errInvalidReleaseDate.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.bookStorage.SetReleaseDate(c.Request.Context(), id, r.ReleaseDate); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to set book release date: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// negotiateLocale picks the locale to respond in from the lang query
+// parameter or the Accept-Language header, aborting the request when it
+// cannot.
+func (h *Handler) negotiateLocale(c *gin.Context) (string, bool) {
+	lang, err := h.localizer.Negotiate(c.Request.Context(), c.Request)
+	if err != nil {
+		log.Printf("failed to negotiate locale: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return "", false
+	}
+
+	c.Header("Content-Language", lang)
+	c.Header("Vary", "Accept-Language")
+	return lang, true
+}
+
+// localize translates books into the given locale, aborting the request
+// when it cannot.
+func (h *Handler) localize(c *gin.Context, lang string, books ...*models.Book) bool {
+	if err := h.localizer.Books(c.Request.Context(), lang, books); err != nil {
+		log.Printf("failed to localize books: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// notModified sets the validators of our listings in a locale, which change
+// along with the catalog state, and responds with 304 Not Modified when the
+// copy the client has is still fresh. If-Modified-Since is only looked at
+// when no If-None-Match is given. Listings are served without validators
+// when the catalog state cannot be fetched.
+func (h *Handler) notModified(c *gin.Context, lang string) bool {
+	state, err := h.bookStorage.GetCatalogState(c.Request.Context())
+	if err != nil {
+		log.Printf("failed to get catalog state: %v", err)
+		c.Header("Cache-Control", "no-store")
+		return false
+	}
+
+	etag := fmt.Sprintf(`"%d-%d-%d-%s"`, state.Version, state.ListingVersion, state.ModifiedAt.Unix(), lang)
+	c.Header("Cache-Control", catalogCacheControl)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", state.ModifiedAt.UTC().Format(http.TimeFormat))
+
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if !etagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		if err != nil || state.ModifiedAt.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header lists the given
+// entity tag, compared weakly as the header asks for.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// setArchived archives or restores the book with the id from the path.
+func (h *Handler) setArchived(c *gin.Context, set func(context.Context, int64) error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return
+	}
+
+	if err := set(c.Request.Context(), id); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to update book archive: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -2,6 +2,7 @@ package book
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -11,12 +12,23 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/locale"
 	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
 	mock_books_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	mock_translation_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation/mock"
 )
 
+var validCatalogState = &models.CatalogState{
+	Version:        3,
+	ListingVersion: 7,
+	ModifiedAt:     time.Date(2030, 1, 31, 10, 0, 0, 0, time.UTC),
+}
+
 func Test_GetBooks(t *testing.T) {
 	t.Parallel()
 
@@ -27,14 +39,19 @@ func Test_GetBooks(t *testing.T) {
 		validEndpoint = "http://localhost:8433/v1/books"
 	)
 
-	mockBookStorage := func(res []*models.Book, err error) func(m *mock_books_storage.MockBookStorage) {
+	mockBookStorage := func(filter *models.BookFilter, res []*models.Book, err error) func(m *mock_books_storage.MockBookStorage) {
 		return func(m *mock_books_storage.MockBookStorage) {
+			m.EXPECT().GetCatalogState(gomock.Any()).Return(validCatalogState, nil)
 			m.
 				EXPECT().
 				GetBooks(
 					gomock.Any(), // context
+					filter,
 				).
 				Return(res, err)
+			if err == nil {
+				m.EXPECT().GetFacets(gomock.Any(), filter).Return(&models.BookFacets{}, nil)
+			}
 		}
 	}
 
@@ -54,11 +71,12 @@ func Test_GetBooks(t *testing.T) {
 		}
 
 		mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
-		mockBookStorage(resBooks, nil)(mockStorageBook)
+		mockBookStorage(&models.BookFilter{}, resBooks, nil)(mockStorageBook)
 
 		w := httptest.NewRecorder()
 		h := &Handler{
 			bookStorage: mockStorageBook,
+			localizer:   newTestLocalizer(t),
 		}
 
 		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
@@ -77,15 +95,107 @@ func Test_GetBooks(t *testing.T) {
 		}
 	})
 
+	t.Run("Publisher", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+		mockBookStorage(&models.BookFilter{Publisher: "penguin"}, []*models.Book{}, nil)(mockStorageBook)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			bookStorage: mockStorageBook,
+			localizer:   newTestLocalizer(t),
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint+"?publisher=penguin", bytes.NewBuffer([]byte{}))
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		h.GetBooks(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("GetBooks() error, got status code = %v, want = %v", res.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Parallel()
+
+		filter := &models.BookFilter{Query: "habits", AuthorID: 1, Category: "self-help", PriceBand: "10-20"}
+		mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+		mockBookStorage(filter, []*models.Book{}, nil)(mockStorageBook)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			bookStorage: mockStorageBook,
+			localizer:   newTestLocalizer(t),
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint+"?q=habits&author=1&category=self-help&price_band=10-20", nil)
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		h.GetBooks(testCtx)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("GetBooks() error, got status code = %v, want = %v", w.Code, http.StatusOK)
+		}
+		if _, ok := getResponseBody(t, w.Body.Bytes())["facets"]; !ok {
+			t.Fatalf("GetBooks() error, got no facets")
+		}
+	})
+
+	t.Run("InvalidFilter", func(t *testing.T) {
+		t.Parallel()
+
+		for query, wantErr := range map[string]error{
+			"?author=abc":       errInvalidAuthorID,
+			"?price_band=cheap": errInvalidPriceBand,
+		} {
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mock_books_storage.NewMockBookStorage(ctrl),
+				localizer:   newTestLocalizer(t),
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint+query, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.GetBooks(testCtx)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("GetBooks() error, got status code = %v, want = %v", w.Code, http.StatusBadRequest)
+			}
+			if diff := cmp.Diff(gin.H{"message": wantErr.Error()}, getResponseBody(t, w.Body.Bytes())); diff != "" {
+				t.Fatalf("GetBooks() mismatch (-want+got):\n%s", diff)
+			}
+		}
+	})
+
 	t.Run("Failed", func(t *testing.T) {
 		t.Parallel()
 
 		mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
-		mockBookStorage(nil, errors.New("failed to execute GetBooks operation"))(mockStorageBook)
+		mockBookStorage(&models.BookFilter{}, nil, errors.New("failed to execute GetBooks operation"))(mockStorageBook)
 
 		w := httptest.NewRecorder()
 		h := &Handler{
 			bookStorage: mockStorageBook,
+			localizer:   newTestLocalizer(t),
 		}
 
 		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
@@ -105,6 +215,942 @@ func Test_GetBooks(t *testing.T) {
 	})
 }
 
+func Test_GetBooks_ConditionalRequest(t *testing.T) {
+	t.Parallel()
+
+	const validETag = `"3-7-1896084000-en"`
+
+	tests := []struct {
+		name             string
+		header           http.Header
+		stateErr         error
+		wantCode         int
+		wantETag         string
+		wantCacheControl string
+	}{
+		{
+			name:             "NoValidators",
+			wantCode:         http.StatusOK,
+			wantETag:         validETag,
+			wantCacheControl: catalogCacheControl,
+		},
+		{
+			name:             "MatchingETag",
+			header:           http.Header{"If-None-Match": {validETag}},
+			wantCode:         http.StatusNotModified,
+			wantETag:         validETag,
+			wantCacheControl: catalogCacheControl,
+		},
+		{
+			name:             "MatchingWeakETagInList",
+			header:           http.Header{"If-None-Match": {`"1-1-1-en", W/` + validETag}},
+			wantCode:         http.StatusNotModified,
+			wantETag:         validETag,
+			wantCacheControl: catalogCacheControl,
+		},
+		{
+			name:             "StaleETag",
+			header:           http.Header{"If-None-Match": {`"2-7-1896084000-en"`}},
+			wantCode:         http.StatusOK,
+			wantETag:         validETag,
+			wantCacheControl: catalogCacheControl,
+		},
+		{
+			// If-Modified-Since is ignored when If-None-Match is given.
+			name: "StaleETagModifiedSince",
+			header: http.Header{
+				"If-None-Match":     {`"2-7-1896084000-en"`},
+				"If-Modified-Since": {"Thu, 31 Jan 2030 10:00:00 GMT"},
+			},
+			wantCode:         http.StatusOK,
+			wantETag:         validETag,
+			wantCacheControl: catalogCacheControl,
+		},
+		{
+			name:             "NotModifiedSince",
+			header:           http.Header{"If-Modified-Since": {"Thu, 31 Jan 2030 10:00:00 GMT"}},
+			wantCode:         http.StatusNotModified,
+			wantETag:         validETag,
+			wantCacheControl: catalogCacheControl,
+		},
+		{
+			name:             "ModifiedSince",
+			header:           http.Header{"If-Modified-Since": {"Thu, 31 Jan 2030 09:59:59 GMT"}},
+			wantCode:         http.StatusOK,
+			wantETag:         validETag,
+			wantCacheControl: catalogCacheControl,
+		},
+		{
+			name:             "CatalogStateFailed",
+			header:           http.Header{"If-None-Match": {validETag}},
+			stateErr:         errors.New("failed to get catalog state"),
+			wantCode:         http.StatusOK,
+			wantCacheControl: "no-store",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+			if tt.stateErr != nil {
+				mockStorageBook.EXPECT().GetCatalogState(gomock.Any()).Return(nil, tt.stateErr)
+			} else {
+				mockStorageBook.EXPECT().GetCatalogState(gomock.Any()).Return(validCatalogState, nil)
+			}
+			if tt.wantCode == http.StatusOK {
+				mockStorageBook.EXPECT().GetBooks(gomock.Any(), gomock.Any()).Return([]*models.Book{}, nil)
+				mockStorageBook.EXPECT().GetFacets(gomock.Any(), gomock.Any()).Return(&models.BookFacets{}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockStorageBook,
+				localizer:   newTestLocalizer(t),
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/books", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+			for k, v := range tt.header {
+				r.Header[k] = v
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.GetBooks(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GetBooks() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Fatalf("GetBooks() ETag error, got = %v, want = %v", got, tt.wantETag)
+			}
+			if got := w.Header().Get("Cache-Control"); got != tt.wantCacheControl {
+				t.Fatalf("GetBooks() Cache-Control error, got = %v, want = %v", got, tt.wantCacheControl)
+			}
+			if tt.wantETag != "" {
+				if got, want := w.Header().Get("Last-Modified"), "Thu, 31 Jan 2030 10:00:00 GMT"; got != want {
+					t.Fatalf("GetBooks() Last-Modified error, got = %v, want = %v", got, want)
+				}
+			}
+			if tt.wantCode == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Fatalf("GetBooks() error, got body = %q, want none", w.Body.String())
+			}
+		})
+	}
+}
+
+func Test_GetBook_Localized(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+	mockStorageBook.EXPECT().GetCatalogState(gomock.Any()).Return(validCatalogState, nil)
+	mockStorageBook.EXPECT().GetBookByID(gomock.Any(), int64(1)).Return(&models.Book{ID: 1, Title: "Atomic Habits", Description: "Habits"}, nil)
+
+	mockTranslationStorage := mock_translation_storage.NewMockTranslationStorage(ctrl)
+	mockTranslationStorage.EXPECT().GetLocales(gomock.Any()).Return([]string{"fr"}, nil)
+	mockTranslationStorage.EXPECT().GetTranslationsByBookIDs(gomock.Any(), "fr", []int64{1}).Return(map[int64]*models.BookTranslation{
+		1: {BookID: 1, Locale: "fr", Title: "Un Petit Rien"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	h := &Handler{
+		bookStorage: mockStorageBook,
+		localizer:   locale.NewLocalizer(mockTranslationStorage, "en"),
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/books/1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when creating http request: %v", err)
+	}
+	r.Header.Set("Accept-Language", "fr-CA, en;q=0.5")
+
+	testCtx, _ := gin.CreateTestContext(w)
+	testCtx.Request = r
+	testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	h.GetBook(testCtx)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetBook() error, got status code = %v, want = %v", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Content-Language"); got != "fr" {
+		t.Fatalf("GetBook() Content-Language error, got = %v, want = %v", got, "fr")
+	}
+	if got, want := w.Header().Get("ETag"), `"3-7-1896084000-fr"`; got != want {
+		t.Fatalf("GetBook() ETag error, got = %v, want = %v", got, want)
+	}
+
+	book := getResponseBody(t, w.Body.Bytes())["book"].(map[string]any)
+	if book["title"] != "Un Petit Rien" || book["description"] != "Habits" {
+		t.Fatalf("GetBook() error, got title = %v, description = %v", book["title"], book["description"])
+	}
+}
+
+func Test_GetChanges(t *testing.T) {
+	t.Parallel()
+
+	changes := func() []*models.BookChange {
+		return []*models.BookChange{
+			{Seq: 11, BookID: 1, Operation: models.BookChangeUpdate},
+			{Seq: 12, BookID: 2, Operation: models.BookChangeUpdate},
+			{Seq: 13, BookID: 1, Operation: models.BookChangeUpdate},
+			{Seq: 14, BookID: 3, Operation: models.BookChangeDelete},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		mock          func(m *mock_books_storage.MockBookStorage)
+		wantCode      int
+		wantErr       gin.H
+		wantSeqs      []float64
+		wantBooks     []any
+		wantNextSince float64
+		wantHasMore   bool
+	}{
+		{
+			name:  "Success",
+			query: "?since=10",
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().GetChanges(gomock.Any(), int64(10), defaultChangesLimit+1).Return(changes(), nil)
+				// book 2 was archived since.
+				m.EXPECT().GetBooksByIDs(gomock.Any(), []int64{1, 2}).Return([]*models.Book{{ID: 1}}, nil)
+			},
+			wantCode:      http.StatusOK,
+			wantSeqs:      []float64{11, 12, 13, 14},
+			wantBooks:     []any{float64(1), nil, float64(1), nil},
+			wantNextSince: 14,
+		},
+		{
+			name:  "HasMore",
+			query: "?since=10&limit=2",
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().GetChanges(gomock.Any(), int64(10), 3).Return(changes()[:3], nil)
+				m.EXPECT().GetBooksByIDs(gomock.Any(), []int64{1, 2}).Return([]*models.Book{{ID: 1}, {ID: 2}}, nil)
+			},
+			wantCode:      http.StatusOK,
+			wantSeqs:      []float64{11, 12},
+			wantBooks:     []any{float64(1), float64(2)},
+			wantNextSince: 12,
+			wantHasMore:   true,
+		},
+		{
+			name:  "NoChanges",
+			query: "?since=14",
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().GetChanges(gomock.Any(), int64(14), defaultChangesLimit+1).Return([]*models.BookChange{}, nil)
+			},
+			wantCode:      http.StatusOK,
+			wantSeqs:      []float64{},
+			wantBooks:     []any{},
+			wantNextSince: 14,
+		},
+		{
+			name:     "InvalidSince",
+			query:    "?since=-1",
+			mock:     func(m *mock_books_storage.MockBookStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidSince.Error()},
+		},
+		{
+			name:     "InvalidLimit",
+			query:    "?limit=1001",
+			mock:     func(m *mock_books_storage.MockBookStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidLimit.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+			tt.mock(mockStorageBook)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockStorageBook,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/books/changes"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.GetChanges(testCtx)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GetChanges() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetChanges() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+
+			seqs, books := []float64{}, []any{}
+			for _, change := range resBody["changes"].([]any) {
+				change := change.(map[string]any)
+				seqs = append(seqs, change["seq"].(float64))
+				if book, ok := change["book"].(map[string]any); ok {
+					books = append(books, book["id"])
+				} else {
+					books = append(books, change["book"])
+				}
+			}
+			if diff := cmp.Diff(tt.wantSeqs, seqs); diff != "" {
+				t.Fatalf("GetChanges() seq mismatch (-want+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBooks, books); diff != "" {
+				t.Fatalf("GetChanges() book mismatch (-want+got):\n%s", diff)
+			}
+			if resBody["next_since"] != tt.wantNextSince || resBody["has_more"] != tt.wantHasMore {
+				t.Fatalf("GetChanges() error, got next_since = %v, has_more = %v, want = %v, %v", resBody["next_since"], resBody["has_more"], tt.wantNextSince, tt.wantHasMore)
+			}
+		})
+	}
+}
+
+func Test_GetBook(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8433/v1/books/1"
+	)
+
+	mockGetBookByID := func(res *models.Book, err error) func(m *mock_books_storage.MockBookStorage) {
+		return func(m *mock_books_storage.MockBookStorage) {
+			m.EXPECT().GetCatalogState(gomock.Any()).Return(validCatalogState, nil)
+			m.
+				EXPECT().
+				GetBookByID(
+					gomock.Any(), // context
+					int64(1),
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		mockBook func(m *mock_books_storage.MockBookStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			mockBook: mockGetBookByID(&models.Book{
+				ID:            1,
+				Title:         genString(),
+				AverageRating: 4.5,
+				ReviewCount:   2,
+			}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "InvalidID",
+			id:       "abc",
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidID.Error(),
+			},
+		},
+		{
+			name:     "BookNotFound",
+			id:       "1",
+			mockBook: mockGetBookByID(nil, sqlite.ErrNotFound),
+			wantCode: http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errBookNotFound.Error(),
+			},
+		},
+		{
+			name:     "GetBookByIDDatabaseOperationFailed",
+			id:       "1",
+			mockBook: mockGetBookByID(nil, errors.New("failed to execute GetBookByID operation")),
+			wantCode: http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+			if tt.mockBook != nil {
+				tt.mockBook(mockStorageBook)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockStorageBook,
+				localizer:   newTestLocalizer(t),
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.GetBook(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetBook() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetBook() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_GetBookByISBN(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8433/v1/books/isbn/0735211299"
+	)
+
+	mockGetBookByISBN := func(res *models.Book, err error) func(m *mock_books_storage.MockBookStorage) {
+		return func(m *mock_books_storage.MockBookStorage) {
+			m.EXPECT().GetCatalogState(gomock.Any()).Return(validCatalogState, nil)
+			m.
+				EXPECT().
+				GetBookByISBN(
+					gomock.Any(), // context
+					"9780735211292",
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		isbn     string
+		header   http.Header
+		mockBook func(m *mock_books_storage.MockBookStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "SuccessISBN10",
+			isbn: "0735211299",
+			mockBook: mockGetBookByISBN(&models.Book{
+				ID:     1,
+				Title:  genString(),
+				ISBN10: "0735211299",
+				ISBN13: "9780735211292",
+			}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "SuccessISBN13",
+			isbn: "978-0-7352-1129-2",
+			mockBook: mockGetBookByISBN(&models.Book{
+				ID:     1,
+				Title:  genString(),
+				ISBN10: "0735211299",
+				ISBN13: "9780735211292",
+			}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			// the book by ISBN is validated like the book by id.
+			name:   "NotModified",
+			isbn:   "0735211299",
+			header: http.Header{"If-None-Match": {`"3-7-1896084000-en"`}},
+			mockBook: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().GetCatalogState(gomock.Any()).Return(validCatalogState, nil)
+			},
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name:     "InvalidChecksum",
+			isbn:     "0735211298",
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": isbn.ErrInvalidChecksum.Error(),
+			},
+		},
+		{
+			name:     "BookNotFound",
+			isbn:     "0735211299",
+			mockBook: mockGetBookByISBN(nil, sqlite.ErrNotFound),
+			wantCode: http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errBookNotFound.Error(),
+			},
+		},
+		{
+			name:     "GetBookByISBNDatabaseOperationFailed",
+			isbn:     "0735211299",
+			mockBook: mockGetBookByISBN(nil, errors.New("failed to execute GetBookByISBN operation")),
+			wantCode: http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+			if tt.mockBook != nil {
+				tt.mockBook(mockStorageBook)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockStorageBook,
+				localizer:   newTestLocalizer(t),
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			for k, v := range tt.header {
+				r.Header[k] = v
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "isbn", Value: tt.isbn}}
+
+			h.GetBookByISBN(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GetBookByISBN() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+			if tt.mockBook != nil {
+				if got, want := w.Header().Get("ETag"), `"3-7-1896084000-en"`; got != want {
+					t.Fatalf("GetBookByISBN() ETag error, got = %v, want = %v", got, want)
+				}
+				if got := w.Header().Get("Cache-Control"); got != catalogCacheControl {
+					t.Fatalf("GetBookByISBN() Cache-Control error, got = %v, want = %v", got, catalogCacheControl)
+				}
+				if got := w.Header().Get("Vary"); got != "Accept-Language" {
+					t.Fatalf("GetBookByISBN() Vary error, got = %v, want = %v", got, "Accept-Language")
+				}
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetBookByISBN() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_ImportBooks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validMethod   = http.MethodPost
+		validEndpoint = "http://localhost:8433/v1/books/import"
+		validCSV      = "id,price\n1,12.00\n"
+	)
+
+	tests := []struct {
+		name     string
+		query    string
+		body     string
+		mock     func(m *mock_books_storage.MockBookStorage)
+		wantCode int
+		wantBody gin.H
+	}{
+		{
+			name: "Success",
+			body: validCSV,
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().
+					UpsertBooks(gomock.Any(), gomock.Any(), false).
+					Return([]*models.BookUpsertResult{{BookID: 1}}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: gin.H{
+				"report": map[string]any{
+					"created":   float64(0),
+					"updated":   float64(1),
+					"dry_run":   false,
+					"committed": true,
+					"errors":    []any{},
+				},
+			},
+		},
+		{
+			name:  "DryRun",
+			query: "?dry_run=true",
+			body:  validCSV,
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().
+					UpsertBooks(gomock.Any(), gomock.Any(), true).
+					Return([]*models.BookUpsertResult{{BookID: 1}}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: gin.H{
+				"report": map[string]any{
+					"created":   float64(0),
+					"updated":   float64(1),
+					"dry_run":   true,
+					"committed": false,
+					"errors":    []any{},
+				},
+			},
+		},
+		{
+			name:     "RowErrors",
+			body:     "id,price\n1,free\n",
+			mock:     func(m *mock_books_storage.MockBookStorage) {},
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: gin.H{
+				"report": map[string]any{
+					"created":   float64(0),
+					"updated":   float64(0),
+					"dry_run":   false,
+					"committed": false,
+					"errors": []any{
+						map[string]any{"line": float64(2), "message": `invalid price "free"`},
+					},
+				},
+			},
+		},
+		{
+			name:     "InvalidHeader",
+			body:     "",
+			mock:     func(m *mock_books_storage.MockBookStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantBody: gin.H{"message": "invalid csv header: missing header"},
+		},
+		{
+			name:     "InvalidDryRun",
+			query:    "?dry_run=maybe",
+			body:     validCSV,
+			mock:     func(m *mock_books_storage.MockBookStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantBody: gin.H{"message": errInvalidDryRun.Error()},
+		},
+		{
+			name: "InternalServerError",
+			body: validCSV,
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().
+					UpsertBooks(gomock.Any(), gomock.Any(), false).
+					Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+			tt.mock(mockStorageBook)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockStorageBook,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint+tt.query, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+			r.Header.Set("Content-Type", "text/csv")
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.ImportBooks(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("ImportBooks() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantBody, resBody); diff != "" {
+				t.Fatalf("ImportBooks() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_ExportBooks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+	mockStorageBook.
+		EXPECT().
+		EachBook(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*models.Book) error) error {
+			return fn(&models.Book{ID: 1, Title: "Atomic Habits", Author: "James Clear", Price: "10.00", Stock: 2})
+		})
+
+	w := httptest.NewRecorder()
+	h := &Handler{
+		bookStorage: mockStorageBook,
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/books/export", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when creating http request: %v", err)
+	}
+
+	testCtx, _ := gin.CreateTestContext(w)
+	testCtx.Request = r
+
+	h.ExportBooks(testCtx)
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("ExportBooks() error, got status code = %v, want = %v", res.StatusCode, http.StatusOK)
+	}
+
+	want := "id,isbn_13,isbn_10,title,author,price,description,stock\n" +
+		"1,,,Atomic Habits,James Clear,10.00,,2\n"
+	if diff := cmp.Diff(want, w.Body.String()); diff != "" {
+		t.Fatalf("ExportBooks() mismatch (-want+got):\n%s", diff)
+	}
+}
+
+func Test_ArchiveBook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       string
+		restore  bool
+		mock     func(m *mock_books_storage.MockBookStorage)
+		wantCode int
+	}{
+		{
+			name: "Archive",
+			id:   "1",
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().Archive(gomock.Any(), int64(1)).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:    "Restore",
+			id:      "1",
+			restore: true,
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().Restore(gomock.Any(), int64(1)).Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "InvalidID",
+			id:       "one",
+			mock:     func(m *mock_books_storage.MockBookStorage) {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "NotFound",
+			id:   "1000",
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().Archive(gomock.Any(), int64(1000)).Return(sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "InternalServerError",
+			id:   "1",
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().Archive(gomock.Any(), int64(1)).Return(errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+			tt.mock(mockStorageBook)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockStorageBook,
+			}
+
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8433/v1/books/"+tt.id+"/archive", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			if tt.restore {
+				h.RestoreBook(testCtx)
+			} else {
+				h.ArchiveBook(testCtx)
+			}
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("ArchiveBook() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_SetReleaseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       string
+		body     string
+		mock     func(m *mock_books_storage.MockBookStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			body: `{"release_date": "2030-01-31"}`,
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().SetReleaseDate(gomock.Any(), int64(1), "2030-01-31").Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name: "Released",
+			id:   "1",
+			body: `{"release_date": ""}`,
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().SetReleaseDate(gomock.Any(), int64(1), "").Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "InvalidReleaseDate",
+			id:       "1",
+			body:     `{"release_date": "2030-02-30"}`,
+			mock:     func(m *mock_books_storage.MockBookStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidReleaseDate.Error()},
+		},
+		{
+			name: "NotFound",
+			id:   "1000",
+			body: `{"release_date": "2030-01-31"}`,
+			mock: func(m *mock_books_storage.MockBookStorage) {
+				m.EXPECT().SetReleaseDate(gomock.Any(), int64(1000), "2030-01-31").Return(sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errBookNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockStorageBook := mock_books_storage.NewMockBookStorage(ctrl)
+			tt.mock(mockStorageBook)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockStorageBook,
+			}
+
+			r, err := http.NewRequest(http.MethodPut, "http://localhost:8433/v1/books/"+tt.id+"/release-date", bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.SetReleaseDate(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("SetReleaseDate() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, getResponseBody(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("SetReleaseDate() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// newTestLocalizer returns a localizer of books written in English, no
+// translations are looked up for requests without a preferred locale.
+func newTestLocalizer(t *testing.T) *locale.Localizer {
+	t.Helper()
+	return locale.NewLocalizer(mock_translation_storage.NewMockTranslationStorage(gomock.NewController(t)), "en")
+}
+
 // getResponseBody unmarshals response body to type gin.H map[string]any.
 func getResponseBody(t testing.TB, data []byte) gin.H {
 	t.Helper()
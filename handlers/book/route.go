@@ -1,9 +1,22 @@
 package book
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
 
-func (h *Handler) AddBookRoutes(rg *gin.RouterGroup) {
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddBookRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
 	r := rg.Group("/books")
 
 	r.GET("/", h.GetBooks)
+	r.GET("/changes", h.GetChanges)
+	r.GET("/:id", h.GetBook)
+	r.GET("/isbn/:isbn", h.GetBookByISBN)
+	r.POST("/import", m.Authenticate(), m.Admin(), h.ImportBooks)
+	r.GET("/export", m.Authenticate(), m.Admin(), h.ExportBooks)
+	r.GET("/archived", m.Authenticate(), m.Admin(), h.GetArchivedBooks)
+	r.POST("/:id/archive", m.Authenticate(), m.Admin(), h.ArchiveBook)
+	r.POST("/:id/restore", m.Authenticate(), m.Admin(), h.RestoreBook)
+	r.PUT("/:id/release-date", m.Authenticate(), m.Admin(), h.SetReleaseDate)
 }
@@ -0,0 +1,263 @@
+package category
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/category"
+)
+
+var (
+	errInternalServer    = errors.New("internal error")
+	errCategoryNotFound  = errors.New("category not found")
+	errNameIsRequired    = errors.New("name is required")
+	errSlugIsRequired    = errors.New("slug is required")
+	errBookIDIsRequired  = errors.New("book id is required")
+	errInvalidBookID     = errors.New("invalid book id")
+	errBookNotInCategory = errors.New("book is not assigned to category")
+)
+
+type Handler struct {
+	categoryStorage category.CategoryStorage
+	bookStorage     book.BookStorage
+}
+
+// NewHandler returns a wrapper for category handler.
+func NewHandler(categoryStorage category.CategoryStorage, bookStorage book.BookStorage) *Handler {
+	return &Handler{
+		categoryStorage: categoryStorage,
+		bookStorage:     bookStorage,
+	}
+}
+
+type CreateCategoryRequest struct {
+	ParentSlug string `json:"parent_slug"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+}
+
+func (r *CreateCategoryRequest) Validate() error {
+	switch "" {
+	case r.Name:
+		return errNameIsRequired
+	case r.Slug:
+		return errSlugIsRequired
+	}
+	return nil
+}
+
+type AssignBookRequest struct {
+	BookID int64 `json:"book_id"`
+}
+
+// GetCategories fetches the category tree.
+func (h *Handler) GetCategories(c *gin.Context) {
+	categories, err := h.categoryStorage.GetCategories(c.Request.Context())
+	if err != nil {
+		log.Printf("failed to get categories: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+	})
+}
+
+// GetCategoryBooks fetches all books in a category, including the books
+// of its descendant categories.
+func (h *Handler) GetCategoryBooks(c *gin.Context) {
+	foundCategory, ok := h.getCategory(c)
+	if !ok {
+		return
+	}
+
+	bookIDs, err := h.categoryStorage.GetBookIDsByCategoryID(c.Request.Context(), foundCategory.ID)
+	if err != nil {
+		log.Printf("failed to get book ids of category %q: %v", foundCategory.Slug, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	books := []*models.Book{}
+	if len(bookIDs) > 0 {
+		books, err = h.bookStorage.GetBooksByIDs(c.Request.Context(), bookIDs)
+		if err != nil {
+			log.Printf("failed to get books by ids: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"category": foundCategory,
+		"books":    books,
+	})
+}
+
+// CreateCategory lets an admin add a new category, optionally under an
+// existing parent category.
+func (h *Handler) CreateCategory(c *gin.Context) {
+	r := &CreateCategoryRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := r.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	newCategory := &models.Category{
+		Name: r.Name,
+		Slug: r.Slug,
+	}
+
+	if r.ParentSlug != "" {
+		parent, err := h.categoryStorage.GetCategoryBySlug(c.Request.Context(), r.ParentSlug)
+		if err != nil {
+			if errors.Is(err, sqlite.ErrNotFound) {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"message": category.ErrParentNotFound.Error(),
+				})
+				return
+			}
+			log.Printf("failed to get parent category: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+		newCategory.ParentID = &parent.ID
+	}
+
+	createdCategory, err := h.categoryStorage.Create(c.Request.Context(), newCategory)
+	if err != nil {
+		if errors.Is(err, category.ErrSlugAlreadyExist) || errors.Is(err, category.ErrParentNotFound) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		log.Printf("failed to create category: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"category": createdCategory,
+	})
+}
+
+// AssignBook lets an admin assign a book to a category.
+func (h *Handler) AssignBook(c *gin.Context) {
+	foundCategory, ok := h.getCategory(c)
+	if !ok {
+		return
+	}
+
+	r := &AssignBookRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.BookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errBookIDIsRequired.Error(),
+		})
+		return
+	}
+
+	if err := h.categoryStorage.AssignBook(c.Request.Context(), foundCategory.ID, r.BookID); err != nil {
+		if errors.Is(err, category.ErrBookIDNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		log.Printf("failed to assign book %d to category %q: %v", r.BookID, foundCategory.Slug, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// UnassignBook lets an admin remove a book from a category.
+func (h *Handler) UnassignBook(c *gin.Context) {
+	foundCategory, ok := h.getCategory(c)
+	if !ok {
+		return
+	}
+
+	bookID, err := strconv.ParseInt(c.Param("book_id"), 10, 64)
+	if err != nil || bookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if err := h.categoryStorage.UnassignBook(c.Request.Context(), foundCategory.ID, bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotInCategory.Error(),
+			})
+			return
+		}
+		log.Printf("failed to unassign book %d from category %q: %v", bookID, foundCategory.Slug, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// getCategory fetches the category referenced by the slug path parameter,
+// aborting the request when it cannot be found.
+func (h *Handler) getCategory(c *gin.Context) (*models.Category, bool) {
+	foundCategory, err := h.categoryStorage.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errCategoryNotFound.Error(),
+			})
+			return nil, false
+		}
+		log.Printf("failed to get category by slug: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return nil, false
+	}
+	return foundCategory, true
+}
@@ -0,0 +1,681 @@
+package category
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/category"
+	mock_storage_category "github.com/wilsonangara/simple-online-book-store/storage/sqlite/category/mock"
+)
+
+func Test_GetCategories(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/categories"
+	)
+
+	mockGetCategories := func(res []*models.Category, err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				GetCategories(
+					gomock.Any(), // context
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		mockCategory func(m *mock_storage_category.MockCategoryStorage)
+		wantCode     int
+	}{
+		{
+			name: "Success",
+			mockCategory: mockGetCategories([]*models.Category{
+				{
+					ID:   1,
+					Name: genString(),
+					Slug: genString(),
+					Children: []*models.Category{
+						{
+							ID:   2,
+							Name: genString(),
+							Slug: genString(),
+						},
+					},
+				},
+			}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:         "GetCategoriesDatabaseOperationFailed",
+			mockCategory: mockGetCategories(nil, errors.New("failed to execute GetCategories operation")),
+			wantCode:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageCategory := mock_storage_category.NewMockCategoryStorage(ctrl)
+			tt.mockCategory(mockStorageCategory)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				categoryStorage: mockStorageCategory,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.GetCategories(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetCategories() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_GetCategoryBooks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/categories/self-help/books"
+
+		validSlug = "self-help"
+	)
+
+	validCategory := &models.Category{
+		ID:   1,
+		Name: genString(),
+		Slug: validSlug,
+	}
+
+	validBook := &models.Book{
+		ID:     1,
+		Title:  genString(),
+		Author: genString(),
+		Price:  "1.10",
+	}
+
+	// mock functions
+	mockGetCategoryBySlug := func(res *models.Category, err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				GetCategoryBySlug(
+					gomock.Any(), // context
+					gomock.Any(), // slug
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetBookIDsByCategoryID := func(res []int64, err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				GetBookIDsByCategoryID(
+					gomock.Any(), // context
+					gomock.Any(), // category id
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetBooksByIDs := func(res []*models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+		return func(m *mock_storage_book.MockBookStorage) {
+			m.
+				EXPECT().
+				GetBooksByIDs(
+					gomock.Any(), // context
+					gomock.Any(), // book IDs
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		mockCategory []func(m *mock_storage_category.MockCategoryStorage)
+		mockBook     func(m *mock_storage_book.MockBookStorage)
+		wantCode     int
+	}{
+		{
+			name: "Success",
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockGetBookIDsByCategoryID([]int64{validBook.ID}, nil),
+			},
+			mockBook: mockGetBooksByIDs([]*models.Book{validBook}, nil),
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "SuccessEmptyCategory",
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockGetBookIDsByCategoryID([]int64{}, nil),
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "CategoryNotFound",
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(nil, sqlite.ErrNotFound),
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "GetBookIDsDatabaseOperationFailed",
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockGetBookIDsByCategoryID(nil, errors.New("failed to execute GetBookIDsByCategoryID operation")),
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageCategory := mock_storage_category.NewMockCategoryStorage(ctrl)
+			for _, mock := range tt.mockCategory {
+				mock(mockStorageCategory)
+			}
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			if tt.mockBook != nil {
+				tt.mockBook(mockStorageBook)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				categoryStorage: mockStorageCategory,
+				bookStorage:     mockStorageBook,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "slug", Value: validSlug}}
+
+			h.GetCategoryBooks(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetCategoryBooks() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_CreateCategory(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodPost
+		validEndpoint = "http://localhost:8443/v1/categories"
+
+		validName = genString()
+		validSlug = genString()
+	)
+
+	mockCreate := func(res *models.Category, err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				Create(
+					gomock.Any(), // context
+					gomock.Any(), // category
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetCategoryBySlug := func(res *models.Category, err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				GetCategoryBySlug(
+					gomock.Any(), // context
+					gomock.Any(), // slug
+				).
+				Return(res, err)
+		}
+	}
+
+	validReq := fmt.Sprintf(`{
+		"name": %q,
+		"slug": %q
+	}`, validName, validSlug)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageCategory := mock_storage_category.NewMockCategoryStorage(ctrl)
+		mockCreate(&models.Category{
+			ID:   1,
+			Name: validName,
+			Slug: validSlug,
+		}, nil)(mockStorageCategory)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			categoryStorage: mockStorageCategory,
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(validReq)))
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		h.CreateCategory(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusCreated {
+			t.Fatalf("CreateCategory() error, got status code = %v, want = %v", res.StatusCode, http.StatusCreated)
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name         string
+			req          string
+			mockCategory func(m *mock_storage_category.MockCategoryStorage)
+			wantErrCode  int
+			wantErr      gin.H
+		}{
+			{
+				name: "NameIsRequired",
+				req: fmt.Sprintf(`{
+					"slug": %q
+				}`, validSlug),
+				wantErrCode: http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": errNameIsRequired.Error(),
+				},
+			},
+			{
+				name: "SlugIsRequired",
+				req: fmt.Sprintf(`{
+					"name": %q
+				}`, validName),
+				wantErrCode: http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": errSlugIsRequired.Error(),
+				},
+			},
+			{
+				name: "ParentNotFound",
+				req: fmt.Sprintf(`{
+					"parent_slug": %q,
+					"name": %q,
+					"slug": %q
+				}`, genString(), validName, validSlug),
+				mockCategory: mockGetCategoryBySlug(nil, sqlite.ErrNotFound),
+				wantErrCode:  http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": category.ErrParentNotFound.Error(),
+				},
+			},
+			{
+				name:         "SlugAlreadyExist",
+				req:          validReq,
+				mockCategory: mockCreate(nil, category.ErrSlugAlreadyExist),
+				wantErrCode:  http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": category.ErrSlugAlreadyExist.Error(),
+				},
+			},
+			{
+				name:         "CreateCategoryDatabaseOperationFailed",
+				req:          validReq,
+				mockCategory: mockCreate(nil, errors.New("failed to execute create category operation")),
+				wantErrCode:  http.StatusInternalServerError,
+				wantErr: gin.H{
+					"message": errInternalServer.Error(),
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				mockStorageCategory := mock_storage_category.NewMockCategoryStorage(ctrl)
+				if tt.mockCategory != nil {
+					tt.mockCategory(mockStorageCategory)
+				}
+
+				w := httptest.NewRecorder()
+				h := &Handler{
+					categoryStorage: mockStorageCategory,
+				}
+
+				r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
+				if err != nil {
+					t.Fatalf("unexpected error when creating http request: %v", err)
+				}
+
+				testCtx, _ := gin.CreateTestContext(w)
+				testCtx.Request = r
+
+				h.CreateCategory(testCtx)
+
+				res := w.Result()
+				if res.StatusCode != tt.wantErrCode {
+					t.Fatalf("CreateCategory() error, got status code = %v, want = %v", res.StatusCode, tt.wantErrCode)
+				}
+
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("CreateCategory() mismatch (-want+got):\n%s", diff)
+				}
+			})
+		}
+	})
+}
+
+func Test_AssignBook(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodPost
+		validEndpoint = "http://localhost:8443/v1/categories/self-help/books"
+
+		validSlug   = "self-help"
+		validBookID = int64(1)
+	)
+
+	validCategory := &models.Category{
+		ID:   1,
+		Name: genString(),
+		Slug: validSlug,
+	}
+
+	mockGetCategoryBySlug := func(res *models.Category, err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				GetCategoryBySlug(
+					gomock.Any(), // context
+					gomock.Any(), // slug
+				).
+				Return(res, err)
+		}
+	}
+
+	mockAssignBook := func(err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				AssignBook(
+					gomock.Any(), // context
+					gomock.Any(), // category id
+					gomock.Any(), // book id
+				).
+				Return(err)
+		}
+	}
+
+	validReq := fmt.Sprintf(`{"book_id": %d}`, validBookID)
+
+	tests := []struct {
+		name         string
+		req          string
+		mockCategory []func(m *mock_storage_category.MockCategoryStorage)
+		wantCode     int
+	}{
+		{
+			name: "Success",
+			req:  validReq,
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockAssignBook(nil),
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "CategoryNotFound",
+			req:  validReq,
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(nil, sqlite.ErrNotFound),
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "BookIDIsRequired",
+			req:  `{}`,
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "BookIDNotFound",
+			req:  validReq,
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockAssignBook(category.ErrBookIDNotFound),
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "AssignBookDatabaseOperationFailed",
+			req:  validReq,
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockAssignBook(errors.New("failed to execute AssignBook operation")),
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageCategory := mock_storage_category.NewMockCategoryStorage(ctrl)
+			for _, mock := range tt.mockCategory {
+				mock(mockStorageCategory)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				categoryStorage: mockStorageCategory,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "slug", Value: validSlug}}
+
+			h.AssignBook(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("AssignBook() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_UnassignBook(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodDelete
+		validEndpoint = "http://localhost:8443/v1/categories/self-help/books/1"
+
+		validSlug   = "self-help"
+		validBookID = "1"
+	)
+
+	validCategory := &models.Category{
+		ID:   1,
+		Name: genString(),
+		Slug: validSlug,
+	}
+
+	mockGetCategoryBySlug := func(res *models.Category, err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				GetCategoryBySlug(
+					gomock.Any(), // context
+					gomock.Any(), // slug
+				).
+				Return(res, err)
+		}
+	}
+
+	mockUnassignBook := func(err error) func(m *mock_storage_category.MockCategoryStorage) {
+		return func(m *mock_storage_category.MockCategoryStorage) {
+			m.
+				EXPECT().
+				UnassignBook(
+					gomock.Any(), // context
+					gomock.Any(), // category id
+					gomock.Any(), // book id
+				).
+				Return(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		bookID       string
+		mockCategory []func(m *mock_storage_category.MockCategoryStorage)
+		wantCode     int
+	}{
+		{
+			name:   "Success",
+			bookID: validBookID,
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockUnassignBook(nil),
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:   "InvalidBookID",
+			bookID: "abc",
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:   "BookNotInCategory",
+			bookID: validBookID,
+			mockCategory: []func(m *mock_storage_category.MockCategoryStorage){
+				mockGetCategoryBySlug(validCategory, nil),
+				mockUnassignBook(sqlite.ErrNotFound),
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageCategory := mock_storage_category.NewMockCategoryStorage(ctrl)
+			for _, mock := range tt.mockCategory {
+				mock(mockStorageCategory)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				categoryStorage: mockStorageCategory,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{
+				{Key: "slug", Value: validSlug},
+				{Key: "book_id", Value: tt.bookID},
+			}
+
+			h.UnassignBook(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("UnassignBook() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
+
+func genString() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,17 @@
+package category
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddCategoryRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/categories")
+
+	r.GET("/", h.GetCategories)
+	r.POST("/", m.Authenticate(), m.Admin(), h.CreateCategory)
+	r.GET("/:slug/books", h.GetCategoryBooks)
+	r.POST("/:slug/books", m.Authenticate(), m.Admin(), h.AssignBook)
+	r.DELETE("/:slug/books/:book_id", m.Authenticate(), m.Admin(), h.UnassignBook)
+}
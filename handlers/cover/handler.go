@@ -0,0 +1,277 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/cover"
+	"github.com/wilsonangara/simple-online-book-store/storage/blob"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+)
+
+// coverCacheControl lets covers be cached for good, a cover is served under
+// the hash of its content so a new cover always has new URLs.
+const coverCacheControl = "public, max-age=31536000, immutable"
+
+var (
+	errInternalServer = errors.New("internal error")
+	errInvalidBookID  = errors.New("invalid book id")
+	errBookNotFound   = errors.New("book not found")
+	errMissingCover   = errors.New("missing cover")
+	errCoverNotFound  = errors.New("cover not found")
+)
+
+type Handler struct {
+	bookStorage book.BookStorage
+	blobStore   blob.Store
+}
+
+// NewHandler returns a wrapper for cover handler.
+func NewHandler(bookStorage book.BookStorage, blobStore blob.Store) *Handler {
+	return &Handler{
+		bookStorage: bookStorage,
+		blobStore:   blobStore,
+	}
+}
+
+// UploadCover sets the cover of a book from the image sent either as the
+// request body or as the "file" of a multipart form, storing the image along
+// with its thumbnails.
+func (h *Handler) UploadCover(c *gin.Context) {
+	bookID, ok := h.getBookID(c)
+	if !ok {
+		return
+	}
+
+	// the book is checked first so no images are stored for a missing book.
+	if _, err := h.bookStorage.GetBookByID(c.Request.Context(), bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get book by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	// leave room for the multipart envelope, the image itself is checked
+	// against the cover limit.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, cover.MaxSize+1<<20)
+
+	var body io.Reader = c.Request.Body
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+					"message": cover.ErrTooLarge.Error(),
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errMissingCover.Error(),
+			})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Printf("failed to open cover file: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, cover.MaxSize+1))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"message": cover.ErrTooLarge.Error(),
+			})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errMissingCover.Error(),
+		})
+		return
+	}
+	if len(data) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errMissingCover.Error(),
+		})
+		return
+	}
+
+	processed, err := cover.Process(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, cover.ErrTooLarge):
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"message": err.Error(),
+			})
+		case errors.Is(err, cover.ErrUnsupportedType):
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+				"message": err.Error(),
+			})
+		case errors.Is(err, cover.ErrInvalidImage):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		default:
+			log.Printf("failed to process cover: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// thumbnails are stored before the original, which is what the book
+	// ends up pointing to.
+	for size, thumbnail := range processed.Thumbnails {
+		if err := h.blobStore.Put(ctx, cover.Key(processed.Hash, size), bytes.NewReader(thumbnail)); err != nil {
+			log.Printf("failed to store cover thumbnail: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+	}
+	if err := h.blobStore.Put(ctx, cover.Key(processed.Hash, cover.Original), bytes.NewReader(processed.Original)); err != nil {
+		log.Printf("failed to store cover: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	if err := h.bookStorage.SetCover(ctx, bookID, processed.Hash); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to set book cover: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cover": models.NewBookCover(processed.Hash),
+	})
+}
+
+// DeleteCover removes the cover of a book. The images are kept since covers
+// are shared by every book uploaded with the same image.
+func (h *Handler) DeleteCover(c *gin.Context) {
+	bookID, ok := h.getBookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.bookStorage.SetCover(c.Request.Context(), bookID, ""); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to remove book cover: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCover serves a cover in the given size.
+func (h *Handler) GetCover(c *gin.Context) {
+	hash, size := c.Param("hash"), c.Param("size")
+	if _, ok := cover.Sizes[size]; !ok && size != cover.Original {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": errCoverNotFound.Error(),
+		})
+		return
+	}
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": errCoverNotFound.Error(),
+		})
+		return
+	}
+
+	etag := strconv.Quote(hash + "-" + size)
+	c.Header("Cache-Control", coverCacheControl)
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	r, err := h.blobStore.Get(c.Request.Context(), cover.Key(hash, size))
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			c.Header("Cache-Control", "no-store")
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errCoverNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get cover: %v", err)
+		c.Header("Cache-Control", "no-store")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		log.Printf("failed to read cover: %v", err)
+		c.Header("Cache-Control", "no-store")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
+
+// getBookID parses the book id from the path, aborting when it is invalid.
+func (h *Handler) getBookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return 0, false
+	}
+	return id, true
+}
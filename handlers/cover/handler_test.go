@@ -0,0 +1,325 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/blob"
+	mock_blob "github.com/wilsonangara/simple-online-book-store/storage/blob/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+)
+
+var validHash = strings.Repeat("ab", 32)
+
+func Test_UploadCover(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validMethod   = http.MethodPut
+		validEndpoint = "http://localhost:8433/v1/books/1/cover"
+	)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 20, 30))); err != nil {
+		t.Fatalf("unexpected error when encoding png: %v", err)
+	}
+	validImage := buf.Bytes()
+
+	tests := []struct {
+		name     string
+		id       string
+		body     []byte
+		mock     func(b *mock_storage_book.MockBookStorage, s *mock_blob.MockStore)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			body: validImage,
+			mock: func(b *mock_storage_book.MockBookStorage, s *mock_blob.MockStore) {
+				b.EXPECT().GetBookByID(gomock.Any(), int64(1)).Return(&models.Book{ID: 1}, nil)
+				// the original and its three thumbnails.
+				s.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(4)
+				b.EXPECT().SetCover(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "InvalidBookID",
+			id:       "one",
+			body:     validImage,
+			mock:     func(b *mock_storage_book.MockBookStorage, s *mock_blob.MockStore) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidBookID.Error()},
+		},
+		{
+			name: "BookNotFound",
+			id:   "1000",
+			body: validImage,
+			mock: func(b *mock_storage_book.MockBookStorage, s *mock_blob.MockStore) {
+				b.EXPECT().GetBookByID(gomock.Any(), int64(1000)).Return(nil, sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errBookNotFound.Error()},
+		},
+		{
+			name: "MissingCover",
+			id:   "1",
+			body: nil,
+			mock: func(b *mock_storage_book.MockBookStorage, s *mock_blob.MockStore) {
+				b.EXPECT().GetBookByID(gomock.Any(), int64(1)).Return(&models.Book{ID: 1}, nil)
+			},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errMissingCover.Error()},
+		},
+		{
+			name: "UnsupportedType",
+			id:   "1",
+			body: []byte("%PDF-1.4 not a cover"),
+			mock: func(b *mock_storage_book.MockBookStorage, s *mock_blob.MockStore) {
+				b.EXPECT().GetBookByID(gomock.Any(), int64(1)).Return(&models.Book{ID: 1}, nil)
+			},
+			wantCode: http.StatusUnsupportedMediaType,
+			wantErr:  gin.H{"message": "cover must be a jpeg, png or gif image"},
+		},
+		{
+			name: "InternalServerError",
+			id:   "1",
+			body: validImage,
+			mock: func(b *mock_storage_book.MockBookStorage, s *mock_blob.MockStore) {
+				b.EXPECT().GetBookByID(gomock.Any(), int64(1)).Return(&models.Book{ID: 1}, nil)
+				s.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("disk full"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantErr:  gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockBookStorage := mock_storage_book.NewMockBookStorage(ctrl)
+			mockBlobStore := mock_blob.NewMockStore(ctrl)
+			tt.mock(mockBookStorage, mockBlobStore)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockBookStorage,
+				blobStore:   mockBlobStore,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+			r.Header.Set("Content-Type", "application/octet-stream")
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.UploadCover(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("UploadCover() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("UploadCover() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+			if _, ok := resBody["cover"]; !ok {
+				t.Fatalf("UploadCover() expected cover in response, got = %v", resBody)
+			}
+		})
+	}
+}
+
+func Test_DeleteCover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "Success",
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "BookNotFound",
+			err:      sqlite.ErrNotFound,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockBookStorage := mock_storage_book.NewMockBookStorage(ctrl)
+			mockBookStorage.EXPECT().SetCover(gomock.Any(), int64(1), "").Return(tt.err)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage: mockBookStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodDelete, "http://localhost:8433/v1/books/1/cover", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			h.DeleteCover(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("DeleteCover() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_GetCover(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("unexpected error when encoding png: %v", err)
+	}
+	validImage := buf.Bytes()
+
+	tests := []struct {
+		name        string
+		hash        string
+		size        string
+		ifNoneMatch string
+		mock        func(s *mock_blob.MockStore)
+		wantCode    int
+	}{
+		{
+			name: "Success",
+			hash: validHash,
+			size: "original",
+			mock: func(s *mock_blob.MockStore) {
+				s.EXPECT().
+					Get(gomock.Any(), "covers/"+validHash+"/original").
+					Return(io.NopCloser(bytes.NewReader(validImage)), nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:        "NotModified",
+			hash:        validHash,
+			size:        "small",
+			ifNoneMatch: `"` + validHash + `-small"`,
+			mock:        func(s *mock_blob.MockStore) {},
+			wantCode:    http.StatusNotModified,
+		},
+		{
+			name:     "InvalidSize",
+			hash:     validHash,
+			size:     "huge",
+			mock:     func(s *mock_blob.MockStore) {},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "InvalidHash",
+			hash:     "../../etc",
+			size:     "small",
+			mock:     func(s *mock_blob.MockStore) {},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "NotFound",
+			hash: validHash,
+			size: "large",
+			mock: func(s *mock_blob.MockStore) {
+				s.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, blob.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockBlobStore := mock_blob.NewMockStore(ctrl)
+			tt.mock(mockBlobStore)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				blobStore: mockBlobStore,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/covers/"+tt.hash+"/"+tt.size, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "hash", Value: tt.hash}, {Key: "size", Value: tt.size}}
+
+			h.GetCover(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GetCover() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK {
+				if got := w.Header().Get("Cache-Control"); got != coverCacheControl {
+					t.Fatalf("GetCover() Cache-Control error, got = %v, want = %v", got, coverCacheControl)
+				}
+				if got := w.Header().Get("Content-Type"); got != "image/png" {
+					t.Fatalf("GetCover() Content-Type error, got = %v, want = %v", got, "image/png")
+				}
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
@@ -0,0 +1,13 @@
+package cover
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddCoverRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	rg.PUT("/books/:id/cover", m.Authenticate(), m.Admin(), h.UploadCover)
+	rg.DELETE("/books/:id/cover", m.Authenticate(), m.Admin(), h.DeleteCover)
+	rg.GET("/covers/:hash/:size", h.GetCover)
+}
@@ -0,0 +1,385 @@
+package edition
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/library"
+	"github.com/wilsonangara/simple-online-book-store/storage/blob"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/edition"
+)
+
+var (
+	errInternalServer   = errors.New("internal error")
+	errInvalidBookID    = errors.New("invalid book id")
+	errInvalidEditionID = errors.New("invalid edition id")
+	errInvalidFormat    = errors.New("format must be one of hardcover, paperback or ebook")
+	errInvalidPrice     = errors.New("price must be a non-negative number")
+	errInvalidStock     = errors.New("stock must not be negative")
+	errBookNotFound     = errors.New("book not found")
+	errEditionNotFound  = errors.New("edition not found")
+	errISBNAlreadyExist = errors.New("isbn already exist")
+	errNotEbook         = errors.New("only ebook editions have files")
+	errMissingFile      = errors.New("missing file")
+	errFileTooLarge     = errors.New("file is too large")
+)
+
+// formats are the formats a book can be sold in.
+var formats = map[string]bool{
+	models.EditionFormatHardcover: true,
+	models.EditionFormatPaperback: true,
+	models.EditionFormatEbook:     true,
+}
+
+type Handler struct {
+	editionStorage edition.EditionStorage
+	blobStore      blob.Store
+}
+
+// NewHandler returns a wrapper for edition handler.
+func NewHandler(editionStorage edition.EditionStorage, blobStore blob.Store) *Handler {
+	return &Handler{
+		editionStorage: editionStorage,
+		blobStore:      blobStore,
+	}
+}
+
+// CreateEditionRequest is a new edition of a book, an edition without a
+// price sells at the price of its book.
+type CreateEditionRequest struct {
+	Format string `json:"format"`
+	ISBN   string `json:"isbn"`
+	Price  string `json:"price"`
+	Stock  int64  `json:"stock"`
+}
+
+// UpdateEditionRequest only changes the fields that are given, an empty
+// price makes the edition sell at the price of its book again.
+type UpdateEditionRequest struct {
+	Format *string `json:"format"`
+	ISBN   *string `json:"isbn"`
+	Price  *string `json:"price"`
+	Stock  *int64  `json:"stock"`
+}
+
+// CreateEdition adds an edition to a book, such as its hardcover or ebook.
+func (h *Handler) CreateEdition(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	r := &CreateEditionRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	update, ok := validate(c, &UpdateEditionRequest{
+		Format: &r.Format,
+		ISBN:   &r.ISBN,
+		Price:  &r.Price,
+		Stock:  &r.Stock,
+	})
+	if !ok {
+		return
+	}
+
+	created, err := h.editionStorage.Create(c.Request.Context(), &models.Edition{
+		BookID: bookID,
+		Format: *update.Format,
+		ISBN13: *update.ISBN13,
+		Price:  *update.Price,
+		Stock:  *update.Stock,
+	})
+	if err != nil {
+		abortWithStorageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"edition": created,
+	})
+}
+
+// UpdateEdition changes the format, ISBN, price or stock of an edition.
+func (h *Handler) UpdateEdition(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	editionID, err := strconv.ParseInt(c.Param("edition_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidEditionID.Error(),
+		})
+		return
+	}
+
+	r := &UpdateEditionRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	update, ok := validate(c, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.editionStorage.Update(c.Request.Context(), bookID, editionID, update)
+	if err != nil {
+		abortWithStorageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"edition": updated,
+	})
+}
+
+// UploadFile sets the file of an ebook edition from the file sent either as
+// the request body, named by the "name" query, or as the "file" of a
+// multipart form. The file is streamed into the blob store as it is read.
+func (h *Handler) UploadFile(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	editionID, err := strconv.ParseInt(c.Param("edition_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidEditionID.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// the edition is checked first so no file is stored for an edition that
+	// cannot have one.
+	editions, err := h.editionStorage.GetEditionsByIDs(ctx, []int64{editionID})
+	if err != nil {
+		log.Printf("failed to get editions by ids: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+	if len(editions) < 1 || editions[0].BookID != bookID {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": errEditionNotFound.Error(),
+		})
+		return
+	}
+	if editions[0].Format != models.EditionFormatEbook {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errNotEbook.Error(),
+		})
+		return
+	}
+
+	// leave room for the multipart envelope, the file itself is checked
+	// against the ebook limit.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, library.MaxEbookSize+1<<20)
+
+	var body io.Reader = c.Request.Body
+	name := c.Query("name")
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			abortWithFileError(c, err)
+			return
+		}
+		defer file.Close()
+		body, name = file, header.Filename
+	}
+	if name = filepath.Base(name); name == "." || name == "/" {
+		name = fmt.Sprintf("ebook-%d", editionID)
+	}
+
+	// an empty file is as good as no file.
+	content := bufio.NewReader(body)
+	if _, err := content.Peek(1); err != nil {
+		abortWithFileError(c, err)
+		return
+	}
+
+	file := &fileReader{r: content, n: library.MaxEbookSize}
+	key := library.EbookKey(editionID)
+	if err := h.blobStore.Put(ctx, key, file); err != nil {
+		if file.err != nil {
+			abortWithFileError(c, file.err)
+			return
+		}
+		log.Printf("failed to store ebook file: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	if err := h.editionStorage.SetFile(ctx, bookID, editionID, key, name); err != nil {
+		switch {
+		case errors.Is(err, sqlite.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errEditionNotFound.Error(),
+			})
+		case errors.Is(err, edition.ErrNotEbook):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errNotEbook.Error(),
+			})
+		default:
+			log.Printf("failed to set edition file: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"file_name": name,
+	})
+}
+
+// fileReader reads an uploaded file, failing once more than n bytes are
+// read so a file is never stored cut short at the limit. It keeps the error
+// the upload failed with apart from the ones of the blob store.
+type fileReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if r.n -= int64(n); r.n < 0 {
+		err = errFileTooLarge
+	}
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// abortWithFileError aborts an upload whose file could not be read.
+func abortWithFileError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || errors.Is(err, errFileTooLarge) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+			"message": errFileTooLarge.Error(),
+		})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+		"message": errMissingFile.Error(),
+	})
+}
+
+// validate checks the given fields of an edition, normalizing its ISBN and
+// price, and aborts the request when any of them is invalid.
+func validate(c *gin.Context, r *UpdateEditionRequest) (*models.EditionUpdate, bool) {
+	update := &models.EditionUpdate{
+		Format: r.Format,
+		Stock:  r.Stock,
+	}
+
+	if r.Format != nil && !formats[*r.Format] {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidFormat.Error(),
+		})
+		return nil, false
+	}
+
+	if r.ISBN != nil {
+		isbn13 := ""
+		if *r.ISBN != "" {
+			normalized, err := isbn.Normalize(*r.ISBN)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"message": fmt.Sprintf("invalid isbn %q: %v", *r.ISBN, err),
+				})
+				return nil, false
+			}
+			isbn13 = normalized
+		}
+		update.ISBN13 = &isbn13
+	}
+
+	if r.Price != nil {
+		price := ""
+		if *r.Price != "" {
+			amount, err := strconv.ParseFloat(*r.Price, 64)
+			if err != nil || amount < 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"message": errInvalidPrice.Error(),
+				})
+				return nil, false
+			}
+			price = strconv.FormatFloat(amount, 'f', 2, 64)
+		}
+		update.Price = &price
+	}
+
+	if r.Stock != nil && *r.Stock < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidStock.Error(),
+		})
+		return nil, false
+	}
+
+	return update, true
+}
+
+// abortWithStorageError aborts the request with the response matching an
+// error of the edition storage.
+func abortWithStorageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, edition.ErrBookIDNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": errBookNotFound.Error(),
+		})
+	case errors.Is(err, sqlite.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": errEditionNotFound.Error(),
+		})
+	case errors.Is(err, edition.ErrISBNAlreadyExist):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"message": errISBNAlreadyExist.Error(),
+		})
+	default:
+		log.Printf("failed to save edition: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+	}
+}
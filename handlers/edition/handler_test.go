@@ -0,0 +1,366 @@
+package edition
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	mock_blob "github.com/wilsonangara/simple-online-book-store/storage/blob/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/edition"
+	mock_storage_edition "github.com/wilsonangara/simple-online-book-store/storage/sqlite/edition/mock"
+)
+
+func Test_CreateEdition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       string
+		body     gin.H
+		mock     func(m *mock_storage_edition.MockEditionStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			body: gin.H{"format": "hardcover", "isbn": "0-306-40615-2", "price": "25", "stock": 3},
+			mock: func(m *mock_storage_edition.MockEditionStorage) {
+				m.EXPECT().
+					Create(gomock.Any(), &models.Edition{
+						BookID: 1,
+						Format: models.EditionFormatHardcover,
+						ISBN13: "9780306406157",
+						Price:  "25.00",
+						Stock:  3,
+					}).
+					Return(&models.Edition{ID: 4, BookID: 1, Format: models.EditionFormatHardcover}, nil)
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "InvalidBookID",
+			id:       "one",
+			body:     gin.H{"format": "ebook"},
+			mock:     func(m *mock_storage_edition.MockEditionStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidBookID.Error()},
+		},
+		{
+			name:     "InvalidFormat",
+			id:       "1",
+			body:     gin.H{"format": "audiobook"},
+			mock:     func(m *mock_storage_edition.MockEditionStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidFormat.Error()},
+		},
+		{
+			name:     "InvalidPrice",
+			id:       "1",
+			body:     gin.H{"format": "ebook", "price": "-1"},
+			mock:     func(m *mock_storage_edition.MockEditionStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidPrice.Error()},
+		},
+		{
+			name:     "InvalidStock",
+			id:       "1",
+			body:     gin.H{"format": "paperback", "stock": -1},
+			mock:     func(m *mock_storage_edition.MockEditionStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidStock.Error()},
+		},
+		{
+			name: "BookNotFound",
+			id:   "1000",
+			body: gin.H{"format": "ebook"},
+			mock: func(m *mock_storage_edition.MockEditionStorage) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, edition.ErrBookIDNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errBookNotFound.Error()},
+		},
+		{
+			name: "ISBNAlreadyExist",
+			id:   "1",
+			body: gin.H{"format": "hardcover", "isbn": "9780306406157"},
+			mock: func(m *mock_storage_edition.MockEditionStorage) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, edition.ErrISBNAlreadyExist)
+			},
+			wantCode: http.StatusConflict,
+			wantErr:  gin.H{"message": errISBNAlreadyExist.Error()},
+		},
+		{
+			name: "InternalServerError",
+			id:   "1",
+			body: gin.H{"format": "ebook"},
+			mock: func(m *mock_storage_edition.MockEditionStorage) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantErr:  gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockEditionStorage := mock_storage_edition.NewMockEditionStorage(ctrl)
+			tt.mock(mockEditionStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				editionStorage: mockEditionStorage,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8433/v1/books/"+tt.id+"/editions", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.CreateEdition(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("CreateEdition() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("CreateEdition() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_UpdateEdition(t *testing.T) {
+	t.Parallel()
+
+	empty, stock := "", int64(7)
+
+	tests := []struct {
+		name      string
+		editionID string
+		body      gin.H
+		mock      func(m *mock_storage_edition.MockEditionStorage)
+		wantCode  int
+	}{
+		{
+			name:      "Success",
+			editionID: "4",
+			body:      gin.H{"price": "", "stock": 7},
+			mock: func(m *mock_storage_edition.MockEditionStorage) {
+				// an empty price makes the edition follow its book again.
+				m.EXPECT().
+					Update(gomock.Any(), int64(1), int64(4), &models.EditionUpdate{Price: &empty, Stock: &stock}).
+					Return(&models.Edition{ID: 4, BookID: 1}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "InvalidEditionID",
+			editionID: "four",
+			body:      gin.H{"stock": 7},
+			mock:      func(m *mock_storage_edition.MockEditionStorage) {},
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "NotFound",
+			editionID: "4",
+			body:      gin.H{"stock": 7},
+			mock: func(m *mock_storage_edition.MockEditionStorage) {
+				m.EXPECT().Update(gomock.Any(), int64(1), int64(4), gomock.Any()).Return(nil, sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockEditionStorage := mock_storage_edition.NewMockEditionStorage(ctrl)
+			tt.mock(mockEditionStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				editionStorage: mockEditionStorage,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+			r, err := http.NewRequest(http.MethodPatch, "http://localhost:8433/v1/books/1/editions/"+tt.editionID, bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "edition_id", Value: tt.editionID}}
+
+			h.UpdateEdition(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("UpdateEdition() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_UploadFile(t *testing.T) {
+	t.Parallel()
+
+	ebook := &models.Edition{ID: 4, BookID: 1, Format: models.EditionFormatEbook}
+	paperback := &models.Edition{ID: 1, BookID: 1, Format: models.EditionFormatPaperback}
+
+	tests := []struct {
+		name      string
+		editionID string
+		query     string
+		body      string
+		mock      func(e *mock_storage_edition.MockEditionStorage, b *mock_blob.MockStore)
+		wantCode  int
+		wantBody  gin.H
+	}{
+		{
+			name:      "Success",
+			editionID: "4",
+			query:     "?name=atomic-habits.epub",
+			body:      "epub content",
+			mock: func(e *mock_storage_edition.MockEditionStorage, b *mock_blob.MockStore) {
+				e.EXPECT().GetEditionsByIDs(gomock.Any(), []int64{4}).Return([]*models.Edition{ebook}, nil)
+				b.EXPECT().
+					Put(gomock.Any(), "ebooks/4", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, r io.Reader) error {
+						data, err := io.ReadAll(r)
+						if err != nil || string(data) != "epub content" {
+							t.Errorf("Put(_, _, _) got content = %q, err = %v", data, err)
+						}
+						return nil
+					})
+				e.EXPECT().SetFile(gomock.Any(), int64(1), int64(4), "ebooks/4", "atomic-habits.epub").Return(nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: gin.H{"file_name": "atomic-habits.epub"},
+		},
+		{
+			name:      "NotEbook",
+			editionID: "1",
+			body:      "epub content",
+			mock: func(e *mock_storage_edition.MockEditionStorage, b *mock_blob.MockStore) {
+				e.EXPECT().GetEditionsByIDs(gomock.Any(), []int64{1}).Return([]*models.Edition{paperback}, nil)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: gin.H{"message": errNotEbook.Error()},
+		},
+		{
+			name:      "EditionNotFound",
+			editionID: "5",
+			body:      "epub content",
+			mock: func(e *mock_storage_edition.MockEditionStorage, b *mock_blob.MockStore) {
+				e.EXPECT().GetEditionsByIDs(gomock.Any(), []int64{5}).Return([]*models.Edition{}, nil)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: gin.H{"message": errEditionNotFound.Error()},
+		},
+		{
+			name:      "MissingFile",
+			editionID: "4",
+			mock: func(e *mock_storage_edition.MockEditionStorage, b *mock_blob.MockStore) {
+				e.EXPECT().GetEditionsByIDs(gomock.Any(), []int64{4}).Return([]*models.Edition{ebook}, nil)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: gin.H{"message": errMissingFile.Error()},
+		},
+		{
+			name:      "InternalServerError",
+			editionID: "4",
+			body:      "epub content",
+			mock: func(e *mock_storage_edition.MockEditionStorage, b *mock_blob.MockStore) {
+				e.EXPECT().GetEditionsByIDs(gomock.Any(), []int64{4}).Return([]*models.Edition{ebook}, nil)
+				b.EXPECT().Put(gomock.Any(), "ebooks/4", gomock.Any()).Return(errors.New("disk full"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockEditionStorage := mock_storage_edition.NewMockEditionStorage(ctrl)
+			mockBlobStore := mock_blob.NewMockStore(ctrl)
+			tt.mock(mockEditionStorage, mockBlobStore)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				editionStorage: mockEditionStorage,
+				blobStore:      mockBlobStore,
+			}
+
+			r, err := http.NewRequest(http.MethodPut, "http://localhost:8433/v1/books/1/editions/"+tt.editionID+"/file"+tt.query, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "edition_id", Value: tt.editionID}}
+
+			h.UploadFile(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("UploadFile() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantBody, resBody); diff != "" {
+				t.Fatalf("UploadFile() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
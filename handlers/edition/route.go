@@ -0,0 +1,15 @@
+package edition
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddEditionRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/books/:id/editions", m.Authenticate(), m.Admin())
+
+	r.POST("/", h.CreateEdition)
+	r.PATCH("/:edition_id", h.UpdateEdition)
+	r.PUT("/:edition_id/file", h.UploadFile)
+}
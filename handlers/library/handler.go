@@ -0,0 +1,234 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/library"
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+	"github.com/wilsonangara/simple-online-book-store/storage/blob"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/entitlement"
+)
+
+// downloadURLPrefix is where the ebooks of entitlements are downloaded from.
+const downloadURLPrefix = "/v1/library/downloads/"
+
+var (
+	errInternalServer        = errors.New("internal error")
+	errInvalidEntitlementID  = errors.New("invalid entitlement id")
+	errEntitlementNotFound   = errors.New("ebook not found in library")
+	errFileNotAvailable      = errors.New("ebook file is not available yet")
+	errDownloadLimitReached  = errors.New("download limit reached, try again later")
+	errInvalidDownloadLink   = errors.New("invalid download link")
+	errDownloadLinkIsExpired = errors.New("download link expired")
+)
+
+type Handler struct {
+	entitlementStorage entitlement.EntitlementStorage
+	blobStore          blob.Store
+	signer             *library.Signer
+
+	// downloadLimit is how many times an entitlement can be downloaded within
+	// downloadWindow.
+	downloadLimit  int64
+	downloadWindow time.Duration
+}
+
+// NewHandler returns a wrapper for library handler.
+func NewHandler(
+	entitlementStorage entitlement.EntitlementStorage,
+	blobStore blob.Store,
+	signer *library.Signer,
+	downloadLimit int64,
+	downloadWindow time.Duration,
+) *Handler {
+	return &Handler{
+		entitlementStorage: entitlementStorage,
+		blobStore:          blobStore,
+		signer:             signer,
+		downloadLimit:      downloadLimit,
+		downloadWindow:     downloadWindow,
+	}
+}
+
+// GetLibrary fetches all the ebooks a user bought.
+func (h *Handler) GetLibrary(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	entitlements, err := h.entitlementStorage.GetEntitlements(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get library of user %d: %v", userID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"library": entitlements,
+	})
+}
+
+// GetDownloadLink returns a signed link to download an ebook of the library
+// of a user, the link can be followed without authentication until it
+// expires.
+func (h *Handler) GetDownloadLink(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidEntitlementID.Error(),
+		})
+		return
+	}
+
+	got, err := h.entitlementStorage.GetEntitlement(c.Request.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errEntitlementNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get entitlement: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+	if !got.Downloadable {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": errFileNotAvailable.Error(),
+		})
+		return
+	}
+
+	signature, expires := h.signer.Sign(got.ID, time.Now())
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":        fmt.Sprintf("%s%d?expires=%d&signature=%s", downloadURLPrefix, got.ID, expires, signature),
+		"expires_at": time.Unix(expires, 0).UTC(),
+	})
+}
+
+// Download serves the ebook of an entitlement through a signed link. Every
+// download is counted, an entitlement downloaded too often within the
+// download window is refused until older downloads fall out of it.
+func (h *Handler) Download(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"message": errInvalidDownloadLink.Error(),
+		})
+		return
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"message": errInvalidDownloadLink.Error(),
+		})
+		return
+	}
+
+	if err := h.signer.Verify(id, expires, c.Query("signature"), time.Now()); err != nil {
+		if errors.Is(err, library.ErrLinkExpired) {
+			c.AbortWithStatusJSON(http.StatusGone, gin.H{
+				"message": errDownloadLinkIsExpired.Error(),
+			})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"message": errInvalidDownloadLink.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	got, err := h.entitlementStorage.GetEntitlementByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errEntitlementNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get entitlement: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+	if !got.Downloadable {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": errFileNotAvailable.Error(),
+		})
+		return
+	}
+
+	// the file is opened before the download is counted so a missing file
+	// never uses up a download.
+	r, err := h.blobStore.Get(ctx, got.FileKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errFileNotAvailable.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get ebook file: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+	defer r.Close()
+
+	if err := h.entitlementStorage.RecordDownload(ctx, got.ID, h.downloadLimit, h.downloadWindow); err != nil {
+		if errors.Is(err, entitlement.ErrDownloadLimitReached) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"message": errDownloadLimitReached.Error(),
+			})
+			return
+		}
+		log.Printf("failed to record download: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(got.FileName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.DataFromReader(http.StatusOK, -1, contentType, r, map[string]string{
+		"Cache-Control":       "private, no-store",
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": got.FileName}),
+	})
+}
@@ -0,0 +1,306 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/library"
+	"github.com/wilsonangara/simple-online-book-store/storage/blob"
+	mock_blob "github.com/wilsonangara/simple-online-book-store/storage/blob/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/entitlement"
+	mock_storage_entitlement "github.com/wilsonangara/simple-online-book-store/storage/sqlite/entitlement/mock"
+)
+
+var (
+	validUser = &models.User{ID: 1}
+
+	validEntitlement = &models.Entitlement{
+		ID:           7,
+		UserID:       1,
+		EditionID:    4,
+		BookID:       1,
+		FileKey:      "ebooks/4",
+		FileName:     "atomic-habits.epub",
+		Downloadable: true,
+	}
+)
+
+func newTestSigner(t testing.TB) *library.Signer {
+	t.Helper()
+
+	signer, err := library.NewSigner("secret", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error when creating signer: %v", err)
+	}
+	return signer
+}
+
+func Test_GetDownloadLink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       string
+		mock     func(m *mock_storage_entitlement.MockEntitlementStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			id:   "7",
+			mock: func(m *mock_storage_entitlement.MockEntitlementStorage) {
+				m.EXPECT().GetEntitlement(gomock.Any(), validUser.ID, int64(7)).Return(validEntitlement, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "InvalidID",
+			id:       "seven",
+			mock:     func(m *mock_storage_entitlement.MockEntitlementStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidEntitlementID.Error()},
+		},
+		{
+			name: "NotFound",
+			id:   "7",
+			mock: func(m *mock_storage_entitlement.MockEntitlementStorage) {
+				m.EXPECT().GetEntitlement(gomock.Any(), validUser.ID, int64(7)).Return(nil, sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errEntitlementNotFound.Error()},
+		},
+		{
+			name: "FileNotAvailable",
+			id:   "7",
+			mock: func(m *mock_storage_entitlement.MockEntitlementStorage) {
+				m.EXPECT().GetEntitlement(gomock.Any(), validUser.ID, int64(7)).Return(&models.Entitlement{ID: 7}, nil)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errFileNotAvailable.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockEntitlementStorage := mock_storage_entitlement.NewMockEntitlementStorage(ctrl)
+			tt.mock(mockEntitlementStorage)
+
+			signer := newTestSigner(t)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				entitlementStorage: mockEntitlementStorage,
+				signer:             signer,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/users/me/library/"+tt.id+"/download", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+			testCtx.Set("user", validUser)
+
+			h.GetDownloadLink(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetDownloadLink() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetDownloadLink() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+
+			// the link returned must be one the signer accepts.
+			link, err := url.Parse(fmt.Sprint(resBody["url"]))
+			if err != nil {
+				t.Fatalf("GetDownloadLink() returned invalid url %q: %v", resBody["url"], err)
+			}
+			if link.Path != downloadURLPrefix+"7" {
+				t.Fatalf("GetDownloadLink() error, got path = %v, want = %v", link.Path, downloadURLPrefix+"7")
+			}
+			expires, err := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+			if err != nil {
+				t.Fatalf("GetDownloadLink() returned invalid expiry %q: %v", link.Query().Get("expires"), err)
+			}
+			if err := signer.Verify(7, expires, link.Query().Get("signature"), time.Now()); err != nil {
+				t.Fatalf("GetDownloadLink() returned a link that does not verify: %v", err)
+			}
+		})
+	}
+}
+
+func Test_Download(t *testing.T) {
+	t.Parallel()
+
+	signer := newTestSigner(t)
+	signature, expires := signer.Sign(7, time.Now())
+	validQuery := fmt.Sprintf("?expires=%d&signature=%s", expires, signature)
+	expiredSignature, expired := signer.Sign(7, time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name     string
+		id       string
+		query    string
+		mock     func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name:  "Success",
+			id:    "7",
+			query: validQuery,
+			mock: func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore) {
+				m.EXPECT().GetEntitlementByID(gomock.Any(), int64(7)).Return(validEntitlement, nil)
+				b.EXPECT().Get(gomock.Any(), "ebooks/4").Return(io.NopCloser(strings.NewReader("epub content")), nil)
+				m.EXPECT().RecordDownload(gomock.Any(), int64(7), int64(5), time.Hour).Return(nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			// a link signed for one entitlement cannot download another.
+			name:     "InvalidSignature",
+			id:       "8",
+			query:    validQuery,
+			mock:     func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore) {},
+			wantCode: http.StatusForbidden,
+			wantErr:  gin.H{"message": errInvalidDownloadLink.Error()},
+		},
+		{
+			name:     "MissingSignature",
+			id:       "7",
+			mock:     func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore) {},
+			wantCode: http.StatusForbidden,
+			wantErr:  gin.H{"message": errInvalidDownloadLink.Error()},
+		},
+		{
+			name:     "Expired",
+			id:       "7",
+			query:    fmt.Sprintf("?expires=%d&signature=%s", expired, expiredSignature),
+			mock:     func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore) {},
+			wantCode: http.StatusGone,
+			wantErr:  gin.H{"message": errDownloadLinkIsExpired.Error()},
+		},
+		{
+			name:  "FileNotFound",
+			id:    "7",
+			query: validQuery,
+			mock: func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore) {
+				m.EXPECT().GetEntitlementByID(gomock.Any(), int64(7)).Return(validEntitlement, nil)
+				b.EXPECT().Get(gomock.Any(), "ebooks/4").Return(nil, blob.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errFileNotAvailable.Error()},
+		},
+		{
+			name:  "DownloadLimitReached",
+			id:    "7",
+			query: validQuery,
+			mock: func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore) {
+				m.EXPECT().GetEntitlementByID(gomock.Any(), int64(7)).Return(validEntitlement, nil)
+				b.EXPECT().Get(gomock.Any(), "ebooks/4").Return(io.NopCloser(strings.NewReader("epub content")), nil)
+				m.EXPECT().RecordDownload(gomock.Any(), int64(7), int64(5), time.Hour).Return(entitlement.ErrDownloadLimitReached)
+			},
+			wantCode: http.StatusTooManyRequests,
+			wantErr:  gin.H{"message": errDownloadLimitReached.Error()},
+		},
+		{
+			name:  "InternalServerError",
+			id:    "7",
+			query: validQuery,
+			mock: func(m *mock_storage_entitlement.MockEntitlementStorage, b *mock_blob.MockStore) {
+				m.EXPECT().GetEntitlementByID(gomock.Any(), int64(7)).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantErr:  gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockEntitlementStorage := mock_storage_entitlement.NewMockEntitlementStorage(ctrl)
+			mockBlobStore := mock_blob.NewMockStore(ctrl)
+			tt.mock(mockEntitlementStorage, mockBlobStore)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				entitlementStorage: mockEntitlementStorage,
+				blobStore:          mockBlobStore,
+				signer:             signer,
+				downloadLimit:      5,
+				downloadWindow:     time.Hour,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/library/downloads/"+tt.id+tt.query, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.Download(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("Download() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("Download() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+
+			if got := w.Body.String(); got != "epub content" {
+				t.Fatalf("Download() error, got body = %q, want = %q", got, "epub content")
+			}
+			if got, want := res.Header.Get("Content-Disposition"), `attachment; filename=atomic-habits.epub`; got != want {
+				t.Fatalf("Download() error, got Content-Disposition = %q, want = %q", got, want)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
@@ -0,0 +1,17 @@
+package library
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddLibraryRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/users/me/library", m.Authenticate())
+
+	r.GET("/", h.GetLibrary)
+	r.GET("/:id/download", h.GetDownloadLink)
+
+	// download links are signed, following one needs no token.
+	rg.GET("/library/downloads/:id", h.Download)
+}
@@ -7,13 +7,19 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/locale"
+	"github.com/wilsonangara/simple-online-book-store/middleware"
 	"github.com/wilsonangara/simple-online-book-store/storage/models"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/edition"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/order"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/reservation"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/user"
 )
 
@@ -21,60 +27,166 @@ var (
 	errInternalServer           = errors.New("internal error")
 	errAtLeastOneBookIsRequired = errors.New("at least 1 book is required")
 	errInvalidQuantity          = errors.New("invalid quantity")
+	errInvalidReservationID     = errors.New("invalid reservation id")
 )
 
 type Handler struct {
-	orderStorage order.OrderStorage
-	bookStorage  book.BookStorage
-	userStorage  user.UserStorage
+	orderStorage       order.OrderStorage
+	bookStorage        book.BookStorage
+	editionStorage     edition.EditionStorage
+	userStorage        user.UserStorage
+	reservationStorage reservation.ReservationStorage
+	localizer          *locale.Localizer
+
+	// reservationTTL is how long a reservation holds the stock of its books.
+	reservationTTL time.Duration
 }
 
 // NewHandler returns a wrapper for order handler.
-func NewHandler(orderStorage order.OrderStorage, bookStorage book.BookStorage, userStorage user.UserStorage) *Handler {
+func NewHandler(
+	orderStorage order.OrderStorage,
+	bookStorage book.BookStorage,
+	editionStorage edition.EditionStorage,
+	userStorage user.UserStorage,
+	reservationStorage reservation.ReservationStorage,
+	localizer *locale.Localizer,
+	reservationTTL time.Duration,
+) *Handler {
 	return &Handler{
-		orderStorage: orderStorage,
-		bookStorage:  bookStorage,
-		userStorage:  userStorage,
+		orderStorage:       orderStorage,
+		bookStorage:        bookStorage,
+		editionStorage:     editionStorage,
+		userStorage:        userStorage,
+		reservationStorage: reservationStorage,
+		localizer:          localizer,
+		reservationTTL:     reservationTTL,
 	}
 }
 
+// BookRequest references the edition of a book to order either by its id or
+// by its ISBN-10 or ISBN-13, or a book by its id to order its default
+// edition. The edition id takes precedence, then the book id.
 type BookRequest struct {
-	BookID   int64 `json:"book_id"`
-	Quantity int64 `json:"quantity"`
+	EditionID int64  `json:"edition_id"`
+	BookID    int64  `json:"book_id"`
+	ISBN      string `json:"isbn"`
+	Quantity  int64  `json:"quantity"`
 }
 
+// OrderRequest lists the books to order, optionally placed with a
+// reservation holding them.
 type OrderRequest struct {
+	Books         []*BookRequest `json:"books"`
+	ReservationID int64          `json:"reservation_id"`
+}
+
+type ReservationRequest struct {
 	Books []*BookRequest `json:"books"`
 }
 
-// Order lets a user purchase books from our online store.
+// Order lets a user purchase books from our online store. An order placed
+// with a reservation commits the stock held by it, the reservation must hold
+// exactly the ordered books that are released. Books not released yet are
+// preordered.
 func (h *Handler) Order(c *gin.Context) {
-	userID, err := getUserIDFromContext(c)
-	if err != nil {
-		log.Printf("failed to get user id from context: %v", err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"message": errInternalServer.Error(),
+	userID, ok := h.getUserID(c)
+	if !ok {
+		return
+	}
+
+	r := &OrderRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
 		})
 		return
 	}
 
-	// check if user exists
-	_, err = h.userStorage.GetUserByID(c.Request.Context(), userID)
-	if err != nil {
-		if errors.Is(err, sqlite.ErrNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
-				"message": err.Error(),
+	newOrder, ok := h.PlaceOrder(c, userID, r)
+	if !ok {
+		return
+	}
+
+	// books not released yet are preordered, they ship once released.
+	items := []gin.H{}
+	for _, item := range newOrder.Items {
+		items = append(items, gin.H{
+			"book_id":            item.BookID,
+			"edition_id":         item.EditionID,
+			"quantity":           item.Quantity,
+			"fulfillment_status": item.FulfillmentStatus,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": newOrder.ID,
+		"total":    newOrder.Total,
+		"items":    items,
+	})
+}
+
+// PlaceOrder places an order of a user for the requested books, aborting the
+// request with the reason when the order cannot be placed.
+func (h *Handler) PlaceOrder(c *gin.Context, userID int64, r *OrderRequest) (*models.Order, bool) {
+	editions, ok := h.getRequestedEditions(c, r.Books)
+	if !ok {
+		return nil, false
+	}
+
+	orderItems := []*models.OrderItem{}
+	totalPrice := float64(0)
+	for _, book := range r.Books {
+		edition := editions[book.EditionID]
+
+		float64Price, err := strconv.ParseFloat(edition.Price, 64)
+		if err != nil {
+			log.Printf("failed to convert price to flaot64: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
 			})
-			return
+			return nil, false
 		}
-		log.Printf("failed to get user by id: %v", err)
+		totalPrice = totalPrice + (float64Price * float64(book.Quantity))
+
+		orderItems = append(orderItems, &models.OrderItem{
+			BookID:    edition.BookID,
+			EditionID: edition.ID,
+			Price:     edition.Price,
+			Quantity:  book.Quantity,
+		})
+	}
+
+	newOrder := &models.Order{
+		UserID:        userID,
+		ReservationID: r.ReservationID,
+		Total:         fmt.Sprintf("%.2f", totalPrice),
+		Items:         orderItems,
+	}
+
+	if err := h.orderStorage.Create(c.Request.Context(), newOrder, orderItems); err != nil {
+		if abortWithReservationError(c, err) {
+			return nil, false
+		}
+		log.Printf("failed to create order(s): %v", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"messsge": errInternalServer.Error(),
+			"message": errInternalServer.Error(),
 		})
+		return nil, false
+	}
+
+	return newOrder, true
+}
+
+// Reserve lets a user hold the stock of books for a limited time while they
+// check out.
+func (h *Handler) Reserve(c *gin.Context) {
+	userID, ok := h.getUserID(c)
+	if !ok {
 		return
 	}
 
-	r := &OrderRequest{}
+	r := &ReservationRequest{}
 	if err := c.BindJSON(r); err != nil {
 		log.Printf("failed to bind json: %v", err)
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
@@ -83,83 +195,58 @@ func (h *Handler) Order(c *gin.Context) {
 		return
 	}
 
-	if len(r.Books) < 1 {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"message": errAtLeastOneBookIsRequired.Error(),
-		})
+	editions, ok := h.getRequestedEditions(c, r.Books)
+	if !ok {
 		return
 	}
 
-	bookIDs := []int64{}
+	items := []*models.ReservationItem{}
 	for _, book := range r.Books {
-		bookIDs = append(bookIDs, book.BookID)
-		if book.Quantity < 1 {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"message": errInvalidQuantity.Error(),
-			})
-			return
-		}
+		items = append(items, &models.ReservationItem{
+			BookID:    editions[book.EditionID].BookID,
+			EditionID: book.EditionID,
+			Quantity:  book.Quantity,
+		})
 	}
 
-	books, err := h.bookStorage.GetBooksByIDs(c.Request.Context(), bookIDs)
+	newReservation, err := h.reservationStorage.Reserve(c.Request.Context(), userID, items, h.reservationTTL)
 	if err != nil {
-		log.Printf("failed to check books by ids: %v", err)
+		if abortWithReservationError(c, err) {
+			return
+		}
+		log.Printf("failed to reserve books: %v", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"mesasge": errInternalServer.Error(),
+			"message": errInternalServer.Error(),
 		})
 		return
 	}
 
-	// check if all book ids given exist in our storage.
-	notFoundIDs := []string{}
-	orderItems := []*models.OrderItem{}
-	totalPrice := float64(0)
-	for _, book := range r.Books {
-		found := false
-		bookPrice := ""
-		for _, b := range books {
-			if b.ID == book.BookID {
-				bookPrice = b.Price
-				found = true
-				break
-			}
-		}
-		if !found {
-			notFoundIDs = append(notFoundIDs, strconv.FormatInt(book.BookID, 10))
-			continue
-		}
-		if len(notFoundIDs) < 1 {
-			float64Price, err := strconv.ParseFloat(bookPrice, 64)
-			if err != nil {
-				log.Printf("failed to convert price to flaot64: %v", err)
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"message": errInternalServer.Error(),
-				})
-				return
-			}
-			totalPrice = totalPrice + (float64Price * float64(book.Quantity))
+	c.JSON(http.StatusCreated, gin.H{
+		"reservation": newReservation,
+	})
+}
 
-			orderItems = append(orderItems, &models.OrderItem{
-				BookID:   book.BookID,
-				Price:    bookPrice,
-				Quantity: book.Quantity,
-			})
-		}
+// ReleaseReservation lets a user give the stock held by their reservation
+// back before it expires.
+func (h *Handler) ReleaseReservation(c *gin.Context) {
+	userID, ok := h.getUserID(c)
+	if !ok {
+		return
 	}
-	if len(notFoundIDs) > 0 {
+
+	reservationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"message": fmt.Sprintf("books with ids: [%s] not found", strings.Join(notFoundIDs, ", ")),
+			"message": errInvalidReservationID.Error(),
 		})
 		return
 	}
 
-	order := &models.Order{
-		UserID: userID,
-		Total:  fmt.Sprintf("%.2f", totalPrice),
-	}
-
-	if err := h.orderStorage.Create(c.Request.Context(), order, orderItems); err != nil {
-		log.Printf("failed to create order(s): %v", err)
+	if err := h.reservationStorage.Release(c.Request.Context(), userID, reservationID); err != nil {
+		if abortWithReservationError(c, err) {
+			return
+		}
+		log.Printf("failed to release reservation: %v", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"message": errInternalServer.Error(),
 		})
@@ -169,60 +256,255 @@ func (h *Handler) Order(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-// GetOrderHistory lets a user to fetch all of their order histories.
+// GetOrderHistory lets a user to fetch all of their order histories, with
+// the books in the locale of the request.
 func (h *Handler) GetOrderHistory(c *gin.Context) {
-	userID, err := getUserIDFromContext(c)
+	userID, ok := h.getUserID(c)
+	if !ok {
+		return
+	}
+
+	lang, err := h.localizer.Negotiate(c.Request.Context(), c.Request)
 	if err != nil {
-		log.Printf("failed to bind json: %v", err)
+		log.Printf("failed to negotiate locale: %v", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"message": errInternalServer.Error(),
 		})
 		return
 	}
 
-	// check if user exists
-	_, err = h.userStorage.GetUserByID(c.Request.Context(), userID)
+	orders, err := h.orderStorage.GetOrderHistory(c.Request.Context(), userID)
 	if err != nil {
-		if errors.Is(err, sqlite.ErrNotFound) {
-			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
-				"message": err.Error(),
-			})
-			return
-		}
-		log.Printf("failed to get user by id: %v", err)
+		log.Printf("failed to get order history for user: %d, with error: %v", userID, err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"messsge": errInternalServer.Error(),
+			"message": errInternalServer.Error(),
 		})
 		return
 	}
 
-	orders, err := h.orderStorage.GetOrderHistory(c.Request.Context(), userID)
-	if err != nil {
-		log.Printf("failed to get order history for user: %d, with error: %v", userID, err)
+	if err := h.localizer.OrderHistory(c.Request.Context(), lang, orders); err != nil {
+		log.Printf("failed to localize order history: %v", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"message": errInternalServer.Error(),
 		})
 		return
 	}
 
+	c.Header("Content-Language", lang)
+
 	c.JSON(http.StatusOK, gin.H{
 		"orders": orders,
 	})
 }
 
-// getUserIDFromContext get user information passed in context from
-// authentication, returning user id.
-func getUserIDFromContext(c *gin.Context) (int64, error) {
-	u, found := c.Get("user")
-	if !found {
-		return 0, errors.New("failed to get user")
+// getUserID returns the id of the authenticated user after making sure they
+// still exist, aborting the request otherwise.
+func (h *Handler) getUserID(c *gin.Context) (int64, bool) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return 0, false
 	}
 
-	// assert token user type
-	assertedUser, ok := u.(*models.User)
-	if !ok {
-		return 0, errors.New("failed to assert user")
+	// check if user exists
+	if _, err := h.userStorage.GetUserByID(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+			return 0, false
+		}
+		log.Printf("failed to get user by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return 0, false
 	}
 
-	return assertedUser.ID, nil
+	return userID, true
+}
+
+// getRequestedEditions validates the requested books, resolving the ones
+// given by their ISBN or book id into edition ids, and returns the requested
+// editions by their id. It aborts the request when any of them is invalid or
+// does not exist.
+func (h *Handler) getRequestedEditions(c *gin.Context, requests []*BookRequest) (map[int64]*models.Edition, bool) {
+	if len(requests) < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errAtLeastOneBookIsRequired.Error(),
+		})
+		return nil, false
+	}
+
+	isbns := []string{}
+	for _, book := range requests {
+		if book.Quantity < 1 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errInvalidQuantity.Error(),
+			})
+			return nil, false
+		}
+
+		if book.EditionID != 0 || book.BookID != 0 || book.ISBN == "" {
+			continue
+		}
+		isbn13, err := isbn.Normalize(book.ISBN)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": fmt.Sprintf("invalid isbn %q: %v", book.ISBN, err),
+			})
+			return nil, false
+		}
+		book.ISBN = isbn13
+		isbns = append(isbns, isbn13)
+	}
+
+	// resolve books requested by their ISBN into the edition with it.
+	if len(isbns) > 0 {
+		isbnEditions, err := h.editionStorage.GetEditionsByISBNs(c.Request.Context(), isbns)
+		if err != nil {
+			log.Printf("failed to check editions by isbns: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return nil, false
+		}
+
+		notFoundISBNs := []string{}
+		for _, book := range requests {
+			if book.EditionID != 0 || book.BookID != 0 || book.ISBN == "" {
+				continue
+			}
+			for _, e := range isbnEditions {
+				if e.ISBN13 == book.ISBN {
+					book.EditionID = e.ID
+					break
+				}
+			}
+			if book.EditionID == 0 {
+				notFoundISBNs = append(notFoundISBNs, book.ISBN)
+			}
+		}
+		if len(notFoundISBNs) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": fmt.Sprintf("books with isbns: [%s] not found", strings.Join(notFoundISBNs, ", ")),
+			})
+			return nil, false
+		}
+	}
+
+	// resolve books requested by their id into their default edition.
+	bookIDs := []int64{}
+	for _, book := range requests {
+		if book.EditionID == 0 {
+			bookIDs = append(bookIDs, book.BookID)
+		}
+	}
+	if len(bookIDs) > 0 {
+		books, err := h.bookStorage.GetBooksByIDs(c.Request.Context(), bookIDs)
+		if err != nil {
+			log.Printf("failed to check books by ids: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return nil, false
+		}
+
+		defaultEditions := map[int64]int64{}
+		for _, b := range books {
+			for _, e := range b.Editions {
+				if e.IsDefault {
+					defaultEditions[b.ID] = e.ID
+				}
+			}
+		}
+
+		// check if all book ids given exist in our storage.
+		notFoundIDs := []string{}
+		for _, book := range requests {
+			if book.EditionID != 0 {
+				continue
+			}
+			editionID, ok := defaultEditions[book.BookID]
+			if !ok {
+				notFoundIDs = append(notFoundIDs, strconv.FormatInt(book.BookID, 10))
+				continue
+			}
+			book.EditionID = editionID
+		}
+		if len(notFoundIDs) > 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": fmt.Sprintf("books with ids: [%s] not found", strings.Join(notFoundIDs, ", ")),
+			})
+			return nil, false
+		}
+	}
+
+	editionIDs := []int64{}
+	for _, book := range requests {
+		editionIDs = append(editionIDs, book.EditionID)
+	}
+
+	editions, err := h.editionStorage.GetEditionsByIDs(c.Request.Context(), editionIDs)
+	if err != nil {
+		log.Printf("failed to check editions by ids: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return nil, false
+	}
+
+	editionsMap := map[int64]*models.Edition{}
+	for _, e := range editions {
+		editionsMap[e.ID] = e
+	}
+
+	// check if all edition ids exist in our storage.
+	notFoundIDs := []string{}
+	for _, book := range requests {
+		if _, ok := editionsMap[book.EditionID]; !ok {
+			notFoundIDs = append(notFoundIDs, strconv.FormatInt(book.EditionID, 10))
+		}
+	}
+	if len(notFoundIDs) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("editions with ids: [%s] not found", strings.Join(notFoundIDs, ", ")),
+		})
+		return nil, false
+	}
+
+	return editionsMap, true
+}
+
+// abortWithReservationError aborts the request with the response matching a
+// reservation error, reporting whether err was one.
+func abortWithReservationError(c *gin.Context, err error) bool {
+	var stockErr *reservation.InsufficientStockError
+	switch {
+	case errors.As(err, &stockErr):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"message":     reservation.ErrInsufficientStock.Error(),
+			"book_ids":    stockErr.BookIDs,
+			"edition_ids": stockErr.EditionIDs,
+		})
+	case errors.Is(err, reservation.ErrReservationNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	case errors.Is(err, reservation.ErrReservationExpired),
+		errors.Is(err, reservation.ErrBookArchived):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	case errors.Is(err, reservation.ErrReservationMismatch):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
 }
@@ -14,10 +14,16 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/locale"
 	"github.com/wilsonangara/simple-online-book-store/storage/models"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
 	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	mock_storage_edition "github.com/wilsonangara/simple-online-book-store/storage/sqlite/edition/mock"
 	mock_storage_order "github.com/wilsonangara/simple-online-book-store/storage/sqlite/order/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/reservation"
+	mock_storage_reservation "github.com/wilsonangara/simple-online-book-store/storage/sqlite/reservation/mock"
+	mock_storage_translation "github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation/mock"
 	mock_storage_user "github.com/wilsonangara/simple-online-book-store/storage/sqlite/user/mock"
 )
 
@@ -34,10 +40,14 @@ func Test_Order(t *testing.T) {
 
 		validBookID          = int64(1)
 		notFoundBookID       = int64(10)
+		validEditionID       = int64(11)
+		notFoundEditionID    = int64(20)
 		validBookTitle       = genString()
 		validBookAuthor      = genString()
 		validBookPrice       = "1.10"
 		validBookDescription = genString()
+		validBookISBN10      = "0735211299"
+		validBookISBN13      = "9780735211292"
 		validBookQuantity    = int64(10)
 		invalidBookQuantity  = int64(-1)
 	)
@@ -55,6 +65,30 @@ func Test_Order(t *testing.T) {
 		}
 	}
 
+	mockGetEditionsByIDs := func(res []*models.Edition, err error) func(m *mock_storage_edition.MockEditionStorage) {
+		return func(m *mock_storage_edition.MockEditionStorage) {
+			m.
+				EXPECT().
+				GetEditionsByIDs(
+					gomock.Any(), // context
+					gomock.Any(), // edition IDs
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetEditionsByISBNs := func(res []*models.Edition, err error) func(m *mock_storage_edition.MockEditionStorage) {
+		return func(m *mock_storage_edition.MockEditionStorage) {
+			m.
+				EXPECT().
+				GetEditionsByISBNs(
+					gomock.Any(), // context
+					gomock.Any(), // edition ISBNs
+				).
+				Return(res, err)
+		}
+	}
+
 	mockCreateOrder := func(err error) func(m *mock_storage_order.MockOrderStorage) {
 		return func(m *mock_storage_order.MockOrderStorage) {
 			m.
@@ -86,12 +120,25 @@ func Test_Order(t *testing.T) {
 		Password: genString(),
 	}
 
+	validEdition := &models.Edition{
+		ID:        validEditionID,
+		BookID:    validBookID,
+		Format:    models.EditionFormatPaperback,
+		ISBN10:    validBookISBN10,
+		ISBN13:    validBookISBN13,
+		Price:     validBookPrice,
+		IsDefault: true,
+	}
+
 	validBook := &models.Book{
 		ID:          int64(validBookID),
 		Title:       validBookTitle,
 		Author:      validBookAuthor,
 		Price:       validBookPrice,
 		Description: validBookDescription,
+		ISBN10:      validBookISBN10,
+		ISBN13:      validBookISBN13,
+		Editions:    []*models.Edition{validEdition},
 	}
 
 	validBookRequest := &BookRequest{
@@ -114,6 +161,9 @@ func Test_Order(t *testing.T) {
 		mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
 		mockGetBooksByIDs([]*models.Book{validBook}, nil)(mockStorageBook)
 
+		mockStorageEdition := mock_storage_edition.NewMockEditionStorage(ctrl)
+		mockGetEditionsByIDs([]*models.Edition{validEdition}, nil)(mockStorageEdition)
+
 		mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
 		mockCreateOrder(nil)(mockStorageOrder)
 
@@ -122,9 +172,10 @@ func Test_Order(t *testing.T) {
 
 		w := httptest.NewRecorder()
 		h := &Handler{
-			bookStorage:  mockStorageBook,
-			orderStorage: mockStorageOrder,
-			userStorage:  mockStorageUser,
+			bookStorage:    mockStorageBook,
+			editionStorage: mockStorageEdition,
+			orderStorage:   mockStorageOrder,
+			userStorage:    mockStorageUser,
 		}
 
 		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(validReq)))
@@ -145,6 +196,195 @@ func Test_Order(t *testing.T) {
 		}
 	})
 
+	t.Run("SuccessByISBN", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageEdition := mock_storage_edition.NewMockEditionStorage(ctrl)
+		mockGetEditionsByISBNs([]*models.Edition{validEdition}, nil)(mockStorageEdition)
+		mockGetEditionsByIDs([]*models.Edition{validEdition}, nil)(mockStorageEdition)
+
+		mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+		mockCreateOrder(nil)(mockStorageOrder)
+
+		mockStorageUser := mock_storage_user.NewMockUserStorage(ctrl)
+		mockGetUserByID(validUser, nil)(mockStorageUser)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			editionStorage: mockStorageEdition,
+			orderStorage:   mockStorageOrder,
+			userStorage:    mockStorageUser,
+		}
+
+		req := fmt.Sprintf(`{
+			"books": [
+				{
+					"isbn": %q,
+					"quantity": %d
+				}
+			]
+		}`, validBookISBN10, validBookQuantity)
+
+		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(req)))
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.Order(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("Order() error, got status code = %v, want = %v", res.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("SuccessByEditionID", func(t *testing.T) {
+		t.Parallel()
+
+		hardcover := &models.Edition{
+			ID:     validEditionID + 1,
+			BookID: validBookID,
+			Format: models.EditionFormatHardcover,
+			Price:  "5.00",
+		}
+
+		mockStorageEdition := mock_storage_edition.NewMockEditionStorage(ctrl)
+		mockGetEditionsByIDs([]*models.Edition{hardcover}, nil)(mockStorageEdition)
+
+		// the order is priced by the edition rather than by its book.
+		mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+		mockStorageOrder.
+			EXPECT().
+			Create(
+				gomock.Any(), // context
+				gomock.Any(), // order
+				[]*models.OrderItem{
+					{
+						BookID:    validBookID,
+						EditionID: hardcover.ID,
+						Price:     hardcover.Price,
+						Quantity:  2,
+					},
+				},
+			).
+			DoAndReturn(func(_ any, order *models.Order, _ []*models.OrderItem) error {
+				if order.Total != "10.00" {
+					t.Errorf("Order() error, got total = %v, want = %v", order.Total, "10.00")
+				}
+				return nil
+			})
+
+		mockStorageUser := mock_storage_user.NewMockUserStorage(ctrl)
+		mockGetUserByID(validUser, nil)(mockStorageUser)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			editionStorage: mockStorageEdition,
+			orderStorage:   mockStorageOrder,
+			userStorage:    mockStorageUser,
+		}
+
+		req := fmt.Sprintf(`{
+			"books": [
+				{
+					"edition_id": %d,
+					"quantity": %d
+				}
+			]
+		}`, hardcover.ID, 2)
+
+		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(req)))
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.Order(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("Order() error, got status code = %v, want = %v", res.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("SuccessWithPreorder", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+		mockGetBooksByIDs([]*models.Book{validBook}, nil)(mockStorageBook)
+
+		mockStorageEdition := mock_storage_edition.NewMockEditionStorage(ctrl)
+		mockGetEditionsByIDs([]*models.Edition{validEdition}, nil)(mockStorageEdition)
+
+		// the storage preorders books that are not released yet.
+		mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+		mockStorageOrder.
+			EXPECT().
+			Create(
+				gomock.Any(), // context
+				gomock.Any(), // order
+				gomock.Any(), // order items
+			).
+			DoAndReturn(func(_ any, order *models.Order, items []*models.OrderItem) error {
+				order.ID = 1
+				items[0].FulfillmentStatus = models.FulfillmentStatusPreordered
+				return nil
+			})
+
+		mockStorageUser := mock_storage_user.NewMockUserStorage(ctrl)
+		mockGetUserByID(validUser, nil)(mockStorageUser)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			bookStorage:    mockStorageBook,
+			editionStorage: mockStorageEdition,
+			orderStorage:   mockStorageOrder,
+			userStorage:    mockStorageUser,
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(validReq)))
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.Order(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("Order() error, got status code = %v, want = %v", res.StatusCode, http.StatusOK)
+		}
+
+		want := gin.H{
+			"order_id": float64(1),
+			"total":    "11.00",
+			"items": []interface{}{
+				map[string]interface{}{
+					"book_id":            float64(validBookID),
+					"edition_id":         float64(validEditionID),
+					"quantity":           float64(validBookQuantity),
+					"fulfillment_status": models.FulfillmentStatusPreordered,
+				},
+			},
+		}
+		if diff := cmp.Diff(want, getResponseBody(t, w.Body.Bytes())); diff != "" {
+			t.Fatalf("Order() mismatch (-want+got):\n%s", diff)
+		}
+	})
+
 	t.Run("Failed", func(t *testing.T) {
 		t.Parallel()
 
@@ -152,6 +392,7 @@ func Test_Order(t *testing.T) {
 			name        string
 			req         string
 			mockBook    func(m *mock_storage_book.MockBookStorage)
+			mockEdition func(m *mock_storage_edition.MockEditionStorage)
 			mockOrder   func(m *mock_storage_order.MockOrderStorage)
 			mockUser    func(m *mock_storage_user.MockUserStorage)
 			wantErrCode int
@@ -210,11 +451,115 @@ func Test_Order(t *testing.T) {
 					"message": fmt.Sprintf("books with ids: [%d] not found", notFoundBookID),
 				},
 			},
+			{
+				name: "EditionIDNotFound",
+				req: fmt.Sprintf(`{
+					"books": [
+						{
+							"edition_id": %d,
+							"quantity": %d
+						}
+					]
+				}`, notFoundEditionID, validBookQuantity),
+				mockUser:    mockGetUserByID(validUser, nil),
+				mockEdition: mockGetEditionsByIDs([]*models.Edition{}, nil),
+				wantErrCode: http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": fmt.Sprintf("editions with ids: [%d] not found", notFoundEditionID),
+				},
+			},
+			{
+				name: "InvalidISBN",
+				req: fmt.Sprintf(`{
+					"books": [
+						{
+							"isbn": %q,
+							"quantity": %d
+						}
+					]
+				}`, "0735211298", validBookQuantity),
+				mockUser:    mockGetUserByID(validUser, nil),
+				wantErrCode: http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": fmt.Sprintf("invalid isbn %q: %v", "0735211298", isbn.ErrInvalidChecksum),
+				},
+			},
+			{
+				name: "ISBNNotFound",
+				req: fmt.Sprintf(`{
+					"books": [
+						{
+							"isbn": %q,
+							"quantity": %d
+						}
+					]
+				}`, validBookISBN10, validBookQuantity),
+				mockUser:    mockGetUserByID(validUser, nil),
+				mockEdition: mockGetEditionsByISBNs([]*models.Edition{}, nil),
+				wantErrCode: http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": fmt.Sprintf("books with isbns: [%s] not found", validBookISBN13),
+				},
+			},
+			{
+				name:        "InsufficientStock",
+				req:         validReq,
+				mockUser:    mockGetUserByID(validUser, nil),
+				mockBook:    mockGetBooksByIDs([]*models.Book{validBook}, nil),
+				mockEdition: mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
+				mockOrder: mockCreateOrder(&reservation.InsufficientStockError{
+					BookIDs:    []int64{validBookID},
+					EditionIDs: []int64{validEditionID},
+				}),
+				wantErrCode: http.StatusConflict,
+				wantErr: gin.H{
+					"message":     reservation.ErrInsufficientStock.Error(),
+					"book_ids":    []any{float64(validBookID)},
+					"edition_ids": []any{float64(validEditionID)},
+				},
+			},
+			{
+				name:        "ReservationExpired",
+				req:         validReq,
+				mockUser:    mockGetUserByID(validUser, nil),
+				mockBook:    mockGetBooksByIDs([]*models.Book{validBook}, nil),
+				mockEdition: mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
+				mockOrder:   mockCreateOrder(reservation.ErrReservationExpired),
+				wantErrCode: http.StatusConflict,
+				wantErr: gin.H{
+					"message": reservation.ErrReservationExpired.Error(),
+				},
+			},
+			{
+				name:        "ReservedBookArchived",
+				req:         validReq,
+				mockUser:    mockGetUserByID(validUser, nil),
+				mockBook:    mockGetBooksByIDs([]*models.Book{validBook}, nil),
+				mockEdition: mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
+				mockOrder:   mockCreateOrder(reservation.ErrBookArchived),
+				wantErrCode: http.StatusConflict,
+				wantErr: gin.H{
+					"message": reservation.ErrBookArchived.Error(),
+				},
+			},
+			{
+				name:        "ReservationMismatch",
+				req:         validReq,
+				mockUser:    mockGetUserByID(validUser, nil),
+				mockBook:    mockGetBooksByIDs([]*models.Book{validBook}, nil),
+				mockEdition: mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
+				mockOrder:   mockCreateOrder(reservation.ErrReservationMismatch),
+				wantErrCode: http.StatusBadRequest,
+				wantErr: gin.H{
+					"message": reservation.ErrReservationMismatch.Error(),
+				},
+			},
 			{
 				name:        "CreateOrderDatabaseOperationFailed",
 				req:         validReq,
 				mockUser:    mockGetUserByID(validUser, nil),
 				mockBook:    mockGetBooksByIDs([]*models.Book{validBook}, nil),
+				mockEdition: mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
 				mockOrder:   mockCreateOrder(errors.New("failed to execute create order operation")),
 				wantErrCode: http.StatusInternalServerError,
 				wantErr: gin.H{
@@ -233,6 +578,11 @@ func Test_Order(t *testing.T) {
 					tt.mockBook(mockStorageBook)
 				}
 
+				mockStorageEdition := mock_storage_edition.NewMockEditionStorage(ctrl)
+				if tt.mockEdition != nil {
+					tt.mockEdition(mockStorageEdition)
+				}
+
 				mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
 				if tt.mockOrder != nil {
 					tt.mockOrder(mockStorageOrder)
@@ -245,9 +595,10 @@ func Test_Order(t *testing.T) {
 
 				w := httptest.NewRecorder()
 				h := &Handler{
-					bookStorage:  mockStorageBook,
-					orderStorage: mockStorageOrder,
-					userStorage:  mockStorageUser,
+					bookStorage:    mockStorageBook,
+					editionStorage: mockStorageEdition,
+					orderStorage:   mockStorageOrder,
+					userStorage:    mockStorageUser,
 				}
 
 				r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
@@ -276,6 +627,359 @@ func Test_Order(t *testing.T) {
 	})
 }
 
+func Test_Reserve(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodPost
+		validEndpoint = "http://localhost:8443/v1/orders/reservations"
+
+		validUserID       = int64(1)
+		validBookID       = int64(1)
+		validEditionID    = int64(11)
+		validBookQuantity = int64(2)
+	)
+
+	// mock functions
+	mockGetBooksByIDs := func(res []*models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+		return func(m *mock_storage_book.MockBookStorage) {
+			m.
+				EXPECT().
+				GetBooksByIDs(
+					gomock.Any(), // context
+					gomock.Any(), // book IDs
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetEditionsByIDs := func(res []*models.Edition, err error) func(m *mock_storage_edition.MockEditionStorage) {
+		return func(m *mock_storage_edition.MockEditionStorage) {
+			m.
+				EXPECT().
+				GetEditionsByIDs(
+					gomock.Any(), // context
+					gomock.Any(), // edition IDs
+				).
+				Return(res, err)
+		}
+	}
+
+	mockReserve := func(res *models.Reservation, err error) func(m *mock_storage_reservation.MockReservationStorage) {
+		return func(m *mock_storage_reservation.MockReservationStorage) {
+			m.
+				EXPECT().
+				Reserve(
+					gomock.Any(), // context
+					gomock.Any(), // user id
+					gomock.Any(), // reservation items
+					gomock.Any(), // ttl
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetUserByID := func(res *models.User, err error) func(m *mock_storage_user.MockUserStorage) {
+		return func(m *mock_storage_user.MockUserStorage) {
+			m.
+				EXPECT().
+				GetUserByID(
+					gomock.Any(), // context
+					gomock.Any(), // user id
+				).
+				Return(res, err)
+		}
+	}
+
+	validUser := &models.User{
+		ID:       validUserID,
+		Email:    genString(),
+		Password: genString(),
+	}
+
+	validEdition := &models.Edition{
+		ID:        validEditionID,
+		BookID:    validBookID,
+		Format:    models.EditionFormatPaperback,
+		Price:     "1.10",
+		IsDefault: true,
+	}
+
+	validBook := &models.Book{
+		ID:       validBookID,
+		Title:    genString(),
+		Price:    "1.10",
+		Editions: []*models.Edition{validEdition},
+	}
+
+	validReq := fmt.Sprintf(`{
+		"books": [
+			{
+				"book_id": %d,
+				"quantity": %d
+			}
+		]
+	}`, validBookID, validBookQuantity)
+
+	tests := []struct {
+		name            string
+		req             string
+		mockBook        func(m *mock_storage_book.MockBookStorage)
+		mockEdition     func(m *mock_storage_edition.MockEditionStorage)
+		mockReservation func(m *mock_storage_reservation.MockReservationStorage)
+		mockUser        func(m *mock_storage_user.MockUserStorage)
+		wantCode        int
+		wantErr         gin.H
+	}{
+		{
+			name:        "Success",
+			req:         validReq,
+			mockUser:    mockGetUserByID(validUser, nil),
+			mockBook:    mockGetBooksByIDs([]*models.Book{validBook}, nil),
+			mockEdition: mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
+			mockReservation: mockReserve(&models.Reservation{
+				ID:     1,
+				Status: models.ReservationStatusActive,
+			}, nil),
+			wantCode: http.StatusCreated,
+		},
+		{
+			name: "EmptyBooks",
+			req: `{
+				"books": []
+			}`,
+			mockUser: mockGetUserByID(validUser, nil),
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errAtLeastOneBookIsRequired.Error(),
+			},
+		},
+		{
+			name:        "InsufficientStock",
+			req:         validReq,
+			mockUser:    mockGetUserByID(validUser, nil),
+			mockBook:    mockGetBooksByIDs([]*models.Book{validBook}, nil),
+			mockEdition: mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
+			mockReservation: mockReserve(nil, &reservation.InsufficientStockError{
+				BookIDs:    []int64{validBookID},
+				EditionIDs: []int64{validEditionID},
+			}),
+			wantCode: http.StatusConflict,
+			wantErr: gin.H{
+				"message":     reservation.ErrInsufficientStock.Error(),
+				"book_ids":    []any{float64(validBookID)},
+				"edition_ids": []any{float64(validEditionID)},
+			},
+		},
+		{
+			name:            "ReserveDatabaseOperationFailed",
+			req:             validReq,
+			mockUser:        mockGetUserByID(validUser, nil),
+			mockBook:        mockGetBooksByIDs([]*models.Book{validBook}, nil),
+			mockEdition:     mockGetEditionsByIDs([]*models.Edition{validEdition}, nil),
+			mockReservation: mockReserve(nil, errors.New("failed to execute reserve operation")),
+			wantCode:        http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			if tt.mockBook != nil {
+				tt.mockBook(mockStorageBook)
+			}
+
+			mockStorageEdition := mock_storage_edition.NewMockEditionStorage(ctrl)
+			if tt.mockEdition != nil {
+				tt.mockEdition(mockStorageEdition)
+			}
+
+			mockStorageReservation := mock_storage_reservation.NewMockReservationStorage(ctrl)
+			if tt.mockReservation != nil {
+				tt.mockReservation(mockStorageReservation)
+			}
+
+			mockStorageUser := mock_storage_user.NewMockUserStorage(ctrl)
+			if tt.mockUser != nil {
+				tt.mockUser(mockStorageUser)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage:        mockStorageBook,
+				editionStorage:     mockStorageEdition,
+				reservationStorage: mockStorageReservation,
+				userStorage:        mockStorageUser,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			testCtx.Set("user", validUser)
+
+			h.Reserve(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("Reserve() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr == nil {
+				return
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+				t.Fatalf("Reserve() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_ReleaseReservation(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodDelete
+		validEndpoint = "http://localhost:8443/v1/orders/reservations/1"
+
+		validUserID = int64(1)
+	)
+
+	// mock functions
+	mockRelease := func(err error) func(m *mock_storage_reservation.MockReservationStorage) {
+		return func(m *mock_storage_reservation.MockReservationStorage) {
+			m.
+				EXPECT().
+				Release(
+					gomock.Any(), // context
+					gomock.Any(), // user id
+					gomock.Any(), // reservation id
+				).
+				Return(err)
+		}
+	}
+
+	mockGetUserByID := func(res *models.User, err error) func(m *mock_storage_user.MockUserStorage) {
+		return func(m *mock_storage_user.MockUserStorage) {
+			m.
+				EXPECT().
+				GetUserByID(
+					gomock.Any(), // context
+					gomock.Any(), // user id
+				).
+				Return(res, err)
+		}
+	}
+
+	validUser := &models.User{
+		ID:       validUserID,
+		Email:    genString(),
+		Password: genString(),
+	}
+
+	tests := []struct {
+		name            string
+		reservationID   string
+		mockReservation func(m *mock_storage_reservation.MockReservationStorage)
+		wantCode        int
+		wantErr         gin.H
+	}{
+		{
+			name:            "Success",
+			reservationID:   "1",
+			mockReservation: mockRelease(nil),
+			wantCode:        http.StatusOK,
+			wantErr:         gin.H{},
+		},
+		{
+			name:          "InvalidReservationID",
+			reservationID: "abc",
+			wantCode:      http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidReservationID.Error(),
+			},
+		},
+		{
+			name:            "ReservationNotFound",
+			reservationID:   "1",
+			mockReservation: mockRelease(reservation.ErrReservationNotFound),
+			wantCode:        http.StatusNotFound,
+			wantErr: gin.H{
+				"message": reservation.ErrReservationNotFound.Error(),
+			},
+		},
+		{
+			name:            "ReleaseDatabaseOperationFailed",
+			reservationID:   "1",
+			mockReservation: mockRelease(errors.New("failed to execute release operation")),
+			wantCode:        http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageReservation := mock_storage_reservation.NewMockReservationStorage(ctrl)
+			if tt.mockReservation != nil {
+				tt.mockReservation(mockStorageReservation)
+			}
+
+			mockStorageUser := mock_storage_user.NewMockUserStorage(ctrl)
+			mockGetUserByID(validUser, nil)(mockStorageUser)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				reservationStorage: mockStorageReservation,
+				userStorage:        mockStorageUser,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.reservationID}}
+
+			testCtx.Set("user", validUser)
+
+			h.ReleaseReservation(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("ReleaseReservation() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+				t.Fatalf("ReleaseReservation() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func Test_GetOrderHistory(t *testing.T) {
 	t.Parallel()
 
@@ -349,6 +1053,7 @@ func Test_GetOrderHistory(t *testing.T) {
 		h := &Handler{
 			orderStorage: mockStorageOrder,
 			userStorage:  mockStorageUser,
+			localizer:    locale.NewLocalizer(mock_storage_translation.NewMockTranslationStorage(ctrl), "en"),
 		}
 
 		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
@@ -369,6 +1074,61 @@ func Test_GetOrderHistory(t *testing.T) {
 		}
 	})
 
+	t.Run("Localized", func(t *testing.T) {
+		t.Parallel()
+
+		orders := []*models.OrderHistory{
+			{
+				ID:    validOrderHistoryID,
+				Total: "10.00",
+				Items: []*models.OrderHistoryItem{
+					{BookID: 1, Price: "10.00", Quantity: 1, Title: "Atomic Habits", Description: "Habits"},
+				},
+			},
+		}
+
+		mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+		mockGetOrderHistory(orders, nil)(mockStorageOrder)
+
+		mockStorageUser := mock_storage_user.NewMockUserStorage(ctrl)
+		mockGetUserByID(validUser, nil)(mockStorageUser)
+
+		mockStorageTranslation := mock_storage_translation.NewMockTranslationStorage(ctrl)
+		mockStorageTranslation.EXPECT().GetLocales(gomock.Any()).Return([]string{"fr"}, nil)
+		mockStorageTranslation.EXPECT().GetTranslationsByBookIDs(gomock.Any(), "fr", []int64{1}).Return(map[int64]*models.BookTranslation{
+			1: {BookID: 1, Locale: "fr", Title: "Un Petit Rien", Description: "Des habitudes"},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			orderStorage: mockStorageOrder,
+			userStorage:  mockStorageUser,
+			localizer:    locale.NewLocalizer(mockStorageTranslation, "en"),
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint+"?lang=fr", bytes.NewBuffer([]byte{}))
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.GetOrderHistory(testCtx)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("GetOrderHistory() error, got status code = %v, want = %v", w.Code, http.StatusOK)
+		}
+		if got := w.Header().Get("Content-Language"); got != "fr" {
+			t.Fatalf("GetOrderHistory() Content-Language error, got = %v, want = %v", got, "fr")
+		}
+		if item := orders[0].Items[0]; item.Title != "Un Petit Rien" || item.Description != "Des habitudes" {
+			t.Fatalf("GetOrderHistory() error, got title = %v, description = %v", item.Title, item.Description)
+		}
+	})
+
 	t.Run("Failed_UserNotFound", func(t *testing.T) {
 		t.Parallel()
 
@@ -420,6 +1180,7 @@ func Test_GetOrderHistory(t *testing.T) {
 		h := &Handler{
 			orderStorage: mockStorageOrder,
 			userStorage:  mockStorageUser,
+			localizer:    locale.NewLocalizer(mock_storage_translation.NewMockTranslationStorage(ctrl), "en"),
 		}
 
 		r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
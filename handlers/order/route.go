@@ -11,4 +11,6 @@ func (h *Handler) AddOrderRoutes(rg *gin.RouterGroup, m *middleware.Middleware)
 
 	r.GET("/history", m.Authenticate(), h.GetOrderHistory)
 	r.POST("/", m.Authenticate(), h.Order)
+	r.POST("/reservations", m.Authenticate(), h.Reserve)
+	r.DELETE("/reservations/:id", m.Authenticate(), h.ReleaseReservation)
 }
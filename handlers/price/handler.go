@@ -0,0 +1,200 @@
+package price
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/price"
+)
+
+var (
+	errInternalServer        = errors.New("internal error")
+	errInvalidBookID         = errors.New("invalid book id")
+	errInvalidPriceID        = errors.New("invalid price id")
+	errInvalidPrice          = errors.New("price must be a non-negative number")
+	errInvalidEffectiveFrom  = errors.New("effective_from must be in the future")
+	errInvalidEffectiveTo    = errors.New("effective_to must be after effective_from")
+	errBookNotFound          = errors.New("book not found")
+	errPriceNotFound         = errors.New("price not found")
+	errPriceAlreadyEffective = errors.New("price already took effect")
+)
+
+type Handler struct {
+	priceStorage price.PriceStorage
+	bookStorage  book.BookStorage
+}
+
+// NewHandler returns a wrapper for price handler.
+func NewHandler(priceStorage price.PriceStorage, bookStorage book.BookStorage) *Handler {
+	return &Handler{
+		priceStorage: priceStorage,
+		bookStorage:  bookStorage,
+	}
+}
+
+// SchedulePriceRequest is a price of a book over a period, a price without
+// an end lasts until a later price takes over.
+type SchedulePriceRequest struct {
+	Price         string     `json:"price"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+}
+
+// GetPriceHistory fetches every price of a book, past and scheduled.
+func (h *Handler) GetPriceHistory(c *gin.Context) {
+	bookID, ok := h.getBookID(c)
+	if !ok {
+		return
+	}
+
+	prices, err := h.priceStorage.GetPriceHistory(c.Request.Context(), bookID)
+	if err != nil {
+		log.Printf("failed to get price history of book %d: %v", bookID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prices": prices,
+	})
+}
+
+// SchedulePrice schedules a price change of a book, either for good or over
+// a period such as a promotion.
+func (h *Handler) SchedulePrice(c *gin.Context) {
+	bookID, ok := h.getBookID(c)
+	if !ok {
+		return
+	}
+
+	r := &SchedulePriceRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	amount, err := strconv.ParseFloat(r.Price, 64)
+	if err != nil || amount < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidPrice.Error(),
+		})
+		return
+	}
+	if !r.EffectiveFrom.After(time.Now()) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidEffectiveFrom.Error(),
+		})
+		return
+	}
+	if r.EffectiveTo != nil && !r.EffectiveTo.After(r.EffectiveFrom) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidEffectiveTo.Error(),
+		})
+		return
+	}
+
+	scheduled, err := h.priceStorage.SchedulePrice(c.Request.Context(), &models.BookPrice{
+		BookID:        bookID,
+		Price:         strconv.FormatFloat(amount, 'f', 2, 64),
+		EffectiveFrom: r.EffectiveFrom,
+		EffectiveTo:   r.EffectiveTo,
+	})
+	if err != nil {
+		if errors.Is(err, price.ErrBookIDNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to schedule price: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"price": scheduled,
+	})
+}
+
+// DeleteScheduledPrice cancels a price change that has not taken effect yet.
+func (h *Handler) DeleteScheduledPrice(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	priceID, err := strconv.ParseInt(c.Param("price_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidPriceID.Error(),
+		})
+		return
+	}
+
+	if err := h.priceStorage.DeleteScheduledPrice(c.Request.Context(), bookID, priceID); err != nil {
+		switch {
+		case errors.Is(err, sqlite.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errPriceNotFound.Error(),
+			})
+		case errors.Is(err, price.ErrPriceAlreadyEffective):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"message": errPriceAlreadyEffective.Error(),
+			})
+		default:
+			log.Printf("failed to delete scheduled price: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getBookID parses the book id from the path and checks the book exists,
+// aborting otherwise.
+func (h *Handler) getBookID(c *gin.Context) (int64, bool) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return 0, false
+	}
+
+	if _, err := h.bookStorage.GetBookByID(c.Request.Context(), bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return 0, false
+		}
+		log.Printf("failed to get book by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return 0, false
+	}
+
+	return bookID, true
+}
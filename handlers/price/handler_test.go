@@ -0,0 +1,313 @@
+package price
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/price"
+	mock_storage_price "github.com/wilsonangara/simple-online-book-store/storage/sqlite/price/mock"
+)
+
+var validBookID = int64(1)
+
+func Test_GetPriceHistory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       string
+		mock     func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage)
+		wantCode int
+		wantBody gin.H
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				b.EXPECT().GetBookByID(gomock.Any(), validBookID).Return(&models.Book{ID: validBookID}, nil)
+				p.EXPECT().GetPriceHistory(gomock.Any(), validBookID).Return([]*models.BookPrice{
+					{ID: 1, BookID: validBookID, Price: "10.00"},
+				}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: gin.H{
+				"prices": []any{
+					map[string]any{
+						"id":             float64(1),
+						"book_id":        float64(1),
+						"price":          "10.00",
+						"effective_from": "0001-01-01T00:00:00Z",
+						"effective_to":   nil,
+						"created_at":     "0001-01-01T00:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name: "BookNotFound",
+			id:   "1000",
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				b.EXPECT().GetBookByID(gomock.Any(), int64(1000)).Return(nil, sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: gin.H{"message": errBookNotFound.Error()},
+		},
+		{
+			name: "InternalServerError",
+			id:   "1",
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				b.EXPECT().GetBookByID(gomock.Any(), validBookID).Return(&models.Book{ID: validBookID}, nil)
+				p.EXPECT().GetPriceHistory(gomock.Any(), validBookID).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockPriceStorage := mock_storage_price.NewMockPriceStorage(ctrl)
+			mockBookStorage := mock_storage_book.NewMockBookStorage(ctrl)
+			tt.mock(mockPriceStorage, mockBookStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				priceStorage: mockPriceStorage,
+				bookStorage:  mockBookStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8433/v1/books/"+tt.id+"/prices", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.GetPriceHistory(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetPriceHistory() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantBody, resBody); diff != "" {
+				t.Fatalf("GetPriceHistory() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_SchedulePrice(t *testing.T) {
+	t.Parallel()
+
+	from := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	to := from.Add(time.Hour)
+
+	mockGetBook := func(b *mock_storage_book.MockBookStorage) {
+		b.EXPECT().GetBookByID(gomock.Any(), validBookID).Return(&models.Book{ID: validBookID}, nil)
+	}
+
+	tests := []struct {
+		name     string
+		body     gin.H
+		mock     func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			body: gin.H{"price": "7.5", "effective_from": from, "effective_to": to},
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				mockGetBook(b)
+				p.EXPECT().
+					SchedulePrice(gomock.Any(), &models.BookPrice{
+						BookID:        validBookID,
+						Price:         "7.50",
+						EffectiveFrom: from,
+						EffectiveTo:   &to,
+					}).
+					Return(&models.BookPrice{ID: 2, BookID: validBookID, Price: "7.50"}, nil)
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name: "InvalidPrice",
+			body: gin.H{"price": "-1", "effective_from": from},
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				mockGetBook(b)
+			},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidPrice.Error()},
+		},
+		{
+			name: "EffectiveFromInThePast",
+			body: gin.H{"price": "7.50", "effective_from": time.Now().Add(-time.Hour)},
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				mockGetBook(b)
+			},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidEffectiveFrom.Error()},
+		},
+		{
+			name: "EffectiveToBeforeEffectiveFrom",
+			body: gin.H{"price": "7.50", "effective_from": to, "effective_to": from},
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				mockGetBook(b)
+			},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidEffectiveTo.Error()},
+		},
+		{
+			name: "InternalServerError",
+			body: gin.H{"price": "7.50", "effective_from": from},
+			mock: func(p *mock_storage_price.MockPriceStorage, b *mock_storage_book.MockBookStorage) {
+				mockGetBook(b)
+				p.EXPECT().SchedulePrice(gomock.Any(), gomock.Any()).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantErr:  gin.H{"message": errInternalServer.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockPriceStorage := mock_storage_price.NewMockPriceStorage(ctrl)
+			mockBookStorage := mock_storage_book.NewMockBookStorage(ctrl)
+			tt.mock(mockPriceStorage, mockBookStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				priceStorage: mockPriceStorage,
+				bookStorage:  mockBookStorage,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8433/v1/books/1/prices", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			h.SchedulePrice(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("SchedulePrice() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("SchedulePrice() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_DeleteScheduledPrice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		priceID  string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "Success",
+			priceID:  "2",
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "InvalidPriceID",
+			priceID:  "two",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "NotFound",
+			priceID:  "2",
+			err:      sqlite.ErrNotFound,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "AlreadyEffective",
+			priceID:  "2",
+			err:      price.ErrPriceAlreadyEffective,
+			wantCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockPriceStorage := mock_storage_price.NewMockPriceStorage(ctrl)
+			if tt.name != "InvalidPriceID" {
+				mockPriceStorage.EXPECT().DeleteScheduledPrice(gomock.Any(), validBookID, int64(2)).Return(tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				priceStorage: mockPriceStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodDelete, "http://localhost:8433/v1/books/1/prices/"+tt.priceID, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "price_id", Value: tt.priceID}}
+
+			h.DeleteScheduledPrice(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("DeleteScheduledPrice() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
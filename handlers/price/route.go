@@ -0,0 +1,15 @@
+package price
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddPriceRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/books/:id/prices", m.Authenticate(), m.Admin())
+
+	r.GET("/", h.GetPriceHistory)
+	r.POST("/", h.SchedulePrice)
+	r.DELETE("/:price_id", h.DeleteScheduledPrice)
+}
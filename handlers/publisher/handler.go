@@ -0,0 +1,288 @@
+package publisher
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/publisher"
+)
+
+// dateLayout is the layout of the dates a sales report is ranged by.
+const dateLayout = "2006-01-02"
+
+var (
+	errInternalServer     = errors.New("internal error")
+	errPublisherNotFound  = errors.New("publisher not found")
+	errNameIsRequired     = errors.New("name is required")
+	errSlugIsRequired     = errors.New("slug is required")
+	errBookIDIsRequired   = errors.New("book id is required")
+	errInvalidBookID      = errors.New("invalid book id")
+	errBookNotOfPublisher = errors.New("book is not published by publisher")
+	errInvalidFrom        = errors.New("invalid from, expected YYYY-MM-DD")
+	errInvalidTo          = errors.New("invalid to, expected YYYY-MM-DD")
+)
+
+type Handler struct {
+	publisherStorage publisher.PublisherStorage
+	bookStorage      book.BookStorage
+}
+
+// NewHandler returns a wrapper for publisher handler.
+func NewHandler(publisherStorage publisher.PublisherStorage, bookStorage book.BookStorage) *Handler {
+	return &Handler{
+		publisherStorage: publisherStorage,
+		bookStorage:      bookStorage,
+	}
+}
+
+type CreatePublisherRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+func (r *CreatePublisherRequest) Validate() error {
+	switch "" {
+	case r.Name:
+		return errNameIsRequired
+	case r.Slug:
+		return errSlugIsRequired
+	}
+	return nil
+}
+
+type AssignBookRequest struct {
+	BookID int64 `json:"book_id"`
+}
+
+// GetPublishers fetches all publishers.
+func (h *Handler) GetPublishers(c *gin.Context) {
+	publishers, err := h.publisherStorage.GetPublishers(c.Request.Context())
+	if err != nil {
+		log.Printf("failed to get publishers: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"publishers": publishers,
+	})
+}
+
+// GetPublisher fetches a publisher by its slug.
+func (h *Handler) GetPublisher(c *gin.Context) {
+	foundPublisher, ok := h.getPublisher(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"publisher": foundPublisher,
+	})
+}
+
+// GetPublisherBooks fetches the books of a publisher.
+func (h *Handler) GetPublisherBooks(c *gin.Context) {
+	foundPublisher, ok := h.getPublisher(c)
+	if !ok {
+		return
+	}
+
+	books, err := h.bookStorage.GetBooks(c.Request.Context(), &models.BookFilter{
+		Publisher: foundPublisher.Slug,
+	})
+	if err != nil {
+		log.Printf("failed to get books of publisher %q: %v", foundPublisher.Slug, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"publisher": foundPublisher,
+		"books":     books,
+	})
+}
+
+// CreatePublisher lets an admin add a new publisher.
+func (h *Handler) CreatePublisher(c *gin.Context) {
+	r := &CreatePublisherRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := r.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	createdPublisher, err := h.publisherStorage.Create(c.Request.Context(), &models.Publisher{
+		Name: r.Name,
+		Slug: r.Slug,
+	})
+	if err != nil {
+		if errors.Is(err, publisher.ErrSlugAlreadyExist) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		log.Printf("failed to create publisher: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"publisher": createdPublisher,
+	})
+}
+
+// AssignBook lets an admin set the publisher of a book, a book has a single
+// publisher.
+func (h *Handler) AssignBook(c *gin.Context) {
+	foundPublisher, ok := h.getPublisher(c)
+	if !ok {
+		return
+	}
+
+	r := &AssignBookRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.BookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errBookIDIsRequired.Error(),
+		})
+		return
+	}
+
+	if err := h.publisherStorage.AssignBook(c.Request.Context(), foundPublisher.ID, r.BookID); err != nil {
+		if errors.Is(err, publisher.ErrBookIDNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		log.Printf("failed to assign book %d to publisher %q: %v", r.BookID, foundPublisher.Slug, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// UnassignBook lets an admin remove the publisher of a book.
+func (h *Handler) UnassignBook(c *gin.Context) {
+	foundPublisher, ok := h.getPublisher(c)
+	if !ok {
+		return
+	}
+
+	bookID, err := strconv.ParseInt(c.Param("book_id"), 10, 64)
+	if err != nil || bookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if err := h.publisherStorage.UnassignBook(c.Request.Context(), foundPublisher.ID, bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotOfPublisher.Error(),
+			})
+			return
+		}
+		log.Printf("failed to unassign book %d from publisher %q: %v", bookID, foundPublisher.Slug, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// GetSales lets an admin see the sales of every publisher, optionally of the
+// orders placed from and to the given dates, both inclusive.
+func (h *Handler) GetSales(c *gin.Context) {
+	var from, to time.Time
+	if v := c.Query("from"); v != "" {
+		var err error
+		if from, err = time.Parse(dateLayout, v); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errInvalidFrom.Error(),
+			})
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		day, err := time.Parse(dateLayout, v)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": errInvalidTo.Error(),
+			})
+			return
+		}
+		// orders of the whole day are included.
+		to = day.AddDate(0, 0, 1)
+	}
+
+	sales, err := h.publisherStorage.GetSales(c.Request.Context(), from, to)
+	if err != nil {
+		log.Printf("failed to get publisher sales: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sales": sales,
+	})
+}
+
+// getPublisher fetches the publisher referenced by the slug path parameter,
+// aborting the request when it cannot be found.
+func (h *Handler) getPublisher(c *gin.Context) (*models.Publisher, bool) {
+	foundPublisher, err := h.publisherStorage.GetPublisherBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errPublisherNotFound.Error(),
+			})
+			return nil, false
+		}
+		log.Printf("failed to get publisher by slug: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return nil, false
+	}
+	return foundPublisher, true
+}
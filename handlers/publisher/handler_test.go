@@ -0,0 +1,351 @@
+package publisher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/publisher"
+	mock_storage_publisher "github.com/wilsonangara/simple-online-book-store/storage/sqlite/publisher/mock"
+)
+
+var validPublisher = &models.Publisher{ID: 1, Name: "Penguin", Slug: "penguin"}
+
+func Test_GetPublisherBooks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		slug     string
+		mock     func(p *mock_storage_publisher.MockPublisherStorage, b *mock_storage_book.MockBookStorage)
+		wantCode int
+	}{
+		{
+			name: "Success",
+			slug: "penguin",
+			mock: func(p *mock_storage_publisher.MockPublisherStorage, b *mock_storage_book.MockBookStorage) {
+				p.EXPECT().GetPublisherBySlug(gomock.Any(), "penguin").Return(validPublisher, nil)
+				b.EXPECT().GetBooks(gomock.Any(), &models.BookFilter{Publisher: "penguin"}).Return([]*models.Book{{ID: 1}}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "NotFound",
+			slug: "unknown",
+			mock: func(p *mock_storage_publisher.MockPublisherStorage, b *mock_storage_book.MockBookStorage) {
+				p.EXPECT().GetPublisherBySlug(gomock.Any(), "unknown").Return(nil, sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "InternalServerError",
+			slug: "penguin",
+			mock: func(p *mock_storage_publisher.MockPublisherStorage, b *mock_storage_book.MockBookStorage) {
+				p.EXPECT().GetPublisherBySlug(gomock.Any(), "penguin").Return(validPublisher, nil)
+				b.EXPECT().GetBooks(gomock.Any(), gomock.Any()).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockPublisherStorage := mock_storage_publisher.NewMockPublisherStorage(ctrl)
+			mockBookStorage := mock_storage_book.NewMockBookStorage(ctrl)
+			tt.mock(mockPublisherStorage, mockBookStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				publisherStorage: mockPublisherStorage,
+				bookStorage:      mockBookStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8443/v1/publishers/"+tt.slug+"/books", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "slug", Value: tt.slug}}
+
+			h.GetPublisherBooks(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetPublisherBooks() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_CreatePublisher(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     gin.H
+		mock     func(p *mock_storage_publisher.MockPublisherStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			body: gin.H{"name": "Penguin", "slug": "penguin"},
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				p.EXPECT().Create(gomock.Any(), &models.Publisher{Name: "Penguin", Slug: "penguin"}).Return(validPublisher, nil)
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "MissingSlug",
+			body:     gin.H{"name": "Penguin"},
+			mock:     func(p *mock_storage_publisher.MockPublisherStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errSlugIsRequired.Error()},
+		},
+		{
+			name: "SlugAlreadyExist",
+			body: gin.H{"name": "Penguin", "slug": "penguin"},
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				p.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, publisher.ErrSlugAlreadyExist)
+			},
+			wantCode: http.StatusConflict,
+			wantErr:  gin.H{"message": publisher.ErrSlugAlreadyExist.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockPublisherStorage := mock_storage_publisher.NewMockPublisherStorage(ctrl)
+			tt.mock(mockPublisherStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				publisherStorage: mockPublisherStorage,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8443/v1/publishers", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.CreatePublisher(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("CreatePublisher() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, getResponseBody(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("CreatePublisher() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_AssignBook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     gin.H
+		mock     func(p *mock_storage_publisher.MockPublisherStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			body: gin.H{"book_id": 1},
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				p.EXPECT().GetPublisherBySlug(gomock.Any(), "penguin").Return(validPublisher, nil)
+				p.EXPECT().AssignBook(gomock.Any(), int64(1), int64(1)).Return(nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "MissingBookID",
+			body: gin.H{},
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				p.EXPECT().GetPublisherBySlug(gomock.Any(), "penguin").Return(validPublisher, nil)
+			},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errBookIDIsRequired.Error()},
+		},
+		{
+			name: "BookNotFound",
+			body: gin.H{"book_id": 100},
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				p.EXPECT().GetPublisherBySlug(gomock.Any(), "penguin").Return(validPublisher, nil)
+				p.EXPECT().AssignBook(gomock.Any(), int64(1), int64(100)).Return(publisher.ErrBookIDNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": publisher.ErrBookIDNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockPublisherStorage := mock_storage_publisher.NewMockPublisherStorage(ctrl)
+			tt.mock(mockPublisherStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				publisherStorage: mockPublisherStorage,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8443/v1/publishers/penguin/books", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "slug", Value: "penguin"}}
+
+			h.AssignBook(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("AssignBook() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, getResponseBody(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("AssignBook() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_GetSales(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		query    string
+		mock     func(p *mock_storage_publisher.MockPublisherStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name:  "AllTime",
+			query: "",
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				p.EXPECT().GetSales(gomock.Any(), time.Time{}, time.Time{}).Return([]*models.PublisherSales{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:  "Range",
+			query: "?from=2024-01-01&to=2024-01-31",
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				// the to date is inclusive.
+				p.EXPECT().GetSales(gomock.Any(),
+					time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				).Return([]*models.PublisherSales{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "InvalidFrom",
+			query:    "?from=yesterday",
+			mock:     func(p *mock_storage_publisher.MockPublisherStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidFrom.Error()},
+		},
+		{
+			name:  "InternalServerError",
+			query: "",
+			mock: func(p *mock_storage_publisher.MockPublisherStorage) {
+				p.EXPECT().GetSales(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockPublisherStorage := mock_storage_publisher.NewMockPublisherStorage(ctrl)
+			tt.mock(mockPublisherStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				publisherStorage: mockPublisherStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8443/v1/publishers/sales"+tt.query, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.GetSales(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetSales() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, getResponseBody(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("GetSales() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
@@ -0,0 +1,19 @@
+package publisher
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddPublisherRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/publishers")
+
+	r.GET("/", h.GetPublishers)
+	r.POST("/", m.Authenticate(), m.Admin(), h.CreatePublisher)
+	r.GET("/sales", m.Authenticate(), m.Admin(), h.GetSales)
+	r.GET("/:slug", h.GetPublisher)
+	r.GET("/:slug/books", h.GetPublisherBooks)
+	r.POST("/:slug/books", m.Authenticate(), m.Admin(), h.AssignBook)
+	r.DELETE("/:slug/books/:book_id", m.Authenticate(), m.Admin(), h.UnassignBook)
+}
@@ -0,0 +1,147 @@
+package recommendation
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/order"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/recommendation"
+)
+
+// recommendationLimit is the maximum number of recommended books returned.
+const recommendationLimit = 10
+
+var (
+	errInternalServer = errors.New("internal error")
+	errInvalidBookID  = errors.New("invalid book id")
+	errBookNotFound   = errors.New("book not found")
+)
+
+type Handler struct {
+	recommendationStorage recommendation.RecommendationStorage
+	bookStorage           book.BookStorage
+	orderStorage          order.OrderStorage
+}
+
+// NewHandler returns a wrapper for recommendation handler.
+func NewHandler(
+	recommendationStorage recommendation.RecommendationStorage,
+	bookStorage book.BookStorage,
+	orderStorage order.OrderStorage,
+) *Handler {
+	return &Handler{
+		recommendationStorage: recommendationStorage,
+		bookStorage:           bookStorage,
+		orderStorage:          orderStorage,
+	}
+}
+
+// GetBookRecommendations fetches the books customers also bought together
+// with a book.
+func (h *Handler) GetBookRecommendations(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if _, err := h.bookStorage.GetBookByID(c.Request.Context(), bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get book by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	h.recommend(c, []int64{bookID})
+}
+
+// GetUserRecommendations fetches the books customers also bought together
+// with the books in the order history of a user.
+func (h *Handler) GetUserRecommendations(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	orders, err := h.orderStorage.GetOrderHistory(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get order history for user: %d, with error: %v", userID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	purchased := map[int64]bool{}
+	bookIDs := []int64{}
+	for _, o := range orders {
+		for _, item := range o.Items {
+			if !purchased[item.BookID] {
+				purchased[item.BookID] = true
+				bookIDs = append(bookIDs, item.BookID)
+			}
+		}
+	}
+
+	h.recommend(c, bookIDs)
+}
+
+// recommend responds with the books most often bought together with the
+// given books, ordered by their score.
+func (h *Handler) recommend(c *gin.Context, bookIDs []int64) {
+	ids, err := h.recommendationStorage.GetRecommendedBookIDs(c.Request.Context(), bookIDs, recommendationLimit)
+	if err != nil {
+		log.Printf("failed to get recommended book ids: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	books := []*models.Book{}
+	if len(ids) > 0 {
+		found, err := h.bookStorage.GetBooksByIDs(c.Request.Context(), ids)
+		if err != nil {
+			log.Printf("failed to get books by ids: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+
+		booksMap := map[int64]*models.Book{}
+		for _, b := range found {
+			booksMap[b.ID] = b
+		}
+		for _, id := range ids {
+			if b, ok := booksMap[id]; ok {
+				books = append(books, b)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"books": books,
+	})
+}
@@ -0,0 +1,307 @@
+package recommendation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	mock_storage_order "github.com/wilsonangara/simple-online-book-store/storage/sqlite/order/mock"
+	mock_storage_recommendation "github.com/wilsonangara/simple-online-book-store/storage/sqlite/recommendation/mock"
+)
+
+func mockGetRecommendedBookIDs(bookIDs []int64, res []int64, err error) func(m *mock_storage_recommendation.MockRecommendationStorage) {
+	return func(m *mock_storage_recommendation.MockRecommendationStorage) {
+		m.
+			EXPECT().
+			GetRecommendedBookIDs(
+				gomock.Any(), // context
+				bookIDs,
+				recommendationLimit,
+			).
+			Return(res, err)
+	}
+}
+
+func mockGetBooksByIDs(res []*models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+	return func(m *mock_storage_book.MockBookStorage) {
+		m.
+			EXPECT().
+			GetBooksByIDs(
+				gomock.Any(), // context
+				gomock.Any(), // book ids
+			).
+			Return(res, err)
+	}
+}
+
+func Test_GetBookRecommendations(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/books/1/recommendations"
+	)
+
+	mockGetBookByID := func(res *models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+		return func(m *mock_storage_book.MockBookStorage) {
+			m.
+				EXPECT().
+				GetBookByID(
+					gomock.Any(), // context
+					int64(1),
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name               string
+		bookID             string
+		mockBook           []func(m *mock_storage_book.MockBookStorage)
+		mockRecommendation func(m *mock_storage_recommendation.MockRecommendationStorage)
+		wantCode           int
+		wantIDs            []int64
+		wantErr            gin.H
+	}{
+		{
+			name:   "Success",
+			bookID: "1",
+			mockBook: []func(m *mock_storage_book.MockBookStorage){
+				mockGetBookByID(&models.Book{ID: 1}, nil),
+				// books are fetched out of order and returned by score.
+				mockGetBooksByIDs([]*models.Book{{ID: 2}, {ID: 3}}, nil),
+			},
+			mockRecommendation: mockGetRecommendedBookIDs([]int64{1}, []int64{3, 2}, nil),
+			wantCode:           http.StatusOK,
+			wantIDs:            []int64{3, 2},
+		},
+		{
+			name:     "InvalidBookID",
+			bookID:   "abc",
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidBookID.Error(),
+			},
+		},
+		{
+			name:   "BookNotFound",
+			bookID: "1",
+			mockBook: []func(m *mock_storage_book.MockBookStorage){
+				mockGetBookByID(nil, sqlite.ErrNotFound),
+			},
+			wantCode: http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errBookNotFound.Error(),
+			},
+		},
+		{
+			name:   "GetRecommendedBookIDsDatabaseOperationFailed",
+			bookID: "1",
+			mockBook: []func(m *mock_storage_book.MockBookStorage){
+				mockGetBookByID(&models.Book{ID: 1}, nil),
+			},
+			mockRecommendation: mockGetRecommendedBookIDs([]int64{1}, nil, errors.New("failed to execute GetRecommendedBookIDs operation")),
+			wantCode:           http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			for _, mock := range tt.mockBook {
+				mock(mockStorageBook)
+			}
+
+			mockStorageRecommendation := mock_storage_recommendation.NewMockRecommendationStorage(ctrl)
+			if tt.mockRecommendation != nil {
+				tt.mockRecommendation(mockStorageRecommendation)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage:           mockStorageBook,
+				recommendationStorage: mockStorageRecommendation,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.bookID}}
+
+			h.GetBookRecommendations(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetBookRecommendations() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetBookRecommendations() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantIDs, getBookIDs(t, w.Body.Bytes())); diff != "" {
+				t.Fatalf("GetBookRecommendations() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_GetUserRecommendations(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/users/me/recommendations"
+	)
+
+	validUser := &models.User{
+		ID:       1,
+		Email:    genString(),
+		Password: genString(),
+	}
+
+	mockGetOrderHistory := func(res []*models.OrderHistory, err error) func(m *mock_storage_order.MockOrderStorage) {
+		return func(m *mock_storage_order.MockOrderStorage) {
+			m.
+				EXPECT().
+				GetOrderHistory(
+					gomock.Any(), // context
+					validUser.ID,
+				).
+				Return(res, err)
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+		mockGetOrderHistory([]*models.OrderHistory{
+			{ID: 1, Items: []*models.OrderHistoryItem{{BookID: 1}, {BookID: 2}}},
+			{ID: 2, Items: []*models.OrderHistoryItem{{BookID: 1}}},
+		}, nil)(mockStorageOrder)
+
+		// every purchased book is used once to look up recommendations.
+		mockStorageRecommendation := mock_storage_recommendation.NewMockRecommendationStorage(ctrl)
+		mockGetRecommendedBookIDs([]int64{1, 2}, []int64{3}, nil)(mockStorageRecommendation)
+
+		mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+		mockGetBooksByIDs([]*models.Book{{ID: 3}}, nil)(mockStorageBook)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			bookStorage:           mockStorageBook,
+			orderStorage:          mockStorageOrder,
+			recommendationStorage: mockStorageRecommendation,
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint, nil)
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.GetUserRecommendations(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("GetUserRecommendations() error, got status code = %v, want = %v", res.StatusCode, http.StatusOK)
+		}
+
+		if diff := cmp.Diff([]int64{3}, getBookIDs(t, w.Body.Bytes())); diff != "" {
+			t.Fatalf("GetUserRecommendations() mismatch (-want+got):\n%s", diff)
+		}
+	})
+
+	t.Run("Failed_GetOrderHistoryDatabaseOperationFailed", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+		mockGetOrderHistory(nil, errors.New("failed to execute GetOrderHistory operation"))(mockStorageOrder)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			orderStorage: mockStorageOrder,
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint, nil)
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.GetUserRecommendations(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("GetUserRecommendations() error, got status code = %v, want = %v", res.StatusCode, http.StatusInternalServerError)
+		}
+	})
+}
+
+// getBookIDs unmarshals the books of the response body returning their ids.
+func getBookIDs(t testing.TB, data []byte) []int64 {
+	t.Helper()
+	var resBody struct {
+		Books []*models.Book `json:"books"`
+	}
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+
+	ids := []int64{}
+	for _, b := range resBody.Books {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
+
+func genString() string {
+	return uuid.New().String()
+}
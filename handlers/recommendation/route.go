@@ -0,0 +1,12 @@
+package recommendation
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddRecommendationRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	rg.GET("/books/:id/recommendations", h.GetBookRecommendations)
+	rg.GET("/users/me/recommendations", m.Authenticate(), h.GetUserRecommendations)
+}
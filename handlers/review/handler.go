@@ -0,0 +1,284 @@
+package review
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/review"
+)
+
+const (
+	minRating = 1
+	maxRating = 5
+)
+
+var (
+	errInternalServer  = errors.New("internal error")
+	errInvalidBookID   = errors.New("invalid book id")
+	errInvalidRating   = errors.New("rating must be between 1 and 5")
+	errBookNotFound    = errors.New("book not found")
+	errReviewNotFound  = errors.New("review not found")
+	errAlreadyReviewed = errors.New("book already reviewed")
+	errNothingToUpdate = errors.New("rating or body is required")
+)
+
+type Handler struct {
+	reviewStorage review.ReviewStorage
+	bookStorage   book.BookStorage
+}
+
+// NewHandler returns a wrapper for review handler.
+func NewHandler(reviewStorage review.ReviewStorage, bookStorage book.BookStorage) *Handler {
+	return &Handler{
+		reviewStorage: reviewStorage,
+		bookStorage:   bookStorage,
+	}
+}
+
+type CreateReviewRequest struct {
+	Rating int64  `json:"rating"`
+	Body   string `json:"body"`
+}
+
+// UpdateReviewRequest only changes the fields that are given.
+type UpdateReviewRequest struct {
+	Rating *int64  `json:"rating"`
+	Body   *string `json:"body"`
+}
+
+// GetReviews fetches all the reviews of a book.
+func (h *Handler) GetReviews(c *gin.Context) {
+	bookID, ok := h.getBookID(c)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.reviewStorage.GetReviewsByBookID(c.Request.Context(), bookID)
+	if err != nil {
+		log.Printf("failed to get reviews of book %d: %v", bookID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+	})
+}
+
+// CreateReview lets a user review a book, a user can only review a book
+// once.
+func (h *Handler) CreateReview(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	bookID, ok := h.getBookID(c)
+	if !ok {
+		return
+	}
+
+	r := &CreateReviewRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.Rating < minRating || r.Rating > maxRating {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidRating.Error(),
+		})
+		return
+	}
+
+	createdReview, err := h.reviewStorage.Create(c.Request.Context(), &models.Review{
+		BookID: bookID,
+		UserID: userID,
+		Rating: r.Rating,
+		Body:   r.Body,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, review.ErrReviewAlreadyExist):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"message": errAlreadyReviewed.Error(),
+			})
+		case errors.Is(err, review.ErrBookIDNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+		default:
+			log.Printf("failed to create review: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"review": createdReview,
+	})
+}
+
+// UpdateReview lets a user change the rating or body of their review of a
+// book.
+func (h *Handler) UpdateReview(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	bookID, ok := h.getBookID(c)
+	if !ok {
+		return
+	}
+
+	r := &UpdateReviewRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.Rating == nil && r.Body == nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errNothingToUpdate.Error(),
+		})
+		return
+	}
+	if r.Rating != nil && (*r.Rating < minRating || *r.Rating > maxRating) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidRating.Error(),
+		})
+		return
+	}
+
+	foundReview, err := h.reviewStorage.GetReview(c.Request.Context(), bookID, userID)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errReviewNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get review: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	if r.Rating != nil {
+		foundReview.Rating = *r.Rating
+	}
+	if r.Body != nil {
+		foundReview.Body = *r.Body
+	}
+
+	updatedReview, err := h.reviewStorage.Update(c.Request.Context(), foundReview)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errReviewNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to update review: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"review": updatedReview,
+	})
+}
+
+// DeleteReview lets a user remove their review of a book.
+func (h *Handler) DeleteReview(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if err := h.reviewStorage.Delete(c.Request.Context(), bookID, userID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errReviewNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to delete review: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// getBookID parses the book id from the path and makes sure the book exists,
+// aborting the request otherwise.
+func (h *Handler) getBookID(c *gin.Context) (int64, bool) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return 0, false
+	}
+
+	if _, err := h.bookStorage.GetBookByID(c.Request.Context(), bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return 0, false
+		}
+		log.Printf("failed to get book by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return 0, false
+	}
+
+	return bookID, true
+}
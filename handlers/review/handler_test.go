@@ -0,0 +1,520 @@
+package review
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/review"
+	mock_storage_review "github.com/wilsonangara/simple-online-book-store/storage/sqlite/review/mock"
+)
+
+var (
+	validBookID = int64(1)
+
+	validUser = &models.User{
+		ID:       1,
+		Email:    genString(),
+		Password: genString(),
+	}
+
+	validReview = &models.Review{
+		ID:               1,
+		BookID:           validBookID,
+		UserID:           1,
+		Rating:           4,
+		Body:             genString(),
+		VerifiedPurchase: true,
+	}
+)
+
+func mockGetBookByID(res *models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+	return func(m *mock_storage_book.MockBookStorage) {
+		m.
+			EXPECT().
+			GetBookByID(
+				gomock.Any(), // context
+				gomock.Any(), // book id
+			).
+			Return(res, err)
+	}
+}
+
+func Test_GetReviews(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/books/1/reviews"
+	)
+
+	mockGetReviewsByBookID := func(res []*models.Review, err error) func(m *mock_storage_review.MockReviewStorage) {
+		return func(m *mock_storage_review.MockReviewStorage) {
+			m.
+				EXPECT().
+				GetReviewsByBookID(
+					gomock.Any(), // context
+					validBookID,
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		bookID     string
+		mockBook   func(m *mock_storage_book.MockBookStorage)
+		mockReview func(m *mock_storage_review.MockReviewStorage)
+		wantCode   int
+		wantErr    gin.H
+	}{
+		{
+			name:       "Success",
+			bookID:     "1",
+			mockBook:   mockGetBookByID(&models.Book{ID: validBookID}, nil),
+			mockReview: mockGetReviewsByBookID([]*models.Review{validReview}, nil),
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:     "InvalidBookID",
+			bookID:   "abc",
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidBookID.Error(),
+			},
+		},
+		{
+			name:     "BookNotFound",
+			bookID:   "1",
+			mockBook: mockGetBookByID(nil, sqlite.ErrNotFound),
+			wantCode: http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errBookNotFound.Error(),
+			},
+		},
+		{
+			name:       "GetReviewsDatabaseOperationFailed",
+			bookID:     "1",
+			mockBook:   mockGetBookByID(&models.Book{ID: validBookID}, nil),
+			mockReview: mockGetReviewsByBookID(nil, errors.New("failed to execute GetReviewsByBookID operation")),
+			wantCode:   http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			if tt.mockBook != nil {
+				tt.mockBook(mockStorageBook)
+			}
+
+			mockStorageReview := mock_storage_review.NewMockReviewStorage(ctrl)
+			if tt.mockReview != nil {
+				tt.mockReview(mockStorageReview)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage:   mockStorageBook,
+				reviewStorage: mockStorageReview,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.bookID}}
+
+			h.GetReviews(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetReviews() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetReviews() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_CreateReview(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodPost
+		validEndpoint = "http://localhost:8443/v1/books/1/reviews"
+	)
+
+	mockCreate := func(res *models.Review, err error) func(m *mock_storage_review.MockReviewStorage) {
+		return func(m *mock_storage_review.MockReviewStorage) {
+			m.
+				EXPECT().
+				Create(
+					gomock.Any(), // context
+					gomock.Any(), // review
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        string
+		mockReview func(m *mock_storage_review.MockReviewStorage)
+		wantCode   int
+		wantErr    gin.H
+	}{
+		{
+			name:       "Success",
+			req:        `{"rating": 4, "body": "great read"}`,
+			mockReview: mockCreate(validReview, nil),
+			wantCode:   http.StatusCreated,
+		},
+		{
+			name:     "InvalidRating",
+			req:      `{"rating": 6}`,
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidRating.Error(),
+			},
+		},
+		{
+			name:       "AlreadyReviewed",
+			req:        `{"rating": 4}`,
+			mockReview: mockCreate(nil, review.ErrReviewAlreadyExist),
+			wantCode:   http.StatusConflict,
+			wantErr: gin.H{
+				"message": errAlreadyReviewed.Error(),
+			},
+		},
+		{
+			name:       "CreateDatabaseOperationFailed",
+			req:        `{"rating": 4}`,
+			mockReview: mockCreate(nil, errors.New("failed to execute Create operation")),
+			wantCode:   http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			mockGetBookByID(&models.Book{ID: validBookID}, nil)(mockStorageBook)
+
+			mockStorageReview := mock_storage_review.NewMockReviewStorage(ctrl)
+			if tt.mockReview != nil {
+				tt.mockReview(mockStorageReview)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage:   mockStorageBook,
+				reviewStorage: mockStorageReview,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			testCtx.Set("user", validUser)
+
+			h.CreateReview(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("CreateReview() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("CreateReview() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_UpdateReview(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodPatch
+		validEndpoint = "http://localhost:8443/v1/books/1/reviews"
+	)
+
+	mockGetReview := func(res *models.Review, err error) func(m *mock_storage_review.MockReviewStorage) {
+		return func(m *mock_storage_review.MockReviewStorage) {
+			m.
+				EXPECT().
+				GetReview(
+					gomock.Any(), // context
+					validBookID,
+					validUser.ID,
+				).
+				Return(res, err)
+		}
+	}
+
+	mockUpdate := func(res *models.Review, err error) func(m *mock_storage_review.MockReviewStorage) {
+		return func(m *mock_storage_review.MockReviewStorage) {
+			m.
+				EXPECT().
+				Update(
+					gomock.Any(), // context
+					gomock.Any(), // review
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        string
+		mockReview []func(m *mock_storage_review.MockReviewStorage)
+		wantCode   int
+		wantErr    gin.H
+	}{
+		{
+			name: "Success",
+			req:  `{"rating": 5}`,
+			mockReview: []func(m *mock_storage_review.MockReviewStorage){
+				mockGetReview(&models.Review{BookID: validBookID, UserID: validUser.ID, Rating: 4}, nil),
+				mockUpdate(validReview, nil),
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "NothingToUpdate",
+			req:      `{}`,
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errNothingToUpdate.Error(),
+			},
+		},
+		{
+			name:     "InvalidRating",
+			req:      `{"rating": 0}`,
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidRating.Error(),
+			},
+		},
+		{
+			name: "ReviewNotFound",
+			req:  `{"body": "changed my mind"}`,
+			mockReview: []func(m *mock_storage_review.MockReviewStorage){
+				mockGetReview(nil, sqlite.ErrNotFound),
+			},
+			wantCode: http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errReviewNotFound.Error(),
+			},
+		},
+		{
+			name: "UpdateDatabaseOperationFailed",
+			req:  `{"rating": 5}`,
+			mockReview: []func(m *mock_storage_review.MockReviewStorage){
+				mockGetReview(&models.Review{BookID: validBookID, UserID: validUser.ID, Rating: 4}, nil),
+				mockUpdate(nil, errors.New("failed to execute Update operation")),
+			},
+			wantCode: http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			mockGetBookByID(&models.Book{ID: validBookID}, nil)(mockStorageBook)
+
+			mockStorageReview := mock_storage_review.NewMockReviewStorage(ctrl)
+			for _, mock := range tt.mockReview {
+				mock(mockStorageReview)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				bookStorage:   mockStorageBook,
+				reviewStorage: mockStorageReview,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			testCtx.Set("user", validUser)
+
+			h.UpdateReview(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("UpdateReview() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("UpdateReview() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_DeleteReview(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodDelete
+		validEndpoint = "http://localhost:8443/v1/books/1/reviews"
+	)
+
+	mockDelete := func(err error) func(m *mock_storage_review.MockReviewStorage) {
+		return func(m *mock_storage_review.MockReviewStorage) {
+			m.
+				EXPECT().
+				Delete(
+					gomock.Any(), // context
+					validBookID,
+					validUser.ID,
+				).
+				Return(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		mockReview func(m *mock_storage_review.MockReviewStorage)
+		wantCode   int
+		wantErr    gin.H
+	}{
+		{
+			name:       "Success",
+			mockReview: mockDelete(nil),
+			wantCode:   http.StatusOK,
+			wantErr:    gin.H{},
+		},
+		{
+			name:       "ReviewNotFound",
+			mockReview: mockDelete(sqlite.ErrNotFound),
+			wantCode:   http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errReviewNotFound.Error(),
+			},
+		},
+		{
+			name:       "DeleteDatabaseOperationFailed",
+			mockReview: mockDelete(errors.New("failed to execute Delete operation")),
+			wantCode:   http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageReview := mock_storage_review.NewMockReviewStorage(ctrl)
+			tt.mockReview(mockStorageReview)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				reviewStorage: mockStorageReview,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			testCtx.Set("user", validUser)
+
+			h.DeleteReview(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("DeleteReview() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+				t.Fatalf("DeleteReview() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
+
+func genString() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,16 @@
+package review
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddReviewRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/books/:id/reviews")
+
+	r.GET("/", h.GetReviews)
+	r.POST("/", m.Authenticate(), h.CreateReview)
+	r.PATCH("/", m.Authenticate(), h.UpdateReview)
+	r.DELETE("/", m.Authenticate(), h.DeleteReview)
+}
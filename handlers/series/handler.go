@@ -0,0 +1,300 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/series"
+)
+
+var (
+	errInternalServer   = errors.New("internal error")
+	errInvalidID        = errors.New("invalid series id")
+	errSeriesNotFound   = errors.New("series not found")
+	errNameIsRequired   = errors.New("name is required")
+	errBookIDIsRequired = errors.New("book id is required")
+	errInvalidBookID    = errors.New("invalid book id")
+	errInvalidPosition  = errors.New("position must be at least 1")
+	errBookNotInSeries  = errors.New("book is not part of series")
+)
+
+type Handler struct {
+	seriesStorage series.SeriesStorage
+	bookStorage   book.BookStorage
+}
+
+// NewHandler returns a wrapper for series handler.
+func NewHandler(seriesStorage series.SeriesStorage, bookStorage book.BookStorage) *Handler {
+	return &Handler{
+		seriesStorage: seriesStorage,
+		bookStorage:   bookStorage,
+	}
+}
+
+type CreateSeriesRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type AssignBookRequest struct {
+	BookID   int64 `json:"book_id"`
+	Position int64 `json:"position"`
+}
+
+// GetSeries fetches a series along with its books in reading order.
+func (h *Handler) GetSeries(c *gin.Context) {
+	foundSeries, ok := h.getSeries(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.seriesStorage.GetSeriesEntries(c.Request.Context(), foundSeries.ID)
+	if err != nil {
+		log.Printf("failed to get books of series %d: %v", foundSeries.ID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	books, err := h.getBooks(c.Request.Context(), entries)
+	if err != nil {
+		log.Printf("failed to get books by ids: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": foundSeries,
+		"books":  books,
+	})
+}
+
+// GetNextInSeries suggests, for every series a user ordered books of, the
+// next book to read after the furthest one they ordered.
+func (h *Handler) GetNextInSeries(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	entries, err := h.seriesStorage.GetNextInSeries(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get next in series of user %d: %v", userID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	books, err := h.getBooks(c.Request.Context(), entries)
+	if err != nil {
+		log.Printf("failed to get books by ids: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"books": books,
+	})
+}
+
+// CreateSeries lets an admin add a new series.
+func (h *Handler) CreateSeries(c *gin.Context) {
+	r := &CreateSeriesRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.Name == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errNameIsRequired.Error(),
+		})
+		return
+	}
+
+	createdSeries, err := h.seriesStorage.Create(c.Request.Context(), &models.Series{
+		Name:        r.Name,
+		Description: r.Description,
+	})
+	if err != nil {
+		if errors.Is(err, series.ErrNameAlreadyExist) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+		log.Printf("failed to create series: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"series": createdSeries,
+	})
+}
+
+// AssignBook lets an admin place a book at a position of a series, a book
+// belongs to a single series.
+func (h *Handler) AssignBook(c *gin.Context) {
+	foundSeries, ok := h.getSeries(c)
+	if !ok {
+		return
+	}
+
+	r := &AssignBookRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.BookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errBookIDIsRequired.Error(),
+		})
+		return
+	}
+	if r.Position < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidPosition.Error(),
+		})
+		return
+	}
+
+	if err := h.seriesStorage.AssignBook(c.Request.Context(), foundSeries.ID, r.BookID, r.Position); err != nil {
+		switch {
+		case errors.Is(err, series.ErrBookIDNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		case errors.Is(err, series.ErrPositionTaken):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		default:
+			log.Printf("failed to assign book %d to series %d: %v", r.BookID, foundSeries.ID, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// UnassignBook lets an admin remove a book from a series.
+func (h *Handler) UnassignBook(c *gin.Context) {
+	foundSeries, ok := h.getSeries(c)
+	if !ok {
+		return
+	}
+
+	bookID, err := strconv.ParseInt(c.Param("book_id"), 10, 64)
+	if err != nil || bookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if err := h.seriesStorage.UnassignBook(c.Request.Context(), foundSeries.ID, bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotInSeries.Error(),
+			})
+			return
+		}
+		log.Printf("failed to unassign book %d from series %d: %v", bookID, foundSeries.ID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// getSeries fetches the series referenced by the id path parameter,
+// aborting the request when it cannot be found.
+func (h *Handler) getSeries(c *gin.Context) (*models.Series, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return nil, false
+	}
+
+	foundSeries, err := h.seriesStorage.GetSeriesByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errSeriesNotFound.Error(),
+			})
+			return nil, false
+		}
+		log.Printf("failed to get series by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return nil, false
+	}
+	return foundSeries, true
+}
+
+// getBooks fetches the books of the given entries keeping their order.
+func (h *Handler) getBooks(ctx context.Context, entries []*models.SeriesEntry) ([]*models.Book, error) {
+	books := []*models.Book{}
+	if len(entries) < 1 {
+		return books, nil
+	}
+
+	bookIDs := []int64{}
+	for _, entry := range entries {
+		bookIDs = append(bookIDs, entry.BookID)
+	}
+
+	found, err := h.bookStorage.GetBooksByIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	booksMap := map[int64]*models.Book{}
+	for _, b := range found {
+		booksMap[b.ID] = b
+	}
+	for _, entry := range entries {
+		if b, ok := booksMap[entry.BookID]; ok {
+			books = append(books, b)
+		}
+	}
+
+	return books, nil
+}
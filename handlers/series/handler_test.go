@@ -0,0 +1,310 @@
+package series
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/series"
+	mock_storage_series "github.com/wilsonangara/simple-online-book-store/storage/sqlite/series/mock"
+)
+
+var validSeries = &models.Series{ID: 1, Name: "Foundation"}
+
+func Test_GetSeries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		id          string
+		mock        func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage)
+		wantCode    int
+		wantBookIDs []int64
+	}{
+		{
+			name: "Success",
+			id:   "1",
+			mock: func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(1)).Return(validSeries, nil)
+				s.EXPECT().GetSeriesEntries(gomock.Any(), int64(1)).Return([]*models.SeriesEntry{
+					{SeriesID: 1, BookID: 3, Position: 1},
+					{SeriesID: 1, BookID: 1, Position: 2},
+				}, nil)
+				// books come back by id, they are listed in reading order.
+				b.EXPECT().GetBooksByIDs(gomock.Any(), []int64{3, 1}).Return([]*models.Book{{ID: 1}, {ID: 3}}, nil)
+			},
+			wantCode:    http.StatusOK,
+			wantBookIDs: []int64{3, 1},
+		},
+		{
+			name: "EmptySeries",
+			id:   "1",
+			mock: func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(1)).Return(validSeries, nil)
+				s.EXPECT().GetSeriesEntries(gomock.Any(), int64(1)).Return([]*models.SeriesEntry{}, nil)
+			},
+			wantCode:    http.StatusOK,
+			wantBookIDs: []int64{},
+		},
+		{
+			name:     "InvalidID",
+			id:       "one",
+			mock:     func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage) {},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "NotFound",
+			id:   "2",
+			mock: func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(2)).Return(nil, sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "InternalServerError",
+			id:   "1",
+			mock: func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(1)).Return(validSeries, nil)
+				s.EXPECT().GetSeriesEntries(gomock.Any(), int64(1)).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSeriesStorage := mock_storage_series.NewMockSeriesStorage(ctrl)
+			mockBookStorage := mock_storage_book.NewMockBookStorage(ctrl)
+			tt.mock(mockSeriesStorage, mockBookStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				seriesStorage: mockSeriesStorage,
+				bookStorage:   mockBookStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8443/v1/series/"+tt.id, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			h.GetSeries(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetSeries() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantBookIDs != nil {
+				if diff := cmp.Diff(tt.wantBookIDs, getBookIDs(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("GetSeries() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_GetNextInSeries(t *testing.T) {
+	t.Parallel()
+
+	validUser := &models.User{ID: 1}
+
+	tests := []struct {
+		name        string
+		mock        func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage)
+		wantCode    int
+		wantBookIDs []int64
+	}{
+		{
+			name: "Success",
+			mock: func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage) {
+				s.EXPECT().GetNextInSeries(gomock.Any(), validUser.ID).Return([]*models.SeriesEntry{
+					{SeriesID: 1, BookID: 2, Position: 2},
+				}, nil)
+				b.EXPECT().GetBooksByIDs(gomock.Any(), []int64{2}).Return([]*models.Book{{ID: 2}}, nil)
+			},
+			wantCode:    http.StatusOK,
+			wantBookIDs: []int64{2},
+		},
+		{
+			name: "InternalServerError",
+			mock: func(s *mock_storage_series.MockSeriesStorage, b *mock_storage_book.MockBookStorage) {
+				s.EXPECT().GetNextInSeries(gomock.Any(), validUser.ID).Return(nil, errors.New("internal error"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSeriesStorage := mock_storage_series.NewMockSeriesStorage(ctrl)
+			mockBookStorage := mock_storage_book.NewMockBookStorage(ctrl)
+			tt.mock(mockSeriesStorage, mockBookStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				seriesStorage: mockSeriesStorage,
+				bookStorage:   mockBookStorage,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8443/v1/users/me/series/next", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Set("user", validUser)
+
+			h.GetNextInSeries(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetNextInSeries() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantBookIDs != nil {
+				if diff := cmp.Diff(tt.wantBookIDs, getBookIDs(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("GetNextInSeries() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_AssignBook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     gin.H
+		mock     func(s *mock_storage_series.MockSeriesStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name: "Success",
+			body: gin.H{"book_id": 1, "position": 2},
+			mock: func(s *mock_storage_series.MockSeriesStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(1)).Return(validSeries, nil)
+				s.EXPECT().AssignBook(gomock.Any(), int64(1), int64(1), int64(2)).Return(nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "InvalidPosition",
+			body: gin.H{"book_id": 1},
+			mock: func(s *mock_storage_series.MockSeriesStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(1)).Return(validSeries, nil)
+			},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errInvalidPosition.Error()},
+		},
+		{
+			name: "BookNotFound",
+			body: gin.H{"book_id": 100, "position": 2},
+			mock: func(s *mock_storage_series.MockSeriesStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(1)).Return(validSeries, nil)
+				s.EXPECT().AssignBook(gomock.Any(), int64(1), int64(100), int64(2)).Return(series.ErrBookIDNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": series.ErrBookIDNotFound.Error()},
+		},
+		{
+			name: "PositionTaken",
+			body: gin.H{"book_id": 1, "position": 2},
+			mock: func(s *mock_storage_series.MockSeriesStorage) {
+				s.EXPECT().GetSeriesByID(gomock.Any(), int64(1)).Return(validSeries, nil)
+				s.EXPECT().AssignBook(gomock.Any(), int64(1), int64(1), int64(2)).Return(series.ErrPositionTaken)
+			},
+			wantCode: http.StatusConflict,
+			wantErr:  gin.H{"message": series.ErrPositionTaken.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSeriesStorage := mock_storage_series.NewMockSeriesStorage(ctrl)
+			tt.mock(mockSeriesStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				seriesStorage: mockSeriesStorage,
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error when marshaling request body: %v", err)
+			}
+			r, err := http.NewRequest(http.MethodPost, "http://localhost:8443/v1/series/1/books", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			h.AssignBook(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("AssignBook() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				var resBody gin.H
+				if err := json.Unmarshal(w.Body.Bytes(), &resBody); err != nil {
+					t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+				}
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("AssignBook() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// getBookIDs unmarshals the ids of the books of a response body in order.
+func getBookIDs(t testing.TB, data []byte) []int64 {
+	t.Helper()
+
+	var resBody struct {
+		Books []*models.Book `json:"books"`
+	}
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+
+	ids := []int64{}
+	for _, b := range resBody.Books {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
@@ -0,0 +1,18 @@
+package series
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddSeriesRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/series")
+
+	r.POST("/", m.Authenticate(), m.Admin(), h.CreateSeries)
+	r.GET("/:id", h.GetSeries)
+	r.POST("/:id/books", m.Authenticate(), m.Admin(), h.AssignBook)
+	r.DELETE("/:id/books/:book_id", m.Authenticate(), m.Admin(), h.UnassignBook)
+
+	rg.GET("/users/me/series/next", m.Authenticate(), h.GetNextInSeries)
+}
@@ -0,0 +1,174 @@
+package similarity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/similarity"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+)
+
+// similarLimit is the maximum number of similar books returned.
+const similarLimit = 10
+
+var (
+	errInternalServer = errors.New("internal error")
+	errInvalidBookID  = errors.New("invalid book id")
+	errBookNotFound   = errors.New("book not found")
+)
+
+type Handler struct {
+	bookStorage book.BookStorage
+
+	// mu guards the index and the catalog version it was built from.
+	mu      sync.RWMutex
+	index   *similarity.Index
+	version int64
+}
+
+// NewHandler returns a wrapper for similarity handler, the index is empty
+// until it is first refreshed.
+func NewHandler(bookStorage book.BookStorage) *Handler {
+	return &Handler{
+		bookStorage: bookStorage,
+		index:       similarity.NewIndex(nil),
+	}
+}
+
+// SimilarBook is a book similar to the requested one, scored between 0 and 1.
+type SimilarBook struct {
+	Book  *models.Book `json:"book"`
+	Score float64      `json:"score"`
+}
+
+// Refresh rebuilds the similarity index from the title, authors and
+// description of every book, only when the catalog changed since the index
+// was last built.
+func (h *Handler) Refresh(ctx context.Context) error {
+	version, err := h.bookStorage.GetCatalogVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	upToDate := h.version == version
+	h.mu.RUnlock()
+	if upToDate {
+		return nil
+	}
+
+	books, err := h.bookStorage.GetBooks(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	docs := []similarity.Document{}
+	for _, b := range books {
+		docs = append(docs, similarity.Document{
+			ID:   b.ID,
+			Text: bookText(b),
+		})
+	}
+	index := similarity.NewIndex(docs)
+
+	h.mu.Lock()
+	h.index = index
+	h.version = version
+	h.mu.Unlock()
+
+	log.Printf("rebuilt similarity index of %d books at catalog version %d", len(docs), version)
+	return nil
+}
+
+// GetSimilarBooks fetches the books whose content is most similar to a book.
+func (h *Handler) GetSimilarBooks(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if _, err := h.bookStorage.GetBookByID(c.Request.Context(), bookID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to get book by id: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	h.mu.RLock()
+	matches := h.index.Similar(bookID, similarLimit)
+	h.mu.RUnlock()
+
+	similarBooks := []*SimilarBook{}
+	if len(matches) > 0 {
+		ids := []int64{}
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+
+		books, err := h.bookStorage.GetBooksByIDs(c.Request.Context(), ids)
+		if err != nil {
+			log.Printf("failed to get books by ids: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+
+		booksMap := map[int64]*models.Book{}
+		for _, b := range books {
+			booksMap[b.ID] = b
+		}
+
+		// books removed since the index was built are skipped.
+		for _, m := range matches {
+			if b, ok := booksMap[m.ID]; ok {
+				similarBooks = append(similarBooks, &SimilarBook{
+					Book:  b,
+					Score: math.Round(m.Score*10000) / 10000,
+				})
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"books": similarBooks,
+	})
+}
+
+// bookText joins the text of a book the similarity is computed over, its
+// translators and illustrators are left out.
+func bookText(b *models.Book) string {
+	authors := []string{}
+	for _, a := range b.Authors {
+		if a.Role == "author" {
+			authors = append(authors, a.Name)
+		}
+	}
+	// books not credited to any author yet fall back to their author
+	// column.
+	if len(authors) == 0 {
+		authors = append(authors, b.Author)
+	}
+	return fmt.Sprintf("%s %s %s", b.Title, strings.Join(authors, " "), b.Description)
+}
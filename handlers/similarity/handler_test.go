@@ -0,0 +1,266 @@
+package similarity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+)
+
+var validBooks = []*models.Book{
+	{ID: 1, Title: "Atomic Habits", Author: "James Clear", Description: "building good habits"},
+	{ID: 2, Title: "The Power of Habit", Author: "Charles Duhigg", Description: "why habits exist"},
+	{ID: 3, Title: "Dune", Author: "Frank Herbert", Description: "a desert planet"},
+}
+
+func Test_Refresh(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+
+	mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+	gomock.InOrder(
+		mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(1), nil),
+		mockStorageBook.EXPECT().GetBooks(gomock.Any(), nil).Return(validBooks, nil),
+		// the index is not rebuilt while the catalog stays the same.
+		mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(1), nil),
+		mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(2), nil),
+		mockStorageBook.EXPECT().GetBooks(gomock.Any(), nil).Return(nil, errors.New("failed to execute GetBooks operation")),
+	)
+
+	h := NewHandler(mockStorageBook)
+
+	if err := h.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh(_) expected nil error, got = %v", err)
+	}
+	if got := h.index.Similar(1, similarLimit); len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("Refresh(_) error, got similar books = %v, want = %v", got, 2)
+	}
+
+	if err := h.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh(_) expected nil error, got = %v", err)
+	}
+
+	// a failed rebuild keeps serving the previous index.
+	if err := h.Refresh(ctx); err == nil {
+		t.Fatalf("Refresh(_) expected error, got = %v", err)
+	}
+	if h.version != 1 {
+		t.Fatalf("Refresh(_) error, got version = %v, want = %v", h.version, 1)
+	}
+}
+
+func Test_bookText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		book *models.Book
+		want string
+	}{
+		{
+			name: "Authors",
+			book: &models.Book{
+				Title:  "Dune",
+				Author: "Frank Herbert",
+				Authors: []*models.BookAuthor{
+					{Name: "Frank Herbert", Role: "author"},
+					{Name: "John Schoenherr", Role: "illustrator"},
+				},
+				Description: "a desert planet",
+			},
+			want: "Dune Frank Herbert a desert planet",
+		},
+		{
+			name: "NoAuthors",
+			book: &models.Book{
+				Title:       "Dune",
+				Author:      "Frank Herbert",
+				Description: "a desert planet",
+			},
+			want: "Dune Frank Herbert a desert planet",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := bookText(tt.book); got != tt.want {
+				t.Fatalf("bookText() error, got = %q, want = %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GetSimilarBooks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/books/1/similar"
+	)
+
+	mockGetBookByID := func(res *models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+		return func(m *mock_storage_book.MockBookStorage) {
+			m.
+				EXPECT().
+				GetBookByID(
+					gomock.Any(), // context
+					int64(1),
+				).
+				Return(res, err)
+		}
+	}
+
+	mockGetBooksByIDs := func(res []*models.Book, err error) func(m *mock_storage_book.MockBookStorage) {
+		return func(m *mock_storage_book.MockBookStorage) {
+			m.
+				EXPECT().
+				GetBooksByIDs(
+					gomock.Any(), // context
+					[]int64{2},
+				).
+				Return(res, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		bookID   string
+		mockBook []func(m *mock_storage_book.MockBookStorage)
+		wantCode int
+		wantIDs  []int64
+		wantErr  gin.H
+	}{
+		{
+			name:   "Success",
+			bookID: "1",
+			mockBook: []func(m *mock_storage_book.MockBookStorage){
+				mockGetBookByID(validBooks[0], nil),
+				mockGetBooksByIDs([]*models.Book{validBooks[1]}, nil),
+			},
+			wantCode: http.StatusOK,
+			wantIDs:  []int64{2},
+		},
+		{
+			name:     "InvalidBookID",
+			bookID:   "abc",
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidBookID.Error(),
+			},
+		},
+		{
+			name:   "BookNotFound",
+			bookID: "1",
+			mockBook: []func(m *mock_storage_book.MockBookStorage){
+				mockGetBookByID(nil, sqlite.ErrNotFound),
+			},
+			wantCode: http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errBookNotFound.Error(),
+			},
+		},
+		{
+			name:   "GetBooksByIDsDatabaseOperationFailed",
+			bookID: "1",
+			mockBook: []func(m *mock_storage_book.MockBookStorage){
+				mockGetBookByID(validBooks[0], nil),
+				mockGetBooksByIDs(nil, errors.New("failed to execute GetBooksByIDs operation")),
+			},
+			wantCode: http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(1), nil)
+			mockStorageBook.EXPECT().GetBooks(gomock.Any(), nil).Return(validBooks, nil)
+			for _, mock := range tt.mockBook {
+				mock(mockStorageBook)
+			}
+
+			h := NewHandler(mockStorageBook)
+			if err := h.Refresh(context.Background()); err != nil {
+				t.Fatalf("unexpected error when refreshing similarity index: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+
+			r, err := http.NewRequest(validMethod, validEndpoint, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.bookID}}
+
+			h.GetSimilarBooks(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetSimilarBooks() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetSimilarBooks() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+
+			var resBody struct {
+				Books []*SimilarBook `json:"books"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resBody); err != nil {
+				t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+			}
+
+			gotIDs := []int64{}
+			for _, b := range resBody.Books {
+				if b.Score <= 0 {
+					t.Fatalf("GetSimilarBooks() error, got score = %v, want > 0", b.Score)
+				}
+				gotIDs = append(gotIDs, b.Book.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Fatalf("GetSimilarBooks() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
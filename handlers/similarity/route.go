@@ -0,0 +1,7 @@
+package similarity
+
+import "github.com/gin-gonic/gin"
+
+func (h *Handler) AddSimilarityRoutes(rg *gin.RouterGroup) {
+	rg.GET("/books/:id/similar", h.GetSimilarBooks)
+}
@@ -0,0 +1,172 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/order"
+	"github.com/wilsonangara/simple-online-book-store/suggest"
+)
+
+const (
+	// suggestLimit is the maximum number of suggestions returned.
+	suggestLimit = 10
+
+	// maxQueryLength is the longest query in characters suggestions are
+	// searched for.
+	maxQueryLength = 100
+
+	suggestionTitle  = "title"
+	suggestionAuthor = "author"
+)
+
+var errInvalidQuery = errors.New("invalid query")
+
+type Handler struct {
+	bookStorage  book.BookStorage
+	orderStorage order.OrderStorage
+
+	// mu guards the index and the catalog version and sales it was built
+	// from.
+	mu      sync.RWMutex
+	index   *suggest.Index
+	version int64
+	sales   map[int64]int64
+}
+
+// NewHandler returns a wrapper for suggest handler, the index is empty until
+// it is first refreshed.
+func NewHandler(bookStorage book.BookStorage, orderStorage order.OrderStorage) *Handler {
+	return &Handler{
+		bookStorage:  bookStorage,
+		orderStorage: orderStorage,
+		index:        suggest.NewIndex(nil),
+	}
+}
+
+// Suggestion is the title of a book or the name of an author starting with
+// the query, ID is the id of the book or of the author.
+type Suggestion struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+}
+
+// Refresh rebuilds the suggestion index from the titles and authors of every
+// book, ranked by the units sold of their books, only when the catalog or
+// the sales changed since the index was last built.
+func (h *Handler) Refresh(ctx context.Context) error {
+	version, err := h.bookStorage.GetCatalogVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	sales, err := h.orderStorage.GetBookSales(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	upToDate := h.version == version && sameSales(h.sales, sales)
+	h.mu.RUnlock()
+	if upToDate {
+		return nil
+	}
+
+	books, err := h.bookStorage.GetBooks(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	entries := []suggest.Entry{}
+	authors := map[int64]*suggest.Entry{}
+	for _, b := range books {
+		entries = append(entries, suggest.Entry{
+			Kind:       suggestionTitle,
+			ID:         b.ID,
+			Text:       b.Title,
+			Popularity: sales[b.ID],
+		})
+
+		// an author is as popular as all of their books together.
+		for _, a := range b.Authors {
+			if a.Role != "author" {
+				continue
+			}
+			if _, ok := authors[a.AuthorID]; !ok {
+				authors[a.AuthorID] = &suggest.Entry{
+					Kind: suggestionAuthor,
+					ID:   a.AuthorID,
+					Text: a.Name,
+				}
+			}
+			authors[a.AuthorID].Popularity += sales[b.ID]
+		}
+	}
+	for _, a := range authors {
+		entries = append(entries, *a)
+	}
+	index := suggest.NewIndex(entries)
+
+	h.mu.Lock()
+	h.index = index
+	h.version = version
+	h.sales = sales
+	h.mu.Unlock()
+
+	log.Printf("rebuilt suggestion index of %d titles and %d authors at catalog version %d", len(books), len(authors), version)
+	return nil
+}
+
+// GetSuggestions fetches the titles and authors starting with the query,
+// the most popular first, tolerating a few typos. Suggestions are served
+// from the index alone so they stay fast enough to fetch on every key
+// stroke.
+func (h *Handler) GetSuggestions(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" || utf8.RuneCountInString(q) > maxQueryLength {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidQuery.Error(),
+		})
+		return
+	}
+
+	h.mu.RLock()
+	matches := h.index.Suggest(q, suggestLimit)
+	h.mu.RUnlock()
+
+	suggestions := []*Suggestion{}
+	for _, m := range matches {
+		suggestions = append(suggestions, &Suggestion{
+			Type: m.Kind,
+			ID:   m.ID,
+			Text: m.Text,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suggestions": suggestions,
+	})
+}
+
+// sameSales reports whether two sales hold the same units for the same
+// books.
+func sameSales(a, b map[int64]int64) bool {
+	if a == nil || len(a) != len(b) {
+		return false
+	}
+	for id, units := range a {
+		if got, ok := b[id]; !ok || got != units {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package suggest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	mock_storage_order "github.com/wilsonangara/simple-online-book-store/storage/sqlite/order/mock"
+)
+
+var validBooks = []*models.Book{
+	{ID: 1, Title: "Atomic Habits", Authors: []*models.BookAuthor{{AuthorID: 1, Name: "James Clear", Role: "author"}}},
+	{ID: 2, Title: "The Tipping Point", Authors: []*models.BookAuthor{{AuthorID: 2, Name: "Malcolm Gladwell", Role: "author"}}},
+	{ID: 3, Title: "The Power of Habit", Authors: []*models.BookAuthor{
+		{AuthorID: 3, Name: "Charles Duhigg", Role: "author"},
+		{AuthorID: 4, Name: "Mike Chamberlain", Role: "narrator"},
+	}},
+}
+
+func Test_Refresh(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+
+	mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+	mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+	gomock.InOrder(
+		mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(1), nil),
+		mockStorageOrder.EXPECT().GetBookSales(gomock.Any()).Return(map[int64]int64{3: 1}, nil),
+		mockStorageBook.EXPECT().GetBooks(gomock.Any(), nil).Return(validBooks, nil),
+		// the index is not rebuilt while the catalog and sales stay the
+		// same.
+		mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(1), nil),
+		mockStorageOrder.EXPECT().GetBookSales(gomock.Any()).Return(map[int64]int64{3: 1}, nil),
+		// new sales rerank the index.
+		mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(1), nil),
+		mockStorageOrder.EXPECT().GetBookSales(gomock.Any()).Return(map[int64]int64{2: 3}, nil),
+		mockStorageBook.EXPECT().GetBooks(gomock.Any(), nil).Return(validBooks, nil),
+		mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(2), nil),
+		mockStorageOrder.EXPECT().GetBookSales(gomock.Any()).Return(map[int64]int64{2: 3}, nil),
+		mockStorageBook.EXPECT().GetBooks(gomock.Any(), nil).Return(nil, errors.New("failed to execute GetBooks operation")),
+	)
+
+	h := NewHandler(mockStorageBook, mockStorageOrder)
+
+	if err := h.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh(_) expected nil error, got = %v", err)
+	}
+	if got := h.index.Suggest("the", suggestLimit); len(got) != 2 || got[0].ID != 3 {
+		t.Fatalf("Refresh(_) error, got suggestions = %v, want first = %v", got, 3)
+	}
+
+	if err := h.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh(_) expected nil error, got = %v", err)
+	}
+
+	if err := h.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh(_) expected nil error, got = %v", err)
+	}
+	if got := h.index.Suggest("the", suggestLimit); len(got) != 2 || got[0].ID != 2 {
+		t.Fatalf("Refresh(_) error, got suggestions = %v, want first = %v", got, 2)
+	}
+
+	// a failed rebuild keeps serving the previous index.
+	if err := h.Refresh(ctx); err == nil {
+		t.Fatalf("Refresh(_) expected error, got = %v", err)
+	}
+	if h.version != 1 {
+		t.Fatalf("Refresh(_) error, got version = %v, want = %v", h.version, 1)
+	}
+}
+
+func Test_GetSuggestions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	validMethod := http.MethodGet
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantErr  gin.H
+		want     []*Suggestion
+	}{
+		{
+			name:     "Success",
+			query:    "ha",
+			wantCode: http.StatusOK,
+			want: []*Suggestion{
+				{Type: suggestionTitle, ID: 1, Text: "Atomic Habits"},
+				{Type: suggestionTitle, ID: 3, Text: "The Power of Habit"},
+			},
+		},
+		{
+			name:     "Authors",
+			query:    "char",
+			wantCode: http.StatusOK,
+			want: []*Suggestion{
+				{Type: suggestionAuthor, ID: 3, Text: "Charles Duhigg"},
+			},
+		},
+		{
+			name:     "Typo",
+			query:    "tiping",
+			wantCode: http.StatusOK,
+			want: []*Suggestion{
+				{Type: suggestionTitle, ID: 2, Text: "The Tipping Point"},
+			},
+		},
+		{
+			name:     "NoSuggestions",
+			query:    "dune",
+			wantCode: http.StatusOK,
+			want:     []*Suggestion{},
+		},
+		{
+			name:     "MissingQuery",
+			query:    " ",
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidQuery.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+			mockStorageBook.EXPECT().GetCatalogVersion(gomock.Any()).Return(int64(1), nil)
+			mockStorageBook.EXPECT().GetBooks(gomock.Any(), nil).Return(validBooks, nil)
+			mockStorageOrder := mock_storage_order.NewMockOrderStorage(ctrl)
+			mockStorageOrder.EXPECT().GetBookSales(gomock.Any()).Return(map[int64]int64{1: 2, 3: 1}, nil)
+
+			h := NewHandler(mockStorageBook, mockStorageOrder)
+			if err := h.Refresh(context.Background()); err != nil {
+				t.Fatalf("unexpected error when refreshing suggestion index: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+
+			r, err := http.NewRequest(validMethod, "/v1/books/suggest?q="+url.QueryEscape(tt.query), nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			h.GetSuggestions(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("GetSuggestions() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("GetSuggestions() mismatch (-want+got):\n%s", diff)
+				}
+				return
+			}
+
+			var resBody struct {
+				Suggestions []*Suggestion `json:"suggestions"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resBody); err != nil {
+				t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, resBody.Suggestions); diff != "" {
+				t.Fatalf("GetSuggestions() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
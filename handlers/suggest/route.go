@@ -0,0 +1,7 @@
+package suggest
+
+import "github.com/gin-gonic/gin"
+
+func (h *Handler) AddSuggestRoutes(rg *gin.RouterGroup) {
+	rg.GET("/books/suggest", h.GetSuggestions)
+}
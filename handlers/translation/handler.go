@@ -0,0 +1,179 @@
+package translation
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/locale"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation"
+)
+
+var (
+	errInternalServer      = errors.New("internal error")
+	errInvalidID           = errors.New("invalid book id")
+	errBookNotFound        = errors.New("book not found")
+	errTranslationNotFound = errors.New("translation not found")
+	errTitleIsRequired     = errors.New("title is required")
+	errDefaultLocale       = errors.New("books are written in the default locale")
+)
+
+type Handler struct {
+	translationStorage translation.TranslationStorage
+
+	// defaultLocale is the locale books are written in.
+	defaultLocale string
+}
+
+// NewHandler returns a wrapper for book translation handler.
+func NewHandler(translationStorage translation.TranslationStorage, defaultLocale string) *Handler {
+	return &Handler{
+		translationStorage: translationStorage,
+		defaultLocale:      defaultLocale,
+	}
+}
+
+// GetTranslations fetches every translation of a book.
+func (h *Handler) GetTranslations(c *gin.Context) {
+	bookID, ok := getBookID(c)
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationStorage.GetTranslations(c.Request.Context(), bookID)
+	if err != nil {
+		log.Printf("failed to get book translations: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"translations": translations,
+	})
+}
+
+type SetTranslationRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// SetTranslation creates or replaces the translation of a book in the
+// locale from the path, a translation without a description shows the
+// description of the book.
+func (h *Handler) SetTranslation(c *gin.Context) {
+	bookID, ok := getBookID(c)
+	if !ok {
+		return
+	}
+	lang, ok := h.getLocale(c)
+	if !ok {
+		return
+	}
+
+	r := &SetTranslationRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+	if r.Title == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errTitleIsRequired.Error(),
+		})
+		return
+	}
+
+	set, err := h.translationStorage.SetTranslation(c.Request.Context(), &models.BookTranslation{
+		BookID:      bookID,
+		Locale:      lang,
+		Title:       r.Title,
+		Description: r.Description,
+	})
+	if err != nil {
+		if errors.Is(err, translation.ErrBookIDNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to set book translation: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"translation": set,
+	})
+}
+
+// DeleteTranslation removes the translation of a book in the locale from
+// the path.
+func (h *Handler) DeleteTranslation(c *gin.Context) {
+	bookID, ok := getBookID(c)
+	if !ok {
+		return
+	}
+	lang, ok := h.getLocale(c)
+	if !ok {
+		return
+	}
+
+	if err := h.translationStorage.DeleteTranslation(c.Request.Context(), bookID, lang); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errTranslationNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to delete book translation: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getBookID returns the book id from the path, aborting the request when it
+// is invalid.
+func getBookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidID.Error(),
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// getLocale returns the canonical locale from the path, aborting the
+// request when it is invalid or the locale books are written in.
+func (h *Handler) getLocale(c *gin.Context) (string, bool) {
+	lang, err := locale.Canonicalize(c.Param("locale"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return "", false
+	}
+	if lang == h.defaultLocale {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errDefaultLocale.Error(),
+		})
+		return "", false
+	}
+	return lang, true
+}
@@ -0,0 +1,190 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/locale"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation"
+	mock_storage_translation "github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation/mock"
+)
+
+func Test_SetTranslation(t *testing.T) {
+	t.Parallel()
+
+	validTranslation := &models.BookTranslation{BookID: 1, Locale: "pt-BR", Title: "Hábitos Atômicos"}
+
+	tests := []struct {
+		name     string
+		id       string
+		locale   string
+		body     string
+		mock     func(m *mock_storage_translation.MockTranslationStorage)
+		wantCode int
+		wantErr  gin.H
+	}{
+		{
+			name:   "Success",
+			id:     "1",
+			locale: "pt_br",
+			body:   `{"title": "Hábitos Atômicos"}`,
+			mock: func(m *mock_storage_translation.MockTranslationStorage) {
+				m.EXPECT().SetTranslation(gomock.Any(), validTranslation).Return(validTranslation, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "MissingTitle",
+			id:       "1",
+			locale:   "fr",
+			body:     `{"description": "Des habitudes"}`,
+			mock:     func(m *mock_storage_translation.MockTranslationStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errTitleIsRequired.Error()},
+		},
+		{
+			name:     "InvalidLocale",
+			id:       "1",
+			locale:   "f",
+			body:     `{"title": "Un Petit Rien"}`,
+			mock:     func(m *mock_storage_translation.MockTranslationStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": locale.ErrInvalidLocale.Error()},
+		},
+		{
+			name:     "DefaultLocale",
+			id:       "1",
+			locale:   "EN",
+			body:     `{"title": "Atomic Habits"}`,
+			mock:     func(m *mock_storage_translation.MockTranslationStorage) {},
+			wantCode: http.StatusBadRequest,
+			wantErr:  gin.H{"message": errDefaultLocale.Error()},
+		},
+		{
+			name:   "BookNotFound",
+			id:     "1000",
+			locale: "fr",
+			body:   `{"title": "Un Petit Rien"}`,
+			mock: func(m *mock_storage_translation.MockTranslationStorage) {
+				m.EXPECT().SetTranslation(gomock.Any(), gomock.Any()).Return(nil, translation.ErrBookIDNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantErr:  gin.H{"message": errBookNotFound.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockTranslationStorage := mock_storage_translation.NewMockTranslationStorage(ctrl)
+			tt.mock(mockTranslationStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				translationStorage: mockTranslationStorage,
+				defaultLocale:      "en",
+			}
+
+			r, err := http.NewRequest(http.MethodPut, "http://localhost:8443/v1/books/"+tt.id+"/translations/"+tt.locale, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: tt.id}, {Key: "locale", Value: tt.locale}}
+
+			h.SetTranslation(testCtx)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("SetTranslation() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+
+			if tt.wantErr != nil {
+				if diff := cmp.Diff(tt.wantErr, getResponseBody(t, w.Body.Bytes())); diff != "" {
+					t.Fatalf("SetTranslation() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_DeleteTranslation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mock     func(m *mock_storage_translation.MockTranslationStorage)
+		wantCode int
+	}{
+		{
+			name: "Success",
+			mock: func(m *mock_storage_translation.MockTranslationStorage) {
+				m.EXPECT().DeleteTranslation(gomock.Any(), int64(1), "fr").Return(nil)
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name: "NotFound",
+			mock: func(m *mock_storage_translation.MockTranslationStorage) {
+				m.EXPECT().DeleteTranslation(gomock.Any(), int64(1), "fr").Return(sqlite.ErrNotFound)
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockTranslationStorage := mock_storage_translation.NewMockTranslationStorage(ctrl)
+			tt.mock(mockTranslationStorage)
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				translationStorage: mockTranslationStorage,
+				defaultLocale:      "en",
+			}
+
+			r, err := http.NewRequest(http.MethodDelete, "http://localhost:8443/v1/books/1/translations/fr", nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "locale", Value: "fr"}}
+
+			h.DeleteTranslation(testCtx)
+			testCtx.Writer.WriteHeaderNow()
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("DeleteTranslation() error, got status code = %v, want = %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
@@ -0,0 +1,15 @@
+package translation
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddTranslationRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/books/:id/translations", m.Authenticate(), m.Admin())
+
+	r.GET("/", h.GetTranslations)
+	r.PUT("/:locale", h.SetTranslation)
+	r.DELETE("/:locale", h.DeleteTranslation)
+}
@@ -0,0 +1,280 @@
+package wishlist
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/handlers/order"
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/wishlist"
+)
+
+var (
+	errInternalServer           = errors.New("internal error")
+	errInvalidBookID            = errors.New("invalid book id")
+	errBookNotFound             = errors.New("book not found")
+	errItemNotFound             = errors.New("book not found in wishlist")
+	errAtLeastOneItemIsRequired = errors.New("at least 1 item is required")
+)
+
+// Orderer places orders on behalf of a user.
+type Orderer interface {
+	PlaceOrder(c *gin.Context, userID int64, r *order.OrderRequest) (*models.Order, bool)
+}
+
+type Handler struct {
+	wishlistStorage wishlist.WishlistStorage
+	bookStorage     book.BookStorage
+	orderer         Orderer
+}
+
+// NewHandler returns a wrapper for wishlist handler.
+func NewHandler(wishlistStorage wishlist.WishlistStorage, bookStorage book.BookStorage, orderer Orderer) *Handler {
+	return &Handler{
+		wishlistStorage: wishlistStorage,
+		bookStorage:     bookStorage,
+		orderer:         orderer,
+	}
+}
+
+type AddItemRequest struct {
+	BookID int64 `json:"book_id"`
+}
+
+// MoveToOrderItem selects a wishlist item to order, the quantity defaults to
+// 1 when it is not given.
+type MoveToOrderItem struct {
+	BookID   int64 `json:"book_id"`
+	Quantity int64 `json:"quantity"`
+}
+
+type MoveToOrderRequest struct {
+	Items         []*MoveToOrderItem `json:"items"`
+	ReservationID int64              `json:"reservation_id"`
+}
+
+// GetWishlist fetches all the books a user saved for later.
+func (h *Handler) GetWishlist(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	items, err := h.wishlistStorage.GetItems(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get wishlist of user %d: %v", userID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	if len(items) > 0 {
+		bookIDs := []int64{}
+		for _, item := range items {
+			bookIDs = append(bookIDs, item.BookID)
+		}
+
+		books, err := h.bookStorage.GetBooksByIDs(c.Request.Context(), bookIDs)
+		if err != nil {
+			log.Printf("failed to get books by ids: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalServer.Error(),
+			})
+			return
+		}
+
+		booksMap := map[int64]*models.Book{}
+		for _, b := range books {
+			booksMap[b.ID] = b
+		}
+		for _, item := range items {
+			item.Book = booksMap[item.BookID]
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+// AddItem lets a user save a book to their wishlist.
+func (h *Handler) AddItem(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	r := &AddItemRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if r.BookID < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if err := h.wishlistStorage.AddItem(c.Request.Context(), userID, r.BookID); err != nil {
+		if errors.Is(err, wishlist.ErrBookIDNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errBookNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to add book to wishlist: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{})
+}
+
+// RemoveItem lets a user remove a book from their wishlist.
+func (h *Handler) RemoveItem(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	bookID, err := strconv.ParseInt(c.Param("book_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errInvalidBookID.Error(),
+		})
+		return
+	}
+
+	if err := h.wishlistStorage.RemoveItems(c.Request.Context(), userID, []int64{bookID}); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"message": errItemNotFound.Error(),
+			})
+			return
+		}
+		log.Printf("failed to remove book from wishlist: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// MoveToOrder orders the selected wishlist items of a user, the ordered
+// books are removed from the wishlist once the order is placed.
+func (h *Handler) MoveToOrder(c *gin.Context) {
+	userID, err := middleware.UserID(c)
+	if err != nil {
+		log.Printf("failed to get user id from context: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	r := &MoveToOrderRequest{}
+	if err := c.BindJSON(r); err != nil {
+		log.Printf("failed to bind json: %v", err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if len(r.Items) < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": errAtLeastOneItemIsRequired.Error(),
+		})
+		return
+	}
+
+	items, err := h.wishlistStorage.GetItems(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get wishlist of user %d: %v", userID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"message": errInternalServer.Error(),
+		})
+		return
+	}
+
+	wishlisted := map[int64]bool{}
+	for _, item := range items {
+		wishlisted[item.BookID] = true
+	}
+
+	// only books saved in the wishlist can be moved to an order.
+	orderRequest := &order.OrderRequest{
+		ReservationID: r.ReservationID,
+	}
+	bookIDs := []int64{}
+	notFoundIDs := []string{}
+	for _, item := range r.Items {
+		if !wishlisted[item.BookID] {
+			notFoundIDs = append(notFoundIDs, strconv.FormatInt(item.BookID, 10))
+			continue
+		}
+
+		quantity := item.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		orderRequest.Books = append(orderRequest.Books, &order.BookRequest{
+			BookID:   item.BookID,
+			Quantity: quantity,
+		})
+		bookIDs = append(bookIDs, item.BookID)
+	}
+	if len(notFoundIDs) > 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("books with ids: [%s] not found in wishlist", strings.Join(notFoundIDs, ", ")),
+		})
+		return
+	}
+
+	newOrder, ok := h.orderer.PlaceOrder(c, userID, orderRequest)
+	if !ok {
+		return
+	}
+
+	// the order is already placed, a wishlist that could not be cleaned up
+	// should not fail the request.
+	if err := h.wishlistStorage.RemoveItems(c.Request.Context(), userID, bookIDs); err != nil {
+		log.Printf("failed to remove ordered books from wishlist: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": newOrder.ID,
+		"total":    newOrder.Total,
+	})
+}
@@ -0,0 +1,476 @@
+package wishlist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/handlers/order"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	mock_storage_book "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book/mock"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/wishlist"
+	mock_storage_wishlist "github.com/wilsonangara/simple-online-book-store/storage/sqlite/wishlist/mock"
+)
+
+var validUser = &models.User{
+	ID:       1,
+	Email:    genString(),
+	Password: genString(),
+}
+
+// fakeOrderer records the order request it was asked to place.
+type fakeOrderer struct {
+	got   *order.OrderRequest
+	order *models.Order
+}
+
+func (o *fakeOrderer) PlaceOrder(c *gin.Context, userID int64, r *order.OrderRequest) (*models.Order, bool) {
+	o.got = r
+	if o.order == nil {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"message": "insufficient stock",
+		})
+		return nil, false
+	}
+	return o.order, true
+}
+
+func mockGetItems(res []*models.WishlistItem, err error) func(m *mock_storage_wishlist.MockWishlistStorage) {
+	return func(m *mock_storage_wishlist.MockWishlistStorage) {
+		m.
+			EXPECT().
+			GetItems(
+				gomock.Any(), // context
+				validUser.ID,
+			).
+			Return(res, err)
+	}
+}
+
+func mockRemoveItems(err error) func(m *mock_storage_wishlist.MockWishlistStorage) {
+	return func(m *mock_storage_wishlist.MockWishlistStorage) {
+		m.
+			EXPECT().
+			RemoveItems(
+				gomock.Any(), // context
+				validUser.ID,
+				gomock.Any(), // book ids
+			).
+			Return(err)
+	}
+}
+
+func Test_GetWishlist(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodGet
+		validEndpoint = "http://localhost:8443/v1/users/me/wishlist"
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageWishlist := mock_storage_wishlist.NewMockWishlistStorage(ctrl)
+		mockGetItems([]*models.WishlistItem{{BookID: 1}}, nil)(mockStorageWishlist)
+
+		mockStorageBook := mock_storage_book.NewMockBookStorage(ctrl)
+		mockStorageBook.
+			EXPECT().
+			GetBooksByIDs(gomock.Any(), []int64{1}).
+			Return([]*models.Book{{ID: 1, Title: genString()}}, nil)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			wishlistStorage: mockStorageWishlist,
+			bookStorage:     mockStorageBook,
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint, nil)
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.GetWishlist(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("GetWishlist() error, got status code = %v, want = %v", res.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("Failed_GetItemsDatabaseOperationFailed", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorageWishlist := mock_storage_wishlist.NewMockWishlistStorage(ctrl)
+		mockGetItems(nil, errors.New("failed to execute GetItems operation"))(mockStorageWishlist)
+
+		w := httptest.NewRecorder()
+		h := &Handler{
+			wishlistStorage: mockStorageWishlist,
+		}
+
+		r, err := http.NewRequest(validMethod, validEndpoint, nil)
+		if err != nil {
+			t.Fatalf("unexpected error when creating http request: %v", err)
+		}
+
+		testCtx, _ := gin.CreateTestContext(w)
+		testCtx.Request = r
+
+		testCtx.Set("user", validUser)
+
+		h.GetWishlist(testCtx)
+
+		res := w.Result()
+		if res.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("GetWishlist() error, got status code = %v, want = %v", res.StatusCode, http.StatusInternalServerError)
+		}
+	})
+}
+
+func Test_AddItem(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodPost
+		validEndpoint = "http://localhost:8443/v1/users/me/wishlist"
+	)
+
+	mockAddItem := func(err error) func(m *mock_storage_wishlist.MockWishlistStorage) {
+		return func(m *mock_storage_wishlist.MockWishlistStorage) {
+			m.
+				EXPECT().
+				AddItem(
+					gomock.Any(), // context
+					validUser.ID,
+					int64(1),
+				).
+				Return(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		req          string
+		mockWishlist func(m *mock_storage_wishlist.MockWishlistStorage)
+		wantCode     int
+		wantErr      gin.H
+	}{
+		{
+			name:         "Success",
+			req:          `{"book_id": 1}`,
+			mockWishlist: mockAddItem(nil),
+			wantCode:     http.StatusCreated,
+			wantErr:      gin.H{},
+		},
+		{
+			name:     "InvalidBookID",
+			req:      `{}`,
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidBookID.Error(),
+			},
+		},
+		{
+			name:         "BookNotFound",
+			req:          `{"book_id": 1}`,
+			mockWishlist: mockAddItem(wishlist.ErrBookIDNotFound),
+			wantCode:     http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errBookNotFound.Error(),
+			},
+		},
+		{
+			name:         "AddItemDatabaseOperationFailed",
+			req:          `{"book_id": 1}`,
+			mockWishlist: mockAddItem(errors.New("failed to execute AddItem operation")),
+			wantCode:     http.StatusInternalServerError,
+			wantErr: gin.H{
+				"message": errInternalServer.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageWishlist := mock_storage_wishlist.NewMockWishlistStorage(ctrl)
+			if tt.mockWishlist != nil {
+				tt.mockWishlist(mockStorageWishlist)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				wishlistStorage: mockStorageWishlist,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			testCtx.Set("user", validUser)
+
+			h.AddItem(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("AddItem() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+				t.Fatalf("AddItem() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_RemoveItem(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodDelete
+		validEndpoint = "http://localhost:8443/v1/users/me/wishlist/1"
+	)
+
+	tests := []struct {
+		name         string
+		bookID       string
+		mockWishlist func(m *mock_storage_wishlist.MockWishlistStorage)
+		wantCode     int
+		wantErr      gin.H
+	}{
+		{
+			name:         "Success",
+			bookID:       "1",
+			mockWishlist: mockRemoveItems(nil),
+			wantCode:     http.StatusOK,
+			wantErr:      gin.H{},
+		},
+		{
+			name:     "InvalidBookID",
+			bookID:   "abc",
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errInvalidBookID.Error(),
+			},
+		},
+		{
+			name:         "ItemNotFound",
+			bookID:       "1",
+			mockWishlist: mockRemoveItems(sqlite.ErrNotFound),
+			wantCode:     http.StatusNotFound,
+			wantErr: gin.H{
+				"message": errItemNotFound.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageWishlist := mock_storage_wishlist.NewMockWishlistStorage(ctrl)
+			if tt.mockWishlist != nil {
+				tt.mockWishlist(mockStorageWishlist)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				wishlistStorage: mockStorageWishlist,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+			testCtx.Params = gin.Params{{Key: "book_id", Value: tt.bookID}}
+
+			testCtx.Set("user", validUser)
+
+			h.RemoveItem(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("RemoveItem() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			resBody := getResponseBody(t, w.Body.Bytes())
+			if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+				t.Fatalf("RemoveItem() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_MoveToOrder(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		validMethod   = http.MethodPost
+		validEndpoint = "http://localhost:8443/v1/users/me/wishlist/order"
+	)
+
+	validItems := []*models.WishlistItem{
+		{BookID: 1},
+		{BookID: 2},
+	}
+
+	tests := []struct {
+		name         string
+		req          string
+		mockWishlist []func(m *mock_storage_wishlist.MockWishlistStorage)
+		orderer      *fakeOrderer
+		wantCode     int
+		wantRequest  *order.OrderRequest
+		wantErr      gin.H
+	}{
+		{
+			name: "Success",
+			req:  `{"items": [{"book_id": 1}, {"book_id": 2, "quantity": 3}]}`,
+			mockWishlist: []func(m *mock_storage_wishlist.MockWishlistStorage){
+				mockGetItems(validItems, nil),
+				mockRemoveItems(nil),
+			},
+			orderer:  &fakeOrderer{order: &models.Order{ID: 1, Total: "10.00"}},
+			wantCode: http.StatusOK,
+			wantRequest: &order.OrderRequest{
+				Books: []*order.BookRequest{
+					{BookID: 1, Quantity: 1},
+					{BookID: 2, Quantity: 3},
+				},
+			},
+		},
+		{
+			name:     "EmptyItems",
+			req:      `{"items": []}`,
+			orderer:  &fakeOrderer{},
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": errAtLeastOneItemIsRequired.Error(),
+			},
+		},
+		{
+			name: "ItemNotInWishlist",
+			req:  `{"items": [{"book_id": 3}]}`,
+			mockWishlist: []func(m *mock_storage_wishlist.MockWishlistStorage){
+				mockGetItems(validItems, nil),
+			},
+			orderer:  &fakeOrderer{},
+			wantCode: http.StatusBadRequest,
+			wantErr: gin.H{
+				"message": "books with ids: [3] not found in wishlist",
+			},
+		},
+		{
+			name: "OrderFailed",
+			req:  `{"items": [{"book_id": 1}]}`,
+			mockWishlist: []func(m *mock_storage_wishlist.MockWishlistStorage){
+				mockGetItems(validItems, nil),
+			},
+			orderer:  &fakeOrderer{},
+			wantCode: http.StatusConflict,
+			wantRequest: &order.OrderRequest{
+				Books: []*order.BookRequest{
+					{BookID: 1, Quantity: 1},
+				},
+			},
+			wantErr: gin.H{
+				"message": "insufficient stock",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorageWishlist := mock_storage_wishlist.NewMockWishlistStorage(ctrl)
+			for _, mock := range tt.mockWishlist {
+				mock(mockStorageWishlist)
+			}
+
+			w := httptest.NewRecorder()
+			h := &Handler{
+				wishlistStorage: mockStorageWishlist,
+				orderer:         tt.orderer,
+			}
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte(tt.req)))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			testCtx.Set("user", validUser)
+
+			h.MoveToOrder(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("MoveToOrder() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+
+			if diff := cmp.Diff(tt.wantRequest, tt.orderer.got); diff != "" {
+				t.Fatalf("MoveToOrder() order request mismatch (-want+got):\n%s", diff)
+			}
+
+			if tt.wantErr != nil {
+				resBody := getResponseBody(t, w.Body.Bytes())
+				if diff := cmp.Diff(tt.wantErr, resBody); diff != "" {
+					t.Fatalf("MoveToOrder() mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// getResponseBody unmarshals response body to type gin.H map[string]any.
+func getResponseBody(t testing.TB, data []byte) gin.H {
+	t.Helper()
+	var resBody gin.H
+	if err := json.Unmarshal(data, &resBody); err != nil {
+		t.Fatalf("unexpected error when unmarshaling response body: %v", err)
+	}
+	return resBody
+}
+
+func genString() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,16 @@
+package wishlist
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wilsonangara/simple-online-book-store/middleware"
+)
+
+func (h *Handler) AddWishlistRoutes(rg *gin.RouterGroup, m *middleware.Middleware) {
+	r := rg.Group("/users/me/wishlist", m.Authenticate())
+
+	r.GET("/", h.GetWishlist)
+	r.POST("/", h.AddItem)
+	r.DELETE("/:book_id", h.RemoveItem)
+	r.POST("/order", h.MoveToOrder)
+}
@@ -0,0 +1,141 @@
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength    = errors.New("isbn must have 10 or 13 digits")
+	ErrInvalidCharacter = errors.New("isbn contains invalid character")
+	ErrInvalidChecksum  = errors.New("isbn has invalid checksum")
+	ErrNotConvertible   = errors.New("isbn-13 cannot be converted to isbn-10")
+)
+
+// bookland is the prefix of every ISBN-13 converted from an ISBN-10.
+const bookland = "978"
+
+// Clean strips the hyphens and spaces commonly used to format an ISBN and
+// upper-cases the ISBN-10 check character.
+func Clean(isbn string) string {
+	isbn = strings.ReplaceAll(isbn, "-", "")
+	isbn = strings.ReplaceAll(isbn, " ", "")
+	return strings.ToUpper(strings.TrimSpace(isbn))
+}
+
+// Normalize validates the given ISBN-10 or ISBN-13 and returns it as an
+// ISBN-13, which is the form we store and look books up with.
+func Normalize(isbn string) (string, error) {
+	isbn = Clean(isbn)
+
+	switch len(isbn) {
+	case 10:
+		return To13(isbn)
+	case 13:
+		if err := Validate13(isbn); err != nil {
+			return "", err
+		}
+		return isbn, nil
+	}
+	return "", ErrInvalidLength
+}
+
+// Validate10 checks the format and checksum of an ISBN-10.
+func Validate10(isbn string) error {
+	isbn = Clean(isbn)
+	if len(isbn) != 10 {
+		return ErrInvalidLength
+	}
+
+	sum := 0
+	for i, r := range isbn {
+		var digit int
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case r == 'X' && i == 9:
+			digit = 10
+		default:
+			return ErrInvalidCharacter
+		}
+		sum += digit * (10 - i)
+	}
+
+	if sum%11 != 0 {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// Validate13 checks the format and checksum of an ISBN-13.
+func Validate13(isbn string) error {
+	isbn = Clean(isbn)
+	if len(isbn) != 13 {
+		return ErrInvalidLength
+	}
+	if !isDigits(isbn) {
+		return ErrInvalidCharacter
+	}
+
+	if checkDigit13(isbn[:12]) != isbn[12] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// To13 converts a valid ISBN-10 into its ISBN-13 form.
+func To13(isbn string) (string, error) {
+	isbn = Clean(isbn)
+	if err := Validate10(isbn); err != nil {
+		return "", err
+	}
+
+	body := bookland + isbn[:9]
+	return body + string(checkDigit13(body)), nil
+}
+
+// To10 converts a valid ISBN-13 into its ISBN-10 form, only ISBN-13s
+// with the 978 prefix have an ISBN-10 equivalent.
+func To10(isbn string) (string, error) {
+	isbn = Clean(isbn)
+	if err := Validate13(isbn); err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(isbn, bookland) {
+		return "", ErrNotConvertible
+	}
+
+	body := isbn[3:12]
+	sum := 0
+	for i, r := range body {
+		sum += int(r-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return body + "X", nil
+	}
+	return body + string(rune('0'+check)), nil
+}
+
+// checkDigit13 computes the ISBN-13 check digit of the first 12 digits.
+func checkDigit13(body string) byte {
+	sum := 0
+	for i, r := range body {
+		digit := int(r - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
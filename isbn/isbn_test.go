@@ -0,0 +1,120 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		isbn    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "ISBN13",
+			isbn: "9780735211292",
+			want: "9780735211292",
+		},
+		{
+			name: "HyphenatedISBN13",
+			isbn: "978-0-316-34662-7",
+			want: "9780316346627",
+		},
+		{
+			name: "ISBN10",
+			isbn: "0735211299",
+			want: "9780735211292",
+		},
+		{
+			name: "ISBN10WithCheckCharacterX",
+			isbn: "0-8044-2957-x",
+			want: "9780804429573",
+		},
+		{
+			name:    "InvalidLength",
+			isbn:    "12345",
+			wantErr: ErrInvalidLength,
+		},
+		{
+			name:    "InvalidCharacter",
+			isbn:    "97807352112A2",
+			wantErr: ErrInvalidCharacter,
+		},
+		{
+			name:    "InvalidISBN13Checksum",
+			isbn:    "9780735211293",
+			wantErr: ErrInvalidChecksum,
+		},
+		{
+			name:    "InvalidISBN10Checksum",
+			isbn:    "0735211298",
+			wantErr: ErrInvalidChecksum,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Normalize(tt.isbn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize(%q) error, got = %v, want = %v", tt.isbn, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("Normalize(%q) error, got = %v, want = %v", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		isbn    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Success",
+			isbn: "9780735211292",
+			want: "0735211299",
+		},
+		{
+			name: "CheckCharacterX",
+			isbn: "9780804429573",
+			want: "080442957X",
+		},
+		{
+			name:    "NotConvertible",
+			isbn:    "9791032305690",
+			wantErr: ErrNotConvertible,
+		},
+		{
+			name:    "InvalidChecksum",
+			isbn:    "9780735211293",
+			wantErr: ErrInvalidChecksum,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := To10(tt.isbn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("To10(%q) error, got = %v, want = %v", tt.isbn, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("To10(%q) error, got = %v, want = %v", tt.isbn, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package library
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// MaxEbookSize is the largest ebook file accepted, in bytes.
+const MaxEbookSize = 100 << 20
+
+var (
+	ErrSecretIsRequired = errors.New("secret is required")
+	ErrInvalidSignature = errors.New("invalid download link")
+	ErrLinkExpired      = errors.New("download link expired")
+)
+
+// Signer signs the download links of entitlements, a signed link can be
+// followed without the token of its owner until it expires.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner creates a signer of download links valid for the given duration.
+func NewSigner(secret string, ttl time.Duration) (*Signer, error) {
+	if secret == "" {
+		return nil, ErrSecretIsRequired
+	}
+
+	return &Signer{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}, nil
+}
+
+// Sign signs a download link of an entitlement, returning its signature and
+// the unix time it expires at.
+func (s *Signer) Sign(entitlementID int64, now time.Time) (string, int64) {
+	expires := now.Add(s.ttl).Unix()
+	return s.signature(entitlementID, expires), expires
+}
+
+// Verify checks the signature of a download link of an entitlement expiring
+// at the given unix time.
+func (s *Signer) Verify(entitlementID, expires int64, signature string, now time.Time) error {
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	want, _ := base64.RawURLEncoding.DecodeString(s.signature(entitlementID, expires))
+	if !hmac.Equal(got, want) {
+		return ErrInvalidSignature
+	}
+
+	// the expiry is only trusted once the signature proved it untouched.
+	if now.Unix() >= expires {
+		return ErrLinkExpired
+	}
+
+	return nil
+}
+
+// signature is the HMAC of an entitlement along with the expiry of its link.
+func (s *Signer) signature(entitlementID, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strconv.FormatInt(entitlementID, 10) + ":" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// EbookKey returns the blob key of the file of an ebook edition.
+func EbookKey(editionID int64) string {
+	return "ebooks/" + strconv.FormatInt(editionID, 10)
+}
@@ -0,0 +1,106 @@
+package library
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	signer, err := NewSigner("secret", 5*time.Minute)
+	if err != nil {
+		t.Fatalf("NewSigner(_, _) expected nil error, got = %v", err)
+	}
+
+	signature, expires := signer.Sign(42, now)
+	if want := now.Add(5 * time.Minute).Unix(); expires != want {
+		t.Fatalf("Sign(_, _) error, got expires = %v, want = %v", expires, want)
+	}
+
+	other, err := NewSigner("other secret", 5*time.Minute)
+	if err != nil {
+		t.Fatalf("NewSigner(_, _) expected nil error, got = %v", err)
+	}
+	otherSignature, _ := other.Sign(42, now)
+
+	tests := []struct {
+		name      string
+		id        int64
+		expires   int64
+		signature string
+		now       time.Time
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			id:        42,
+			expires:   expires,
+			signature: signature,
+			now:       now.Add(time.Minute),
+		},
+		{
+			name:      "Expired",
+			id:        42,
+			expires:   expires,
+			signature: signature,
+			now:       now.Add(5 * time.Minute),
+			wantErr:   ErrLinkExpired,
+		},
+		{
+			name:      "OtherEntitlement",
+			id:        43,
+			expires:   expires,
+			signature: signature,
+			now:       now,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			// an expiry pushed back invalidates the signature.
+			name:      "ExtendedExpiry",
+			id:        42,
+			expires:   expires + 3600,
+			signature: signature,
+			now:       now,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "OtherSecret",
+			id:        42,
+			expires:   expires,
+			signature: otherSignature,
+			now:       now,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "Malformed",
+			id:        42,
+			expires:   expires,
+			signature: "not a signature!",
+			now:       now,
+			wantErr:   ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := signer.Verify(tt.id, tt.expires, tt.signature, tt.now); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify(_, _, _, _) error, got = %v, want = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewSigner_SecretIsRequired(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSigner("", time.Minute); !errors.Is(err, ErrSecretIsRequired) {
+		t.Fatalf("NewSigner(_, _) error, got = %v, want = %v", err, ErrSecretIsRequired)
+	}
+}
@@ -0,0 +1,241 @@
+package locale
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation"
+)
+
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// Canonicalize returns a locale such as "pt_br" in its canonical form
+// "pt-BR": a language followed by optional subtags, the region in upper
+// case and the script in title case.
+func Canonicalize(tag string) (string, error) {
+	subtags := strings.FieldsFunc(tag, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(subtags) < 1 || strings.Trim(tag, "-_") != tag {
+		return "", ErrInvalidLocale
+	}
+
+	for i, subtag := range subtags {
+		if len(subtag) > 8 || !isAlphanumeric(subtag) {
+			return "", ErrInvalidLocale
+		}
+
+		switch {
+		case i == 0:
+			if len(subtag) < 2 || len(subtag) > 3 || !isAlpha(subtag) {
+				return "", ErrInvalidLocale
+			}
+			subtags[i] = strings.ToLower(subtag)
+		case len(subtag) == 2 && isAlpha(subtag):
+			subtags[i] = strings.ToUpper(subtag)
+		case len(subtag) == 4 && isAlpha(subtag):
+			subtags[i] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		default:
+			subtags[i] = strings.ToLower(subtag)
+		}
+	}
+
+	return strings.Join(subtags, "-"), nil
+}
+
+// Preferred returns the locales a request asks for in order of preference,
+// the lang query parameter first and then the Accept-Language header by
+// quality. Wildcards and invalid locales are left out.
+func Preferred(r *http.Request) []string {
+	preferred := []string{}
+	if lang, err := Canonicalize(r.URL.Query().Get("lang")); err == nil {
+		preferred = append(preferred, lang)
+	}
+
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+	accepted := []weighted{}
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale, err := Canonicalize(strings.TrimSpace(tag))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			if quality, err = strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); err != nil {
+				continue
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		accepted = append(accepted, weighted{locale: locale, quality: quality})
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+	for _, a := range accepted {
+		preferred = append(preferred, a.locale)
+	}
+
+	return preferred
+}
+
+// Match returns the first preferred locale that is available. A preferred
+// locale without an exact match falls back to an available locale of the
+// same language, so "fr-CA" matches "fr" and "pt" matches "pt-BR".
+func Match(preferred, available []string) (string, bool) {
+	for _, p := range preferred {
+		for _, a := range available {
+			if strings.EqualFold(p, a) {
+				return a, true
+			}
+		}
+		for _, a := range available {
+			if strings.EqualFold(language(p), language(a)) {
+				return a, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Localizer picks the locale of requests among the locales books are
+// translated in, and translates books into it. Books are written in the
+// default locale.
+type Localizer struct {
+	translationStorage translation.TranslationStorage
+	defaultLocale      string
+}
+
+// NewLocalizer creates a localizer of books written in the given locale.
+func NewLocalizer(translationStorage translation.TranslationStorage, defaultLocale string) *Localizer {
+	return &Localizer{
+		translationStorage: translationStorage,
+		defaultLocale:      defaultLocale,
+	}
+}
+
+// Default returns the locale books are written in.
+func (l *Localizer) Default() string {
+	return l.defaultLocale
+}
+
+// Negotiate returns the locale to respond to a request in, the default
+// locale unless the request prefers one books are translated in.
+func (l *Localizer) Negotiate(ctx context.Context, r *http.Request) (string, error) {
+	preferred := Preferred(r)
+	if len(preferred) < 1 {
+		return l.defaultLocale, nil
+	}
+
+	available, err := l.translationStorage.GetLocales(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// the default locale is listed first so it wins over a translation
+	// of the same language.
+	locale, ok := Match(preferred, append([]string{l.defaultLocale}, available...))
+	if !ok {
+		return l.defaultLocale, nil
+	}
+	return locale, nil
+}
+
+// Books translates the title and description of books into a locale, books
+// without a translation are left as they are.
+func (l *Localizer) Books(ctx context.Context, locale string, books []*models.Book) error {
+	ids := make([]int64, 0, len(books))
+	for _, b := range books {
+		ids = append(ids, b.ID)
+	}
+
+	translations, err := l.translations(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+	for _, b := range books {
+		if t, ok := translations[b.ID]; ok {
+			b.Title, b.Description = translate(t, b.Description)
+		}
+	}
+
+	return nil
+}
+
+// OrderHistory translates the title and description of the books of past
+// orders into a locale.
+func (l *Localizer) OrderHistory(ctx context.Context, locale string, orders []*models.OrderHistory) error {
+	ids := []int64{}
+	for _, o := range orders {
+		for _, item := range o.Items {
+			ids = append(ids, item.BookID)
+		}
+	}
+
+	translations, err := l.translations(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+	for _, o := range orders {
+		for _, item := range o.Items {
+			if t, ok := translations[item.BookID]; ok {
+				item.Title, item.Description = translate(t, item.Description)
+			}
+		}
+	}
+
+	return nil
+}
+
+// translations fetches the translations of the given books, none are
+// needed in the default locale.
+func (l *Localizer) translations(ctx context.Context, locale string, bookIDs []int64) (map[int64]*models.BookTranslation, error) {
+	if locale == l.defaultLocale || len(bookIDs) < 1 {
+		return map[int64]*models.BookTranslation{}, nil
+	}
+	return l.translationStorage.GetTranslationsByBookIDs(ctx, locale, bookIDs)
+}
+
+// translate returns the translated title and description, a translation
+// without a description keeps the given one.
+func translate(t *models.BookTranslation, description string) (string, string) {
+	if t.Description != "" {
+		description = t.Description
+	}
+	return t.Title, description
+}
+
+// language returns the language of a locale, "fr" of "fr-CA".
+func language(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	return lang
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
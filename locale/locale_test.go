@@ -0,0 +1,200 @@
+package locale
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	mock_storage_translation "github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation/mock"
+)
+
+func TestCanonicalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{tag: "fr", want: "fr"},
+		{tag: "pt_br", want: "pt-BR"},
+		{tag: "ZH-hant-TW", want: "zh-Hant-TW"},
+		{tag: "es-419", want: "es-419"},
+		{tag: "", wantErr: true},
+		{tag: "*", wantErr: true},
+		{tag: "f", wantErr: true},
+		{tag: "fr-", wantErr: true},
+		{tag: "fr-toolongsubtag", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Canonicalize(tt.tag)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("Canonicalize(%q) error, got = %v, want error = %v", tt.tag, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Fatalf("Canonicalize(%q) error, got = %v, want = %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		want           []string
+	}{
+		{
+			name: "None",
+			want: []string{},
+		},
+		{
+			name:           "ByQuality",
+			acceptLanguage: "de;q=0.5, fr-CA, fr;q=0.9, *;q=0.1, en;q=0",
+			want:           []string{"fr-CA", "fr", "de"},
+		},
+		{
+			name:           "LangFirst",
+			lang:           "pt_br",
+			acceptLanguage: "fr",
+			want:           []string{"pt-BR", "fr"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := http.NewRequest(http.MethodGet, "http://localhost:8443/v1/books?lang="+tt.lang, nil)
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			if diff := cmp.Diff(tt.want, Preferred(r)); diff != "" {
+				t.Fatalf("Preferred(_) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	available := []string{"en", "fr", "pt-BR"}
+
+	tests := []struct {
+		preferred []string
+		want      string
+		wantOK    bool
+	}{
+		{preferred: []string{"de", "fr"}, want: "fr", wantOK: true},
+		{preferred: []string{"fr-CA"}, want: "fr", wantOK: true},
+		{preferred: []string{"pt"}, want: "pt-BR", wantOK: true},
+		{preferred: []string{"EN"}, want: "en", wantOK: true},
+		{preferred: []string{"de"}},
+	}
+
+	for _, tt := range tests {
+		got, ok := Match(tt.preferred, available)
+		if got != tt.want || ok != tt.wantOK {
+			t.Fatalf("Match(%v, _) error, got = %v, %v, want = %v, %v", tt.preferred, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Negotiate", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockTranslationStorage := mock_storage_translation.NewMockTranslationStorage(ctrl)
+		mockTranslationStorage.EXPECT().GetLocales(gomock.Any()).Return([]string{"de", "fr"}, nil).Times(2)
+
+		l := NewLocalizer(mockTranslationStorage, "en")
+
+		// no preference needs no lookup.
+		r, _ := http.NewRequest(http.MethodGet, "http://localhost:8443/v1/books", nil)
+		if got, err := l.Negotiate(ctx, r); err != nil || got != "en" {
+			t.Fatalf("Negotiate(_, _) error, got = %v, %v, want = %v", got, err, "en")
+		}
+
+		r.Header.Set("Accept-Language", "fr-CA, en;q=0.8")
+		if got, err := l.Negotiate(ctx, r); err != nil || got != "fr" {
+			t.Fatalf("Negotiate(_, _) error, got = %v, %v, want = %v", got, err, "fr")
+		}
+
+		// locales nothing is translated in fall back to the default.
+		r.Header.Set("Accept-Language", "ja")
+		if got, err := l.Negotiate(ctx, r); err != nil || got != "en" {
+			t.Fatalf("Negotiate(_, _) error, got = %v, %v, want = %v", got, err, "en")
+		}
+	})
+
+	t.Run("Books", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockTranslationStorage := mock_storage_translation.NewMockTranslationStorage(ctrl)
+		mockTranslationStorage.EXPECT().GetTranslationsByBookIDs(gomock.Any(), "fr", []int64{1, 2}).Return(map[int64]*models.BookTranslation{
+			1: {BookID: 1, Locale: "fr", Title: "Un Petit Rien", Description: "Des habitudes"},
+			2: {BookID: 2, Locale: "fr", Title: "Le Point de Bascule"},
+		}, nil)
+
+		l := NewLocalizer(mockTranslationStorage, "en")
+
+		books := []*models.Book{
+			{ID: 1, Title: "Atomic Habits", Description: "Habits"},
+			{ID: 2, Title: "The Tipping Point", Description: "Tipping"},
+		}
+		if err := l.Books(ctx, "fr", books); err != nil {
+			t.Fatalf("Books(_, _, _) expected nil error, got = %v", err)
+		}
+		want := []*models.Book{
+			{ID: 1, Title: "Un Petit Rien", Description: "Des habitudes"},
+			{ID: 2, Title: "Le Point de Bascule", Description: "Tipping"},
+		}
+		if diff := cmp.Diff(want, books); diff != "" {
+			t.Fatalf("Books(_, _, _) mismatch (-want +got):\n%s", diff)
+		}
+
+		// books are written in the default locale.
+		if err := l.Books(ctx, "en", books); err != nil {
+			t.Fatalf("Books(_, _, _) expected nil error, got = %v", err)
+		}
+	})
+
+	t.Run("OrderHistory", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockTranslationStorage := mock_storage_translation.NewMockTranslationStorage(ctrl)
+		mockTranslationStorage.EXPECT().GetTranslationsByBookIDs(gomock.Any(), "fr", []int64{1, 2}).Return(map[int64]*models.BookTranslation{
+			1: {BookID: 1, Locale: "fr", Title: "Un Petit Rien"},
+		}, nil)
+
+		l := NewLocalizer(mockTranslationStorage, "en")
+
+		orders := []*models.OrderHistory{
+			{ID: 1, Items: []*models.OrderHistoryItem{{BookID: 1, Title: "Atomic Habits"}, {BookID: 2, Title: "The Tipping Point"}}},
+		}
+		if err := l.OrderHistory(ctx, "fr", orders); err != nil {
+			t.Fatalf("OrderHistory(_, _, _) expected nil error, got = %v", err)
+		}
+		if got := []string{orders[0].Items[0].Title, orders[0].Items[1].Title}; !cmp.Equal(got, []string{"Un Petit Rien", "The Tipping Point"}) {
+			t.Fatalf("OrderHistory(_, _, _) error, got titles = %v", got)
+		}
+	})
+}
@@ -13,15 +13,68 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/kenshaw/envcfg"
 
+	"github.com/wilsonangara/simple-online-book-store/alerting"
 	"github.com/wilsonangara/simple-online-book-store/auth"
+	"github.com/wilsonangara/simple-online-book-store/catalog"
+	"github.com/wilsonangara/simple-online-book-store/handlers/alert"
+	"github.com/wilsonangara/simple-online-book-store/handlers/author"
 	"github.com/wilsonangara/simple-online-book-store/handlers/book"
+	"github.com/wilsonangara/simple-online-book-store/handlers/category"
+	"github.com/wilsonangara/simple-online-book-store/handlers/cover"
+	"github.com/wilsonangara/simple-online-book-store/handlers/edition"
+	"github.com/wilsonangara/simple-online-book-store/handlers/library"
 	"github.com/wilsonangara/simple-online-book-store/handlers/order"
+	"github.com/wilsonangara/simple-online-book-store/handlers/price"
+	"github.com/wilsonangara/simple-online-book-store/handlers/publisher"
+	"github.com/wilsonangara/simple-online-book-store/handlers/recommendation"
+	"github.com/wilsonangara/simple-online-book-store/handlers/review"
+	"github.com/wilsonangara/simple-online-book-store/handlers/series"
+	"github.com/wilsonangara/simple-online-book-store/handlers/similarity"
+	"github.com/wilsonangara/simple-online-book-store/handlers/suggest"
+	"github.com/wilsonangara/simple-online-book-store/handlers/translation"
 	"github.com/wilsonangara/simple-online-book-store/handlers/user"
+	"github.com/wilsonangara/simple-online-book-store/handlers/wishlist"
+	lib "github.com/wilsonangara/simple-online-book-store/library"
+	"github.com/wilsonangara/simple-online-book-store/locale"
 	"github.com/wilsonangara/simple-online-book-store/middleware"
+	"github.com/wilsonangara/simple-online-book-store/scheduler"
+	"github.com/wilsonangara/simple-online-book-store/storage/blob"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	alert_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/alert"
+	author_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/author"
 	book_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/book"
+	category_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/category"
+	edition_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/edition"
+	entitlement_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/entitlement"
 	order_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/order"
+	price_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/price"
+	publisher_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/publisher"
+	recommendation_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/recommendation"
+	reservation_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/reservation"
+	review_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/review"
+	series_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/series"
+	translation_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/translation"
 	user_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/user"
+	wishlist_storage "github.com/wilsonangara/simple-online-book-store/storage/sqlite/wishlist"
+)
+
+const (
+	defaultReservationTTL           = 15 * time.Minute
+	defaultReservationSweepInterval = time.Minute
+	defaultPreorderReleaseInterval  = time.Hour
+
+	defaultRecommendationRefreshInterval = time.Hour
+	defaultSimilarityRefreshInterval     = time.Minute
+	defaultSuggestRefreshInterval        = time.Minute
+	defaultONIXWatchInterval             = time.Minute
+	defaultAlertEvaluateInterval         = 5 * time.Minute
+	defaultAlertWebhookTimeout           = 10 * time.Second
+
+	defaultDownloadLinkTTL = 5 * time.Minute
+	defaultDownloadLimit   = 5
+	defaultDownloadWindow  = 24 * time.Hour
+
+	defaultLocale = "en"
 )
 
 var config *envcfg.Envcfg
@@ -35,7 +88,11 @@ func init() {
 }
 
 func main() {
-	handlers := setupHandlers()
+	// jobsCtx stops background jobs once the server shuts down.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	handlers := setupHandlers(jobsCtx)
 
 	addr := net.JoinHostPort("", config.GetString("server.port"))
 	srv := &http.Server{
@@ -52,6 +109,8 @@ func main() {
 		signal.Notify(sigint, os.Interrupt)
 		<-sigint
 
+		stopJobs()
+
 		ctx, cancel := context.WithTimeout(
 			context.Background(),
 			shutdownTimeout,
@@ -73,7 +132,7 @@ func main() {
 	log.Print("server exited gracefully")
 }
 
-func setupHandlers() http.Handler {
+func setupHandlers(jobsCtx context.Context) http.Handler {
 	r := gin.Default()
 
 	// handle default endpoint response
@@ -113,6 +172,42 @@ func setupHandlers() http.Handler {
 	userStorage := user_storage.NewStorage(storage.Database())
 	bookStorage := book_storage.NewStorage(storage.Database())
 	orderStorage := order_storage.NewStorage(storage.Database())
+	categoryStorage := category_storage.NewStorage(storage.Database())
+	authorStorage := author_storage.NewStorage(storage.Database())
+	reservationStorage := reservation_storage.NewStorage(storage.Database())
+	reviewStorage := review_storage.NewStorage(storage.Database())
+	wishlistStorage := wishlist_storage.NewStorage(storage.Database())
+	recommendationStorage := recommendation_storage.NewStorage(storage.Database())
+	priceStorage := price_storage.NewStorage(storage.Database())
+	editionStorage := edition_storage.NewStorage(storage.Database())
+	entitlementStorage := entitlement_storage.NewStorage(storage.Database())
+	seriesStorage := series_storage.NewStorage(storage.Database())
+	publisherStorage := publisher_storage.NewStorage(storage.Database())
+	alertStorage := alert_storage.NewStorage(storage.Database())
+	translationStorage := translation_storage.NewStorage(storage.Database())
+
+	// blobs such as covers are kept next to the database unless configured
+	// otherwise.
+	blobDir := config.GetString("blob.dir")
+	if blobDir == "" {
+		blobDir = filepath.Join(wd, "storage", "blobs")
+	}
+	blobStore, err := blob.NewLocalStore(blobDir)
+	if err != nil {
+		log.Fatalf("failed to initialize blob store: %v", err)
+	}
+
+	// books are written in the default locale, other locales are served
+	// from their translations.
+	bookLocale := config.GetString("i18n.default_locale")
+	if bookLocale == "" {
+		bookLocale = defaultLocale
+	}
+	bookLocale, err = locale.Canonicalize(bookLocale)
+	if err != nil {
+		log.Fatalf("failed to parse default locale: %v", err)
+	}
+	localizer := locale.NewLocalizer(translationStorage, bookLocale)
 
 	middleware := middleware.NewMiddleware(authClient, userStorage)
 
@@ -121,11 +216,176 @@ func setupHandlers() http.Handler {
 	userHandler := user.NewHandler(authClient, userStorage)
 	userHandler.AddUserRoutes(v1)
 
-	bookHandler := book.NewHandler(bookStorage)
-	bookHandler.AddBookRoutes(v1)
+	bookHandler := book.NewHandler(bookStorage, localizer)
+	bookHandler.AddBookRoutes(v1, middleware)
+
+	reservationTTL := config.GetDuration("reservation.ttl")
+	if reservationTTL <= 0 {
+		reservationTTL = defaultReservationTTL
+	}
 
-	orderHandler := order.NewHandler(orderStorage, bookStorage, userStorage)
+	orderHandler := order.NewHandler(orderStorage, bookStorage, editionStorage, userStorage, reservationStorage, localizer, reservationTTL)
 	orderHandler.AddOrderRoutes(v1, middleware)
 
+	categoryHandler := category.NewHandler(categoryStorage, bookStorage)
+	categoryHandler.AddCategoryRoutes(v1, middleware)
+
+	authorHandler := author.NewHandler(authorStorage, bookStorage)
+	authorHandler.AddAuthorRoutes(v1, middleware)
+
+	reviewHandler := review.NewHandler(reviewStorage, bookStorage)
+	reviewHandler.AddReviewRoutes(v1, middleware)
+
+	wishlistHandler := wishlist.NewHandler(wishlistStorage, bookStorage, orderHandler)
+	wishlistHandler.AddWishlistRoutes(v1, middleware)
+
+	recommendationHandler := recommendation.NewHandler(recommendationStorage, bookStorage, orderStorage)
+	recommendationHandler.AddRecommendationRoutes(v1, middleware)
+
+	similarityHandler := similarity.NewHandler(bookStorage)
+	similarityHandler.AddSimilarityRoutes(v1)
+
+	suggestHandler := suggest.NewHandler(bookStorage, orderStorage)
+	suggestHandler.AddSuggestRoutes(v1)
+
+	coverHandler := cover.NewHandler(bookStorage, blobStore)
+	coverHandler.AddCoverRoutes(v1, middleware)
+
+	priceHandler := price.NewHandler(priceStorage, bookStorage)
+	priceHandler.AddPriceRoutes(v1, middleware)
+
+	editionHandler := edition.NewHandler(editionStorage, blobStore)
+	editionHandler.AddEditionRoutes(v1, middleware)
+
+	// download links are signed with their own secret when one is set.
+	librarySecret := config.GetString("library.secret")
+	if librarySecret == "" {
+		librarySecret = config.GetString("jwt.secret")
+	}
+	linkTTL := config.GetDuration("library.link_ttl")
+	if linkTTL <= 0 {
+		linkTTL = defaultDownloadLinkTTL
+	}
+	signer, err := lib.NewSigner(librarySecret, linkTTL)
+	if err != nil {
+		log.Fatalf("failed to initialize download link signer: %v", err)
+	}
+	downloadLimit := config.GetInt64("library.download_limit", 10, 64)
+	if downloadLimit <= 0 {
+		downloadLimit = defaultDownloadLimit
+	}
+	downloadWindow := config.GetDuration("library.download_window")
+	if downloadWindow <= 0 {
+		downloadWindow = defaultDownloadWindow
+	}
+
+	libraryHandler := library.NewHandler(entitlementStorage, blobStore, signer, downloadLimit, downloadWindow)
+	libraryHandler.AddLibraryRoutes(v1, middleware)
+
+	seriesHandler := series.NewHandler(seriesStorage, bookStorage)
+	seriesHandler.AddSeriesRoutes(v1, middleware)
+
+	publisherHandler := publisher.NewHandler(publisherStorage, bookStorage)
+	publisherHandler.AddPublisherRoutes(v1, middleware)
+
+	alertHandler := alert.NewHandler(alertStorage)
+	alertHandler.AddAlertRoutes(v1, middleware)
+
+	translationHandler := translation.NewHandler(translationStorage, bookLocale)
+	translationHandler.AddTranslationRoutes(v1, middleware)
+
+	// jobs
+	sweepInterval := config.GetDuration("reservation.sweep_interval")
+	if sweepInterval <= 0 {
+		sweepInterval = defaultReservationSweepInterval
+	}
+	go scheduler.Every(jobsCtx, "release expired reservations", sweepInterval, func(ctx context.Context) error {
+		released, err := reservationStorage.ReleaseExpired(ctx)
+		if err != nil {
+			return err
+		}
+		if released > 0 {
+			log.Printf("released %d expired reservation(s)", released)
+		}
+		return nil
+	})
+
+	releaseInterval := config.GetDuration("preorder.release_interval")
+	if releaseInterval <= 0 {
+		releaseInterval = defaultPreorderReleaseInterval
+	}
+	go scheduler.Every(jobsCtx, "release preorders", releaseInterval, func(ctx context.Context) error {
+		released, err := orderStorage.ReleasePreorders(ctx)
+		if err != nil {
+			return err
+		}
+		if released > 0 {
+			log.Printf("moved %d preordered item(s) to ready to ship", released)
+		}
+		return nil
+	})
+
+	refreshInterval := config.GetDuration("recommendation.refresh_interval")
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRecommendationRefreshInterval
+	}
+	go func() {
+		// compute recommendations right away rather than serving none until
+		// the first refresh.
+		if err := recommendationStorage.Refresh(jobsCtx); err != nil {
+			log.Printf("failed to refresh recommendations: %v", err)
+		}
+		scheduler.Every(jobsCtx, "refresh recommendations", refreshInterval, recommendationStorage.Refresh)
+	}()
+
+	// the similarity index is cheap to check, it is only rebuilt when the
+	// catalog version changed.
+	similarityInterval := config.GetDuration("similarity.refresh_interval")
+	if similarityInterval <= 0 {
+		similarityInterval = defaultSimilarityRefreshInterval
+	}
+	go func() {
+		if err := similarityHandler.Refresh(jobsCtx); err != nil {
+			log.Printf("failed to refresh similarity index: %v", err)
+		}
+		scheduler.Every(jobsCtx, "refresh similarity index", similarityInterval, similarityHandler.Refresh)
+	}()
+
+	// suggestions are served from memory, the index is only rebuilt when the
+	// catalog version or the sales changed.
+	suggestInterval := config.GetDuration("suggest.refresh_interval")
+	if suggestInterval <= 0 {
+		suggestInterval = defaultSuggestRefreshInterval
+	}
+	go func() {
+		if err := suggestHandler.Refresh(jobsCtx); err != nil {
+			log.Printf("failed to refresh suggestion index: %v", err)
+		}
+		scheduler.Every(jobsCtx, "refresh suggestion index", suggestInterval, suggestHandler.Refresh)
+	}()
+
+	// stock alerts are only logged unless a webhook is set to deliver them to.
+	var notifier alerting.Notifier = alerting.LogNotifier{}
+	if webhookURL := config.GetString("alerting.webhook_url"); webhookURL != "" {
+		notifier = alerting.NewWebhookNotifier(webhookURL, defaultAlertWebhookTimeout)
+	}
+	alertInterval := config.GetDuration("alerting.evaluate_interval")
+	if alertInterval <= 0 {
+		alertInterval = defaultAlertEvaluateInterval
+	}
+	evaluator := alerting.NewEvaluator(alertStorage, notifier)
+	go scheduler.Every(jobsCtx, "evaluate stock alerts", alertInterval, evaluator.Evaluate)
+
+	// publisher feeds are only ingested when a directory to watch is set.
+	if onixDir := config.GetString("onix.watch_dir"); onixDir != "" {
+		onixInterval := config.GetDuration("onix.watch_interval")
+		if onixInterval <= 0 {
+			onixInterval = defaultONIXWatchInterval
+		}
+		go scheduler.Every(jobsCtx, "ingest onix feeds", onixInterval, func(ctx context.Context) error {
+			return catalog.IngestONIXDir(ctx, bookStorage, onixDir)
+		})
+	}
+
 	return r
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/wilsonangara/simple-online-book-store/auth"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/user"
 )
@@ -22,6 +23,7 @@ var (
 	errTokenIsRequired    = errors.New("token is required")
 	errInvalidTokenFormat = errors.New("invalid token format")
 	errInternalError      = errors.New("internal server error")
+	errForbidden          = errors.New("forbidden")
 )
 
 // NewMiddleware returns a wrapper around middleware client.
@@ -90,3 +92,53 @@ func (m *Middleware) Authenticate() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// Admin will check whether the authenticated user is allowed to access
+// administrative endpoints, it must be chained after Authenticate.
+func (m *Middleware) Admin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		u, found := ctx.Get("user")
+		if !found {
+			log.Print("failed to get user from context")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalError.Error(),
+			})
+			return
+		}
+
+		user, ok := u.(*models.User)
+		if !ok {
+			log.Print("failed to assert user")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"message": errInternalError.Error(),
+			})
+			return
+		}
+
+		if !user.IsAdmin {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"message": errForbidden.Error(),
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// UserID returns the id of the user authenticated by Authenticate, it must
+// be called from handlers chained after Authenticate.
+func UserID(c *gin.Context) (int64, error) {
+	u, found := c.Get("user")
+	if !found {
+		return 0, errors.New("failed to get user")
+	}
+
+	// assert token user type
+	assertedUser, ok := u.(*models.User)
+	if !ok {
+		return 0, errors.New("failed to assert user")
+	}
+
+	return assertedUser.ID, nil
+}
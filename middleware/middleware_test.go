@@ -196,6 +196,118 @@ func Test_Authenticate(t *testing.T) {
 	})
 }
 
+func Test_Admin(t *testing.T) {
+	t.Parallel()
+
+	const (
+		validMethod = http.MethodPost
+		// We are setting this for http test, so any valid endpoint will do
+		validEndpoint = "http://test-admin"
+	)
+
+	tests := []struct {
+		name     string
+		user     any
+		wantCode int
+	}{
+		{
+			name: "Success",
+			user: &models.User{
+				ID:      1,
+				IsAdmin: true,
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "NotAdmin",
+			user: &models.User{
+				ID: 1,
+			},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "UserNotInContext",
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &Middleware{}
+
+			w := httptest.NewRecorder()
+
+			r, err := http.NewRequest(validMethod, validEndpoint, bytes.NewBuffer([]byte{}))
+			if err != nil {
+				t.Fatalf("unexpected error when creating http request: %v", err)
+			}
+
+			testCtx, _ := gin.CreateTestContext(w)
+			testCtx.Request = r
+
+			if tt.user != nil {
+				testCtx.Set("user", tt.user)
+			}
+
+			m.Admin()(testCtx)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("Admin() error, got status code = %v, want = %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_UserID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		user    any
+		wantID  int64
+		wantErr bool
+	}{
+		{
+			name:   "Success",
+			user:   &models.User{ID: 7},
+			wantID: 7,
+		},
+		{
+			name:    "UserNotInContext",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidUser",
+			user:    "user",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			if tt.user != nil {
+				testCtx.Set("user", tt.user)
+			}
+
+			id, err := UserID(testCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UserID() error = %v, want error = %v", err, tt.wantErr)
+			}
+			if id != tt.wantID {
+				t.Fatalf("UserID() error, got = %v, want = %v", id, tt.wantID)
+			}
+		})
+	}
+}
+
 // getResponseBody unmarshals response body to type gin.H map[string]any.
 func getResponseBody(t testing.TB, data []byte) gin.H {
 	t.Helper()
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work run periodically by Every.
+type Job func(context.Context) error
+
+// Every runs the job once every interval until the context is done, an
+// error returned by the job is logged and does not stop the schedule.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.Printf("failed to run %s job: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs int64
+	done := make(chan struct{})
+	go func() {
+		Every(ctx, "test", time.Millisecond, func(ctx context.Context) error {
+			// a failing run must not stop the schedule.
+			if atomic.AddInt64(&runs, 1) >= 3 {
+				cancel()
+			}
+			return errors.New("failed to run job")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Every(_, _, _, _) did not return after its context was canceled")
+	}
+
+	if got := atomic.LoadInt64(&runs); got < 3 {
+		t.Fatalf("Every(_, _, _, _) error, got = %v, want at least = %v runs", got, 3)
+	}
+}
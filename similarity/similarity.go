@@ -0,0 +1,137 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that carry no meaning on their own and
+// would only add noise to the similarity of two books.
+var stopWords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "are": true, "as": true,
+	"at": true, "be": true, "by": true, "for": true, "from": true, "how": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"what": true, "when": true, "who": true, "why": true, "will": true, "with": true,
+	"you": true, "your": true,
+}
+
+// Document is the text of a book to index.
+type Document struct {
+	ID   int64
+	Text string
+}
+
+// Match is a document similar to another one, scored between 0 and 1.
+type Match struct {
+	ID    int64
+	Score float64
+}
+
+// Index holds the TF-IDF vector of every indexed document. An Index is
+// immutable once built so it can be shared between goroutines.
+type Index struct {
+	vectors map[int64]map[string]float64
+}
+
+// NewIndex builds an index over the given documents, every vector is
+// normalized so the similarity of two documents is their dot product.
+func NewIndex(docs []Document) *Index {
+	termFrequencies := map[int64]map[string]float64{}
+	documentFrequencies := map[string]int{}
+	for _, doc := range docs {
+		tf := map[string]float64{}
+		for _, term := range Tokenize(doc.Text) {
+			tf[term]++
+		}
+		for term := range tf {
+			documentFrequencies[term]++
+		}
+		termFrequencies[doc.ID] = tf
+	}
+
+	n := float64(len(docs))
+	vectors := map[int64]map[string]float64{}
+	for id, tf := range termFrequencies {
+		vector := map[string]float64{}
+		norm := float64(0)
+		for term, count := range tf {
+			// smoothed idf, so a term found in every document still counts
+			// a little.
+			weight := count * (math.Log((1+n)/(1+float64(documentFrequencies[term]))) + 1)
+			vector[term] = weight
+			norm += weight * weight
+		}
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+		vectors[id] = vector
+	}
+
+	return &Index{vectors: vectors}
+}
+
+// Similar returns up to limit documents most similar to the document with
+// the given id, the most similar first. Documents sharing no term with it are
+// left out.
+func (idx *Index) Similar(id int64, limit int) []Match {
+	vector, ok := idx.vectors[id]
+	if !ok {
+		return []Match{}
+	}
+
+	matches := []Match{}
+	for otherID, other := range idx.vectors {
+		if otherID == id {
+			continue
+		}
+		if score := dot(vector, other); score > 0 {
+			matches = append(matches, Match{ID: otherID, Score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Tokenize splits a text into lower-cased terms, dropping stop words and
+// single characters.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := []string{}
+	for _, field := range fields {
+		if len([]rune(field)) < 2 || stopWords[field] {
+			continue
+		}
+		terms = append(terms, field)
+	}
+	return terms
+}
+
+// dot iterates over the smaller vector to multiply both vectors.
+func dot(a, b map[string]float64) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	sum := float64(0)
+	for term, weight := range a {
+		sum += weight * b[term]
+	}
+	return sum
+}
@@ -0,0 +1,95 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	got := Tokenize("The Power of Habit: Why We Do What We Do, 2nd ed.")
+	want := []string{"power", "habit", "we", "do", "we", "do", "2nd", "ed"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("Tokenize() mismatch (-want+got):\n%s", diff)
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	t.Parallel()
+
+	idx := NewIndex([]Document{
+		{ID: 1, Text: "Atomic Habits James Clear building good habits and breaking bad ones"},
+		{ID: 2, Text: "The Power of Habit Charles Duhigg why habits exist and how to change them"},
+		{ID: 3, Text: "Dune Frank Herbert a desert planet and its spice"},
+		{ID: 4, Text: "Habit stacking small habits"},
+	})
+
+	tests := []struct {
+		name    string
+		id      int64
+		limit   int
+		wantIDs []int64
+	}{
+		{
+			name:    "RankedBySimilarity",
+			id:      1,
+			limit:   10,
+			wantIDs: []int64{4, 2},
+		},
+		{
+			name:    "Limit",
+			id:      1,
+			limit:   1,
+			wantIDs: []int64{4},
+		},
+		{
+			name:    "NothingInCommon",
+			id:      3,
+			limit:   10,
+			wantIDs: []int64{},
+		},
+		{
+			name:    "UnknownDocument",
+			id:      1000,
+			limit:   10,
+			wantIDs: []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			matches := idx.Similar(tt.id, tt.limit)
+
+			gotIDs := []int64{}
+			for _, m := range matches {
+				if m.Score <= 0 || m.Score > 1+1e-9 {
+					t.Fatalf("Similar(%d, %d) error, got score = %v, want within (0, 1]", tt.id, tt.limit, m.Score)
+				}
+				gotIDs = append(gotIDs, m.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Fatalf("Similar(%d, %d) mismatch (-want+got):\n%s", tt.id, tt.limit, diff)
+			}
+		})
+	}
+}
+
+func TestSimilarIdenticalDocuments(t *testing.T) {
+	t.Parallel()
+
+	idx := NewIndex([]Document{
+		{ID: 1, Text: "deep work focused success"},
+		{ID: 2, Text: "deep work focused success"},
+	})
+
+	matches := idx.Similar(1, 10)
+	if len(matches) != 1 || math.Abs(matches[0].Score-1) > 1e-9 {
+		t.Fatalf("Similar(1, 10) error, got = %v, want a single match scored 1", matches)
+	}
+}
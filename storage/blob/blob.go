@@ -0,0 +1,127 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+//go:generate mockgen -source=blob.go -destination=mock/blob.go -package=mock
+type Store interface {
+	// Put stores the content read from r under the given key, replacing
+	// what was stored under it before.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens the content stored under the given key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the content stored under the given key.
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalStore stores blobs as files within a directory of the local
+// filesystem, a key is the slash separated path of its file.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a blob store within the given directory.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %v", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put stores the content read from r under the given key. The content is
+// written to a temporary file first so a failed write never leaves a partial
+// blob behind.
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blob: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store blob: %v", err)
+	}
+
+	return nil
+}
+
+// Get opens the content stored under the given key.
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to open blob: %v", err)
+	}
+
+	return f, nil
+}
+
+// Delete removes the content stored under the given key.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete blob: %v", err)
+	}
+
+	return nil
+}
+
+// path returns the file of the given key, keys cannot reach outside of the
+// directory of the store.
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, ".") {
+			return "", ErrInvalidKey
+		}
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,50 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore(_) expected nil error, got = %v", err)
+	}
+
+	if err := store.Put(ctx, "covers/abc/small", strings.NewReader("thumbnail")); err != nil {
+		t.Fatalf("Put(_, _, _) expected nil error, got = %v", err)
+	}
+
+	r, err := store.Get(ctx, "covers/abc/small")
+	if err != nil {
+		t.Fatalf("Get(_, _) expected nil error, got = %v", err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatalf("unexpected error when reading blob: %v", err)
+	}
+	if string(data) != "thumbnail" {
+		t.Fatalf("Get(_, _) error, got = %q, want = %q", data, "thumbnail")
+	}
+
+	if err := store.Delete(ctx, "covers/abc/small"); err != nil {
+		t.Fatalf("Delete(_, _) expected nil error, got = %v", err)
+	}
+	if _, err := store.Get(ctx, "covers/abc/small"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(_, _) error, got = %v, want = %v", err, ErrNotFound)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "covers/../../outside", "covers//small"} {
+		if err := store.Put(ctx, key, strings.NewReader("")); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("Put(_, %q, _) error, got = %v, want = %v", key, err, ErrInvalidKey)
+		}
+	}
+}
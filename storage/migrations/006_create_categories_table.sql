@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS categories (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        parent_id INTEGER,
+        name TEXT NOT NULL,
+        slug TEXT NOT NULL UNIQUE,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (parent_id) REFERENCES categories(id)
+);
+
+-- +goose StatementBegin
+INSERT INTO categories (name, slug)
+        VALUES  ('Fiction', 'fiction'),
+                ('Nonfiction', 'nonfiction');
+
+INSERT INTO categories (parent_id, name, slug)
+        VALUES  ((SELECT id FROM categories WHERE slug = 'nonfiction'), 'Self-Help', 'self-help'),
+                ((SELECT id FROM categories WHERE slug = 'nonfiction'), 'Psychology', 'psychology');
+
+INSERT INTO categories (parent_id, name, slug)
+        VALUES  ((SELECT id FROM categories WHERE slug = 'self-help'), 'Productivity', 'productivity');
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS book_categories (
+        book_id INTEGER NOT NULL,
+        category_id INTEGER NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (book_id, category_id),
+        FOREIGN KEY (book_id) REFERENCES books(id),
+        FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+-- +goose StatementBegin
+INSERT INTO book_categories (book_id, category_id)
+        SELECT b.id, c.id
+        FROM books b
+        JOIN categories c
+                ON (b.title = 'Atomic Habits' AND c.slug = 'self-help')
+                OR (b.title = 'The Tipping Point' AND c.slug = 'psychology')
+                OR (b.title = 'Building a Second brain' AND c.slug = 'productivity');
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS book_categories;
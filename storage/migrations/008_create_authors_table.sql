@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS authors (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL UNIQUE,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementBegin
+INSERT INTO authors (name)
+        SELECT DISTINCT TRIM(author)
+        FROM books
+        WHERE TRIM(author) <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS authors;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS book_authors (
+        book_id INTEGER NOT NULL,
+        author_id INTEGER NOT NULL,
+        role TEXT NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'translator', 'illustrator')),
+        position INTEGER NOT NULL DEFAULT 0,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (book_id, author_id, role),
+        FOREIGN KEY (book_id) REFERENCES books(id),
+        FOREIGN KEY (author_id) REFERENCES authors(id)
+);
+
+-- +goose StatementBegin
+INSERT INTO book_authors (book_id, author_id, role, position)
+        SELECT b.id, a.id, 'author', 0
+        FROM books b
+        JOIN authors a
+                ON a.name = TRIM(b.author);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS book_authors;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE books ADD COLUMN isbn_10 TEXT;
+ALTER TABLE books ADD COLUMN isbn_13 TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS books_isbn_10_idx ON books (isbn_10);
+CREATE UNIQUE INDEX IF NOT EXISTS books_isbn_13_idx ON books (isbn_13);
+
+-- +goose StatementBegin
+UPDATE books SET isbn_10 = '0735211299', isbn_13 = '9780735211292' WHERE title = 'Atomic Habits';
+UPDATE books SET isbn_10 = '0316346624', isbn_13 = '9780316346627' WHERE title = 'The Tipping Point';
+UPDATE books SET isbn_10 = '1982167386', isbn_13 = '9781982167387' WHERE title = 'Building a Second brain';
+-- +goose StatementEnd
+
+-- +goose Down
+DROP INDEX IF EXISTS books_isbn_13_idx;
+DROP INDEX IF EXISTS books_isbn_10_idx;
+ALTER TABLE books DROP COLUMN isbn_13;
+ALTER TABLE books DROP COLUMN isbn_10;
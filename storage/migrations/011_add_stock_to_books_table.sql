@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE books ADD COLUMN stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0);
+
+-- +goose StatementBegin
+UPDATE books SET stock = 10;
+-- +goose StatementEnd
+
+-- +goose Down
+ALTER TABLE books DROP COLUMN stock;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reservations (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'released', 'committed')),
+        expires_at DATETIME NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS reservations_status_expires_at_idx ON reservations (status, expires_at);
+
+-- +goose StatementBegin
+-- +goose StatementEnd
+
+-- +goose Down
+DROP INDEX IF EXISTS reservations_status_expires_at_idx;
+DROP TABLE IF EXISTS reservations;
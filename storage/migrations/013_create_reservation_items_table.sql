@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reservation_items (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        reservation_id INTEGER NOT NULL,
+        book_id INTEGER NOT NULL,
+        quantity INTEGER NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (reservation_id) REFERENCES reservations(id),
+        FOREIGN KEY (book_id) REFERENCES books(id)
+);
+
+-- +goose StatementBegin
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS reservation_items;
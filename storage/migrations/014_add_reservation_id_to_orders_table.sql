@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE orders ADD COLUMN reservation_id INTEGER REFERENCES reservations(id);
+
+-- +goose StatementBegin
+-- +goose StatementEnd
+
+-- +goose Down
+ALTER TABLE orders DROP COLUMN reservation_id;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS wishlist_items (
+        user_id INTEGER NOT NULL,
+        book_id INTEGER NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (user_id, book_id),
+        FOREIGN KEY (user_id) REFERENCES users(id),
+        FOREIGN KEY (book_id) REFERENCES books(id)
+);
+
+-- +goose StatementBegin
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS wishlist_items;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS book_recommendations (
+        book_id INTEGER NOT NULL,
+        recommended_book_id INTEGER NOT NULL,
+        score INTEGER NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (book_id, recommended_book_id),
+        FOREIGN KEY (book_id) REFERENCES books(id),
+        FOREIGN KEY (recommended_book_id) REFERENCES books(id)
+);
+CREATE INDEX IF NOT EXISTS book_recommendations_book_id_score_idx ON book_recommendations (book_id, score DESC);
+
+-- +goose StatementBegin
+-- +goose StatementEnd
+
+-- +goose Down
+DROP INDEX IF EXISTS book_recommendations_book_id_score_idx;
+DROP TABLE IF EXISTS book_recommendations;
@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS catalog_version (
+        id INTEGER PRIMARY KEY CHECK (id = 1),
+        version INTEGER NOT NULL DEFAULT 0,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementBegin
+INSERT INTO catalog_version (id, version) VALUES (1, 1);
+
+-- the catalog version is bumped whenever what we show about a book changes,
+-- stock movements are left out as they happen on every order.
+CREATE TRIGGER IF NOT EXISTS books_insert_catalog_version AFTER INSERT ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_update_catalog_version
+AFTER UPDATE OF title, author, price, description, isbn_10, isbn_13 ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_delete_catalog_version AFTER DELETE ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_authors_insert_catalog_version AFTER INSERT ON book_authors
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_authors_delete_catalog_version AFTER DELETE ON book_authors
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS book_authors_delete_catalog_version;
+DROP TRIGGER IF EXISTS book_authors_insert_catalog_version;
+DROP TRIGGER IF EXISTS books_delete_catalog_version;
+DROP TRIGGER IF EXISTS books_update_catalog_version;
+DROP TRIGGER IF EXISTS books_insert_catalog_version;
+DROP TABLE IF EXISTS catalog_version;
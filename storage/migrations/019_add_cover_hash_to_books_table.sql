@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE books ADD COLUMN cover_hash TEXT;
+
+-- +goose StatementBegin
+-- a new cover changes the URLs shown for a book.
+CREATE TRIGGER IF NOT EXISTS books_update_cover_catalog_version
+AFTER UPDATE OF cover_hash ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS books_update_cover_catalog_version;
+ALTER TABLE books DROP COLUMN cover_hash;
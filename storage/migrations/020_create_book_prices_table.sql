@@ -0,0 +1,56 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS book_prices (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        book_id INTEGER NOT NULL,
+        price TEXT NOT NULL,
+        effective_from DATETIME NOT NULL,
+        effective_to DATETIME,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (book_id) REFERENCES books(id),
+        CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+CREATE INDEX IF NOT EXISTS book_prices_book_id_idx ON book_prices (book_id, effective_from);
+
+-- +goose StatementBegin
+INSERT INTO book_prices (book_id, price, effective_from)
+        SELECT id, price, created_at
+        FROM books;
+
+-- a price set on the book itself takes effect right away, the price it
+-- replaces is kept as history.
+CREATE TRIGGER IF NOT EXISTS books_insert_book_prices AFTER INSERT ON books
+BEGIN
+        INSERT INTO book_prices (book_id, price, effective_from) VALUES (NEW.id, NEW.price, CURRENT_TIMESTAMP);
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_update_book_prices
+AFTER UPDATE OF price ON books
+WHEN NEW.price <> OLD.price
+BEGIN
+        UPDATE book_prices
+        SET effective_to = CURRENT_TIMESTAMP
+        WHERE book_id = NEW.id
+                AND effective_to IS NULL
+                AND effective_from < CURRENT_TIMESTAMP;
+        INSERT INTO book_prices (book_id, price, effective_from) VALUES (NEW.id, NEW.price, CURRENT_TIMESTAMP);
+END;
+
+-- a scheduled price changes what we show about a book once it takes effect.
+CREATE TRIGGER IF NOT EXISTS book_prices_insert_catalog_version AFTER INSERT ON book_prices
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_prices_delete_catalog_version AFTER DELETE ON book_prices
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS book_prices_delete_catalog_version;
+DROP TRIGGER IF EXISTS book_prices_insert_catalog_version;
+DROP TRIGGER IF EXISTS books_update_book_prices;
+DROP TRIGGER IF EXISTS books_insert_book_prices;
+DROP INDEX IF EXISTS book_prices_book_id_idx;
+DROP TABLE IF EXISTS book_prices;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE books ADD COLUMN archived_at DATETIME;
+
+-- +goose StatementBegin
+-- archiving or restoring a book adds or removes it from our listings.
+CREATE TRIGGER IF NOT EXISTS books_archive_catalog_version
+AFTER UPDATE OF archived_at ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS books_archive_catalog_version;
+ALTER TABLE books DROP COLUMN archived_at;
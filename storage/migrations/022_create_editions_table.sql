@@ -0,0 +1,128 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS editions (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        book_id INTEGER NOT NULL,
+        format TEXT NOT NULL CHECK (format IN ('hardcover', 'paperback', 'ebook')),
+        isbn_10 TEXT,
+        isbn_13 TEXT,
+        price TEXT,
+        stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+        is_default BOOLEAN NOT NULL DEFAULT 0,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (book_id) REFERENCES books(id)
+);
+CREATE INDEX IF NOT EXISTS editions_book_id_idx ON editions (book_id);
+CREATE UNIQUE INDEX IF NOT EXISTS editions_isbn_10_idx ON editions (isbn_10);
+CREATE UNIQUE INDEX IF NOT EXISTS editions_isbn_13_idx ON editions (isbn_13);
+CREATE UNIQUE INDEX IF NOT EXISTS editions_default_idx ON editions (book_id) WHERE is_default;
+
+ALTER TABLE reservation_items ADD COLUMN edition_id INTEGER REFERENCES editions(id);
+ALTER TABLE order_items ADD COLUMN edition_id INTEGER REFERENCES editions(id);
+
+-- +goose StatementBegin
+-- every book is sold as its default edition, which keeps the id of the book
+-- so existing reservation and order items point at the edition they sold.
+INSERT INTO editions (id, book_id, format, isbn_10, isbn_13, stock, is_default)
+        SELECT id, id, 'paperback', isbn_10, isbn_13, stock, 1
+        FROM books;
+
+UPDATE reservation_items SET edition_id = book_id;
+UPDATE order_items SET edition_id = book_id;
+
+-- the price a book sells at right now, a price with an end such as a
+-- promotion takes precedence over open-ended ones, then the latest start
+-- wins. The price set on the book itself is only a fallback.
+CREATE VIEW IF NOT EXISTS current_book_prices AS
+        SELECT b.id AS book_id,
+                COALESCE((
+                        SELECT bp.price
+                        FROM book_prices bp
+                        WHERE bp.book_id = b.id
+                                AND bp.effective_from <= CURRENT_TIMESTAMP
+                                AND (bp.effective_to IS NULL OR bp.effective_to > CURRENT_TIMESTAMP)
+                        ORDER BY bp.effective_to IS NULL, bp.effective_from DESC, bp.id DESC
+                        LIMIT 1
+                ), b.price) AS price
+        FROM books b;
+
+-- the ISBNs and stock set on a book are the ones of its default edition, an
+-- edition without a price of its own sells at the price of its book.
+CREATE TRIGGER IF NOT EXISTS books_insert_editions AFTER INSERT ON books
+BEGIN
+        INSERT INTO editions (book_id, format, isbn_10, isbn_13, stock, is_default)
+        VALUES (NEW.id, 'paperback', NEW.isbn_10, NEW.isbn_13, NEW.stock, 1);
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_update_isbn_editions
+AFTER UPDATE OF isbn_10, isbn_13 ON books
+WHEN NEW.isbn_10 IS NOT OLD.isbn_10 OR NEW.isbn_13 IS NOT OLD.isbn_13
+BEGIN
+        UPDATE editions
+        SET isbn_10 = NEW.isbn_10, isbn_13 = NEW.isbn_13, updated_at = CURRENT_TIMESTAMP
+        WHERE book_id = NEW.id AND is_default
+                AND (isbn_10 IS NOT NEW.isbn_10 OR isbn_13 IS NOT NEW.isbn_13);
+END;
+
+CREATE TRIGGER IF NOT EXISTS editions_update_isbn_books
+AFTER UPDATE OF isbn_10, isbn_13 ON editions
+WHEN NEW.is_default AND (NEW.isbn_10 IS NOT OLD.isbn_10 OR NEW.isbn_13 IS NOT OLD.isbn_13)
+BEGIN
+        UPDATE books
+        SET isbn_10 = NEW.isbn_10, isbn_13 = NEW.isbn_13, updated_at = CURRENT_TIMESTAMP
+        WHERE id = NEW.book_id
+                AND (isbn_10 IS NOT NEW.isbn_10 OR isbn_13 IS NOT NEW.isbn_13);
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_update_stock_editions
+AFTER UPDATE OF stock ON books
+WHEN NEW.stock <> OLD.stock
+BEGIN
+        UPDATE editions
+        SET stock = NEW.stock, updated_at = CURRENT_TIMESTAMP
+        WHERE book_id = NEW.id AND is_default AND stock <> NEW.stock;
+END;
+
+CREATE TRIGGER IF NOT EXISTS editions_update_stock_books
+AFTER UPDATE OF stock ON editions
+WHEN NEW.is_default AND NEW.stock <> OLD.stock
+BEGIN
+        UPDATE books
+        SET stock = NEW.stock, updated_at = CURRENT_TIMESTAMP
+        WHERE id = NEW.book_id AND stock <> NEW.stock;
+END;
+
+CREATE TRIGGER IF NOT EXISTS editions_insert_catalog_version AFTER INSERT ON editions
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS editions_update_catalog_version
+AFTER UPDATE OF format, isbn_10, isbn_13, price ON editions
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS editions_delete_catalog_version AFTER DELETE ON editions
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS editions_delete_catalog_version;
+DROP TRIGGER IF EXISTS editions_update_catalog_version;
+DROP TRIGGER IF EXISTS editions_insert_catalog_version;
+DROP TRIGGER IF EXISTS editions_update_stock_books;
+DROP TRIGGER IF EXISTS books_update_stock_editions;
+DROP TRIGGER IF EXISTS books_update_isbn_editions;
+DROP TRIGGER IF EXISTS editions_update_isbn_books;
+DROP TRIGGER IF EXISTS books_insert_editions;
+DROP VIEW IF EXISTS current_book_prices;
+ALTER TABLE order_items DROP COLUMN edition_id;
+ALTER TABLE reservation_items DROP COLUMN edition_id;
+DROP INDEX IF EXISTS editions_default_idx;
+DROP INDEX IF EXISTS editions_isbn_13_idx;
+DROP INDEX IF EXISTS editions_isbn_10_idx;
+DROP INDEX IF EXISTS editions_book_id_idx;
+DROP TABLE IF EXISTS editions;
@@ -0,0 +1,46 @@
+-- +goose Up
+ALTER TABLE editions ADD COLUMN file_key TEXT;
+ALTER TABLE editions ADD COLUMN file_name TEXT;
+
+CREATE TABLE IF NOT EXISTS entitlements (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        user_id INTEGER NOT NULL,
+        edition_id INTEGER NOT NULL,
+        order_id INTEGER NOT NULL,
+        download_count INTEGER NOT NULL DEFAULT 0,
+        last_downloaded_at DATETIME,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (user_id, edition_id),
+        FOREIGN KEY (user_id) REFERENCES users(id),
+        FOREIGN KEY (edition_id) REFERENCES editions(id),
+        FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE IF NOT EXISTS entitlement_downloads (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        entitlement_id INTEGER NOT NULL,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (entitlement_id) REFERENCES entitlements(id)
+);
+CREATE INDEX IF NOT EXISTS entitlement_downloads_entitlement_id_idx ON entitlement_downloads (entitlement_id, created_at);
+
+-- +goose StatementBegin
+-- ebooks ordered before entitlements existed are owned by their buyer too.
+INSERT OR IGNORE INTO entitlements (user_id, edition_id, order_id)
+        SELECT o.user_id, oi.edition_id, MIN(o.id)
+        FROM order_items oi
+        JOIN orders o
+                ON o.id = oi.order_id
+        JOIN editions e
+                ON e.id = oi.edition_id
+        WHERE e.format = 'ebook'
+        GROUP BY o.user_id, oi.edition_id;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP INDEX IF EXISTS entitlement_downloads_entitlement_id_idx;
+DROP TABLE IF EXISTS entitlement_downloads;
+DROP TABLE IF EXISTS entitlements;
+ALTER TABLE editions DROP COLUMN file_name;
+ALTER TABLE editions DROP COLUMN file_key;
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS series (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL UNIQUE,
+        description TEXT NOT NULL DEFAULT '',
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE books ADD COLUMN series_id INTEGER REFERENCES series(id);
+ALTER TABLE books ADD COLUMN series_position INTEGER;
+
+-- a position holds a single book of a series.
+CREATE UNIQUE INDEX IF NOT EXISTS books_series_position_idx ON books (series_id, series_position) WHERE series_id IS NOT NULL;
+
+-- +goose StatementBegin
+-- books show the series they belong to.
+CREATE TRIGGER IF NOT EXISTS books_update_series_catalog_version
+AFTER UPDATE OF series_id, series_position ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS series_update_catalog_version
+AFTER UPDATE OF name ON series
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS series_update_catalog_version;
+DROP TRIGGER IF EXISTS books_update_series_catalog_version;
+DROP INDEX IF EXISTS books_series_position_idx;
+ALTER TABLE books DROP COLUMN series_position;
+ALTER TABLE books DROP COLUMN series_id;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS publishers (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL,
+        slug TEXT NOT NULL UNIQUE,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE books ADD COLUMN publisher_id INTEGER REFERENCES publishers(id);
+CREATE INDEX IF NOT EXISTS books_publisher_id_idx ON books (publisher_id);
+
+-- +goose StatementBegin
+-- books show their publisher.
+CREATE TRIGGER IF NOT EXISTS books_update_publisher_catalog_version
+AFTER UPDATE OF publisher_id ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS publishers_update_catalog_version
+AFTER UPDATE OF name, slug ON publishers
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS publishers_update_catalog_version;
+DROP TRIGGER IF EXISTS books_update_publisher_catalog_version;
+DROP INDEX IF EXISTS books_publisher_id_idx;
+ALTER TABLE books DROP COLUMN publisher_id;
+DROP TABLE IF EXISTS publishers;
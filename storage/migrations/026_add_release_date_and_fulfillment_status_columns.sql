@@ -0,0 +1,24 @@
+-- +goose Up
+-- books are released on their release date, a book without one is out
+-- already. Dates are kept as YYYY-MM-DD so they compare as text.
+ALTER TABLE books ADD COLUMN release_date TEXT CHECK (release_date IS NULL OR date(release_date) IS release_date);
+
+-- items of books ordered before their release are preordered until they can
+-- be shipped.
+ALTER TABLE order_items ADD COLUMN fulfillment_status TEXT NOT NULL DEFAULT 'ready_to_ship'
+        CHECK (fulfillment_status IN ('preordered', 'ready_to_ship'));
+CREATE INDEX IF NOT EXISTS order_items_fulfillment_status_idx ON order_items (fulfillment_status);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS books_update_release_date_catalog_version
+AFTER UPDATE OF release_date ON books
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS books_update_release_date_catalog_version;
+DROP INDEX IF EXISTS order_items_fulfillment_status_idx;
+ALTER TABLE order_items DROP COLUMN fulfillment_status;
+ALTER TABLE books DROP COLUMN release_date;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- books are reordered once their stock falls to their reorder threshold, a
+-- book without one is never alerted on.
+ALTER TABLE books ADD COLUMN reorder_threshold INTEGER CHECK (reorder_threshold IS NULL OR reorder_threshold >= 0);
+
+CREATE TABLE IF NOT EXISTS stock_alerts (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        book_id INTEGER NOT NULL,
+        threshold INTEGER NOT NULL,
+        stock INTEGER NOT NULL,
+        status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+        notified_at DATETIME,
+        resolved_at DATETIME,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        FOREIGN KEY (book_id) REFERENCES books(id)
+);
+
+-- a book has at most one open alert, so a low stock is alerted on once.
+CREATE UNIQUE INDEX IF NOT EXISTS stock_alerts_open_book_id_idx ON stock_alerts (book_id) WHERE status = 'open';
+
+-- +goose StatementBegin
+-- +goose StatementEnd
+
+-- +goose Down
+DROP INDEX IF EXISTS stock_alerts_open_book_id_idx;
+DROP TABLE IF EXISTS stock_alerts;
+ALTER TABLE books DROP COLUMN reorder_threshold;
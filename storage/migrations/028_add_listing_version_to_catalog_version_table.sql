@@ -0,0 +1,41 @@
+-- +goose Up
+-- the listing version is bumped whenever what we list about a book changes
+-- without changing the catalog, such as its stock or its reviews.
+ALTER TABLE catalog_version ADD COLUMN listing_version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE catalog_version ADD COLUMN listing_updated_at DATETIME;
+
+-- +goose StatementBegin
+UPDATE catalog_version SET listing_version = 1, listing_updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+
+CREATE TRIGGER IF NOT EXISTS editions_update_stock_listing_version
+AFTER UPDATE OF stock ON editions
+WHEN NEW.stock <> OLD.stock
+BEGIN
+        UPDATE catalog_version SET listing_version = listing_version + 1, listing_updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviews_insert_listing_version AFTER INSERT ON reviews
+BEGIN
+        UPDATE catalog_version SET listing_version = listing_version + 1, listing_updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviews_update_listing_version
+AFTER UPDATE OF rating ON reviews
+WHEN NEW.rating <> OLD.rating
+BEGIN
+        UPDATE catalog_version SET listing_version = listing_version + 1, listing_updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS reviews_delete_listing_version AFTER DELETE ON reviews
+BEGIN
+        UPDATE catalog_version SET listing_version = listing_version + 1, listing_updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS reviews_delete_listing_version;
+DROP TRIGGER IF EXISTS reviews_update_listing_version;
+DROP TRIGGER IF EXISTS reviews_insert_listing_version;
+DROP TRIGGER IF EXISTS editions_update_stock_listing_version;
+ALTER TABLE catalog_version DROP COLUMN listing_updated_at;
+ALTER TABLE catalog_version DROP COLUMN listing_version;
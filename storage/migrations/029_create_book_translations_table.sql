@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS book_translations (
+        book_id INTEGER NOT NULL,
+        locale TEXT NOT NULL,
+        title TEXT NOT NULL,
+        description TEXT,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (book_id, locale),
+        FOREIGN KEY (book_id) REFERENCES books(id)
+);
+CREATE INDEX IF NOT EXISTS book_translations_locale_idx ON book_translations (locale);
+
+-- +goose StatementBegin
+-- books are listed with their translations.
+CREATE TRIGGER IF NOT EXISTS book_translations_insert_catalog_version AFTER INSERT ON book_translations
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_translations_update_catalog_version
+AFTER UPDATE OF title, description ON book_translations
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_translations_delete_catalog_version AFTER DELETE ON book_translations
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS book_translations_delete_catalog_version;
+DROP TRIGGER IF EXISTS book_translations_update_catalog_version;
+DROP TRIGGER IF EXISTS book_translations_insert_catalog_version;
+DROP INDEX IF EXISTS book_translations_locale_idx;
+DROP TABLE IF EXISTS book_translations;
@@ -0,0 +1,38 @@
+-- +goose Up
+-- book_changes logs every change of a book in order, books are not
+-- referenced so changes of deleted books are kept.
+CREATE TABLE IF NOT EXISTS book_changes (
+        seq INTEGER PRIMARY KEY AUTOINCREMENT,
+        book_id INTEGER NOT NULL,
+        operation TEXT NOT NULL CHECK (operation IN ('insert', 'update', 'delete')),
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementBegin
+-- existing books are logged as inserted so the log replays the catalog.
+INSERT INTO book_changes (book_id, operation)
+        SELECT id, 'insert'
+        FROM books
+        ORDER BY id;
+
+CREATE TRIGGER IF NOT EXISTS books_insert_book_changes AFTER INSERT ON books
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.id, 'insert');
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_update_book_changes AFTER UPDATE ON books
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS books_delete_book_changes AFTER DELETE ON books
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (OLD.id, 'delete');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS books_delete_book_changes;
+DROP TRIGGER IF EXISTS books_update_book_changes;
+DROP TRIGGER IF EXISTS books_insert_book_changes;
+DROP TABLE IF EXISTS book_changes;
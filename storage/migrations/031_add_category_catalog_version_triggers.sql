@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- books are filtered and counted by their categories, including the
+-- categories under them.
+CREATE TRIGGER IF NOT EXISTS book_categories_insert_catalog_version AFTER INSERT ON book_categories
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_categories_delete_catalog_version AFTER DELETE ON book_categories
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS categories_update_catalog_version
+AFTER UPDATE OF parent_id, name, slug ON categories
+WHEN NEW.parent_id IS NOT OLD.parent_id OR NEW.name IS NOT OLD.name OR NEW.slug IS NOT OLD.slug
+BEGIN
+        UPDATE catalog_version SET version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = 1;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS categories_update_catalog_version;
+DROP TRIGGER IF EXISTS book_categories_delete_catalog_version;
+DROP TRIGGER IF EXISTS book_categories_insert_catalog_version;
@@ -0,0 +1,86 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a book is listed with the price, stock and translations of its editions,
+-- and with the authors it credits, so writing any of them changes the book.
+-- The ISBNs and stock of a default edition are copied to its book, whose own
+-- trigger logs them, and a book inserts its default edition itself.
+CREATE TRIGGER IF NOT EXISTS editions_insert_book_changes AFTER INSERT ON editions
+WHEN NOT NEW.is_default
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS editions_update_book_changes
+AFTER UPDATE OF format, isbn_10, isbn_13, price, stock, is_default ON editions
+WHEN NEW.format IS NOT OLD.format
+        OR NEW.price IS NOT OLD.price
+        OR NEW.is_default IS NOT OLD.is_default
+        OR (NOT NEW.is_default AND (
+                NEW.isbn_10 IS NOT OLD.isbn_10
+                OR NEW.isbn_13 IS NOT OLD.isbn_13
+                OR NEW.stock IS NOT OLD.stock
+        ))
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS editions_delete_book_changes AFTER DELETE ON editions
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (OLD.book_id, 'update');
+END;
+
+-- scheduling a price is logged when it is written, not once it takes
+-- effect.
+CREATE TRIGGER IF NOT EXISTS book_prices_insert_book_changes AFTER INSERT ON book_prices
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_prices_update_book_changes AFTER UPDATE ON book_prices
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_prices_delete_book_changes AFTER DELETE ON book_prices
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (OLD.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_translations_insert_book_changes AFTER INSERT ON book_translations
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_translations_update_book_changes AFTER UPDATE ON book_translations
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_translations_delete_book_changes AFTER DELETE ON book_translations
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (OLD.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_authors_insert_book_changes AFTER INSERT ON book_authors
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.book_id, 'update');
+END;
+
+CREATE TRIGGER IF NOT EXISTS book_authors_delete_book_changes AFTER DELETE ON book_authors
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (OLD.book_id, 'update');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS book_authors_delete_book_changes;
+DROP TRIGGER IF EXISTS book_authors_insert_book_changes;
+DROP TRIGGER IF EXISTS book_translations_delete_book_changes;
+DROP TRIGGER IF EXISTS book_translations_update_book_changes;
+DROP TRIGGER IF EXISTS book_translations_insert_book_changes;
+DROP TRIGGER IF EXISTS book_prices_delete_book_changes;
+DROP TRIGGER IF EXISTS book_prices_update_book_changes;
+DROP TRIGGER IF EXISTS book_prices_insert_book_changes;
+DROP TRIGGER IF EXISTS editions_delete_book_changes;
+DROP TRIGGER IF EXISTS editions_update_book_changes;
+DROP TRIGGER IF EXISTS editions_insert_book_changes;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- authors were backfilled from the author column of books as it was written,
+-- so co-authors such as "Chip Heath, Dan Heath" became a single author. Every
+-- one of them is made an author of their own, credited in the order they were
+-- written from the position of the credit they were split from.
+INSERT OR IGNORE INTO authors (name)
+        WITH RECURSIVE split(name, rest) AS (
+                SELECT '', name || ','
+                FROM authors
+                WHERE name LIKE '%,%'
+                UNION ALL
+                SELECT TRIM(SUBSTR(rest, 1, INSTR(rest, ',') - 1)), SUBSTR(rest, INSTR(rest, ',') + 1)
+                FROM split
+                WHERE rest <> ''
+        )
+        SELECT DISTINCT name
+        FROM split
+        WHERE name <> '';
+
+INSERT OR IGNORE INTO book_authors (book_id, author_id, role, position)
+        WITH RECURSIVE split(book_id, role, position, name, rest) AS (
+                SELECT ba.book_id, ba.role, ba.position - 1, '', a.name || ','
+                FROM book_authors ba
+                JOIN authors a
+                        ON a.id = ba.author_id
+                WHERE a.name LIKE '%,%'
+                UNION ALL
+                SELECT book_id, role, position + 1, TRIM(SUBSTR(rest, 1, INSTR(rest, ',') - 1)), SUBSTR(rest, INSTR(rest, ',') + 1)
+                FROM split
+                WHERE rest <> ''
+        )
+        SELECT s.book_id, a.id, s.role, s.position
+        FROM split s
+        JOIN authors a
+                ON a.name = s.name
+        WHERE s.name <> '';
+
+DELETE FROM book_authors
+WHERE author_id IN (SELECT id FROM authors WHERE name LIKE '%,%');
+
+DELETE FROM authors
+WHERE name LIKE '%,%';
+-- +goose StatementEnd
+
+-- +goose Down
+-- co-authors are not joined back into a single author.
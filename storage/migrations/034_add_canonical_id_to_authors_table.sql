@@ -0,0 +1,9 @@
+-- +goose Up
+-- an author merged into another is kept as an alias of it, so books credited
+-- to the alias later on are credited to the author it was merged into.
+ALTER TABLE authors ADD COLUMN canonical_id INTEGER REFERENCES authors(id);
+CREATE INDEX IF NOT EXISTS authors_canonical_id_idx ON authors (canonical_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS authors_canonical_id_idx;
+ALTER TABLE authors DROP COLUMN canonical_id;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- only writes changing what a book is listed with are logged, leaving out
+-- writes such as of its reorder threshold or of values it already has.
+DROP TRIGGER IF EXISTS books_update_book_changes;
+
+CREATE TRIGGER IF NOT EXISTS books_update_book_changes
+AFTER UPDATE OF title, author, price, description, isbn_10, isbn_13, stock, cover_hash, archived_at,
+        series_id, series_position, publisher_id, release_date ON books
+WHEN NEW.title IS NOT OLD.title
+        OR NEW.author IS NOT OLD.author
+        OR NEW.price IS NOT OLD.price
+        OR NEW.description IS NOT OLD.description
+        OR NEW.isbn_10 IS NOT OLD.isbn_10
+        OR NEW.isbn_13 IS NOT OLD.isbn_13
+        OR NEW.stock IS NOT OLD.stock
+        OR NEW.cover_hash IS NOT OLD.cover_hash
+        OR NEW.archived_at IS NOT OLD.archived_at
+        OR NEW.series_id IS NOT OLD.series_id
+        OR NEW.series_position IS NOT OLD.series_position
+        OR NEW.publisher_id IS NOT OLD.publisher_id
+        OR NEW.release_date IS NOT OLD.release_date
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.id, 'update');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS books_update_book_changes;
+
+CREATE TRIGGER IF NOT EXISTS books_update_book_changes AFTER UPDATE ON books
+BEGIN
+        INSERT INTO book_changes (book_id, operation) VALUES (NEW.id, 'update');
+END;
+-- +goose StatementEnd
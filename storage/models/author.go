@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Author struct {
+	ID   int64  `db:"id" json:"id"`
+	Name string `db:"name" json:"name"`
+	// Aliases are the names of the authors merged into this one.
+	Aliases   []string  `db:"-" json:"aliases,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"-"`
+	UpdatedAt time.Time `db:"updated_at" json:"-"`
+}
+
+// BookAuthor is the credit of an author on a book, ordered by position.
+type BookAuthor struct {
+	BookID   int64  `db:"book_id" json:"-"`
+	AuthorID int64  `db:"author_id" json:"id"`
+	Name     string `db:"name" json:"name"`
+	Role     string `db:"role" json:"role"`
+	Position int64  `db:"position" json:"position"`
+}
@@ -3,11 +3,99 @@ package models
 import "time"
 
 type Book struct {
-	ID          int64     `db:"id" json:"id"`
-	Title       string    `db:"title" json:"title"`
-	Author      string    `db:"author" json:"author"`
-	Price       string    `db:"price" json:"price"`
-	Description string    `db:"description" json:"description"`
-	CreatedAt   time.Time `db:"created_at" json:"-"`
-	UpdatedAt   time.Time `db:"updated_at" json:"-"`
+	ID             int64          `db:"id" json:"id"`
+	Title          string         `db:"title" json:"title"`
+	Author         string         `db:"author" json:"author"`
+	Price          string         `db:"price" json:"price"`
+	Description    string         `db:"description" json:"description"`
+	ISBN10         string         `db:"isbn_10" json:"isbn_10"`
+	ISBN13         string         `db:"isbn_13" json:"isbn_13"`
+	Stock          int64          `db:"stock" json:"stock"`
+	StockStatus    string         `db:"stock_status" json:"stock_status"`
+	ReleaseDate    string         `db:"release_date" json:"release_date,omitempty"`
+	AverageRating  float64        `db:"average_rating" json:"average_rating"`
+	ReviewCount    int64          `db:"review_count" json:"review_count"`
+	Authors        []*BookAuthor  `db:"-" json:"authors"`
+	Editions       []*Edition     `db:"-" json:"editions"`
+	CoverHash      string         `db:"cover_hash" json:"-"`
+	Cover          *BookCover     `db:"-" json:"cover,omitempty"`
+	SeriesID       int64          `db:"series_id" json:"-"`
+	SeriesName     string         `db:"series_name" json:"-"`
+	SeriesPosition int64          `db:"series_position" json:"-"`
+	Series         *BookSeries    `db:"-" json:"series,omitempty"`
+	PublisherID    int64          `db:"publisher_id" json:"-"`
+	PublisherName  string         `db:"publisher_name" json:"-"`
+	PublisherSlug  string         `db:"publisher_slug" json:"-"`
+	Publisher      *BookPublisher `db:"-" json:"publisher,omitempty"`
+	ArchivedAt     *time.Time     `db:"archived_at" json:"archived_at,omitempty"`
+	CreatedAt      time.Time      `db:"created_at" json:"-"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"-"`
+}
+
+// BookFilter narrows down the books listed from our catalog, empty fields
+// do not filter.
+type BookFilter struct {
+	// Publisher is the slug of the publisher of the books.
+	Publisher string
+
+	// Query is matched against the title and the credited authors of the
+	// books.
+	Query string
+
+	// AuthorID is the id of an author credited on the books.
+	AuthorID int64
+
+	// Category is the slug of a category of the books, which includes its
+	// subcategories.
+	Category string
+
+	// PriceBand is the key of the price band of the current price of the
+	// books, see PriceBands.
+	PriceBand string
+}
+
+// CatalogState identifies what our book listings show, it changes whenever
+// anything they show changes.
+type CatalogState struct {
+	Version        int64
+	ListingVersion int64
+	ModifiedAt     time.Time
+}
+
+// BookUpsert is a book to create, or to update when it matches an existing
+// book by its id or else by its ISBN-13. Fields left nil are kept as they are
+// when updating a book, given authors replace the credited authors in order.
+type BookUpsert struct {
+	ID          int64
+	ISBN13      string
+	Title       *string
+	Authors     []string
+	Price       *string
+	Description *string
+	Stock       *int64
+}
+
+// BookUpsertResult reports what happened to a book upsert, Err is set when
+// the book could not be upserted.
+type BookUpsertResult struct {
+	BookID  int64
+	Created bool
+	Err     error
+}
+
+const (
+	BookChangeInsert = "insert"
+	BookChangeUpdate = "update"
+	BookChangeDelete = "delete"
+)
+
+// BookChange is an entry of the change log of our books, changes are
+// sequenced in the order they happened. Book is the book as it is now, nil
+// when it was deleted or archived since.
+type BookChange struct {
+	Seq       int64     `db:"seq" json:"seq"`
+	BookID    int64     `db:"book_id" json:"book_id"`
+	Operation string    `db:"operation" json:"operation"`
+	CreatedAt time.Time `db:"created_at" json:"changed_at"`
+	Book      *Book     `db:"-" json:"book"`
 }
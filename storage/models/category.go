@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Category struct {
+	ID        int64       `db:"id" json:"id"`
+	ParentID  *int64      `db:"parent_id" json:"parent_id"`
+	Name      string      `db:"name" json:"name"`
+	Slug      string      `db:"slug" json:"slug"`
+	Children  []*Category `db:"-" json:"children"`
+	CreatedAt time.Time   `db:"created_at" json:"-"`
+	UpdatedAt time.Time   `db:"updated_at" json:"-"`
+}
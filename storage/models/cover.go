@@ -0,0 +1,30 @@
+package models
+
+// coverURLPrefix is where covers are served from, a cover is stored under the
+// hash of its original image so its URLs never change.
+const coverURLPrefix = "/v1/covers/"
+
+// BookCover are the URLs of the cover of a book, its original image and its
+// thumbnails.
+type BookCover struct {
+	Original string `json:"original"`
+	Small    string `json:"small"`
+	Medium   string `json:"medium"`
+	Large    string `json:"large"`
+}
+
+// NewBookCover returns the URLs of the cover with the given hash, or nil when
+// there is no cover.
+func NewBookCover(hash string) *BookCover {
+	if hash == "" {
+		return nil
+	}
+
+	url := coverURLPrefix + hash + "/"
+	return &BookCover{
+		Original: url + "original",
+		Small:    url + "small",
+		Medium:   url + "medium",
+		Large:    url + "large",
+	}
+}
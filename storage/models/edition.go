@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	EditionFormatHardcover = "hardcover"
+	EditionFormatPaperback = "paperback"
+	EditionFormatEbook     = "ebook"
+)
+
+// Edition is a format a book is sold in with its own ISBN, price and stock.
+// Every book has a default edition carrying the ISBN and stock set on the
+// book, an edition without a price of its own sells at the price of its
+// book. Ebooks never run out of stock.
+type Edition struct {
+	ID          int64     `db:"id" json:"id"`
+	BookID      int64     `db:"book_id" json:"book_id"`
+	Format      string    `db:"format" json:"format"`
+	ISBN10      string    `db:"isbn_10" json:"isbn_10"`
+	ISBN13      string    `db:"isbn_13" json:"isbn_13"`
+	Price       string    `db:"price" json:"price"`
+	Stock       int64     `db:"stock" json:"stock"`
+	StockStatus string    `db:"stock_status" json:"stock_status"`
+	IsDefault   bool      `db:"is_default" json:"is_default"`
+	CreatedAt   time.Time `db:"created_at" json:"-"`
+	UpdatedAt   time.Time `db:"updated_at" json:"-"`
+}
+
+// EditionUpdate are the fields of an edition to change, fields left nil are
+// kept as they are. An empty price makes the edition sell at the price of its
+// book again.
+type EditionUpdate struct {
+	Format *string
+	ISBN13 *string
+	Price  *string
+	Stock  *int64
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Entitlement lets a user download an ebook they bought, it is granted once
+// per user and edition however many times the ebook is ordered.
+type Entitlement struct {
+	ID               int64      `db:"id" json:"id"`
+	UserID           int64      `db:"user_id" json:"-"`
+	EditionID        int64      `db:"edition_id" json:"edition_id"`
+	BookID           int64      `db:"book_id" json:"book_id"`
+	OrderID          int64      `db:"order_id" json:"order_id"`
+	Title            string     `db:"title" json:"title"`
+	Author           string     `db:"author" json:"author"`
+	FileKey          string     `db:"file_key" json:"-"`
+	FileName         string     `db:"file_name" json:"-"`
+	Downloadable     bool       `db:"downloadable" json:"downloadable"`
+	DownloadCount    int64      `db:"download_count" json:"download_count"`
+	LastDownloadedAt *time.Time `db:"last_downloaded_at" json:"last_downloaded_at"`
+	CreatedAt        time.Time  `db:"created_at" json:"granted_at"`
+}
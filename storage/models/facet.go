@@ -0,0 +1,60 @@
+package models
+
+// PriceBand is a range of prices books are counted in, from MinCents up to
+// but excluding MaxCents. A band without a MaxCents has no upper bound.
+type PriceBand struct {
+	Key      string
+	MinCents int64
+	MaxCents int64
+}
+
+// PriceBands are the price bands books are faceted by, in order.
+var PriceBands = []*PriceBand{
+	{Key: "under-10", MinCents: 0, MaxCents: 1000},
+	{Key: "10-20", MinCents: 1000, MaxCents: 2000},
+	{Key: "20-50", MinCents: 2000, MaxCents: 5000},
+	{Key: "50-and-over", MinCents: 5000},
+}
+
+// GetPriceBand returns the price band with the given key.
+func GetPriceBand(key string) (*PriceBand, bool) {
+	for _, band := range PriceBands {
+		if band.Key == key {
+			return band, true
+		}
+	}
+	return nil, false
+}
+
+// BookFacets count the books matching a filter by author, price band and
+// category. Each facet counts the books matching every other field of the
+// filter, so the alternatives to a chosen author, price band or category
+// stay listed.
+type BookFacets struct {
+	Authors    []*AuthorFacet    `json:"authors"`
+	PriceBands []*PriceBandFacet `json:"price_bands"`
+	Categories []*CategoryFacet  `json:"categories"`
+}
+
+type AuthorFacet struct {
+	ID    int64  `db:"id" json:"id"`
+	Name  string `db:"name" json:"name"`
+	Count int64  `db:"count" json:"count"`
+}
+
+// PriceBandFacet counts the books of a price band, every band is listed.
+type PriceBandFacet struct {
+	Key   string `db:"key" json:"key"`
+	Min   string `db:"-" json:"min"`
+	Max   string `db:"-" json:"max,omitempty"`
+	Count int64  `db:"count" json:"count"`
+}
+
+// CategoryFacet counts the books of a category, including the books of its
+// subcategories.
+type CategoryFacet struct {
+	ID    int64  `db:"id" json:"id"`
+	Slug  string `db:"slug" json:"slug"`
+	Name  string `db:"name" json:"name"`
+	Count int64  `db:"count" json:"count"`
+}
@@ -2,40 +2,61 @@ package models
 
 import "time"
 
+// fulfillment statuses of an order item, items of books ordered before their
+// release are preordered until they can be shipped.
+const (
+	FulfillmentStatusPreordered  = "preordered"
+	FulfillmentStatusReadyToShip = "ready_to_ship"
+)
+
 type Order struct {
-	ID        int64     `db:"id"`
-	UserID    int64     `db:"user_id"`
-	Total     string    `db:"total"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID            int64        `db:"id"`
+	UserID        int64        `db:"user_id"`
+	ReservationID int64        `db:"reservation_id"`
+	Total         string       `db:"total"`
+	Items         []*OrderItem `db:"-"`
+	CreatedAt     time.Time    `db:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at"`
 }
 
 type OrderItem struct {
-	ID        int64     `db:"id"`
-	OrderID   int64     `db:"order_id"`
-	BookID    int64     `db:"book_id"`
-	Price     string    `db:"price"`
-	Quantity  int64     `db:"quantity"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID                int64     `db:"id"`
+	OrderID           int64     `db:"order_id"`
+	BookID            int64     `db:"book_id"`
+	EditionID         int64     `db:"edition_id"`
+	Price             string    `db:"price"`
+	Quantity          int64     `db:"quantity"`
+	FulfillmentStatus string    `db:"fulfillment_status"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
 }
 
 type OrderHistoryData struct {
-	ID          int64  `db:"id"`
-	Total       string `db:"total"`
-	Price       string `db:"price"`
-	Quantity    int64  `db:"quantity"`
-	Title       string `db:"title"`
-	Author      string `db:"author"`
-	Description string `db:"description"`
+	ID                int64  `db:"id"`
+	Total             string `db:"total"`
+	BookID            int64  `db:"book_id"`
+	EditionID         int64  `db:"edition_id"`
+	Format            string `db:"format"`
+	Price             string `db:"price"`
+	Quantity          int64  `db:"quantity"`
+	FulfillmentStatus string `db:"fulfillment_status"`
+	ReleaseDate       string `db:"release_date"`
+	Title             string `db:"title"`
+	Author            string `db:"author"`
+	Description       string `db:"description"`
 }
 
 type OrderHistoryItem struct {
-	Price       string `json:"price"`
-	Quantity    int64  `json:"quantity"`
-	Title       string `json:"title"`
-	Author      string `json:"author"`
-	Description string `json:"description"`
+	BookID            int64  `json:"book_id"`
+	EditionID         int64  `json:"edition_id"`
+	Format            string `json:"format"`
+	Price             string `json:"price"`
+	Quantity          int64  `json:"quantity"`
+	FulfillmentStatus string `json:"fulfillment_status"`
+	ReleaseDate       string `json:"release_date,omitempty"`
+	Title             string `json:"title"`
+	Author            string `json:"author"`
+	Description       string `json:"description"`
 }
 
 type OrderHistory struct {
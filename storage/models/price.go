@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// BookPrice is the price of a book over a period, a price without an end
+// lasts until a later price takes over.
+type BookPrice struct {
+	ID            int64      `db:"id" json:"id"`
+	BookID        int64      `db:"book_id" json:"book_id"`
+	Price         string     `db:"price" json:"price"`
+	EffectiveFrom time.Time  `db:"effective_from" json:"effective_from"`
+	EffectiveTo   *time.Time `db:"effective_to" json:"effective_to"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
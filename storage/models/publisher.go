@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type Publisher struct {
+	ID        int64     `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Slug      string    `db:"slug" json:"slug"`
+	CreatedAt time.Time `db:"created_at" json:"-"`
+	UpdatedAt time.Time `db:"updated_at" json:"-"`
+}
+
+// BookPublisher is the publisher shown on a book.
+type BookPublisher struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// NewBookPublisher returns the publisher of a book, or nil when the book has
+// no publisher.
+func NewBookPublisher(id int64, name, slug string) *BookPublisher {
+	if id == 0 {
+		return nil
+	}
+
+	return &BookPublisher{
+		ID:   id,
+		Name: name,
+		Slug: slug,
+	}
+}
+
+// PublisherSales are the sales of the books of a publisher, revenue is the
+// sum of the prices the books were ordered at.
+type PublisherSales struct {
+	PublisherID int64  `db:"publisher_id" json:"publisher_id"`
+	Name        string `db:"name" json:"name"`
+	Slug        string `db:"slug" json:"slug"`
+	Orders      int64  `db:"orders" json:"orders"`
+	Units       int64  `db:"units" json:"units"`
+	Revenue     string `db:"-" json:"revenue"`
+	// RevenueCents is summed in cents so prices add up exactly.
+	RevenueCents int64 `db:"revenue_cents" json:"-"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusReleased  = "released"
+	ReservationStatusCommitted = "committed"
+)
+
+type Reservation struct {
+	ID        int64              `db:"id" json:"id"`
+	UserID    int64              `db:"user_id" json:"-"`
+	Status    string             `db:"status" json:"status"`
+	ExpiresAt time.Time          `db:"expires_at" json:"expires_at"`
+	Items     []*ReservationItem `db:"-" json:"items"`
+	CreatedAt time.Time          `db:"created_at" json:"-"`
+	UpdatedAt time.Time          `db:"updated_at" json:"-"`
+}
+
+type ReservationItem struct {
+	ID            int64     `db:"id" json:"-"`
+	ReservationID int64     `db:"reservation_id" json:"-"`
+	BookID        int64     `db:"book_id" json:"book_id"`
+	EditionID     int64     `db:"edition_id" json:"edition_id"`
+	Quantity      int64     `db:"quantity" json:"quantity"`
+	CreatedAt     time.Time `db:"created_at" json:"-"`
+	UpdatedAt     time.Time `db:"updated_at" json:"-"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Review struct {
+	ID     int64  `db:"id" json:"id"`
+	BookID int64  `db:"book_id" json:"book_id"`
+	UserID int64  `db:"user_id" json:"user_id"`
+	Rating int64  `db:"rating" json:"rating"`
+	Body   string `db:"body" json:"body"`
+
+	// VerifiedPurchase is set when the reviewer has ordered the book.
+	VerifiedPurchase bool      `db:"verified_purchase" json:"verified_purchase"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
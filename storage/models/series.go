@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type Series struct {
+	ID          int64     `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"-"`
+	UpdatedAt   time.Time `db:"updated_at" json:"-"`
+}
+
+// SeriesEntry is a book at its position within a series.
+type SeriesEntry struct {
+	SeriesID int64 `db:"series_id"`
+	BookID   int64 `db:"book_id"`
+	Position int64 `db:"series_position"`
+}
+
+// BookSeries is the series a book belongs to along with its position in the
+// reading order of the series.
+type BookSeries struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Position int64  `json:"position"`
+}
+
+// NewBookSeries returns the series of a book, or nil when the book is not
+// part of a series.
+func NewBookSeries(id int64, name string, position int64) *BookSeries {
+	if id == 0 {
+		return nil
+	}
+
+	return &BookSeries{
+		ID:       id,
+		Name:     name,
+		Position: position,
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	StockAlertStatusOpen     = "open"
+	StockAlertStatusResolved = "resolved"
+)
+
+// StockAlert warns that the stock of a book fell to its reorder threshold,
+// it stays open until the book is restocked above it.
+type StockAlert struct {
+	ID         int64      `db:"id" json:"id"`
+	BookID     int64      `db:"book_id" json:"book_id"`
+	Title      string     `db:"title" json:"title"`
+	Threshold  int64      `db:"threshold" json:"threshold"`
+	Stock      int64      `db:"stock" json:"stock"`
+	Status     string     `db:"status" json:"status"`
+	NotifiedAt *time.Time `db:"notified_at" json:"notified_at"`
+	CreatedAt  time.Time  `db:"created_at" json:"opened_at"`
+}
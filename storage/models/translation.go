@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// BookTranslation is the title and description of a book in a locale, an
+// empty description falls back to the description of the book.
+type BookTranslation struct {
+	BookID      int64     `db:"book_id" json:"book_id"`
+	Locale      string    `db:"locale" json:"locale"`
+	Title       string    `db:"title" json:"title"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"-"`
+	UpdatedAt   time.Time `db:"updated_at" json:"-"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type WishlistItem struct {
+	UserID    int64     `db:"user_id" json:"-"`
+	BookID    int64     `db:"book_id" json:"book_id"`
+	Book      *Book     `db:"-" json:"book"`
+	CreatedAt time.Time `db:"created_at" json:"added_at"`
+}
@@ -0,0 +1,200 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+//go:generate mockgen -source=alert.go -destination=mock/alert.go -package=mock
+type AlertStorage interface {
+	// SetReorderThreshold sets the stock a book is reordered at, a nil
+	// threshold stops alerting on the book.
+	SetReorderThreshold(ctx context.Context, bookID int64, threshold *int64) error
+
+	// Evaluate opens an alert for every book whose stock fell to its
+	// reorder threshold, unless it has one open already, and resolves the
+	// open alerts of books restocked above it.
+	Evaluate(context.Context) (opened int64, resolved int64, err error)
+
+	// GetOpenAlerts fetches all open alerts, the oldest first.
+	GetOpenAlerts(context.Context) ([]*models.StockAlert, error)
+
+	// GetUndeliveredAlerts fetches the open alerts nobody was notified of
+	// yet, the oldest first.
+	GetUndeliveredAlerts(context.Context) ([]*models.StockAlert, error)
+
+	// MarkNotified records that an alert was delivered.
+	MarkNotified(ctx context.Context, id int64) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around alert storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// bookStock is the stock of every book with a reorder threshold that is not
+// archived, summed over its printed editions. Books sold only as ebooks
+// never run out.
+const bookStock = `
+book_stock AS (
+	SELECT b.id AS book_id, b.reorder_threshold AS threshold, SUM(e.stock) AS stock
+	FROM books b
+	JOIN editions e
+		ON e.book_id = b.id AND e.format <> 'ebook'
+	WHERE b.reorder_threshold IS NOT NULL AND b.archived_at IS NULL
+	GROUP BY b.id
+)`
+
+// alertColumns are the columns selected into the stock alert model.
+const alertColumns = `a.id, a.book_id, b.title, a.threshold, a.stock, a.status, a.notified_at, a.created_at`
+
+// SetReorderThreshold sets the stock a book is reordered at, a nil
+// threshold stops alerting on the book.
+func (s *Storage) SetReorderThreshold(ctx context.Context, bookID int64, threshold *int64) error {
+	stmt := `
+UPDATE books
+SET reorder_threshold = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+`
+
+	res, err := s.db.ExecContext(ctx, stmt, threshold, bookID)
+	if err != nil {
+		return fmt.Errorf("failed to set reorder threshold: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
+
+// Evaluate opens an alert for every book whose stock fell to its reorder
+// threshold, unless it has one open already, and resolves the open alerts of
+// books restocked above it or no longer alerted on. A book falling low again
+// after being restocked opens a new alert.
+func (s *Storage) Evaluate(ctx context.Context) (int64, int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	resolveStmt := `
+WITH ` + bookStock + `
+UPDATE stock_alerts
+SET status = :resolved, resolved_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE status = :open AND NOT EXISTS (
+	SELECT 1
+	FROM book_stock bs
+	WHERE bs.book_id = stock_alerts.book_id AND bs.stock <= bs.threshold
+);
+`
+
+	arg := map[string]interface{}{
+		"open":     models.StockAlertStatusOpen,
+		"resolved": models.StockAlertStatusResolved,
+	}
+
+	res, err := tx.NamedExecContext(ctx, resolveStmt, arg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve stock alerts: %v", err)
+	}
+	resolved, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	openStmt := `
+WITH ` + bookStock + `
+INSERT INTO stock_alerts (book_id, threshold, stock, status)
+SELECT bs.book_id, bs.threshold, bs.stock, :open
+FROM book_stock bs
+WHERE bs.stock <= bs.threshold AND NOT EXISTS (
+	SELECT 1
+	FROM stock_alerts a
+	WHERE a.book_id = bs.book_id AND a.status = :open
+);
+`
+
+	res, err = tx.NamedExecContext(ctx, openStmt, arg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open stock alerts: %v", err)
+	}
+	opened, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return opened, resolved, nil
+}
+
+// GetOpenAlerts fetches all open alerts, the oldest first.
+func (s *Storage) GetOpenAlerts(ctx context.Context) ([]*models.StockAlert, error) {
+	return s.getAlerts(ctx, "a.status = ?", models.StockAlertStatusOpen)
+}
+
+// GetUndeliveredAlerts fetches the open alerts nobody was notified of yet,
+// the oldest first.
+func (s *Storage) GetUndeliveredAlerts(ctx context.Context) ([]*models.StockAlert, error) {
+	return s.getAlerts(ctx, "a.status = ? AND a.notified_at IS NULL", models.StockAlertStatusOpen)
+}
+
+func (s *Storage) getAlerts(ctx context.Context, cond string, args ...interface{}) ([]*models.StockAlert, error) {
+	query := `
+SELECT %s
+FROM stock_alerts a
+JOIN books b
+	ON b.id = a.book_id
+WHERE %s
+ORDER BY a.id;
+`
+
+	alerts := []*models.StockAlert{}
+	if err := s.db.SelectContext(ctx, &alerts, fmt.Sprintf(query, alertColumns, cond), args...); err != nil {
+		return nil, fmt.Errorf("failed to query from stock_alerts table: %v", err)
+	}
+
+	return alerts, nil
+}
+
+// MarkNotified records that an alert was delivered.
+func (s *Storage) MarkNotified(ctx context.Context, id int64) error {
+	stmt := `
+UPDATE stock_alerts
+SET notified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+`
+
+	res, err := s.db.ExecContext(ctx, stmt, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark stock alert notified: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+// testSetStock sets the stock of the default edition of a book.
+func testSetStock(t *testing.T, ts *Storage, bookID, stock int64) {
+	t.Helper()
+
+	if _, err := ts.db.Exec(`UPDATE books SET stock = ? WHERE id = ?;`, stock, bookID); err != nil {
+		t.Fatalf("unexpected error when setting book stock: %v", err)
+	}
+}
+
+func testEvaluate(t *testing.T, ts *Storage, wantOpened, wantResolved int64) {
+	t.Helper()
+
+	opened, resolved, err := ts.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate(_) expected nil error, got = %v", err)
+	}
+	if opened != wantOpened || resolved != wantResolved {
+		t.Fatalf("Evaluate(_) error, got = (%v, %v), want = (%v, %v)", opened, resolved, wantOpened, wantResolved)
+	}
+}
+
+func Test_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// seeded book 1 has a stock of 10, its ebook does not count towards
+	// it.
+	threshold := int64(5)
+	if err := ts.SetReorderThreshold(ctx, 1, &threshold); err != nil {
+		t.Fatalf("SetReorderThreshold(_, _, _) expected nil error, got = %v", err)
+	}
+	if _, err := ts.db.Exec(`INSERT INTO editions (book_id, format, price) VALUES (1, 'ebook', '3.99');`); err != nil {
+		t.Fatalf("unexpected error when creating ebook edition: %v", err)
+	}
+	testEvaluate(t, ts, 0, 0)
+
+	// falling to the threshold opens a single alert however often it is
+	// evaluated.
+	testSetStock(t, ts, 1, 5)
+	testEvaluate(t, ts, 1, 0)
+	testSetStock(t, ts, 1, 2)
+	testEvaluate(t, ts, 0, 0)
+
+	alerts, err := ts.GetOpenAlerts(ctx)
+	if err != nil {
+		t.Fatalf("GetOpenAlerts(_) expected nil error, got = %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("GetOpenAlerts(_) error, got = %v alerts, want = %v", len(alerts), 1)
+	}
+	if a := alerts[0]; a.BookID != 1 || a.Title != "Atomic Habits" || a.Threshold != 5 || a.Stock != 5 || a.NotifiedAt != nil {
+		t.Fatalf("GetOpenAlerts(_) unexpected alert: %+v", a)
+	}
+
+	// a delivered alert is not delivered again.
+	undelivered, err := ts.GetUndeliveredAlerts(ctx)
+	if err != nil {
+		t.Fatalf("GetUndeliveredAlerts(_) expected nil error, got = %v", err)
+	}
+	if len(undelivered) != 1 {
+		t.Fatalf("GetUndeliveredAlerts(_) error, got = %v alerts, want = %v", len(undelivered), 1)
+	}
+	if err := ts.MarkNotified(ctx, undelivered[0].ID); err != nil {
+		t.Fatalf("MarkNotified(_, _) expected nil error, got = %v", err)
+	}
+	undelivered, err = ts.GetUndeliveredAlerts(ctx)
+	if err != nil {
+		t.Fatalf("GetUndeliveredAlerts(_) expected nil error, got = %v", err)
+	}
+	if len(undelivered) != 0 {
+		t.Fatalf("GetUndeliveredAlerts(_) error, got = %v alerts, want = %v", len(undelivered), 0)
+	}
+
+	// restocking resolves the alert, falling low again opens a new one.
+	testSetStock(t, ts, 1, 20)
+	testEvaluate(t, ts, 0, 1)
+	testSetStock(t, ts, 1, 0)
+	testEvaluate(t, ts, 1, 0)
+
+	// removing the threshold resolves the alert.
+	if err := ts.SetReorderThreshold(ctx, 1, nil); err != nil {
+		t.Fatalf("SetReorderThreshold(_, _, _) expected nil error, got = %v", err)
+	}
+	testEvaluate(t, ts, 0, 1)
+
+	alerts, err = ts.GetOpenAlerts(ctx)
+	if err != nil {
+		t.Fatalf("GetOpenAlerts(_) expected nil error, got = %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("GetOpenAlerts(_) error, got = %v alerts, want = %v", len(alerts), 0)
+	}
+}
+
+func Test_SetReorderThreshold(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	threshold := int64(-1)
+	if err := ts.SetReorderThreshold(ctx, 1, &threshold); err == nil {
+		t.Fatalf("SetReorderThreshold(_, _, _) expected error for a negative threshold")
+	}
+	if err := ts.SetReorderThreshold(ctx, 1000, nil); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("SetReorderThreshold(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+	if err := ts.MarkNotified(ctx, 1000); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("MarkNotified(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: alert.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockAlertStorage is a mock of AlertStorage interface.
+type MockAlertStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertStorageMockRecorder
+}
+
+// MockAlertStorageMockRecorder is the mock recorder for MockAlertStorage.
+type MockAlertStorageMockRecorder struct {
+	mock *MockAlertStorage
+}
+
+// NewMockAlertStorage creates a new mock instance.
+func NewMockAlertStorage(ctrl *gomock.Controller) *MockAlertStorage {
+	mock := &MockAlertStorage{ctrl: ctrl}
+	mock.recorder = &MockAlertStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertStorage) EXPECT() *MockAlertStorageMockRecorder {
+	return m.recorder
+}
+
+// Evaluate mocks base method.
+func (m *MockAlertStorage) Evaluate(arg0 context.Context) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evaluate", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockAlertStorageMockRecorder) Evaluate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockAlertStorage)(nil).Evaluate), arg0)
+}
+
+// GetOpenAlerts mocks base method.
+func (m *MockAlertStorage) GetOpenAlerts(arg0 context.Context) ([]*models.StockAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenAlerts", arg0)
+	ret0, _ := ret[0].([]*models.StockAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenAlerts indicates an expected call of GetOpenAlerts.
+func (mr *MockAlertStorageMockRecorder) GetOpenAlerts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenAlerts", reflect.TypeOf((*MockAlertStorage)(nil).GetOpenAlerts), arg0)
+}
+
+// GetUndeliveredAlerts mocks base method.
+func (m *MockAlertStorage) GetUndeliveredAlerts(arg0 context.Context) ([]*models.StockAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUndeliveredAlerts", arg0)
+	ret0, _ := ret[0].([]*models.StockAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUndeliveredAlerts indicates an expected call of GetUndeliveredAlerts.
+func (mr *MockAlertStorageMockRecorder) GetUndeliveredAlerts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUndeliveredAlerts", reflect.TypeOf((*MockAlertStorage)(nil).GetUndeliveredAlerts), arg0)
+}
+
+// MarkNotified mocks base method.
+func (m *MockAlertStorage) MarkNotified(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotified", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotified indicates an expected call of MarkNotified.
+func (mr *MockAlertStorageMockRecorder) MarkNotified(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotified", reflect.TypeOf((*MockAlertStorage)(nil).MarkNotified), ctx, id)
+}
+
+// SetReorderThreshold mocks base method.
+func (m *MockAlertStorage) SetReorderThreshold(ctx context.Context, bookID int64, threshold *int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReorderThreshold", ctx, bookID, threshold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReorderThreshold indicates an expected call of SetReorderThreshold.
+func (mr *MockAlertStorageMockRecorder) SetReorderThreshold(ctx, bookID, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReorderThreshold", reflect.TypeOf((*MockAlertStorage)(nil).SetReorderThreshold), ctx, bookID, threshold)
+}
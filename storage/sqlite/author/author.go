@@ -0,0 +1,297 @@
+package author
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrMergeIntoItself = errors.New("author cannot be merged into itself")
+	ErrInvalidRole     = errors.New("role must be translator or illustrator")
+	ErrBookIDNotFound  = errors.New("book id not found")
+	ErrAlreadyCredited = errors.New("author is already credited in this role")
+)
+
+// creditRoles are the roles a book credits besides its authors, which are
+// credited from the author of the book instead.
+var creditRoles = map[string]bool{
+	"translator":  true,
+	"illustrator": true,
+}
+
+//go:generate mockgen -source=author.go -destination=mock/author.go -package=mock
+type AuthorStorage interface {
+	// GetAuthorByID fetches the author in our storage, an author merged into
+	// another is fetched as the author it was merged into.
+	GetAuthorByID(context.Context, int64) (*models.Author, error)
+
+	// GetBookAuthorsByAuthorID fetches every credit of an author, ordered
+	// by book id.
+	GetBookAuthorsByAuthorID(context.Context, int64) ([]*models.BookAuthor, error)
+
+	// Merge merges an author into another, moving its credits and keeping
+	// it as an alias of the author it was merged into.
+	Merge(ctx context.Context, id, intoID int64) (*models.Author, error)
+
+	// CreditBook credits the author of the given name on a book in the
+	// given role, after the authors already credited in that role.
+	CreditBook(ctx context.Context, bookID int64, name, role string) (*models.BookAuthor, error)
+
+	// UncreditBook removes the credit of an author on a book in the given
+	// role.
+	UncreditBook(ctx context.Context, bookID, authorID int64, role string) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around author storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// GetAuthorByID fetches the author in our storage along with its aliases,
+// an author merged into another is fetched as the author it was merged into.
+func (s *Storage) GetAuthorByID(ctx context.Context, id int64) (*models.Author, error) {
+	query := `
+SELECT a.id, a.name
+FROM authors alias
+JOIN authors a
+	ON a.id = COALESCE(alias.canonical_id, alias.id)
+WHERE alias.id = :id
+`
+
+	stmt, err := s.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare GetAuthorByID statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var author models.Author
+	arg := map[string]interface{}{
+		"id": id,
+	}
+	if err := stmt.GetContext(ctx, &author, arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to perform GetAuthorByID storage operation: %w", err)
+	}
+
+	author.Aliases = []string{}
+	if err := s.db.SelectContext(ctx, &author.Aliases, `SELECT name FROM authors WHERE canonical_id = ? ORDER BY name;`, author.ID); err != nil {
+		return nil, fmt.Errorf("failed to get aliases of author: %v", err)
+	}
+
+	return &author, nil
+}
+
+// GetBookAuthorsByAuthorID fetches every credit of an author, ordered by
+// book id.
+func (s *Storage) GetBookAuthorsByAuthorID(ctx context.Context, authorID int64) ([]*models.BookAuthor, error) {
+	query := `
+SELECT ba.book_id, ba.author_id, a.name, ba.role, ba.position
+FROM book_authors ba
+JOIN authors a
+	ON ba.author_id = a.id
+WHERE ba.author_id = :author_id
+ORDER BY ba.book_id, ba.role
+`
+
+	stmt, err := s.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare GetBookAuthorsByAuthorID statement: %w", err)
+	}
+	defer stmt.Close()
+
+	arg := map[string]interface{}{
+		"author_id": authorID,
+	}
+
+	rows, err := stmt.QueryxContext(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query from book_authors table: %v", err)
+	}
+	defer rows.Close()
+
+	// iterate through each row and save it as book author model.
+	credits := []*models.BookAuthor{}
+	for rows.Next() {
+		var credit models.BookAuthor
+
+		if err := rows.StructScan(&credit); err != nil {
+			return nil, fmt.Errorf("failed when scanning through rows: %v", err)
+		}
+
+		credits = append(credits, &credit)
+	}
+
+	return credits, nil
+}
+
+// Merge merges an author into another, such as "M. Gladwell" into "Malcolm
+// Gladwell". The credits of the merged author are moved to the author it was
+// merged into, and books credited to it later on are credited to that author
+// instead. Merging into an alias merges into the author the alias was merged
+// into, the merged author is returned.
+func (s *Storage) Merge(ctx context.Context, id, intoID int64) (*models.Author, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM authors WHERE id = ?);`, id); err != nil {
+		return nil, fmt.Errorf("failed to get author by id: %v", err)
+	}
+	if !exists {
+		return nil, sqlite.ErrNotFound
+	}
+
+	var canonicalID int64
+	if err := tx.GetContext(ctx, &canonicalID, `SELECT COALESCE(canonical_id, id) FROM authors WHERE id = ?;`, intoID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get author by id: %v", err)
+	}
+	if canonicalID == id {
+		return nil, ErrMergeIntoItself
+	}
+
+	// a book credited to both authors keeps a single credit.
+	stmt := `
+INSERT OR IGNORE INTO book_authors (book_id, author_id, role, position)
+SELECT book_id, ?, role, position
+FROM book_authors
+WHERE author_id = ?;
+`
+	if _, err := tx.ExecContext(ctx, stmt, canonicalID, id); err != nil {
+		return nil, fmt.Errorf("failed to move book authors: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_authors WHERE author_id = ?;`, id); err != nil {
+		return nil, fmt.Errorf("failed to remove book authors: %v", err)
+	}
+
+	stmt = `
+UPDATE authors
+SET canonical_id = ?,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = ? OR canonical_id = ?;
+`
+	if _, err := tx.ExecContext(ctx, stmt, canonicalID, id, id); err != nil {
+		return nil, fmt.Errorf("failed to perform Merge operation: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return s.GetAuthorByID(ctx, canonicalID)
+}
+
+// CreditBook credits the author of the given name on a book as its translator
+// or illustrator, after the authors already credited in that role. An author
+// is added when none has the name, and a name of an author merged into another
+// is credited to the author it was merged into.
+func (s *Storage) CreditBook(ctx context.Context, bookID int64, name, role string) (*models.BookAuthor, error) {
+	if !creditRoles[role] {
+		return nil, ErrInvalidRole
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO authors (name) VALUES (?);`, name); err != nil {
+		return nil, fmt.Errorf("failed to insert author: %v", err)
+	}
+
+	credit := models.BookAuthor{
+		BookID: bookID,
+		Role:   role,
+	}
+	query := `
+SELECT a.id AS author_id, a.name
+FROM authors alias
+JOIN authors a
+	ON a.id = COALESCE(alias.canonical_id, alias.id)
+WHERE alias.name = ?;
+`
+	if err := tx.GetContext(ctx, &credit, query, name); err != nil {
+		return nil, fmt.Errorf("failed to get author by name: %v", err)
+	}
+
+	query = `SELECT COALESCE(MAX(position) + 1, 0) FROM book_authors WHERE book_id = ? AND role = ?;`
+	if err := tx.GetContext(ctx, &credit.Position, query, bookID, role); err != nil {
+		return nil, fmt.Errorf("failed to get position of credit: %v", err)
+	}
+
+	stmt := `
+INSERT INTO book_authors (book_id, author_id, role, position)
+VALUES (?, ?, ?, ?);
+`
+	if _, err := tx.ExecContext(ctx, stmt, bookID, credit.AuthorID, role, credit.Position); err != nil {
+		switch {
+		case strings.Contains(err.Error(), errForeignKeyConstraint):
+			return nil, ErrBookIDNotFound
+		case strings.Contains(err.Error(), "UNIQUE"):
+			return nil, ErrAlreadyCredited
+		}
+		return nil, fmt.Errorf("failed to perform CreditBook operation: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return &credit, nil
+}
+
+// UncreditBook removes the credit of an author on a book as its translator or
+// illustrator.
+func (s *Storage) UncreditBook(ctx context.Context, bookID, authorID int64, role string) error {
+	if !creditRoles[role] {
+		return ErrInvalidRole
+	}
+
+	stmt := `
+DELETE FROM book_authors
+WHERE book_id = :book_id AND author_id = :author_id AND role = :role;
+`
+
+	arg := map[string]interface{}{
+		"book_id":   bookID,
+		"author_id": authorID,
+		"role":      role,
+	}
+
+	res, err := s.db.NamedExecContext(ctx, stmt, arg)
+	if err != nil {
+		return fmt.Errorf("failed to perform UncreditBook operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,325 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/pressly/goose"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_BackfillAuthors(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+	ts, err := sqlite.NewStorage(filepath.Join(dir, genString()), "")
+	if err != nil {
+		t.Fatalf("failed to create new test storage: %v", err)
+	}
+	t.Cleanup(ts.Teardown)
+	db := ts.Database()
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	// books written before authors were introduced, backfilled as a single
+	// author each before co-authors were split.
+	if err := goose.UpTo(db.DB, pathToMigrationsDir, 7); err != nil {
+		t.Fatalf("unexpected error when migrating to books: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO books (title, author, price) VALUES ('Switch', ' Chip Heath, Dan Heath ', '12.00'), ('Made to Stick', 'Dan Heath,Chip Heath', '12.00');`); err != nil {
+		t.Fatalf("unexpected error when inserting co-authored books: %v", err)
+	}
+	if err := goose.UpTo(db.DB, pathToMigrationsDir, 32); err != nil {
+		t.Fatalf("unexpected error when migrating to authors: %v", err)
+	}
+	if err := goose.Up(db.DB, pathToMigrationsDir); err != nil {
+		t.Fatalf("unexpected error when migrating: %v", err)
+	}
+
+	for title, want := range map[string][]string{
+		"Switch":        {"Chip Heath", "Dan Heath"},
+		"Made to Stick": {"Dan Heath", "Chip Heath"},
+		"Atomic Habits": {"James Clear"},
+	} {
+		got := []string{}
+		if err := db.Select(&got, `
+SELECT a.name
+FROM book_authors ba
+JOIN authors a
+	ON a.id = ba.author_id
+JOIN books b
+	ON b.id = ba.book_id
+WHERE b.title = ? AND ba.role = 'author'
+ORDER BY ba.position;
+`, title); err != nil {
+			t.Fatalf("unexpected error when getting backfilled authors: %v", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("backfilled authors of %q mismatch (-want +got):\n%s", title, diff)
+		}
+	}
+
+	var count int
+	if err := db.Get(&count, `SELECT COUNT(*) FROM authors WHERE name LIKE '%Heath%';`); err != nil {
+		t.Fatalf("unexpected error when counting authors: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("backfilled %d authors named Heath, want = %d", count, 2)
+	}
+}
+
+func Test_GetAuthorByID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// authors are backfilled from the author column of the seeded books.
+	var authorID int64
+	if err := ts.db.Get(&authorID, `SELECT id FROM authors WHERE name = 'Malcolm Gladwell'`); err != nil {
+		t.Fatalf("unexpected error when getting backfilled author: %v", err)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		author, err := ts.GetAuthorByID(ctx, authorID)
+		if err != nil {
+			t.Fatalf("GetAuthorByID(_, _) expected nil error, got = %v", err)
+		}
+		if author.Name != "Malcolm Gladwell" {
+			t.Fatalf("GetAuthorByID(_, _) error, got = %v, want = %v", author.Name, "Malcolm Gladwell")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ts.GetAuthorByID(ctx, int64(100000))
+		if !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("GetAuthorByID(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+		}
+	})
+}
+
+func Test_GetBookAuthorsByAuthorID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	var authorID int64
+	if err := ts.db.Get(&authorID, `SELECT id FROM authors WHERE name = 'James Clear'`); err != nil {
+		t.Fatalf("unexpected error when getting backfilled author: %v", err)
+	}
+
+	credits, err := ts.GetBookAuthorsByAuthorID(ctx, authorID)
+	if err != nil {
+		t.Fatalf("GetBookAuthorsByAuthorID(_, _) expected nil error, got = %v", err)
+	}
+
+	if len(credits) != 1 {
+		t.Fatalf("GetBookAuthorsByAuthorID(_, _) error, got = %v, want = %v credits", len(credits), 1)
+	}
+	if credits[0].Role != "author" {
+		t.Fatalf("GetBookAuthorsByAuthorID(_, _) error, got role = %v, want = %v", credits[0].Role, "author")
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	var intoID int64
+	if err := ts.db.Get(&intoID, `SELECT id FROM authors WHERE name = 'Malcolm Gladwell'`); err != nil {
+		t.Fatalf("unexpected error when getting backfilled author: %v", err)
+	}
+
+	// the same author written differently on another book.
+	res, err := ts.db.Exec(`INSERT INTO books (title, author, price) VALUES ('Outliers', 'M. Gladwell', '15.00');`)
+	if err != nil {
+		t.Fatalf("unexpected error when inserting book: %v", err)
+	}
+	bookID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting book id: %v", err)
+	}
+	res, err = ts.db.Exec(`INSERT INTO authors (name) VALUES ('M. Gladwell');`)
+	if err != nil {
+		t.Fatalf("unexpected error when inserting author: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting author id: %v", err)
+	}
+	if _, err := ts.db.Exec(`INSERT INTO book_authors (book_id, author_id) VALUES (?, ?);`, bookID, id); err != nil {
+		t.Fatalf("unexpected error when crediting author: %v", err)
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		if _, err := ts.Merge(ctx, int64(100000), intoID); !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("Merge(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+		}
+		if _, err := ts.Merge(ctx, id, int64(100000)); !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("Merge(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+		}
+	})
+
+	t.Run("IntoItself", func(t *testing.T) {
+		if _, err := ts.Merge(ctx, id, id); !errors.Is(err, ErrMergeIntoItself) {
+			t.Fatalf("Merge(_, _, _) error, got = %v, want = %v", err, ErrMergeIntoItself)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		author, err := ts.Merge(ctx, id, intoID)
+		if err != nil {
+			t.Fatalf("Merge(_, _, _) expected nil error, got = %v", err)
+		}
+		want := &models.Author{ID: intoID, Name: "Malcolm Gladwell", Aliases: []string{"M. Gladwell"}}
+		if diff := cmp.Diff(want, author); diff != "" {
+			t.Fatalf("Merge(_, _, _) mismatch (-want +got):\n%s", diff)
+		}
+
+		credits, err := ts.GetBookAuthorsByAuthorID(ctx, intoID)
+		if err != nil {
+			t.Fatalf("GetBookAuthorsByAuthorID(_, _) expected nil error, got = %v", err)
+		}
+		if len(credits) != 2 || credits[1].BookID != bookID {
+			t.Fatalf("Merge(_, _, _) error, got credits = %v, want the credit of book %v moved", credits, bookID)
+		}
+
+		// the alias is fetched as the author it was merged into.
+		alias, err := ts.GetAuthorByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetAuthorByID(_, _) expected nil error, got = %v", err)
+		}
+		if diff := cmp.Diff(want, alias); diff != "" {
+			t.Fatalf("GetAuthorByID(_, _) mismatch (-want +got):\n%s", diff)
+		}
+
+		// an author cannot be merged into its own alias.
+		if _, err := ts.Merge(ctx, intoID, id); !errors.Is(err, ErrMergeIntoItself) {
+			t.Fatalf("Merge(_, _, _) error, got = %v, want = %v", err, ErrMergeIntoItself)
+		}
+	})
+}
+
+func Test_CreditBook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	t.Run("Success", func(t *testing.T) {
+		credit, err := ts.CreditBook(ctx, 2, "Anna Translator", "translator")
+		if err != nil {
+			t.Fatalf("CreditBook(_, _, _, _) expected nil error, got = %v", err)
+		}
+		if credit.Name != "Anna Translator" || credit.Role != "translator" || credit.Position != 0 {
+			t.Fatalf("CreditBook(_, _, _, _) error, got credit = %+v", credit)
+		}
+
+		// the next translator is credited after the first.
+		next, err := ts.CreditBook(ctx, 2, "Ben Translator", "translator")
+		if err != nil {
+			t.Fatalf("CreditBook(_, _, _, _) expected nil error, got = %v", err)
+		}
+		if next.Position != 1 {
+			t.Fatalf("CreditBook(_, _, _, _) error, got position = %v, want = %v", next.Position, 1)
+		}
+
+		// an author can be credited in several roles.
+		if _, err := ts.CreditBook(ctx, 2, "Anna Translator", "illustrator"); err != nil {
+			t.Fatalf("CreditBook(_, _, _, _) expected nil error, got = %v", err)
+		}
+
+		credits, err := ts.GetBookAuthorsByAuthorID(ctx, credit.AuthorID)
+		if err != nil {
+			t.Fatalf("GetBookAuthorsByAuthorID(_, _) expected nil error, got = %v", err)
+		}
+		if len(credits) != 2 {
+			t.Fatalf("CreditBook(_, _, _, _) error, got = %v, want = %v credits", len(credits), 2)
+		}
+
+		if _, err := ts.CreditBook(ctx, 2, "Anna Translator", "translator"); !errors.Is(err, ErrAlreadyCredited) {
+			t.Fatalf("CreditBook(_, _, _, _) error, got = %v, want = %v", err, ErrAlreadyCredited)
+		}
+
+		if err := ts.UncreditBook(ctx, 2, credit.AuthorID, "translator"); err != nil {
+			t.Fatalf("UncreditBook(_, _, _, _) expected nil error, got = %v", err)
+		}
+		if err := ts.UncreditBook(ctx, 2, credit.AuthorID, "translator"); !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("UncreditBook(_, _, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+		}
+	})
+
+	t.Run("Alias", func(t *testing.T) {
+		if _, err := ts.db.Exec(`INSERT INTO authors (name, canonical_id) SELECT 'T. Illustrator', id FROM authors WHERE name = 'Tiago Forte';`); err != nil {
+			t.Fatalf("unexpected error when inserting alias: %v", err)
+		}
+
+		credit, err := ts.CreditBook(ctx, 3, "T. Illustrator", "illustrator")
+		if err != nil {
+			t.Fatalf("CreditBook(_, _, _, _) expected nil error, got = %v", err)
+		}
+		if credit.Name != "Tiago Forte" {
+			t.Fatalf("CreditBook(_, _, _, _) error, got = %v, want = %v", credit.Name, "Tiago Forte")
+		}
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		if _, err := ts.CreditBook(ctx, 1, "James Clear", "author"); !errors.Is(err, ErrInvalidRole) {
+			t.Fatalf("CreditBook(_, _, _, _) error, got = %v, want = %v", err, ErrInvalidRole)
+		}
+		if err := ts.UncreditBook(ctx, 1, 1, "author"); !errors.Is(err, ErrInvalidRole) {
+			t.Fatalf("UncreditBook(_, _, _, _) error, got = %v, want = %v", err, ErrInvalidRole)
+		}
+	})
+
+	t.Run("BookNotFound", func(t *testing.T) {
+		if _, err := ts.CreditBook(ctx, 100000, "Anna Translator", "translator"); !errors.Is(err, ErrBookIDNotFound) {
+			t.Fatalf("CreditBook(_, _, _, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+		}
+	})
+}
+
+func genString() string {
+	return uuid.New().String()
+}
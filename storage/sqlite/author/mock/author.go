@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: author.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockAuthorStorage is a mock of AuthorStorage interface.
+type MockAuthorStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorStorageMockRecorder
+}
+
+// MockAuthorStorageMockRecorder is the mock recorder for MockAuthorStorage.
+type MockAuthorStorageMockRecorder struct {
+	mock *MockAuthorStorage
+}
+
+// NewMockAuthorStorage creates a new mock instance.
+func NewMockAuthorStorage(ctrl *gomock.Controller) *MockAuthorStorage {
+	mock := &MockAuthorStorage{ctrl: ctrl}
+	mock.recorder = &MockAuthorStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorStorage) EXPECT() *MockAuthorStorageMockRecorder {
+	return m.recorder
+}
+
+// CreditBook mocks base method.
+func (m *MockAuthorStorage) CreditBook(ctx context.Context, bookID int64, name, role string) (*models.BookAuthor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditBook", ctx, bookID, name, role)
+	ret0, _ := ret[0].(*models.BookAuthor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditBook indicates an expected call of CreditBook.
+func (mr *MockAuthorStorageMockRecorder) CreditBook(ctx, bookID, name, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditBook", reflect.TypeOf((*MockAuthorStorage)(nil).CreditBook), ctx, bookID, name, role)
+}
+
+// GetAuthorByID mocks base method.
+func (m *MockAuthorStorage) GetAuthorByID(arg0 context.Context, arg1 int64) (*models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorByID indicates an expected call of GetAuthorByID.
+func (mr *MockAuthorStorageMockRecorder) GetAuthorByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockAuthorStorage)(nil).GetAuthorByID), arg0, arg1)
+}
+
+// GetBookAuthorsByAuthorID mocks base method.
+func (m *MockAuthorStorage) GetBookAuthorsByAuthorID(arg0 context.Context, arg1 int64) ([]*models.BookAuthor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAuthorsByAuthorID", arg0, arg1)
+	ret0, _ := ret[0].([]*models.BookAuthor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAuthorsByAuthorID indicates an expected call of GetBookAuthorsByAuthorID.
+func (mr *MockAuthorStorageMockRecorder) GetBookAuthorsByAuthorID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAuthorsByAuthorID", reflect.TypeOf((*MockAuthorStorage)(nil).GetBookAuthorsByAuthorID), arg0, arg1)
+}
+
+// Merge mocks base method.
+func (m *MockAuthorStorage) Merge(ctx context.Context, id, intoID int64) (*models.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, id, intoID)
+	ret0, _ := ret[0].(*models.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockAuthorStorageMockRecorder) Merge(ctx, id, intoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockAuthorStorage)(nil).Merge), ctx, id, intoID)
+}
+
+// UncreditBook mocks base method.
+func (m *MockAuthorStorage) UncreditBook(ctx context.Context, bookID, authorID int64, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UncreditBook", ctx, bookID, authorID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UncreditBook indicates an expected call of UncreditBook.
+func (mr *MockAuthorStorageMockRecorder) UncreditBook(ctx, bookID, authorID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UncreditBook", reflect.TypeOf((*MockAuthorStorage)(nil).UncreditBook), ctx, bookID, authorID, role)
+}
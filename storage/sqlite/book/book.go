@@ -5,18 +5,80 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
 )
 
+// timeLayout is the layout times are stored in.
+const timeLayout = "2006-01-02 15:04:05"
+
 //go:generate mockgen -source=book.go -destination=mock/book.go -package=mock
 type BookStorage interface {
-	// GetBooks fetches all books from our storage.
-	GetBooks(context.Context) ([]*models.Book, error)
+	// GetBooks fetches all books from our storage that are not archived and
+	// match the given filter, a nil filter matches every book.
+	GetBooks(context.Context, *models.BookFilter) ([]*models.Book, error)
+
+	// GetFacets counts the books matching a filter by author, price band
+	// and category, a nil filter matches every book.
+	GetFacets(context.Context, *models.BookFilter) (*models.BookFacets, error)
 
-	// GetBooksByIDs fetches all the books by the given IDs.
+	// GetBooksByIDs fetches all the books by the given IDs that are not
+	// archived.
 	GetBooksByIDs(context.Context, []int64) ([]*models.Book, error)
+
+	// GetBookByID fetches the book with the given id, unless archived.
+	GetBookByID(context.Context, int64) (*models.Book, error)
+
+	// GetBookByISBN fetches the book with an edition of the given ISBN-13,
+	// unless archived.
+	GetBookByISBN(context.Context, string) (*models.Book, error)
+
+	// GetBooksByISBNs fetches all the books with an edition of the given
+	// ISBN-13s that are not archived.
+	GetBooksByISBNs(context.Context, []string) ([]*models.Book, error)
+
+	// GetArchivedBooks fetches all archived books, the most recently
+	// archived first.
+	GetArchivedBooks(context.Context) ([]*models.Book, error)
+
+	// Archive hides a book from our listings and stops it from being
+	// ordered, while orders of the book keep showing it.
+	Archive(ctx context.Context, id int64) error
+
+	// Restore brings an archived book back to our listings.
+	Restore(ctx context.Context, id int64) error
+
+	// GetCatalogVersion fetches the version of our catalog, which changes
+	// whenever a book is added, removed or edited.
+	GetCatalogVersion(context.Context) (int64, error)
+
+	// GetCatalogState fetches what identifies our book listings at this
+	// time, which changes whenever anything they show changes.
+	GetCatalogState(context.Context) (*models.CatalogState, error)
+
+	// GetChanges fetches up to limit changes of our books sequenced after
+	// the given one, in order.
+	GetChanges(ctx context.Context, since int64, limit int) ([]*models.BookChange, error)
+
+	// EachBook streams every book of our catalog ordered by id to fn with
+	// its authors, stopping at the first error returned by fn.
+	EachBook(ctx context.Context, fn func(*models.Book) error) error
+
+	// UpsertBooks creates or updates the given books in one transaction,
+	// returning a result for every book in the same order. Nothing is
+	// committed when any book fails or when dryRun is set.
+	UpsertBooks(ctx context.Context, books []*models.BookUpsert, dryRun bool) ([]*models.BookUpsertResult, error)
+
+	// SetCover sets the hash of the cover of a book, an empty hash removes
+	// its cover.
+	SetCover(ctx context.Context, id int64, hash string) error
+
+	// SetReleaseDate sets the YYYY-MM-DD release date of a book, an empty
+	// date marks the book as released.
+	SetReleaseDate(ctx context.Context, id int64, date string) error
 }
 
 type Storage struct {
@@ -28,40 +90,85 @@ func NewStorage(db *sqlx.DB) *Storage {
 	return &Storage{db: db}
 }
 
-// GetBooks fetches all books from our storage.
-func (s *Storage) GetBooks(ctx context.Context) ([]*models.Book, error) {
+// bookColumns are the columns selected into the book model, ISBNs are
+// nullable so a book without an ISBN does not break their unique index. The
+// price is the one in effect, see the current_book_prices view. A book not
+// released yet is up for preorder, otherwise it is in stock while any of its
+// editions is.
+const bookColumns = `id, title, author,
+	(SELECT cp.price FROM current_book_prices cp WHERE cp.book_id = books.id) AS price,
+	description,
+	COALESCE(isbn_10, '') AS isbn_10,
+	COALESCE(isbn_13, '') AS isbn_13,
+	stock,
+	COALESCE(cover_hash, '') AS cover_hash,
+	archived_at,
+	COALESCE(series_id, 0) AS series_id,
+	COALESCE((SELECT s.name FROM series s WHERE s.id = books.series_id), '') AS series_name,
+	COALESCE(series_position, 0) AS series_position,
+	COALESCE(publisher_id, 0) AS publisher_id,
+	COALESCE((SELECT p.name FROM publishers p WHERE p.id = books.publisher_id), '') AS publisher_name,
+	COALESCE((SELECT p.slug FROM publishers p WHERE p.id = books.publisher_id), '') AS publisher_slug,
+	COALESCE(release_date, '') AS release_date,
+	CASE WHEN release_date > date('now') THEN 'preorder'
+	WHEN EXISTS (
+		SELECT 1
+		FROM editions e
+		WHERE e.book_id = books.id AND (e.stock > 0 OR e.format = 'ebook')
+	) THEN 'in_stock' ELSE 'out_of_stock' END AS stock_status,
+	(SELECT COALESCE(ROUND(AVG(r.rating), 2), 0) FROM reviews r WHERE r.book_id = books.id) AS average_rating,
+	(SELECT COUNT(*) FROM reviews r WHERE r.book_id = books.id) AS review_count`
+
+// editionColumns are the columns selected into the edition model from
+// editions joined with the current price of their book.
+const editionColumns = `e.id, e.book_id, e.format,
+	COALESCE(e.isbn_10, '') AS isbn_10,
+	COALESCE(e.isbn_13, '') AS isbn_13,
+	COALESCE(e.price, cp.price) AS price,
+	e.stock,
+	CASE WHEN e.stock > 0 OR e.format = 'ebook' THEN 'in_stock' ELSE 'out_of_stock' END AS stock_status,
+	e.is_default`
+
+// GetBooks fetches all books from our storage that are not archived and
+// match the given filter, a nil filter matches every book.
+func (s *Storage) GetBooks(ctx context.Context, filter *models.BookFilter) ([]*models.Book, error) {
 	query := `
-SELECT id, title, author, price, description
+SELECT %s
 FROM books
+WHERE %s
 `
 
-	rows, err := s.db.Queryx(query)
+	conditions, args := filterConditions(filter, "")
+
+	rows, err := s.db.QueryxContext(ctx, fmt.Sprintf(query, bookColumns, strings.Join(conditions, " AND ")), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query from books table: %v", err)
 	}
 	defer rows.Close()
 
-	// iterate through each row and save it as book model.
-	books := []*models.Book{}
-	for rows.Next() {
-		var book models.Book
+	books, err := scanBooks(rows)
+	if err != nil {
+		return nil, err
+	}
 
-		err := rows.StructScan(&book)
-		if err != nil {
-			return nil, fmt.Errorf("failed when scanning through rows: %v", err)
-		}
+	if err := s.attachAuthors(ctx, books); err != nil {
+		return nil, err
+	}
 
-		books = append(books, &book)
+	if err := s.attachEditions(ctx, books); err != nil {
+		return nil, err
 	}
 
 	return books, nil
 }
 
+// GetBooksByIDs fetches all the books by the given IDs that are not
+// archived.
 func (s *Storage) GetBooksByIDs(ctx context.Context, ids []int64) ([]*models.Book, error) {
 	query := `
-SELECT id, title, author, price, description
+SELECT %s
 FROM books
-WHERE id IN (%v);
+WHERE id IN (%v) AND archived_at IS NULL;
 `
 
 	strIDs := []string{}
@@ -69,13 +176,345 @@ WHERE id IN (%v);
 		strIDs = append(strIDs, strconv.FormatInt(id, 10))
 	}
 
-	rows, err := s.db.Queryx(fmt.Sprintf(query, strings.Join(strIDs, ",")))
+	rows, err := s.db.Queryx(fmt.Sprintf(query, bookColumns, strings.Join(strIDs, ",")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query from books table: %v", err)
+	}
+	defer rows.Close()
+
+	books, err := scanBooks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachAuthors(ctx, books); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachEditions(ctx, books); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// GetBookByID fetches the book with the given id, unless archived.
+func (s *Storage) GetBookByID(ctx context.Context, id int64) (*models.Book, error) {
+	books, err := s.GetBooksByIDs(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(books) < 1 {
+		return nil, sqlite.ErrNotFound
+	}
+	return books[0], nil
+}
+
+// GetBookByISBN fetches the book with an edition of the given ISBN-13,
+// unless archived.
+func (s *Storage) GetBookByISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	books, err := s.GetBooksByISBNs(ctx, []string{isbn})
+	if err != nil {
+		return nil, err
+	}
+	if len(books) < 1 {
+		return nil, sqlite.ErrNotFound
+	}
+	return books[0], nil
+}
+
+// GetBooksByISBNs fetches all the books with an edition of the given
+// ISBN-13s that are not archived. Every edition has its own ISBN, the ISBN
+// of a book is the one of its default edition.
+func (s *Storage) GetBooksByISBNs(ctx context.Context, isbns []string) ([]*models.Book, error) {
+	if len(isbns) < 1 {
+		return []*models.Book{}, nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(`
+SELECT %s
+FROM books
+WHERE id IN (SELECT e.book_id FROM editions e WHERE e.isbn_13 IN (?))
+	AND archived_at IS NULL;
+`, bookColumns), isbns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GetBooksByISBNs query: %v", err)
+	}
+
+	rows, err := s.db.QueryxContext(ctx, s.db.Rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query from books table: %v", err)
 	}
 	defer rows.Close()
 
-	// iterate through each row and save it as book model.
+	books, err := scanBooks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachAuthors(ctx, books); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachEditions(ctx, books); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// GetArchivedBooks fetches all archived books, the most recently archived
+// first.
+func (s *Storage) GetArchivedBooks(ctx context.Context) ([]*models.Book, error) {
+	query := `
+SELECT %s
+FROM books
+WHERE archived_at IS NOT NULL
+ORDER BY archived_at DESC, id;
+`
+
+	rows, err := s.db.QueryxContext(ctx, fmt.Sprintf(query, bookColumns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query from books table: %v", err)
+	}
+	defer rows.Close()
+
+	books, err := scanBooks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachAuthors(ctx, books); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachEditions(ctx, books); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// Archive hides a book from our listings and stops it from being ordered,
+// archiving an archived book keeps the time it was first archived.
+func (s *Storage) Archive(ctx context.Context, id int64) error {
+	stmt := `
+UPDATE books
+SET archived_at = COALESCE(archived_at, CURRENT_TIMESTAMP)
+WHERE id = ?;
+`
+	return s.setArchived(ctx, stmt, id)
+}
+
+// Restore brings an archived book back to our listings.
+func (s *Storage) Restore(ctx context.Context, id int64) error {
+	stmt := `
+UPDATE books
+SET archived_at = NULL
+WHERE id = ?;
+`
+	return s.setArchived(ctx, stmt, id)
+}
+
+// setArchived runs the statement archiving or restoring a book.
+func (s *Storage) setArchived(ctx context.Context, stmt string, id int64) error {
+	res, err := s.db.ExecContext(ctx, stmt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update book archive: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetCatalogVersion fetches the version of our catalog, which changes
+// whenever a book is added, removed or edited.
+func (s *Storage) GetCatalogVersion(ctx context.Context) (int64, error) {
+	var version int64
+	if err := s.db.GetContext(ctx, &version, `SELECT version FROM catalog_version WHERE id = 1;`); err != nil {
+		return 0, fmt.Errorf("failed to get catalog version: %v", err)
+	}
+
+	return version, nil
+}
+
+// GetCatalogState fetches what identifies our book listings at this time.
+// Besides the catalog and listing versions, listings change without any
+// write once a scheduled price starts or ends and once a book is released,
+// the last of these moments that passed counts as a modification.
+func (s *Storage) GetCatalogState(ctx context.Context) (*models.CatalogState, error) {
+	query := `
+SELECT version, listing_version, MAX(
+	datetime(updated_at),
+	COALESCE(datetime(listing_updated_at), ''),
+	COALESCE((SELECT datetime(MAX(bp.effective_from)) FROM book_prices bp WHERE bp.effective_from <= CURRENT_TIMESTAMP), ''),
+	COALESCE((SELECT datetime(MAX(bp.effective_to)) FROM book_prices bp WHERE bp.effective_to <= CURRENT_TIMESTAMP), ''),
+	COALESCE((SELECT datetime(MAX(b.release_date)) FROM books b WHERE b.release_date <= date('now')), '')
+) AS modified_at
+FROM catalog_version
+WHERE id = 1;`
+
+	var row struct {
+		Version        int64  `db:"version"`
+		ListingVersion int64  `db:"listing_version"`
+		ModifiedAt     string `db:"modified_at"`
+	}
+	if err := s.db.GetContext(ctx, &row, query); err != nil {
+		return nil, fmt.Errorf("failed to get catalog state: %v", err)
+	}
+
+	modifiedAt, err := time.Parse(timeLayout, row.ModifiedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse catalog modification time: %v", err)
+	}
+
+	return &models.CatalogState{
+		Version:        row.Version,
+		ListingVersion: row.ListingVersion,
+		ModifiedAt:     modifiedAt,
+	}, nil
+}
+
+// GetChanges fetches up to limit changes of our books sequenced after the
+// given one, in order. Changes only carry the book they are of, see
+// GetBooksByIDs for its current state. Writes of a book, its editions,
+// prices, translations and credits are logged, a single write can log more
+// than one change. A scheduled price is logged when it is scheduled, taking
+// effect later is not a write and logs nothing.
+func (s *Storage) GetChanges(ctx context.Context, since int64, limit int) ([]*models.BookChange, error) {
+	query := `
+SELECT seq, book_id, operation, created_at
+FROM book_changes
+WHERE seq > ?
+ORDER BY seq
+LIMIT ?;
+`
+
+	changes := []*models.BookChange{}
+	if err := s.db.SelectContext(ctx, &changes, query, since, limit); err != nil {
+		return nil, fmt.Errorf("failed to get book changes: %v", err)
+	}
+
+	return changes, nil
+}
+
+// SetCover sets the hash of the cover of a book, an empty hash removes its
+// cover.
+func (s *Storage) SetCover(ctx context.Context, id int64, hash string) error {
+	stmt := `
+UPDATE books
+SET cover_hash = NULLIF(?, ''), updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+`
+
+	res, err := s.db.ExecContext(ctx, stmt, hash, id)
+	if err != nil {
+		return fmt.Errorf("failed to set book cover: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetReleaseDate sets the YYYY-MM-DD release date of a book, an empty date
+// marks the book as released.
+func (s *Storage) SetReleaseDate(ctx context.Context, id int64, date string) error {
+	stmt := `
+UPDATE books
+SET release_date = NULLIF(?, ''), updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+`
+
+	res, err := s.db.ExecContext(ctx, stmt, date, id)
+	if err != nil {
+		return fmt.Errorf("failed to set book release date: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
+
+// eachBookBatch is how many books EachBook reads before attaching their
+// authors and streaming them.
+const eachBookBatch = 100
+
+// EachBook streams every book of our catalog ordered by id to fn, stopping
+// at the first error returned by fn. Books are read a batch at a time so the
+// catalog is never loaded at once, editions are not attached.
+func (s *Storage) EachBook(ctx context.Context, fn func(*models.Book) error) error {
+	query := `
+SELECT %s
+FROM books
+ORDER BY id;
+`
+
+	rows, err := s.db.QueryxContext(ctx, fmt.Sprintf(query, bookColumns))
+	if err != nil {
+		return fmt.Errorf("failed to query from books table: %v", err)
+	}
+	defer rows.Close()
+
+	batch := []*models.Book{}
+	flush := func() error {
+		if err := s.attachAuthors(ctx, batch); err != nil {
+			return err
+		}
+		for _, book := range batch {
+			if err := fn(book); err != nil {
+				return err
+			}
+		}
+		batch = []*models.Book{}
+		return nil
+	}
+
+	for rows.Next() {
+		var book models.Book
+
+		if err := rows.StructScan(&book); err != nil {
+			return fmt.Errorf("failed when scanning through rows: %v", err)
+		}
+		book.Cover = models.NewBookCover(book.CoverHash)
+		book.Series = models.NewBookSeries(book.SeriesID, book.SeriesName, book.SeriesPosition)
+		book.Publisher = models.NewBookPublisher(book.PublisherID, book.PublisherName, book.PublisherSlug)
+
+		batch = append(batch, &book)
+		if len(batch) == eachBookBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed when iterating through rows: %v", err)
+	}
+
+	return flush()
+}
+
+// scanBooks iterates through each row and save it as book model.
+func scanBooks(rows *sqlx.Rows) ([]*models.Book, error) {
 	books := []*models.Book{}
 	for rows.Next() {
 		var book models.Book
@@ -83,9 +522,102 @@ WHERE id IN (%v);
 		if err := rows.StructScan(&book); err != nil {
 			return nil, fmt.Errorf("failed when scanning through rows: %v", err)
 		}
+		book.Cover = models.NewBookCover(book.CoverHash)
+		book.Series = models.NewBookSeries(book.SeriesID, book.SeriesName, book.SeriesPosition)
+		book.Publisher = models.NewBookPublisher(book.PublisherID, book.PublisherName, book.PublisherSlug)
 
 		books = append(books, &book)
 	}
 
 	return books, nil
 }
+
+// attachAuthors fetches the authors credited on the given books and
+// attaches them to each book ordered by their position.
+func (s *Storage) attachAuthors(ctx context.Context, books []*models.Book) error {
+	if len(books) < 1 {
+		return nil
+	}
+
+	query := `
+SELECT ba.book_id, ba.author_id, a.name, ba.role, ba.position
+FROM book_authors ba
+JOIN authors a
+	ON ba.author_id = a.id
+WHERE ba.book_id IN (%v)
+ORDER BY ba.book_id, ba.position, a.name;
+`
+
+	strIDs := []string{}
+	booksMap := map[int64]*models.Book{}
+	for _, book := range books {
+		strIDs = append(strIDs, strconv.FormatInt(book.ID, 10))
+		book.Authors = []*models.BookAuthor{}
+		booksMap[book.ID] = book
+	}
+
+	rows, err := s.db.QueryxContext(ctx, fmt.Sprintf(query, strings.Join(strIDs, ",")))
+	if err != nil {
+		return fmt.Errorf("failed to query from book_authors table: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var author models.BookAuthor
+
+		if err := rows.StructScan(&author); err != nil {
+			return fmt.Errorf("failed when scanning through rows: %v", err)
+		}
+
+		if book, ok := booksMap[author.BookID]; ok {
+			book.Authors = append(book.Authors, &author)
+		}
+	}
+
+	return nil
+}
+
+// attachEditions fetches the editions of the given books and attaches them
+// to each book, the default edition first.
+func (s *Storage) attachEditions(ctx context.Context, books []*models.Book) error {
+	if len(books) < 1 {
+		return nil
+	}
+
+	query := `
+SELECT %s
+FROM editions e
+JOIN current_book_prices cp
+	ON cp.book_id = e.book_id
+WHERE e.book_id IN (%v)
+ORDER BY e.book_id, e.is_default DESC, e.id;
+`
+
+	strIDs := []string{}
+	booksMap := map[int64]*models.Book{}
+	for _, book := range books {
+		strIDs = append(strIDs, strconv.FormatInt(book.ID, 10))
+		book.Editions = []*models.Edition{}
+		booksMap[book.ID] = book
+	}
+
+	rows, err := s.db.QueryxContext(ctx, fmt.Sprintf(query, editionColumns, strings.Join(strIDs, ",")))
+	if err != nil {
+		return fmt.Errorf("failed to query from editions table: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edition models.Edition
+
+		if err := rows.StructScan(&edition); err != nil {
+			return fmt.Errorf("failed when scanning through rows: %v", err)
+		}
+
+		if book, ok := booksMap[edition.BookID]; ok {
+			book.Editions = append(book.Editions, &edition)
+		}
+	}
+
+	return nil
+}
@@ -2,11 +2,16 @@ package book
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
 )
 
@@ -35,9 +40,74 @@ func Test_GetBooks(t *testing.T) {
 	ts, teardown := newTestStorage(t)
 	t.Cleanup(teardown)
 
-	_, err := ts.GetBooks(ctx)
+	_, err := ts.GetBooks(ctx, nil)
 	if err != nil {
-		t.Fatalf("GetBooks(_) expected nil error, got = %v", err)
+		t.Fatalf("GetBooks(_, _) expected nil error, got = %v", err)
+	}
+}
+
+func Test_GetBooks_Publisher(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	if _, err := ts.db.Exec(`INSERT INTO publishers (id, name, slug) VALUES (1, 'Penguin', 'penguin'), (2, 'Avery', 'avery');`); err != nil {
+		t.Fatalf("unexpected error when creating publishers: %v", err)
+	}
+	if _, err := ts.db.Exec(`UPDATE books SET publisher_id = 1 WHERE id IN (1, 3);`); err != nil {
+		t.Fatalf("unexpected error when assigning publisher: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		filter  *models.BookFilter
+		wantIDs []int64
+	}{
+		{
+			name:    "Publisher",
+			filter:  &models.BookFilter{Publisher: "penguin"},
+			wantIDs: []int64{1, 3},
+		},
+		{
+			name:    "PublisherWithoutBooks",
+			filter:  &models.BookFilter{Publisher: "avery"},
+			wantIDs: []int64{},
+		},
+		{
+			name:    "UnknownPublisher",
+			filter:  &models.BookFilter{Publisher: genString()},
+			wantIDs: []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			books, err := ts.GetBooks(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetBooks(_, _) expected nil error, got = %v", err)
+			}
+
+			gotIDs := []int64{}
+			for _, b := range books {
+				gotIDs = append(gotIDs, b.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Fatalf("GetBooks(_, _) mismatch (-want+got):\n%s", diff)
+			}
+
+			for _, b := range books {
+				want := &models.BookPublisher{ID: 1, Name: "Penguin", Slug: "penguin"}
+				if diff := cmp.Diff(want, b.Publisher); diff != "" {
+					t.Fatalf("GetBooks(_, _) publisher mismatch (-want+got):\n%s", diff)
+				}
+			}
+		})
 	}
 }
 
@@ -50,7 +120,7 @@ func Test_GetBooksByIDs(t *testing.T) {
 	t.Cleanup(teardown)
 
 	// first get all books to obtain its ids.
-	books, err := ts.GetBooks(ctx)
+	books, err := ts.GetBooks(ctx, nil)
 	if err != nil {
 		t.Fatalf("unexpected error when GetBooks: %v", err)
 	}
@@ -66,6 +136,615 @@ func Test_GetBooksByIDs(t *testing.T) {
 	if gotBooks[0].ID != books[0].ID {
 		t.Fatalf("GetBooksByIDs(_, _) error, got = %v, want = %v", gotBooks[0].ID, books[0].ID)
 	}
+
+	// seeded books have their author backfilled as their only credit.
+	if len(gotBooks[0].Authors) != 1 {
+		t.Fatalf("GetBooksByIDs(_, _) error, got = %v, want = %v authors", len(gotBooks[0].Authors), 1)
+	}
+	if gotBooks[0].Authors[0].Name != books[0].Author {
+		t.Fatalf("GetBooksByIDs(_, _) error, got = %v, want = %v", gotBooks[0].Authors[0].Name, books[0].Author)
+	}
+}
+
+func Test_GetBookByID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	t.Run("SuccessWithRatings", func(t *testing.T) {
+		t.Parallel()
+
+		bookID := int64(1)
+		for _, rating := range []int64{4, 5} {
+			res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+			if err != nil {
+				t.Fatalf("unexpected error when creating dummy user: %v", err)
+			}
+			userID, err := res.LastInsertId()
+			if err != nil {
+				t.Fatalf("unexpected error when getting dummy user id: %v", err)
+			}
+
+			if _, err := ts.db.Exec(`INSERT INTO reviews (book_id, user_id, rating) VALUES (?, ?, ?);`, bookID, userID, rating); err != nil {
+				t.Fatalf("unexpected error when creating dummy review: %v", err)
+			}
+		}
+
+		book, err := ts.GetBookByID(ctx, bookID)
+		if err != nil {
+			t.Fatalf("GetBookByID(_, _) expected nil error, got = %v", err)
+		}
+		if book.AverageRating != 4.5 {
+			t.Fatalf("GetBookByID(_, _) error, got average rating = %v, want = %v", book.AverageRating, 4.5)
+		}
+		if book.ReviewCount != 2 {
+			t.Fatalf("GetBookByID(_, _) error, got review count = %v, want = %v", book.ReviewCount, 2)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ts.GetBookByID(ctx, 1000)
+		if !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("GetBookByID(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+		}
+	})
+}
+
+func Test_GetBooksByIDs_CurrentPrice(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// a running promotion overrides the price of book 1, a price scheduled
+	// for later does not change the price of book 2 yet.
+	stmt := `
+INSERT INTO book_prices (book_id, price, effective_from, effective_to)
+VALUES
+	(1, '7.50', datetime('now', '-1 hour'), datetime('now', '+1 hour')),
+	(2, '1.00', datetime('now', '+1 hour'), NULL),
+	(3, '2.00', datetime('now', '-2 hour'), datetime('now', '-1 hour'));
+`
+	if _, err := ts.db.Exec(stmt); err != nil {
+		t.Fatalf("unexpected error when inserting book prices: %v", err)
+	}
+
+	books, err := ts.GetBooksByIDs(ctx, []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("GetBooksByIDs(_, _) expected nil error, got = %v", err)
+	}
+
+	got := map[int64]string{}
+	for _, book := range books {
+		got[book.ID] = book.Price
+	}
+	want := map[int64]string{1: "7.50", 2: "9.80", 3: "11.20"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("GetBooksByIDs(_, _) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_GetBookByID_Editions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// a sold out book stays in stock while it is sold as an ebook.
+	if _, err := ts.db.Exec(`UPDATE books SET stock = 0 WHERE id = 1;`); err != nil {
+		t.Fatalf("unexpected error when updating book stock: %v", err)
+	}
+	if _, err := ts.db.Exec(`INSERT INTO editions (book_id, format, price) VALUES (1, 'ebook', '4.99');`); err != nil {
+		t.Fatalf("unexpected error when creating ebook edition: %v", err)
+	}
+
+	book, err := ts.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetBookByID(_, _) expected nil error, got = %v", err)
+	}
+	if len(book.Editions) != 2 {
+		t.Fatalf("GetBookByID(_, _) error, got = %v editions, want = %v", len(book.Editions), 2)
+	}
+	if e := book.Editions[0]; !e.IsDefault || e.Stock != 0 || e.Price != book.Price || e.ISBN13 != book.ISBN13 {
+		t.Fatalf("GetBookByID(_, _) unexpected default edition: %+v", e)
+	}
+	if e := book.Editions[1]; e.Format != "ebook" || e.Price != "4.99" || e.StockStatus != "in_stock" {
+		t.Fatalf("GetBookByID(_, _) unexpected ebook edition: %+v", e)
+	}
+	if book.StockStatus != "in_stock" {
+		t.Fatalf("GetBookByID(_, _) error, got stock status = %v, want = %v", book.StockStatus, "in_stock")
+	}
+}
+
+func Test_GetBookByID_Series(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	book, err := ts.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetBookByID(_, _) expected nil error, got = %v", err)
+	}
+	if book.Series != nil {
+		t.Fatalf("GetBookByID(_, _) error, got series = %+v, want = nil", book.Series)
+	}
+
+	if _, err := ts.db.Exec(`INSERT INTO series (id, name) VALUES (1, 'Habits');`); err != nil {
+		t.Fatalf("unexpected error when creating series: %v", err)
+	}
+	if _, err := ts.db.Exec(`UPDATE books SET series_id = 1, series_position = 2 WHERE id = 1;`); err != nil {
+		t.Fatalf("unexpected error when assigning series: %v", err)
+	}
+
+	book, err = ts.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetBookByID(_, _) expected nil error, got = %v", err)
+	}
+	want := &models.BookSeries{ID: 1, Name: "Habits", Position: 2}
+	if diff := cmp.Diff(want, book.Series); diff != "" {
+		t.Fatalf("GetBookByID(_, _) mismatch (-want+got):\n%s", diff)
+	}
+}
+
+func Test_GetBookByISBN(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		// seeded ISBN of The Tipping Point.
+		book, err := ts.GetBookByISBN(ctx, "9780316346627")
+		if err != nil {
+			t.Fatalf("GetBookByISBN(_, _) expected nil error, got = %v", err)
+		}
+		if book.ISBN10 != "0316346624" {
+			t.Fatalf("GetBookByISBN(_, _) error, got = %v, want = %v", book.ISBN10, "0316346624")
+		}
+	})
+
+	t.Run("Edition", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := ts.db.Exec(`INSERT INTO editions (book_id, format, isbn_13, stock) VALUES (2, 'hardcover', '9780316316965', 1);`); err != nil {
+			t.Fatalf("unexpected error when creating edition: %v", err)
+		}
+
+		book, err := ts.GetBookByISBN(ctx, "9780316316965")
+		if err != nil {
+			t.Fatalf("GetBookByISBN(_, _) expected nil error, got = %v", err)
+		}
+		if book.ID != 2 {
+			t.Fatalf("GetBookByISBN(_, _) error, got = %v, want = %v", book.ID, 2)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ts.GetBookByISBN(ctx, "9780000000002")
+		if !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("GetBookByISBN(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+		}
+	})
+}
+
+func Test_GetBooksByISBNs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	books, err := ts.GetBooksByISBNs(ctx, []string{"9780735211292", "9781982167387", "9780000000002"})
+	if err != nil {
+		t.Fatalf("GetBooksByISBNs(_, _) expected nil error, got = %v", err)
+	}
+
+	if len(books) != 2 {
+		t.Fatalf("GetBooksByISBNs(_, _) error, got = %v, want = %v books", len(books), 2)
+	}
+}
+
+func Test_GetCatalogVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	version, err := ts.GetCatalogVersion(ctx)
+	if err != nil {
+		t.Fatalf("GetCatalogVersion(_) expected nil error, got = %v", err)
+	}
+
+	// stock movements do not change the catalog.
+	if _, err := ts.db.Exec(`UPDATE books SET stock = stock - 1 WHERE id = 1;`); err != nil {
+		t.Fatalf("unexpected error when updating book stock: %v", err)
+	}
+	got, err := ts.GetCatalogVersion(ctx)
+	if err != nil {
+		t.Fatalf("GetCatalogVersion(_) expected nil error, got = %v", err)
+	}
+	if got != version {
+		t.Fatalf("GetCatalogVersion(_) error, got = %v, want = %v", got, version)
+	}
+
+	if _, err := ts.db.Exec(`UPDATE books SET description = ? WHERE id = 1;`, genString()); err != nil {
+		t.Fatalf("unexpected error when updating book description: %v", err)
+	}
+	got, err = ts.GetCatalogVersion(ctx)
+	if err != nil {
+		t.Fatalf("GetCatalogVersion(_) expected nil error, got = %v", err)
+	}
+	if got <= version {
+		t.Fatalf("GetCatalogVersion(_) error, got = %v, want > %v", got, version)
+	}
+}
+
+func Test_GetCatalogState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	state, err := ts.GetCatalogState(ctx)
+	if err != nil {
+		t.Fatalf("GetCatalogState(_) expected nil error, got = %v", err)
+	}
+
+	// stock movements change the listings but not the catalog.
+	if _, err := ts.db.Exec(`UPDATE books SET stock = stock - 1 WHERE id = 1;`); err != nil {
+		t.Fatalf("unexpected error when updating book stock: %v", err)
+	}
+	got, err := ts.GetCatalogState(ctx)
+	if err != nil {
+		t.Fatalf("GetCatalogState(_) expected nil error, got = %v", err)
+	}
+	if got.Version != state.Version {
+		t.Fatalf("GetCatalogState(_) version error, got = %v, want = %v", got.Version, state.Version)
+	}
+	if got.ListingVersion <= state.ListingVersion {
+		t.Fatalf("GetCatalogState(_) listing version error, got = %v, want > %v", got.ListingVersion, state.ListingVersion)
+	}
+
+	// once written changes are long past, the last release or price change
+	// that took effect is the modification time.
+	resetModifiedAt := func() {
+		t.Helper()
+		if _, err := ts.db.Exec(`UPDATE catalog_version SET updated_at = '2000-01-01 00:00:00', listing_updated_at = '2000-01-01 00:00:00';`); err != nil {
+			t.Fatalf("unexpected error when resetting catalog version: %v", err)
+		}
+	}
+	if _, err := ts.db.Exec(`UPDATE book_prices SET effective_from = '2000-01-01 00:00:00';`); err != nil {
+		t.Fatalf("unexpected error when updating book prices: %v", err)
+	}
+	if _, err := ts.db.Exec(`UPDATE books SET release_date = '2020-05-01' WHERE id = 2;`); err != nil {
+		t.Fatalf("unexpected error when updating book release date: %v", err)
+	}
+	resetModifiedAt()
+
+	got, err = ts.GetCatalogState(ctx)
+	if err != nil {
+		t.Fatalf("GetCatalogState(_) expected nil error, got = %v", err)
+	}
+	if want := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC); !got.ModifiedAt.Equal(want) {
+		t.Fatalf("GetCatalogState(_) modified at error, got = %v, want = %v", got.ModifiedAt, want)
+	}
+
+	if _, err := ts.db.Exec(`INSERT INTO book_prices (book_id, price, effective_from, effective_to) VALUES (1, '5.00', '2021-01-01 00:00:00', '2021-06-01 00:00:00');`); err != nil {
+		t.Fatalf("unexpected error when inserting book price: %v", err)
+	}
+	resetModifiedAt()
+
+	got, err = ts.GetCatalogState(ctx)
+	if err != nil {
+		t.Fatalf("GetCatalogState(_) expected nil error, got = %v", err)
+	}
+	if want := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC); !got.ModifiedAt.Equal(want) {
+		t.Fatalf("GetCatalogState(_) modified at error, got = %v, want = %v", got.ModifiedAt, want)
+	}
+}
+
+func Test_GetChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// the seeded books are logged as inserted.
+	changes, err := ts.GetChanges(ctx, 0, 100)
+	if err != nil {
+		t.Fatalf("GetChanges(_, _, _) expected nil error, got = %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("GetChanges(_, _, _) error, got %d changes, want = %d", len(changes), 3)
+	}
+	for i, c := range changes {
+		if c.BookID != int64(i+1) || c.Operation != models.BookChangeInsert {
+			t.Fatalf("GetChanges(_, _, _) error, got change = %+v", c)
+		}
+	}
+	since := changes[len(changes)-1].Seq
+
+	if _, err := ts.db.Exec(`UPDATE books SET description = ? WHERE id = 2;`, genString()); err != nil {
+		t.Fatalf("unexpected error when updating book description: %v", err)
+	}
+	if err := ts.Archive(ctx, 1); err != nil {
+		t.Fatalf("unexpected error when archiving book: %v", err)
+	}
+	res, err := ts.db.Exec(`INSERT INTO books (title, author, price) VALUES (?, ?, '1.00');`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when inserting book: %v", err)
+	}
+	bookID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting inserted book id: %v", err)
+	}
+	if _, err := ts.db.Exec(`DELETE FROM book_prices WHERE book_id = ?; DELETE FROM editions WHERE book_id = ?; DELETE FROM books WHERE id = ?;`, bookID, bookID, bookID); err != nil {
+		t.Fatalf("unexpected error when deleting book: %v", err)
+	}
+
+	// writes of what a book is listed with are logged as updates of it.
+	for _, stmt := range []string{
+		// writes leaving what a book is listed with as it is are not.
+		`UPDATE books SET reorder_threshold = 2 WHERE id = 3;`,
+		`UPDATE books SET title = title, updated_at = CURRENT_TIMESTAMP WHERE id = 3;`,
+		`INSERT INTO editions (book_id, format, isbn_13, stock) VALUES (3, 'hardcover', '9780000000019', 1);`,
+		`UPDATE editions SET stock = stock + 1 WHERE book_id = 3 AND NOT is_default;`,
+		// copied to the book, which logs it once.
+		`UPDATE editions SET stock = stock + 1 WHERE book_id = 3 AND is_default;`,
+		`INSERT INTO book_prices (book_id, price, effective_from) VALUES (3, '1.00', '2999-01-01 00:00:00');`,
+		`INSERT INTO book_translations (book_id, locale, title) VALUES (3, 'fr', 'Construire un second cerveau');`,
+		`INSERT INTO book_authors (book_id, author_id, role) SELECT 3, id, 'illustrator' FROM authors WHERE name = 'James Clear';`,
+	} {
+		if _, err := ts.db.Exec(stmt); err != nil {
+			t.Fatalf("unexpected error when executing %q: %v", stmt, err)
+		}
+	}
+
+	want := []struct {
+		bookID    int64
+		operation string
+	}{
+		{2, models.BookChangeUpdate},
+		{1, models.BookChangeUpdate},
+		{bookID, models.BookChangeInsert},
+		// the price set on the inserted book.
+		{bookID, models.BookChangeUpdate},
+		{bookID, models.BookChangeUpdate},
+		{bookID, models.BookChangeUpdate},
+		{bookID, models.BookChangeDelete},
+		{3, models.BookChangeUpdate},
+		{3, models.BookChangeUpdate},
+		{3, models.BookChangeUpdate},
+		{3, models.BookChangeUpdate},
+		{3, models.BookChangeUpdate},
+		{3, models.BookChangeUpdate},
+	}
+
+	// changes are paged in order.
+	for _, w := range want {
+		changes, err := ts.GetChanges(ctx, since, 1)
+		if err != nil {
+			t.Fatalf("GetChanges(_, _, _) expected nil error, got = %v", err)
+		}
+		if len(changes) != 1 {
+			t.Fatalf("GetChanges(_, _, _) error, got %d changes, want = %d", len(changes), 1)
+		}
+		if c := changes[0]; c.Seq <= since || c.BookID != w.bookID || c.Operation != w.operation {
+			t.Fatalf("GetChanges(_, _, _) error, got change = %+v, want book %d %s after %d", c, w.bookID, w.operation, since)
+		}
+		since = changes[0].Seq
+	}
+
+	changes, err = ts.GetChanges(ctx, since, 100)
+	if err != nil {
+		t.Fatalf("GetChanges(_, _, _) expected nil error, got = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("GetChanges(_, _, _) error, got %d changes, want none", len(changes))
+	}
+}
+
+func Test_SetCover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	hash := strings.Repeat("ab", 32)
+	if err := ts.SetCover(ctx, 1, hash); err != nil {
+		t.Fatalf("SetCover(_, _, _) expected nil error, got = %v", err)
+	}
+	book, err := ts.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error when GetBookByID: %v", err)
+	}
+	if diff := cmp.Diff(models.NewBookCover(hash), book.Cover); diff != "" {
+		t.Fatalf("SetCover(_, _, _) mismatch (-want +got):\n%s", diff)
+	}
+
+	// an empty hash removes the cover.
+	if err := ts.SetCover(ctx, 1, ""); err != nil {
+		t.Fatalf("SetCover(_, _, _) expected nil error, got = %v", err)
+	}
+	book, err = ts.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error when GetBookByID: %v", err)
+	}
+	if book.Cover != nil {
+		t.Fatalf("SetCover(_, _, _) error, got = %v, want = nil", book.Cover)
+	}
+
+	if err := ts.SetCover(ctx, 1000, hash); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("SetCover(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_SetReleaseDate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	tests := []struct {
+		name            string
+		date            string
+		wantStockStatus string
+	}{
+		{
+			name:            "Upcoming",
+			date:            time.Now().UTC().AddDate(0, 1, 0).Format("2006-01-02"),
+			wantStockStatus: "preorder",
+		},
+		{
+			name:            "ReleasedToday",
+			date:            time.Now().UTC().Format("2006-01-02"),
+			wantStockStatus: "in_stock",
+		},
+		{
+			name:            "Released",
+			date:            "",
+			wantStockStatus: "in_stock",
+		},
+	}
+
+	// cases run in order on the same book.
+	for _, tt := range tests {
+		if err := ts.SetReleaseDate(ctx, 1, tt.date); err != nil {
+			t.Fatalf("%s: SetReleaseDate(_, _, _) expected nil error, got = %v", tt.name, err)
+		}
+		book, err := ts.GetBookByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("unexpected error when GetBookByID: %v", err)
+		}
+		if book.ReleaseDate != tt.date || book.StockStatus != tt.wantStockStatus {
+			t.Fatalf("%s: SetReleaseDate(_, _, _) error, got = (%q, %q), want = (%q, %q)",
+				tt.name, book.ReleaseDate, book.StockStatus, tt.date, tt.wantStockStatus)
+		}
+	}
+
+	if err := ts.SetReleaseDate(ctx, 1, "soon"); err == nil {
+		t.Fatalf("SetReleaseDate(_, _, _) expected error for an invalid date")
+	}
+	if err := ts.SetReleaseDate(ctx, 1000, ""); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("SetReleaseDate(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_Archive(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	if err := ts.Archive(ctx, 1); err != nil {
+		t.Fatalf("Archive(_, _) expected nil error, got = %v", err)
+	}
+
+	// archived books are hidden from listings.
+	books, err := ts.GetBooks(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error when GetBooks: %v", err)
+	}
+	for _, book := range books {
+		if book.ID == 1 {
+			t.Fatalf("GetBooks(_, _) expected archived book to be hidden")
+		}
+	}
+	if _, err := ts.GetBookByID(ctx, 1); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("GetBookByID(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+	if _, err := ts.GetBookByISBN(ctx, "9780735211292"); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("GetBookByISBN(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+
+	archived, err := ts.GetArchivedBooks(ctx)
+	if err != nil {
+		t.Fatalf("GetArchivedBooks(_) expected nil error, got = %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != 1 || archived[0].ArchivedAt == nil {
+		t.Fatalf("GetArchivedBooks(_) error, got = %+v", archived)
+	}
+
+	if err := ts.Restore(ctx, 1); err != nil {
+		t.Fatalf("Restore(_, _) expected nil error, got = %v", err)
+	}
+	book, err := ts.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetBookByID(_, _) expected nil error, got = %v", err)
+	}
+	if book.ArchivedAt != nil {
+		t.Fatalf("Restore(_, _) error, got archived at = %v, want = nil", book.ArchivedAt)
+	}
+
+	if err := ts.Archive(ctx, 1000); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("Archive(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_EachBook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	var ids []int64
+	err := ts.EachBook(ctx, func(book *models.Book) error {
+		ids = append(ids, book.ID)
+		if len(book.Authors) != 1 || book.Authors[0].Name != book.Author {
+			t.Fatalf("EachBook(_, _) error, got authors = %v, want = %v", book.Authors, book.Author)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachBook(_, _) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff([]int64{1, 2, 3}, ids); diff != "" {
+		t.Fatalf("EachBook(_, _) mismatch (-want +got):\n%s", diff)
+	}
+
+	// the first error returned stops the iteration.
+	errStop := errors.New("stop")
+	calls := 0
+	err = ts.EachBook(ctx, func(book *models.Book) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Fatalf("EachBook(_, _) error, got = %v after %d calls, want = %v after 1 call", err, calls, errStop)
+	}
 }
 
 func genString() string {
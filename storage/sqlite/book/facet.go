@@ -0,0 +1,179 @@
+package book
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// facets a filter field is left out of when counting books by it.
+const (
+	facetAuthor    = "author"
+	facetPriceBand = "price_band"
+	facetCategory  = "category"
+)
+
+// priceCents is the current price of a book in cents.
+const priceCents = `CAST(ROUND(CAST((SELECT cp.price FROM current_book_prices cp WHERE cp.book_id = books.id) AS REAL) * 100) AS INTEGER)`
+
+// filterConditions returns the conditions on the books table matching the
+// unarchived books of a filter along with their arguments, leaving out the
+// field of the given facet. A price band that does not exist matches no
+// book.
+func filterConditions(filter *models.BookFilter, facet string) ([]string, []interface{}) {
+	conditions := []string{"archived_at IS NULL"}
+	args := []interface{}{}
+	if filter == nil {
+		return conditions, args
+	}
+
+	if filter.Publisher != "" {
+		conditions = append(conditions, "publisher_id = (SELECT p.id FROM publishers p WHERE p.slug = ?)")
+		args = append(args, filter.Publisher)
+	}
+	if filter.Query != "" {
+		pattern := likePattern(filter.Query)
+		conditions = append(conditions, `(title LIKE ? ESCAPE '\' OR id IN (
+	SELECT ba.book_id
+	FROM book_authors ba
+	JOIN authors a
+		ON a.id = ba.author_id
+	WHERE a.name LIKE ? ESCAPE '\'
+))`)
+		args = append(args, pattern, pattern)
+	}
+	if filter.AuthorID != 0 && facet != facetAuthor {
+		conditions = append(conditions, "id IN (SELECT ba.book_id FROM book_authors ba WHERE ba.author_id = ? AND ba.role = 'author')")
+		args = append(args, filter.AuthorID)
+	}
+	if filter.PriceBand != "" && facet != facetPriceBand {
+		band, ok := models.GetPriceBand(filter.PriceBand)
+		switch {
+		case !ok:
+			conditions = append(conditions, "0")
+		case band.MaxCents == 0:
+			conditions = append(conditions, priceCents+" >= ?")
+			args = append(args, band.MinCents)
+		default:
+			conditions = append(conditions, priceCents+" >= ? AND "+priceCents+" < ?")
+			args = append(args, band.MinCents, band.MaxCents)
+		}
+	}
+	if filter.Category != "" && facet != facetCategory {
+		conditions = append(conditions, `id IN (
+	WITH RECURSIVE descendants(id) AS (
+		SELECT id FROM categories WHERE slug = ?
+		UNION
+		SELECT c.id FROM categories c
+		JOIN descendants d
+			ON c.parent_id = d.id
+	)
+	SELECT bc.book_id
+	FROM book_categories bc
+	JOIN descendants d
+		ON bc.category_id = d.id
+)`)
+		args = append(args, filter.Category)
+	}
+
+	return conditions, args
+}
+
+// likePattern returns a LIKE pattern matching text containing s, escaping
+// the wildcards in s.
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s) + "%"
+}
+
+// GetFacets counts the books matching a filter by author, price band and
+// category, a nil filter matches every book. Each facet leaves its own field
+// of the filter out. Only authors and categories with books are listed,
+// every price band is.
+func (s *Storage) GetFacets(ctx context.Context, filter *models.BookFilter) (*models.BookFacets, error) {
+	facets := &models.BookFacets{}
+
+	conditions, args := filterConditions(filter, facetAuthor)
+	query := `
+SELECT a.id, a.name, COUNT(DISTINCT ba.book_id) AS count
+FROM book_authors ba
+JOIN authors a
+	ON a.id = ba.author_id
+WHERE ba.role = 'author' AND ba.book_id IN (SELECT id FROM books WHERE %s)
+GROUP BY a.id, a.name
+ORDER BY count DESC, a.name, a.id;
+`
+	facets.Authors = []*models.AuthorFacet{}
+	if err := s.db.SelectContext(ctx, &facets.Authors, fmt.Sprintf(query, strings.Join(conditions, " AND ")), args...); err != nil {
+		return nil, fmt.Errorf("failed to count books by author: %v", err)
+	}
+
+	// price bands are listed as values so empty bands are counted too.
+	bands := []string{}
+	bandArgs := []interface{}{}
+	for i, band := range models.PriceBands {
+		bands = append(bands, "(?, ?, ?, ?)")
+		var maxCents interface{}
+		if band.MaxCents != 0 {
+			maxCents = band.MaxCents
+		}
+		bandArgs = append(bandArgs, band.Key, i, band.MinCents, maxCents)
+	}
+	conditions, args = filterConditions(filter, facetPriceBand)
+	query = `
+WITH bands(key, position, min_cents, max_cents) AS (VALUES %s),
+filtered(cents) AS (SELECT %s FROM books WHERE %s)
+SELECT b.key, COUNT(f.cents) AS count
+FROM bands b
+LEFT JOIN filtered f
+	ON f.cents >= b.min_cents AND (b.max_cents IS NULL OR f.cents < b.max_cents)
+GROUP BY b.key, b.position
+ORDER BY b.position;
+`
+	facets.PriceBands = []*models.PriceBandFacet{}
+	if err := s.db.SelectContext(ctx, &facets.PriceBands,
+		fmt.Sprintf(query, strings.Join(bands, ", "), priceCents, strings.Join(conditions, " AND ")),
+		append(bandArgs, args...)...,
+	); err != nil {
+		return nil, fmt.Errorf("failed to count books by price band: %v", err)
+	}
+	for _, facet := range facets.PriceBands {
+		band, _ := models.GetPriceBand(facet.Key)
+		facet.Min = formatCents(band.MinCents)
+		if band.MaxCents != 0 {
+			facet.Max = formatCents(band.MaxCents)
+		}
+	}
+
+	conditions, args = filterConditions(filter, facetCategory)
+	query = `
+WITH RECURSIVE tree(category_id, descendant_id) AS (
+	SELECT id, id FROM categories
+	UNION
+	SELECT t.category_id, c.id FROM categories c
+	JOIN tree t
+		ON c.parent_id = t.descendant_id
+)
+SELECT c.id, c.slug, c.name, COUNT(DISTINCT bc.book_id) AS count
+FROM tree t
+JOIN categories c
+	ON c.id = t.category_id
+JOIN book_categories bc
+	ON bc.category_id = t.descendant_id
+WHERE bc.book_id IN (SELECT id FROM books WHERE %s)
+GROUP BY c.id, c.slug, c.name
+ORDER BY count DESC, c.name, c.id;
+`
+	facets.Categories = []*models.CategoryFacet{}
+	if err := s.db.SelectContext(ctx, &facets.Categories, fmt.Sprintf(query, strings.Join(conditions, " AND ")), args...); err != nil {
+		return nil, fmt.Errorf("failed to count books by category: %v", err)
+	}
+
+	return facets, nil
+}
+
+// formatCents formats cents as a price such as "10.00".
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
@@ -0,0 +1,231 @@
+package book
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// testGetAuthorID returns the id of the seeded author with the given name.
+func testGetAuthorID(t *testing.T, ts *Storage, name string) int64 {
+	t.Helper()
+
+	var id int64
+	if err := ts.db.Get(&id, `SELECT id FROM authors WHERE name = ?;`, name); err != nil {
+		t.Fatalf("unexpected error when getting author id: %v", err)
+	}
+	return id
+}
+
+func Test_GetBooks_Filter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	clear := testGetAuthorID(t, ts, "James Clear")
+
+	tests := []struct {
+		name    string
+		filter  *models.BookFilter
+		wantIDs []int64
+	}{
+		{
+			name:    "Query",
+			filter:  &models.BookFilter{Query: "SECOND"},
+			wantIDs: []int64{3},
+		},
+		{
+			name:    "QueryAuthor",
+			filter:  &models.BookFilter{Query: "gladwell"},
+			wantIDs: []int64{2},
+		},
+		{
+			name:    "QueryWildcard",
+			filter:  &models.BookFilter{Query: "%"},
+			wantIDs: []int64{},
+		},
+		{
+			name:    "Author",
+			filter:  &models.BookFilter{AuthorID: clear},
+			wantIDs: []int64{1},
+		},
+		{
+			name:    "CategoryWithSubcategories",
+			filter:  &models.BookFilter{Category: "self-help"},
+			wantIDs: []int64{1, 3},
+		},
+		{
+			name:    "PriceBand",
+			filter:  &models.BookFilter{PriceBand: "under-10"},
+			wantIDs: []int64{2},
+		},
+		{
+			name:    "UnknownPriceBand",
+			filter:  &models.BookFilter{PriceBand: "free"},
+			wantIDs: []int64{},
+		},
+		{
+			name:    "Combined",
+			filter:  &models.BookFilter{Category: "nonfiction", PriceBand: "10-20", Query: "a"},
+			wantIDs: []int64{1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			books, err := ts.GetBooks(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetBooks(_, _) expected nil error, got = %v", err)
+			}
+
+			gotIDs := []int64{}
+			for _, b := range books {
+				gotIDs = append(gotIDs, b.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, gotIDs); diff != "" {
+				t.Fatalf("GetBooks(_, _) mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_GetFacets(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	var (
+		clear    = &models.AuthorFacet{ID: testGetAuthorID(t, ts, "James Clear"), Name: "James Clear", Count: 1}
+		gladwell = &models.AuthorFacet{ID: testGetAuthorID(t, ts, "Malcolm Gladwell"), Name: "Malcolm Gladwell", Count: 1}
+		forte    = &models.AuthorFacet{ID: testGetAuthorID(t, ts, "Tiago Forte"), Name: "Tiago Forte", Count: 1}
+	)
+
+	priceBands := func(counts ...int64) []*models.PriceBandFacet {
+		return []*models.PriceBandFacet{
+			{Key: "under-10", Min: "0.00", Max: "10.00", Count: counts[0]},
+			{Key: "10-20", Min: "10.00", Max: "20.00", Count: counts[1]},
+			{Key: "20-50", Min: "20.00", Max: "50.00", Count: counts[2]},
+			{Key: "50-and-over", Min: "50.00", Count: counts[3]},
+		}
+	}
+
+	category := func(id int64, slug, name string, count int64) *models.CategoryFacet {
+		return &models.CategoryFacet{ID: id, Slug: slug, Name: name, Count: count}
+	}
+	allCategories := []*models.CategoryFacet{
+		category(2, "nonfiction", "Nonfiction", 3),
+		category(3, "self-help", "Self-Help", 2),
+		category(5, "productivity", "Productivity", 1),
+		category(4, "psychology", "Psychology", 1),
+	}
+
+	tests := []struct {
+		name   string
+		filter *models.BookFilter
+		want   *models.BookFacets
+	}{
+		{
+			name:   "NoFilter",
+			filter: nil,
+			want: &models.BookFacets{
+				Authors:    []*models.AuthorFacet{clear, gladwell, forte},
+				PriceBands: priceBands(1, 2, 0, 0),
+				Categories: allCategories,
+			},
+		},
+		{
+			// the category facet keeps listing the other categories.
+			name:   "Category",
+			filter: &models.BookFilter{Category: "self-help"},
+			want: &models.BookFacets{
+				Authors:    []*models.AuthorFacet{clear, forte},
+				PriceBands: priceBands(0, 2, 0, 0),
+				Categories: allCategories,
+			},
+		},
+		{
+			name:   "AuthorAndPriceBand",
+			filter: &models.BookFilter{AuthorID: clear.ID, PriceBand: "10-20"},
+			want: &models.BookFacets{
+				Authors:    []*models.AuthorFacet{clear, forte},
+				PriceBands: priceBands(0, 1, 0, 0),
+				Categories: []*models.CategoryFacet{
+					category(2, "nonfiction", "Nonfiction", 1),
+					category(3, "self-help", "Self-Help", 1),
+				},
+			},
+		},
+		{
+			name:   "Query",
+			filter: &models.BookFilter{Query: "tipping"},
+			want: &models.BookFacets{
+				Authors:    []*models.AuthorFacet{gladwell},
+				PriceBands: priceBands(1, 0, 0, 0),
+				Categories: []*models.CategoryFacet{
+					category(2, "nonfiction", "Nonfiction", 1),
+					category(4, "psychology", "Psychology", 1),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ts.GetFacets(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetFacets(_, _) expected nil error, got = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("GetFacets(_, _) mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_GetCatalogVersion_Categories(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// categorizing books and moving categories change what the category
+	// filter and facets return.
+	for _, stmt := range []string{
+		`INSERT INTO book_categories (book_id, category_id) VALUES (1, 4);`,
+		`DELETE FROM book_categories WHERE book_id = 1 AND category_id = 4;`,
+		`UPDATE categories SET parent_id = 1 WHERE slug = 'productivity';`,
+		`UPDATE categories SET name = 'Habits' WHERE slug = 'self-help';`,
+	} {
+		version, err := ts.GetCatalogVersion(ctx)
+		if err != nil {
+			t.Fatalf("GetCatalogVersion(_) expected nil error, got = %v", err)
+		}
+		if _, err := ts.db.Exec(stmt); err != nil {
+			t.Fatalf("unexpected error when executing %q: %v", stmt, err)
+		}
+		got, err := ts.GetCatalogVersion(ctx)
+		if err != nil {
+			t.Fatalf("GetCatalogVersion(_) expected nil error, got = %v", err)
+		}
+		if got <= version {
+			t.Fatalf("GetCatalogVersion(_) error after %q, got = %v, want > %v", stmt, got, version)
+		}
+	}
+}
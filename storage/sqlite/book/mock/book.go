@@ -35,19 +35,92 @@ func (m *MockBookStorage) EXPECT() *MockBookStorageMockRecorder {
 	return m.recorder
 }
 
+// Archive mocks base method.
+func (m *MockBookStorage) Archive(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockBookStorageMockRecorder) Archive(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockBookStorage)(nil).Archive), ctx, id)
+}
+
+// EachBook mocks base method.
+func (m *MockBookStorage) EachBook(ctx context.Context, fn func(*models.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachBook", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachBook indicates an expected call of EachBook.
+func (mr *MockBookStorageMockRecorder) EachBook(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachBook", reflect.TypeOf((*MockBookStorage)(nil).EachBook), ctx, fn)
+}
+
+// GetArchivedBooks mocks base method.
+func (m *MockBookStorage) GetArchivedBooks(arg0 context.Context) ([]*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedBooks", arg0)
+	ret0, _ := ret[0].([]*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedBooks indicates an expected call of GetArchivedBooks.
+func (mr *MockBookStorageMockRecorder) GetArchivedBooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedBooks", reflect.TypeOf((*MockBookStorage)(nil).GetArchivedBooks), arg0)
+}
+
+// GetBookByID mocks base method.
+func (m *MockBookStorage) GetBookByID(arg0 context.Context, arg1 int64) (*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByID indicates an expected call of GetBookByID.
+func (mr *MockBookStorageMockRecorder) GetBookByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookStorage)(nil).GetBookByID), arg0, arg1)
+}
+
+// GetBookByISBN mocks base method.
+func (m *MockBookStorage) GetBookByISBN(arg0 context.Context, arg1 string) (*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", arg0, arg1)
+	ret0, _ := ret[0].(*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockBookStorageMockRecorder) GetBookByISBN(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookStorage)(nil).GetBookByISBN), arg0, arg1)
+}
+
 // GetBooks mocks base method.
-func (m *MockBookStorage) GetBooks(arg0 context.Context) ([]*models.Book, error) {
+func (m *MockBookStorage) GetBooks(arg0 context.Context, arg1 *models.BookFilter) ([]*models.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBooks", arg0)
+	ret := m.ctrl.Call(m, "GetBooks", arg0, arg1)
 	ret0, _ := ret[0].([]*models.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBooks indicates an expected call of GetBooks.
-func (mr *MockBookStorageMockRecorder) GetBooks(arg0 interface{}) *gomock.Call {
+func (mr *MockBookStorageMockRecorder) GetBooks(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookStorage)(nil).GetBooks), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookStorage)(nil).GetBooks), arg0, arg1)
 }
 
 // GetBooksByIDs mocks base method.
@@ -64,3 +137,135 @@ func (mr *MockBookStorageMockRecorder) GetBooksByIDs(arg0, arg1 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByIDs", reflect.TypeOf((*MockBookStorage)(nil).GetBooksByIDs), arg0, arg1)
 }
+
+// GetBooksByISBNs mocks base method.
+func (m *MockBookStorage) GetBooksByISBNs(arg0 context.Context, arg1 []string) ([]*models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksByISBNs", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksByISBNs indicates an expected call of GetBooksByISBNs.
+func (mr *MockBookStorageMockRecorder) GetBooksByISBNs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByISBNs", reflect.TypeOf((*MockBookStorage)(nil).GetBooksByISBNs), arg0, arg1)
+}
+
+// GetCatalogState mocks base method.
+func (m *MockBookStorage) GetCatalogState(arg0 context.Context) (*models.CatalogState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalogState", arg0)
+	ret0, _ := ret[0].(*models.CatalogState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogState indicates an expected call of GetCatalogState.
+func (mr *MockBookStorageMockRecorder) GetCatalogState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogState", reflect.TypeOf((*MockBookStorage)(nil).GetCatalogState), arg0)
+}
+
+// GetCatalogVersion mocks base method.
+func (m *MockBookStorage) GetCatalogVersion(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalogVersion", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogVersion indicates an expected call of GetCatalogVersion.
+func (mr *MockBookStorageMockRecorder) GetCatalogVersion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogVersion", reflect.TypeOf((*MockBookStorage)(nil).GetCatalogVersion), arg0)
+}
+
+// GetChanges mocks base method.
+func (m *MockBookStorage) GetChanges(ctx context.Context, since int64, limit int) ([]*models.BookChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChanges", ctx, since, limit)
+	ret0, _ := ret[0].([]*models.BookChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockBookStorageMockRecorder) GetChanges(ctx, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockBookStorage)(nil).GetChanges), ctx, since, limit)
+}
+
+// GetFacets mocks base method.
+func (m *MockBookStorage) GetFacets(arg0 context.Context, arg1 *models.BookFilter) (*models.BookFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFacets", arg0, arg1)
+	ret0, _ := ret[0].(*models.BookFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFacets indicates an expected call of GetFacets.
+func (mr *MockBookStorageMockRecorder) GetFacets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFacets", reflect.TypeOf((*MockBookStorage)(nil).GetFacets), arg0, arg1)
+}
+
+// Restore mocks base method.
+func (m *MockBookStorage) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockBookStorageMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockBookStorage)(nil).Restore), ctx, id)
+}
+
+// SetCover mocks base method.
+func (m *MockBookStorage) SetCover(ctx context.Context, id int64, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCover", ctx, id, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCover indicates an expected call of SetCover.
+func (mr *MockBookStorageMockRecorder) SetCover(ctx, id, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCover", reflect.TypeOf((*MockBookStorage)(nil).SetCover), ctx, id, hash)
+}
+
+// SetReleaseDate mocks base method.
+func (m *MockBookStorage) SetReleaseDate(ctx context.Context, id int64, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReleaseDate", ctx, id, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReleaseDate indicates an expected call of SetReleaseDate.
+func (mr *MockBookStorageMockRecorder) SetReleaseDate(ctx, id, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReleaseDate", reflect.TypeOf((*MockBookStorage)(nil).SetReleaseDate), ctx, id, date)
+}
+
+// UpsertBooks mocks base method.
+func (m *MockBookStorage) UpsertBooks(ctx context.Context, books []*models.BookUpsert, dryRun bool) ([]*models.BookUpsertResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertBooks", ctx, books, dryRun)
+	ret0, _ := ret[0].([]*models.BookUpsertResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertBooks indicates an expected call of UpsertBooks.
+func (mr *MockBookStorageMockRecorder) UpsertBooks(ctx, books, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBooks", reflect.TypeOf((*MockBookStorage)(nil).UpsertBooks), ctx, books, dryRun)
+}
@@ -0,0 +1,242 @@
+package book
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	ErrMissingRequiredFields = errors.New("title, author and price are required to create a book")
+	ErrISBNAlreadyExist      = errors.New("isbn already exist")
+)
+
+// UpsertBooks creates or updates the given books in one transaction,
+// returning a result for every book in the same order. A book matches an
+// existing one by its id, or else by the ISBN-13 of any of its editions, and
+// is created when it matches none. A book matched by an edition other than
+// its default one sets the stock of that edition, leaving the ISBN and stock
+// of the book as they are. Every book is upserted within its own savepoint so
+// a failing book does not hide the errors of the others, but nothing is
+// committed when any book fails or when dryRun is set.
+func (s *Storage) UpsertBooks(ctx context.Context, books []*models.BookUpsert, dryRun bool) ([]*models.BookUpsertResult, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	failed := false
+	results := []*models.BookUpsertResult{}
+	for _, book := range books {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT upsert_book;`); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %v", err)
+		}
+
+		result, err := upsertBook(ctx, tx, book)
+		if err != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO upsert_book;`); err != nil {
+				return nil, fmt.Errorf("failed to roll back to savepoint: %v", err)
+			}
+			failed = true
+			result = &models.BookUpsertResult{
+				BookID: book.ID,
+				Err:    err,
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `RELEASE upsert_book;`); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %v", err)
+		}
+		results = append(results, result)
+	}
+
+	if failed || dryRun {
+		return results, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return results, nil
+}
+
+// upsertBook creates or updates a single book within the given transaction.
+func upsertBook(ctx context.Context, tx *sqlx.Tx, book *models.BookUpsert) (*models.BookUpsertResult, error) {
+	bookID, editionID, err := findBookID(ctx, tx, book)
+	if err != nil {
+		return nil, err
+	}
+
+	// both ISBNs are stored, an ISBN-13 outside the 978 prefix simply has
+	// no ISBN-10.
+	fields := map[string]interface{}{}
+	if book.ISBN13 != "" {
+		fields["isbn_13"] = book.ISBN13
+		if isbn10, err := isbn.To10(book.ISBN13); err == nil {
+			fields["isbn_10"] = isbn10
+		} else {
+			fields["isbn_10"] = nil
+		}
+	}
+	if book.Title != nil {
+		fields["title"] = *book.Title
+	}
+	if book.Authors != nil {
+		fields["author"] = strings.Join(book.Authors, ", ")
+	}
+	if book.Price != nil {
+		fields["price"] = *book.Price
+	}
+	if book.Description != nil {
+		fields["description"] = *book.Description
+	}
+	if book.Stock != nil {
+		fields["stock"] = *book.Stock
+	}
+
+	if editionID != 0 {
+		delete(fields, "isbn_13")
+		delete(fields, "isbn_10")
+		delete(fields, "stock")
+		if book.Stock != nil {
+			if _, err := tx.ExecContext(ctx, `UPDATE editions SET stock = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;`, *book.Stock, editionID); err != nil {
+				return nil, upsertError(err)
+			}
+		}
+	}
+
+	created := bookID == 0
+	if created {
+		if book.Title == nil || len(book.Authors) == 0 || book.Price == nil {
+			return nil, ErrMissingRequiredFields
+		}
+		if book.Description == nil {
+			fields["description"] = ""
+		}
+
+		columns := []string{}
+		values := []string{}
+		for column := range fields {
+			columns = append(columns, column)
+			values = append(values, ":"+column)
+		}
+
+		res, err := tx.NamedExecContext(ctx,
+			fmt.Sprintf(`INSERT INTO books (%s) VALUES (%s);`, strings.Join(columns, ","), strings.Join(values, ",")),
+			fields,
+		)
+		if err != nil {
+			return nil, upsertError(err)
+		}
+
+		bookID, err = res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inserted book id: %v", err)
+		}
+	} else if len(fields) > 0 {
+		sets := []string{"updated_at = CURRENT_TIMESTAMP"}
+		for column := range fields {
+			sets = append(sets, fmt.Sprintf("%s = :%s", column, column))
+		}
+		fields["id"] = bookID
+
+		if _, err := tx.NamedExecContext(ctx,
+			fmt.Sprintf(`UPDATE books SET %s WHERE id = :id;`, strings.Join(sets, ", ")),
+			fields,
+		); err != nil {
+			return nil, upsertError(err)
+		}
+	}
+
+	if book.Authors != nil {
+		if err := creditAuthors(ctx, tx, bookID, book.Authors); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.BookUpsertResult{
+		BookID:  bookID,
+		Created: created,
+	}, nil
+}
+
+// findBookID fetches the id of the existing book matching the upsert,
+// returning 0 when the book is to be created. The id of the edition matched
+// by its ISBN is returned as well when it is not the default edition of its
+// book.
+func findBookID(ctx context.Context, tx *sqlx.Tx, book *models.BookUpsert) (int64, int64, error) {
+	var bookID, editionID int64
+	switch {
+	case book.ID != 0:
+		err := tx.GetContext(ctx, &bookID, `SELECT id FROM books WHERE id = ?;`, book.ID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return 0, 0, fmt.Errorf("book with id %d %w", book.ID, sqlite.ErrNotFound)
+			}
+			return 0, 0, fmt.Errorf("failed to get book by id: %v", err)
+		}
+	case book.ISBN13 != "":
+		query := `
+SELECT book_id, CASE WHEN is_default THEN 0 ELSE id END
+FROM editions
+WHERE isbn_13 = ?;
+`
+		err := tx.QueryRowxContext(ctx, query, book.ISBN13).Scan(&bookID, &editionID)
+		if err != nil && err != sql.ErrNoRows {
+			return 0, 0, fmt.Errorf("failed to get book by isbn: %v", err)
+		}
+	}
+	return bookID, editionID, nil
+}
+
+// creditAuthors makes the given names the authors of a book in order,
+// replacing the authors it was credited to before. A name of an author merged
+// into another is credited to the author it was merged into.
+func creditAuthors(ctx context.Context, tx *sqlx.Tx, bookID int64, names []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_authors WHERE book_id = ? AND role = 'author';`, bookID); err != nil {
+		return fmt.Errorf("failed to remove book authors: %v", err)
+	}
+
+	stmt := `
+INSERT OR IGNORE INTO book_authors (book_id, author_id, role, position)
+SELECT ?, COALESCE(canonical_id, id), 'author', ?
+FROM authors
+WHERE name = ?;
+`
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO authors (name) VALUES (?);`, name); err != nil {
+			return fmt.Errorf("failed to insert author: %v", err)
+		}
+		if _, err := tx.ExecContext(ctx, stmt, bookID, i, name); err != nil {
+			return fmt.Errorf("failed to credit book author: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// upsertError maps the constraint a book upsert failed on to its error.
+func upsertError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "UNIQUE") && strings.Contains(err.Error(), "isbn"):
+		return ErrISBNAlreadyExist
+	case strings.Contains(err.Error(), "CHECK"):
+		return fmt.Errorf("invalid book: %v", err)
+	}
+	return fmt.Errorf("failed to upsert book: %v", err)
+}
@@ -0,0 +1,204 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func Test_UpsertBooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	newBook := func() *models.BookUpsert {
+		stock := int64(5)
+		return &models.BookUpsert{
+			ISBN13:  "9780132350884",
+			Title:   strPtr("Clean Code"),
+			Authors: []string{"Robert C. Martin"},
+			Price:   strPtr("32.50"),
+			Stock:   &stock,
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		results, err := ts.UpsertBooks(ctx, []*models.BookUpsert{
+			newBook(),
+			// matched by id, only the price changes.
+			{ID: 1, Price: strPtr("12.00")},
+			// matched by the seeded ISBN of The Tipping Point.
+			{ISBN13: "9780316346627", Authors: []string{"M. Gladwell"}},
+		}, false)
+		if err != nil {
+			t.Fatalf("UpsertBooks(_, _, _) expected nil error, got = %v", err)
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				t.Fatalf("UpsertBooks(_, _, _) expected nil result error, got = %v", result.Err)
+			}
+		}
+		if !results[0].Created || results[1].Created || results[2].BookID != 2 {
+			t.Fatalf("UpsertBooks(_, _, _) unexpected results: %+v, %+v, %+v", results[0], results[1], results[2])
+		}
+
+		created, err := ts.GetBookByID(ctx, results[0].BookID)
+		if err != nil {
+			t.Fatalf("unexpected error when GetBookByID: %v", err)
+		}
+		if created.ISBN10 != "0132350882" || created.Stock != 5 || len(created.Authors) != 1 {
+			t.Fatalf("UpsertBooks(_, _, _) created unexpected book: %+v", created)
+		}
+
+		updated, err := ts.GetBookByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("unexpected error when GetBookByID: %v", err)
+		}
+		if updated.Price != "12.00" || updated.Title != "Atomic Habits" {
+			t.Fatalf("UpsertBooks(_, _, _) updated unexpected book: %+v", updated)
+		}
+
+		credited, err := ts.GetBookByID(ctx, 2)
+		if err != nil {
+			t.Fatalf("unexpected error when GetBookByID: %v", err)
+		}
+		if len(credited.Authors) != 1 || credited.Authors[0].Name != "M. Gladwell" {
+			t.Fatalf("UpsertBooks(_, _, _) credited unexpected authors: %+v", credited.Authors)
+		}
+	})
+
+	t.Run("Alias", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		// M. Gladwell was merged into Malcolm Gladwell.
+		if _, err := ts.db.Exec(`INSERT INTO authors (name, canonical_id) SELECT 'M. Gladwell', id FROM authors WHERE name = 'Malcolm Gladwell';`); err != nil {
+			t.Fatalf("unexpected error when inserting alias: %v", err)
+		}
+
+		if _, err := ts.UpsertBooks(ctx, []*models.BookUpsert{
+			{ISBN13: "9780316346627", Authors: []string{"M. Gladwell"}},
+		}, false); err != nil {
+			t.Fatalf("UpsertBooks(_, _, _) expected nil error, got = %v", err)
+		}
+
+		credited, err := ts.GetBookByID(ctx, 2)
+		if err != nil {
+			t.Fatalf("unexpected error when GetBookByID: %v", err)
+		}
+		if len(credited.Authors) != 1 || credited.Authors[0].Name != "Malcolm Gladwell" {
+			t.Fatalf("UpsertBooks(_, _, _) credited unexpected authors: %+v", credited.Authors)
+		}
+	})
+
+	t.Run("EditionISBN", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		if _, err := ts.db.Exec(`INSERT INTO editions (book_id, format, isbn_13, stock) VALUES (3, 'hardcover', '9780306406157', 1);`); err != nil {
+			t.Fatalf("unexpected error when inserting edition: %v", err)
+		}
+		before, err := ts.GetBookByID(ctx, 3)
+		if err != nil {
+			t.Fatalf("unexpected error when GetBookByID: %v", err)
+		}
+
+		stock := int64(7)
+		results, err := ts.UpsertBooks(ctx, []*models.BookUpsert{
+			{ISBN13: "9780306406157", Title: strPtr("Building a Second Brain"), Stock: &stock},
+		}, false)
+		if err != nil {
+			t.Fatalf("UpsertBooks(_, _, _) expected nil error, got = %v", err)
+		}
+		if results[0].Err != nil || results[0].Created || results[0].BookID != 3 {
+			t.Fatalf("UpsertBooks(_, _, _) unexpected result: %+v", results[0])
+		}
+
+		updated, err := ts.GetBookByID(ctx, 3)
+		if err != nil {
+			t.Fatalf("unexpected error when GetBookByID: %v", err)
+		}
+		if updated.Title != "Building a Second Brain" || updated.ISBN13 != before.ISBN13 || updated.Stock != before.Stock {
+			t.Fatalf("UpsertBooks(_, _, _) updated unexpected book: %+v", updated)
+		}
+
+		var editionStock int64
+		if err := ts.db.Get(&editionStock, `SELECT stock FROM editions WHERE isbn_13 = '9780306406157';`); err != nil {
+			t.Fatalf("unexpected error when getting edition stock: %v", err)
+		}
+		if editionStock != stock {
+			t.Fatalf("UpsertBooks(_, _, _) error, got edition stock = %v, want = %v", editionStock, stock)
+		}
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		results, err := ts.UpsertBooks(ctx, []*models.BookUpsert{newBook()}, true)
+		if err != nil {
+			t.Fatalf("UpsertBooks(_, _, _) expected nil error, got = %v", err)
+		}
+		if results[0].Err != nil || !results[0].Created {
+			t.Fatalf("UpsertBooks(_, _, _) unexpected result: %+v", results[0])
+		}
+
+		if _, err := ts.GetBookByISBN(ctx, "9780132350884"); !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("UpsertBooks(_, _, _) committed on dry run, got = %v", err)
+		}
+	})
+
+	t.Run("RowErrors", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		results, err := ts.UpsertBooks(ctx, []*models.BookUpsert{
+			newBook(),
+			{ID: 1000, Price: strPtr("1.00")},
+			{ISBN13: "9780306406157", Title: strPtr("Missing Author")},
+			// ISBN of Atomic Habits given to The Tipping Point.
+			{ID: 2, ISBN13: "9780735211292"},
+		}, false)
+		if err != nil {
+			t.Fatalf("UpsertBooks(_, _, _) expected nil error, got = %v", err)
+		}
+
+		if results[0].Err != nil {
+			t.Fatalf("UpsertBooks(_, _, _) expected nil result error, got = %v", results[0].Err)
+		}
+		if !errors.Is(results[1].Err, sqlite.ErrNotFound) {
+			t.Fatalf("UpsertBooks(_, _, _) error, got = %v, want = %v", results[1].Err, sqlite.ErrNotFound)
+		}
+		if !errors.Is(results[2].Err, ErrMissingRequiredFields) {
+			t.Fatalf("UpsertBooks(_, _, _) error, got = %v, want = %v", results[2].Err, ErrMissingRequiredFields)
+		}
+		if !errors.Is(results[3].Err, ErrISBNAlreadyExist) {
+			t.Fatalf("UpsertBooks(_, _, _) error, got = %v, want = %v", results[3].Err, ErrISBNAlreadyExist)
+		}
+
+		// the valid book is not committed either.
+		if _, err := ts.GetBookByISBN(ctx, "9780132350884"); !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("UpsertBooks(_, _, _) committed with row errors, got = %v", err)
+		}
+	})
+}
@@ -0,0 +1,268 @@
+package category
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrSlugAlreadyExist = errors.New("slug already exist")
+	ErrParentNotFound   = errors.New("parent category not found")
+	ErrBookIDNotFound   = errors.New("book id not found")
+)
+
+//go:generate mockgen -source=category.go -destination=mock/category.go -package=mock
+type CategoryStorage interface {
+	// GetCategories fetches all categories from our storage as a tree,
+	// returning only the root categories with their children nested.
+	GetCategories(context.Context) ([]*models.Category, error)
+
+	// GetCategoryBySlug fetches a category by the given slug.
+	GetCategoryBySlug(context.Context, string) (*models.Category, error)
+
+	// GetBookIDsByCategoryID fetches the ids of all books assigned to the
+	// given category or to any of its descendants.
+	GetBookIDsByCategoryID(context.Context, int64) ([]int64, error)
+
+	// Create adds a new category to our storage.
+	Create(context.Context, *models.Category) (*models.Category, error)
+
+	// AssignBook assigns a book to a category.
+	AssignBook(ctx context.Context, categoryID, bookID int64) error
+
+	// UnassignBook removes a book from a category.
+	UnassignBook(ctx context.Context, categoryID, bookID int64) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around category storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// GetCategories fetches all categories from our storage as a tree,
+// returning only the root categories with their children nested.
+func (s *Storage) GetCategories(ctx context.Context) ([]*models.Category, error) {
+	query := `
+SELECT id, parent_id, name, slug
+FROM categories
+ORDER BY name
+`
+
+	rows, err := s.db.QueryxContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query from categories table: %v", err)
+	}
+	defer rows.Close()
+
+	// iterate through each row and save it as category model.
+	categories := []*models.Category{}
+	for rows.Next() {
+		var category models.Category
+
+		if err := rows.StructScan(&category); err != nil {
+			return nil, fmt.Errorf("failed when scanning through rows: %v", err)
+		}
+		category.Children = []*models.Category{}
+
+		categories = append(categories, &category)
+	}
+
+	// attach every category to its parent, categories without a parent
+	// are the roots of the tree.
+	categoriesMap := map[int64]*models.Category{}
+	for _, c := range categories {
+		categoriesMap[c.ID] = c
+	}
+
+	roots := []*models.Category{}
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		parent, ok := categoriesMap[*c.ParentID]
+		if !ok {
+			roots = append(roots, c)
+			continue
+		}
+		parent.Children = append(parent.Children, c)
+	}
+
+	return roots, nil
+}
+
+// GetCategoryBySlug fetches a category by the given slug.
+func (s *Storage) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	query := `
+SELECT id, parent_id, name, slug
+FROM categories
+WHERE slug = :slug
+`
+
+	stmt, err := s.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare GetCategoryBySlug statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var category models.Category
+	arg := map[string]interface{}{
+		"slug": slug,
+	}
+	if err := stmt.GetContext(ctx, &category, arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to perform GetCategoryBySlug storage operation: %w", err)
+	}
+	category.Children = []*models.Category{}
+
+	return &category, nil
+}
+
+// GetBookIDsByCategoryID fetches the ids of all books assigned to the
+// given category or to any of its descendants.
+func (s *Storage) GetBookIDsByCategoryID(ctx context.Context, categoryID int64) ([]int64, error) {
+	query := `
+WITH RECURSIVE descendants(id) AS (
+	SELECT id FROM categories WHERE id = :category_id
+	UNION
+	SELECT c.id FROM categories c
+	JOIN descendants d
+		ON c.parent_id = d.id
+)
+SELECT DISTINCT bc.book_id
+FROM book_categories bc
+JOIN descendants d
+	ON bc.category_id = d.id
+ORDER BY bc.book_id
+`
+
+	stmt, err := s.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare GetBookIDsByCategoryID statement: %w", err)
+	}
+	defer stmt.Close()
+
+	arg := map[string]interface{}{
+		"category_id": categoryID,
+	}
+
+	ids := []int64{}
+	if err := stmt.SelectContext(ctx, &ids, arg); err != nil {
+		return nil, fmt.Errorf("failed to query from book_categories table: %v", err)
+	}
+
+	return ids, nil
+}
+
+// Create adds a new category to our storage.
+func (s *Storage) Create(ctx context.Context, category *models.Category) (*models.Category, error) {
+	stmt := `INSERT INTO categories(%s) VALUES(%s);`
+
+	// fields and values to be operated
+	fields := []string{
+		"parent_id",
+		"name",
+		"slug",
+	}
+	values := []string{
+		":parent_id",
+		":name",
+		":slug",
+	}
+
+	res, err := s.db.NamedExecContext(ctx,
+		fmt.Sprintf(stmt, strings.Join(fields, ","), strings.Join(values, ",")),
+		category,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return nil, ErrSlugAlreadyExist
+		}
+		if strings.Contains(err.Error(), errForeignKeyConstraint) {
+			return nil, ErrParentNotFound
+		}
+		return nil, fmt.Errorf("failed to perform Create operation: %w", err)
+	}
+
+	insertedID, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to Create category: %v", err)
+	}
+
+	createdCategory := &models.Category{
+		ID:       insertedID,
+		ParentID: category.ParentID,
+		Name:     category.Name,
+		Slug:     category.Slug,
+		Children: []*models.Category{},
+	}
+
+	return createdCategory, nil
+}
+
+// AssignBook assigns a book to a category, assigning a book that is
+// already in the category is a no-op.
+func (s *Storage) AssignBook(ctx context.Context, categoryID, bookID int64) error {
+	stmt := `
+INSERT OR IGNORE INTO book_categories (book_id, category_id)
+VALUES (:book_id, :category_id);
+`
+
+	arg := map[string]interface{}{
+		"book_id":     bookID,
+		"category_id": categoryID,
+	}
+
+	if _, err := s.db.NamedExecContext(ctx, stmt, arg); err != nil {
+		if strings.Contains(err.Error(), errForeignKeyConstraint) {
+			return ErrBookIDNotFound
+		}
+		return fmt.Errorf("failed to perform AssignBook operation: %w", err)
+	}
+
+	return nil
+}
+
+// UnassignBook removes a book from a category.
+func (s *Storage) UnassignBook(ctx context.Context, categoryID, bookID int64) error {
+	stmt := `
+DELETE FROM book_categories
+WHERE book_id = :book_id AND category_id = :category_id;
+`
+
+	arg := map[string]interface{}{
+		"book_id":     bookID,
+		"category_id": categoryID,
+	}
+
+	res, err := s.db.NamedExecContext(ctx, stmt, arg)
+	if err != nil {
+		return fmt.Errorf("failed to perform UnassignBook operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
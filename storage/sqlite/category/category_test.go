@@ -0,0 +1,261 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_GetCategories(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	categories, err := ts.GetCategories(ctx)
+	if err != nil {
+		t.Fatalf("GetCategories(_) expected nil error, got = %v", err)
+	}
+
+	// seeded categories have fiction and nonfiction as their roots.
+	if len(categories) != 2 {
+		t.Fatalf("GetCategories(_) error, got = %v, want = %v root categories", len(categories), 2)
+	}
+	for _, c := range categories {
+		if c.ParentID != nil {
+			t.Fatalf("GetCategories(_) error, root category %q has parent id %v", c.Slug, *c.ParentID)
+		}
+	}
+}
+
+func Test_GetCategoryBySlug(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		category, err := ts.GetCategoryBySlug(ctx, "self-help")
+		if err != nil {
+			t.Fatalf("GetCategoryBySlug(_, _) expected nil error, got = %v", err)
+		}
+		if category.Slug != "self-help" {
+			t.Fatalf("GetCategoryBySlug(_, _) error, got = %v, want = %v", category.Slug, "self-help")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ts.GetCategoryBySlug(ctx, genString())
+		if !errors.Is(err, sqlite.ErrNotFound) {
+			t.Fatalf("GetCategoryBySlug(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+		}
+	})
+}
+
+func Test_GetBookIDsByCategoryID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	tests := []struct {
+		name      string
+		slug      string
+		wantBooks int
+	}{
+		{
+			// nonfiction has no books of its own, but all of its
+			// descendants do.
+			name:      "IncludeDescendants",
+			slug:      "nonfiction",
+			wantBooks: 3,
+		},
+		{
+			name:      "Leaf",
+			slug:      "productivity",
+			wantBooks: 1,
+		},
+		{
+			name:      "Empty",
+			slug:      "fiction",
+			wantBooks: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			category, err := ts.GetCategoryBySlug(ctx, tt.slug)
+			if err != nil {
+				t.Fatalf("unexpected error when getting category: %v", err)
+			}
+
+			ids, err := ts.GetBookIDsByCategoryID(ctx, category.ID)
+			if err != nil {
+				t.Fatalf("GetBookIDsByCategoryID(_, _) expected nil error, got = %v", err)
+			}
+			if len(ids) != tt.wantBooks {
+				t.Fatalf("GetBookIDsByCategoryID(_, _) error, got = %v, want = %v books", len(ids), tt.wantBooks)
+			}
+		})
+	}
+}
+
+func Test_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	parent, err := ts.GetCategoryBySlug(ctx, "fiction")
+	if err != nil {
+		t.Fatalf("unexpected error when getting category: %v", err)
+	}
+
+	notFoundParentID := int64(100000)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		created, err := ts.Create(ctx, &models.Category{
+			ParentID: &parent.ID,
+			Name:     genString(),
+			Slug:     genString(),
+		})
+		if err != nil {
+			t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+		}
+		if created.ID == 0 {
+			t.Fatalf("Create(_, _) error, expected non zero id")
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			category *models.Category
+			wantErr  error
+		}{
+			{
+				name: "SlugAlreadyExist",
+				category: &models.Category{
+					Name: genString(),
+					Slug: "fiction",
+				},
+				wantErr: ErrSlugAlreadyExist,
+			},
+			{
+				name: "ParentNotFound",
+				category: &models.Category{
+					ParentID: &notFoundParentID,
+					Name:     genString(),
+					Slug:     genString(),
+				},
+				wantErr: ErrParentNotFound,
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := ts.Create(ctx, tt.category)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create(_, _) error, got = %v, want = %v", err, tt.wantErr)
+				}
+			})
+		}
+	})
+}
+
+func Test_AssignBook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	category, err := ts.GetCategoryBySlug(ctx, "fiction")
+	if err != nil {
+		t.Fatalf("unexpected error when getting category: %v", err)
+	}
+
+	var bookID int64
+	if err := ts.db.Get(&bookID, `SELECT id FROM books LIMIT 1`); err != nil {
+		t.Fatalf("unexpected error when getting book id: %v", err)
+	}
+
+	if err := ts.AssignBook(ctx, category.ID, bookID); err != nil {
+		t.Fatalf("AssignBook(_, _, _) expected nil error, got = %v", err)
+	}
+
+	// assigning the same book twice should not fail.
+	if err := ts.AssignBook(ctx, category.ID, bookID); err != nil {
+		t.Fatalf("AssignBook(_, _, _) expected nil error, got = %v", err)
+	}
+
+	ids, err := ts.GetBookIDsByCategoryID(ctx, category.ID)
+	if err != nil {
+		t.Fatalf("unexpected error when getting book ids: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != bookID {
+		t.Fatalf("AssignBook(_, _, _) error, got = %v, want = %v", ids, []int64{bookID})
+	}
+
+	if err := ts.AssignBook(ctx, category.ID, int64(100000)); !errors.Is(err, ErrBookIDNotFound) {
+		t.Fatalf("AssignBook(_, _, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+	}
+
+	if err := ts.UnassignBook(ctx, category.ID, bookID); err != nil {
+		t.Fatalf("UnassignBook(_, _, _) expected nil error, got = %v", err)
+	}
+	if err := ts.UnassignBook(ctx, category.ID, bookID); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("UnassignBook(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
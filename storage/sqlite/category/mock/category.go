@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: category.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockCategoryStorage is a mock of CategoryStorage interface.
+type MockCategoryStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryStorageMockRecorder
+}
+
+// MockCategoryStorageMockRecorder is the mock recorder for MockCategoryStorage.
+type MockCategoryStorageMockRecorder struct {
+	mock *MockCategoryStorage
+}
+
+// NewMockCategoryStorage creates a new mock instance.
+func NewMockCategoryStorage(ctrl *gomock.Controller) *MockCategoryStorage {
+	mock := &MockCategoryStorage{ctrl: ctrl}
+	mock.recorder = &MockCategoryStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryStorage) EXPECT() *MockCategoryStorageMockRecorder {
+	return m.recorder
+}
+
+// AssignBook mocks base method.
+func (m *MockCategoryStorage) AssignBook(ctx context.Context, categoryID, bookID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignBook", ctx, categoryID, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignBook indicates an expected call of AssignBook.
+func (mr *MockCategoryStorageMockRecorder) AssignBook(ctx, categoryID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignBook", reflect.TypeOf((*MockCategoryStorage)(nil).AssignBook), ctx, categoryID, bookID)
+}
+
+// Create mocks base method.
+func (m *MockCategoryStorage) Create(arg0 context.Context, arg1 *models.Category) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoryStorageMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategoryStorage)(nil).Create), arg0, arg1)
+}
+
+// GetBookIDsByCategoryID mocks base method.
+func (m *MockCategoryStorage) GetBookIDsByCategoryID(arg0 context.Context, arg1 int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookIDsByCategoryID", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookIDsByCategoryID indicates an expected call of GetBookIDsByCategoryID.
+func (mr *MockCategoryStorageMockRecorder) GetBookIDsByCategoryID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookIDsByCategoryID", reflect.TypeOf((*MockCategoryStorage)(nil).GetBookIDsByCategoryID), arg0, arg1)
+}
+
+// GetCategories mocks base method.
+func (m *MockCategoryStorage) GetCategories(arg0 context.Context) ([]*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", arg0)
+	ret0, _ := ret[0].([]*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockCategoryStorageMockRecorder) GetCategories(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockCategoryStorage)(nil).GetCategories), arg0)
+}
+
+// GetCategoryBySlug mocks base method.
+func (m *MockCategoryStorage) GetCategoryBySlug(arg0 context.Context, arg1 string) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryBySlug", arg0, arg1)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryBySlug indicates an expected call of GetCategoryBySlug.
+func (mr *MockCategoryStorageMockRecorder) GetCategoryBySlug(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryBySlug", reflect.TypeOf((*MockCategoryStorage)(nil).GetCategoryBySlug), arg0, arg1)
+}
+
+// UnassignBook mocks base method.
+func (m *MockCategoryStorage) UnassignBook(ctx context.Context, categoryID, bookID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignBook", ctx, categoryID, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignBook indicates an expected call of UnassignBook.
+func (mr *MockCategoryStorageMockRecorder) UnassignBook(ctx, categoryID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignBook", reflect.TypeOf((*MockCategoryStorage)(nil).UnassignBook), ctx, categoryID, bookID)
+}
@@ -0,0 +1,300 @@
+package edition
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/isbn"
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrBookIDNotFound   = errors.New("book id not found")
+	ErrISBNAlreadyExist = errors.New("isbn already exist")
+	ErrNotEbook         = errors.New("edition is not an ebook")
+)
+
+//go:generate mockgen -source=edition.go -destination=mock/edition.go -package=mock
+type EditionStorage interface {
+	// GetEditionsByIDs fetches all the editions by the given ids whose book
+	// is not archived.
+	GetEditionsByIDs(ctx context.Context, ids []int64) ([]*models.Edition, error)
+
+	// GetEditionsByISBNs fetches all the editions by the given ISBN-13s
+	// whose book is not archived.
+	GetEditionsByISBNs(ctx context.Context, isbns []string) ([]*models.Edition, error)
+
+	// Create adds a new edition of a book.
+	Create(context.Context, *models.Edition) (*models.Edition, error)
+
+	// Update changes the given fields of an edition of a book.
+	Update(ctx context.Context, bookID, editionID int64, update *models.EditionUpdate) (*models.Edition, error)
+
+	// SetFile sets the blob key and name of the file of an ebook edition.
+	SetFile(ctx context.Context, bookID, editionID int64, key, name string) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around edition storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// editionColumns are the columns selected into the edition model, an edition
+// without a price of its own sells at the current price of its book.
+const editionColumns = `e.id, e.book_id, e.format,
+	COALESCE(e.isbn_10, '') AS isbn_10,
+	COALESCE(e.isbn_13, '') AS isbn_13,
+	COALESCE(e.price, cp.price) AS price,
+	e.stock,
+	CASE WHEN e.stock > 0 OR e.format = 'ebook' THEN 'in_stock' ELSE 'out_of_stock' END AS stock_status,
+	e.is_default`
+
+// editionsQuery selects editions whose book is not archived matching the
+// given condition.
+const editionsQuery = `
+SELECT %s
+FROM editions e
+JOIN books b
+	ON b.id = e.book_id
+JOIN current_book_prices cp
+	ON cp.book_id = e.book_id
+WHERE %s AND b.archived_at IS NULL
+ORDER BY e.book_id, e.is_default DESC, e.id;
+`
+
+// GetEditionsByIDs fetches all the editions by the given ids whose book is
+// not archived.
+func (s *Storage) GetEditionsByIDs(ctx context.Context, ids []int64) ([]*models.Edition, error) {
+	if len(ids) < 1 {
+		return []*models.Edition{}, nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(editionsQuery, editionColumns, "e.id IN (?)"), ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GetEditionsByIDs query: %v", err)
+	}
+
+	editions := []*models.Edition{}
+	if err := s.db.SelectContext(ctx, &editions, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to query from editions table: %v", err)
+	}
+
+	return editions, nil
+}
+
+// GetEditionsByISBNs fetches all the editions by the given ISBN-13s whose
+// book is not archived.
+func (s *Storage) GetEditionsByISBNs(ctx context.Context, isbns []string) ([]*models.Edition, error) {
+	if len(isbns) < 1 {
+		return []*models.Edition{}, nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(editionsQuery, editionColumns, "e.isbn_13 IN (?)"), isbns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GetEditionsByISBNs query: %v", err)
+	}
+
+	editions := []*models.Edition{}
+	if err := s.db.SelectContext(ctx, &editions, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to query from editions table: %v", err)
+	}
+
+	return editions, nil
+}
+
+// Create adds a new edition of a book, an edition without a price sells at
+// the price of its book. Editions created here are never the default
+// edition of their book.
+func (s *Storage) Create(ctx context.Context, edition *models.Edition) (*models.Edition, error) {
+	stmt := `INSERT INTO editions(%s) VALUES(%s);`
+
+	// fields and values to be operated
+	fields := []string{
+		"book_id",
+		"format",
+		"isbn_10",
+		"isbn_13",
+		"price",
+		"stock",
+	}
+	values := []string{
+		":book_id",
+		":format",
+		":isbn_10",
+		":isbn_13",
+		":price",
+		":stock",
+	}
+
+	res, err := s.db.NamedExecContext(ctx,
+		fmt.Sprintf(stmt, strings.Join(fields, ","), strings.Join(values, ",")),
+		map[string]interface{}{
+			"book_id": edition.BookID,
+			"format":  edition.Format,
+			"isbn_10": nullISBN10(edition.ISBN13),
+			"isbn_13": nullString(edition.ISBN13),
+			"price":   nullString(edition.Price),
+			"stock":   edition.Stock,
+		},
+	)
+	if err != nil {
+		return nil, editionError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inserted edition id: %v", err)
+	}
+
+	return s.getEdition(ctx, edition.BookID, id)
+}
+
+// Update changes the given fields of an edition of a book. Changing the
+// ISBN or stock of the default edition changes the ones of its book too.
+func (s *Storage) Update(ctx context.Context, bookID, editionID int64, update *models.EditionUpdate) (*models.Edition, error) {
+	sets := []string{}
+	args := map[string]interface{}{
+		"id":      editionID,
+		"book_id": bookID,
+	}
+	if update.Format != nil {
+		sets = append(sets, "format = :format")
+		args["format"] = *update.Format
+	}
+	if update.ISBN13 != nil {
+		sets = append(sets, "isbn_10 = :isbn_10", "isbn_13 = :isbn_13")
+		args["isbn_10"] = nullISBN10(*update.ISBN13)
+		args["isbn_13"] = nullString(*update.ISBN13)
+	}
+	if update.Price != nil {
+		sets = append(sets, "price = :price")
+		args["price"] = nullString(*update.Price)
+	}
+	if update.Stock != nil {
+		sets = append(sets, "stock = :stock")
+		args["stock"] = *update.Stock
+	}
+	sets = append(sets, "updated_at = CURRENT_TIMESTAMP")
+
+	stmt := `
+UPDATE editions
+SET %s
+WHERE id = :id AND book_id = :book_id;
+`
+
+	res, err := s.db.NamedExecContext(ctx, fmt.Sprintf(stmt, strings.Join(sets, ", ")), args)
+	if err != nil {
+		return nil, editionError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return nil, sqlite.ErrNotFound
+	}
+
+	return s.getEdition(ctx, bookID, editionID)
+}
+
+// SetFile sets the blob key and name of the file of an ebook edition,
+// returning ErrNotEbook for editions in any other format.
+func (s *Storage) SetFile(ctx context.Context, bookID, editionID int64, key, name string) error {
+	stmt := `
+UPDATE editions
+SET file_key = :file_key,
+	file_name = :file_name,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :id AND book_id = :book_id AND format = 'ebook';
+`
+
+	res, err := s.db.NamedExecContext(ctx, stmt, map[string]interface{}{
+		"id":        editionID,
+		"book_id":   bookID,
+		"file_key":  key,
+		"file_name": name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set edition file: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	// tell a missing edition apart from one that is not an ebook.
+	if _, err := s.getEdition(ctx, bookID, editionID); err != nil {
+		if errors.Is(err, sqlite.ErrNotFound) {
+			return sqlite.ErrNotFound
+		}
+		return err
+	}
+	return ErrNotEbook
+}
+
+// getEdition fetches an edition of a book, whether or not the book is
+// archived.
+func (s *Storage) getEdition(ctx context.Context, bookID, editionID int64) (*models.Edition, error) {
+	query := `
+SELECT %s
+FROM editions e
+JOIN current_book_prices cp
+	ON cp.book_id = e.book_id
+WHERE e.id = ? AND e.book_id = ?;
+`
+
+	var edition models.Edition
+	if err := s.db.GetContext(ctx, &edition, fmt.Sprintf(query, editionColumns), editionID, bookID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get edition: %v", err)
+	}
+
+	return &edition, nil
+}
+
+// editionError maps the constraint an edition failed on to its error.
+func editionError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), errForeignKeyConstraint):
+		return ErrBookIDNotFound
+	case strings.Contains(err.Error(), "UNIQUE") && strings.Contains(err.Error(), "isbn"):
+		return ErrISBNAlreadyExist
+	}
+	return fmt.Errorf("failed to perform edition operation: %w", err)
+}
+
+// nullString stores an empty string as NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullISBN10 returns the ISBN-10 of an ISBN-13, or NULL when it has none.
+func nullISBN10(isbn13 string) *string {
+	isbn10, err := isbn.To10(isbn13)
+	if err != nil {
+		return nil
+	}
+	return &isbn10
+}
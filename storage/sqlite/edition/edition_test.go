@@ -0,0 +1,227 @@
+package edition
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_GetEditionsByIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// every existing book is sold as a default edition keeping its id.
+	editions, err := ts.GetEditionsByIDs(ctx, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("GetEditionsByIDs(_, _) expected nil error, got = %v", err)
+	}
+	if len(editions) != 2 {
+		t.Fatalf("GetEditionsByIDs(_, _) error, got = %v editions, want = %v", len(editions), 2)
+	}
+	if e := editions[0]; e.BookID != 1 || !e.IsDefault || e.Format != models.EditionFormatPaperback || e.Price != "10.00" {
+		t.Fatalf("GetEditionsByIDs(_, _) unexpected default edition: %+v", e)
+	}
+
+	// editions of archived books cannot be found.
+	if _, err := ts.db.Exec(`UPDATE books SET archived_at = CURRENT_TIMESTAMP WHERE id = 2;`); err != nil {
+		t.Fatalf("unexpected error when archiving book: %v", err)
+	}
+	editions, err = ts.GetEditionsByIDs(ctx, []int64{1, 2})
+	if err != nil {
+		t.Fatalf("GetEditionsByIDs(_, _) expected nil error, got = %v", err)
+	}
+	if len(editions) != 1 || editions[0].ID != 1 {
+		t.Fatalf("GetEditionsByIDs(_, _) error, got = %+v, want edition %v only", editions, 1)
+	}
+}
+
+func Test_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		hardcover, err := ts.Create(ctx, &models.Edition{
+			BookID: 1,
+			Format: models.EditionFormatHardcover,
+			ISBN13: "9780306406157",
+			Price:  "25.00",
+			Stock:  3,
+		})
+		if err != nil {
+			t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+		}
+		if hardcover.IsDefault || hardcover.Price != "25.00" || hardcover.ISBN10 != "0306406152" {
+			t.Fatalf("Create(_, _) unexpected edition: %+v", hardcover)
+		}
+
+		// an edition without a price sells at the price of its book.
+		ebook, err := ts.Create(ctx, &models.Edition{
+			BookID: 1,
+			Format: models.EditionFormatEbook,
+		})
+		if err != nil {
+			t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+		}
+		if ebook.Price != "10.00" || ebook.StockStatus != "in_stock" {
+			t.Fatalf("Create(_, _) unexpected edition: %+v", ebook)
+		}
+
+		editions, err := ts.GetEditionsByISBNs(ctx, []string{"9780306406157"})
+		if err != nil {
+			t.Fatalf("GetEditionsByISBNs(_, _) expected nil error, got = %v", err)
+		}
+		if len(editions) != 1 || editions[0].ID != hardcover.ID {
+			t.Fatalf("GetEditionsByISBNs(_, _) error, got = %+v, want edition %v", editions, hardcover.ID)
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			edition *models.Edition
+			wantErr error
+		}{
+			{
+				name:    "BookIDNotFound",
+				edition: &models.Edition{BookID: 100000, Format: models.EditionFormatEbook},
+				wantErr: ErrBookIDNotFound,
+			},
+			{
+				// the ISBN of the default edition of another book.
+				name:    "ISBNAlreadyExist",
+				edition: &models.Edition{BookID: 1, Format: models.EditionFormatHardcover, ISBN13: "9780316346627"},
+				wantErr: ErrISBNAlreadyExist,
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				ts, teardown := newTestStorage(t)
+				t.Cleanup(teardown)
+
+				if _, err := ts.Create(ctx, tt.edition); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create(_, _) error, got = %v, want = %v", err, tt.wantErr)
+				}
+			})
+		}
+	})
+}
+
+func Test_Update(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	stock, price := int64(42), "12.50"
+	updated, err := ts.Update(ctx, 1, 1, &models.EditionUpdate{Stock: &stock, Price: &price})
+	if err != nil {
+		t.Fatalf("Update(_, _, _, _) expected nil error, got = %v", err)
+	}
+	if updated.Stock != stock || updated.Price != price {
+		t.Fatalf("Update(_, _, _, _) unexpected edition: %+v", updated)
+	}
+
+	// the stock of the default edition is the stock of its book.
+	var bookStock int64
+	if err := ts.db.Get(&bookStock, `SELECT stock FROM books WHERE id = 1;`); err != nil {
+		t.Fatalf("unexpected error when getting book stock: %v", err)
+	}
+	if bookStock != stock {
+		t.Fatalf("Update(_, _, _, _) error, got book stock = %v, want = %v", bookStock, stock)
+	}
+
+	// an empty price sells the edition at the price of its book again.
+	empty := ""
+	updated, err = ts.Update(ctx, 1, 1, &models.EditionUpdate{Price: &empty})
+	if err != nil {
+		t.Fatalf("Update(_, _, _, _) expected nil error, got = %v", err)
+	}
+	if updated.Price != "10.00" {
+		t.Fatalf("Update(_, _, _, _) error, got price = %v, want = %v", updated.Price, "10.00")
+	}
+
+	// an edition is only found under its own book.
+	if _, err := ts.Update(ctx, 2, 1, &models.EditionUpdate{Stock: &stock}); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("Update(_, _, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_SetFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	ebook, err := ts.Create(ctx, &models.Edition{BookID: 1, Format: models.EditionFormatEbook})
+	if err != nil {
+		t.Fatalf("unexpected error when creating ebook edition: %v", err)
+	}
+
+	if err := ts.SetFile(ctx, 1, ebook.ID, "ebooks/1", "atomic-habits.epub"); err != nil {
+		t.Fatalf("SetFile(_, _, _, _, _) expected nil error, got = %v", err)
+	}
+
+	var key string
+	if err := ts.db.Get(&key, `SELECT file_key FROM editions WHERE id = ?;`, ebook.ID); err != nil {
+		t.Fatalf("unexpected error when getting file key: %v", err)
+	}
+	if key != "ebooks/1" {
+		t.Fatalf("SetFile(_, _, _, _, _) error, got key = %v, want = %v", key, "ebooks/1")
+	}
+
+	// only ebooks have files to download.
+	if err := ts.SetFile(ctx, 1, 1, "ebooks/1", "atomic-habits.epub"); !errors.Is(err, ErrNotEbook) {
+		t.Fatalf("SetFile(_, _, _, _, _) error, got = %v, want = %v", err, ErrNotEbook)
+	}
+	if err := ts.SetFile(ctx, 2, ebook.ID, "ebooks/1", "atomic-habits.epub"); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("SetFile(_, _, _, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
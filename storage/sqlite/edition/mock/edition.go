@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: edition.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockEditionStorage is a mock of EditionStorage interface.
+type MockEditionStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockEditionStorageMockRecorder
+}
+
+// MockEditionStorageMockRecorder is the mock recorder for MockEditionStorage.
+type MockEditionStorageMockRecorder struct {
+	mock *MockEditionStorage
+}
+
+// NewMockEditionStorage creates a new mock instance.
+func NewMockEditionStorage(ctrl *gomock.Controller) *MockEditionStorage {
+	mock := &MockEditionStorage{ctrl: ctrl}
+	mock.recorder = &MockEditionStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEditionStorage) EXPECT() *MockEditionStorageMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockEditionStorage) Create(arg0 context.Context, arg1 *models.Edition) (*models.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*models.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEditionStorageMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEditionStorage)(nil).Create), arg0, arg1)
+}
+
+// GetEditionsByIDs mocks base method.
+func (m *MockEditionStorage) GetEditionsByIDs(ctx context.Context, ids []int64) ([]*models.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEditionsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*models.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEditionsByIDs indicates an expected call of GetEditionsByIDs.
+func (mr *MockEditionStorageMockRecorder) GetEditionsByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEditionsByIDs", reflect.TypeOf((*MockEditionStorage)(nil).GetEditionsByIDs), ctx, ids)
+}
+
+// GetEditionsByISBNs mocks base method.
+func (m *MockEditionStorage) GetEditionsByISBNs(ctx context.Context, isbns []string) ([]*models.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEditionsByISBNs", ctx, isbns)
+	ret0, _ := ret[0].([]*models.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEditionsByISBNs indicates an expected call of GetEditionsByISBNs.
+func (mr *MockEditionStorageMockRecorder) GetEditionsByISBNs(ctx, isbns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEditionsByISBNs", reflect.TypeOf((*MockEditionStorage)(nil).GetEditionsByISBNs), ctx, isbns)
+}
+
+// SetFile mocks base method.
+func (m *MockEditionStorage) SetFile(ctx context.Context, bookID, editionID int64, key, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFile", ctx, bookID, editionID, key, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFile indicates an expected call of SetFile.
+func (mr *MockEditionStorageMockRecorder) SetFile(ctx, bookID, editionID, key, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFile", reflect.TypeOf((*MockEditionStorage)(nil).SetFile), ctx, bookID, editionID, key, name)
+}
+
+// Update mocks base method.
+func (m *MockEditionStorage) Update(ctx context.Context, bookID, editionID int64, update *models.EditionUpdate) (*models.Edition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, bookID, editionID, update)
+	ret0, _ := ret[0].(*models.Edition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockEditionStorageMockRecorder) Update(ctx, bookID, editionID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEditionStorage)(nil).Update), ctx, bookID, editionID, update)
+}
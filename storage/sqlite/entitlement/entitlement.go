@@ -0,0 +1,198 @@
+package entitlement
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+// timeLayout is how times are stored, the layout of CURRENT_TIMESTAMP so
+// stored times compare with it as text.
+const timeLayout = "2006-01-02 15:04:05"
+
+var ErrDownloadLimitReached = errors.New("download limit reached")
+
+//go:generate mockgen -source=entitlement.go -destination=mock/entitlement.go -package=mock
+type EntitlementStorage interface {
+	// GetEntitlements fetches all the ebooks a user is entitled to, the most
+	// recently granted first.
+	GetEntitlements(ctx context.Context, userID int64) ([]*models.Entitlement, error)
+
+	// GetEntitlement fetches an entitlement of a user.
+	GetEntitlement(ctx context.Context, userID, id int64) (*models.Entitlement, error)
+
+	// GetEntitlementByID fetches an entitlement whoever it belongs to.
+	GetEntitlementByID(ctx context.Context, id int64) (*models.Entitlement, error)
+
+	// RecordDownload counts a download of an entitlement, unless it was
+	// already downloaded limit times within the given window.
+	RecordDownload(ctx context.Context, id, limit int64, window time.Duration) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around entitlement storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// entitlementsQuery selects entitlements along with the book and file of
+// their edition matching the given condition.
+const entitlementsQuery = `
+SELECT
+	en.id,
+	en.user_id,
+	en.edition_id,
+	e.book_id,
+	en.order_id,
+	b.title,
+	b.author,
+	COALESCE(e.file_key, '') AS file_key,
+	COALESCE(e.file_name, '') AS file_name,
+	e.file_key IS NOT NULL AS downloadable,
+	en.download_count,
+	en.last_downloaded_at,
+	en.created_at
+FROM entitlements en
+JOIN editions e
+	ON e.id = en.edition_id
+JOIN books b
+	ON b.id = e.book_id
+WHERE %s
+ORDER BY en.created_at DESC, en.id DESC;
+`
+
+// GetEntitlements fetches all the ebooks a user is entitled to, the most
+// recently granted first. Ebooks stay in the library of their buyer even
+// once their book is archived.
+func (s *Storage) GetEntitlements(ctx context.Context, userID int64) ([]*models.Entitlement, error) {
+	entitlements := []*models.Entitlement{}
+	if err := s.db.SelectContext(ctx, &entitlements, fmt.Sprintf(entitlementsQuery, "en.user_id = ?"), userID); err != nil {
+		return nil, fmt.Errorf("failed to query from entitlements table: %v", err)
+	}
+
+	return entitlements, nil
+}
+
+// GetEntitlement fetches an entitlement of a user.
+func (s *Storage) GetEntitlement(ctx context.Context, userID, id int64) (*models.Entitlement, error) {
+	return s.getEntitlement(ctx, "en.id = ? AND en.user_id = ?", id, userID)
+}
+
+// GetEntitlementByID fetches an entitlement whoever it belongs to.
+func (s *Storage) GetEntitlementByID(ctx context.Context, id int64) (*models.Entitlement, error) {
+	return s.getEntitlement(ctx, "en.id = ?", id)
+}
+
+func (s *Storage) getEntitlement(ctx context.Context, cond string, args ...interface{}) (*models.Entitlement, error) {
+	var entitlement models.Entitlement
+	if err := s.db.GetContext(ctx, &entitlement, fmt.Sprintf(entitlementsQuery, cond), args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get entitlement: %v", err)
+	}
+
+	return &entitlement, nil
+}
+
+// RecordDownload counts a download of an entitlement, returning
+// ErrDownloadLimitReached when it was already downloaded limit times within
+// the given window. The limit is checked by the insert itself so concurrent
+// downloads cannot get past it.
+func (s *Storage) RecordDownload(ctx context.Context, id, limit int64, window time.Duration) error {
+	timeNow := time.Now().UTC()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	stmt := `
+UPDATE entitlements
+SET download_count = download_count + 1,
+	last_downloaded_at = :now,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :id;
+`
+
+	args := map[string]interface{}{
+		"id":    id,
+		"now":   timeNow.Format(timeLayout),
+		"since": timeNow.Add(-window).Format(timeLayout),
+		"limit": limit,
+	}
+
+	res, err := tx.NamedExecContext(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to count download: %v", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	stmt = `
+INSERT INTO entitlement_downloads (entitlement_id, created_at)
+SELECT :id, :now
+WHERE (
+	SELECT COUNT(*)
+	FROM entitlement_downloads
+	WHERE entitlement_id = :id AND created_at > :since
+) < :limit;
+`
+
+	res, err = tx.NamedExecContext(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to record download: %v", err)
+	}
+	affected, err = res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return ErrDownloadLimitReached
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// GrantTx entitles the buyer of an order to every ebook ordered within the
+// given transaction, preordered ebooks are granted once they are ready to
+// ship. Buying an ebook the buyer already owns grants nothing.
+func GrantTx(ctx context.Context, tx *sqlx.Tx, orderID int64) error {
+	stmt := `
+INSERT INTO entitlements (user_id, edition_id, order_id)
+SELECT o.user_id, oi.edition_id, o.id
+FROM order_items oi
+JOIN orders o
+	ON o.id = oi.order_id
+JOIN editions e
+	ON e.id = oi.edition_id
+WHERE oi.order_id = ? AND e.format = 'ebook' AND oi.fulfillment_status = 'ready_to_ship'
+ON CONFLICT (user_id, edition_id) DO NOTHING;
+`
+
+	if _, err := tx.ExecContext(ctx, stmt, orderID); err != nil {
+		return fmt.Errorf("failed to grant entitlements: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package entitlement
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+// testOrder places an order of the given editions for a new user, granting
+// its entitlements, and returns the id of the user.
+func testOrder(t *testing.T, ts *Storage, editionIDs ...int64) int64 {
+	t.Helper()
+
+	ctx := context.Background()
+
+	res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when creating user: %v", err)
+	}
+	userID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting user id: %v", err)
+	}
+
+	res, err = ts.db.Exec(`INSERT INTO orders (user_id, total) VALUES (?, '0.00');`, userID)
+	if err != nil {
+		t.Fatalf("unexpected error when creating order: %v", err)
+	}
+	orderID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting order id: %v", err)
+	}
+
+	for _, editionID := range editionIDs {
+		if _, err := ts.db.Exec(`
+INSERT INTO order_items (order_id, book_id, edition_id, price, quantity)
+SELECT ?, book_id, id, '0.00', 1 FROM editions WHERE id = ?;`,
+			orderID, editionID,
+		); err != nil {
+			t.Fatalf("unexpected error when creating order item: %v", err)
+		}
+	}
+
+	tx, err := ts.db.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error when starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+	if err := GrantTx(ctx, tx, orderID); err != nil {
+		t.Fatalf("GrantTx(_, _, _) expected nil error, got = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("unexpected error when committing transaction: %v", err)
+	}
+
+	return userID
+}
+
+// testCreateEbook creates an ebook edition of a book with a file.
+func testCreateEbook(t *testing.T, ts *Storage, bookID int64) int64 {
+	t.Helper()
+
+	res, err := ts.db.Exec(`
+INSERT INTO editions (book_id, format, file_key, file_name)
+VALUES (?, 'ebook', 'ebooks/test', 'book.epub');`, bookID)
+	if err != nil {
+		t.Fatalf("unexpected error when creating ebook edition: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting edition id: %v", err)
+	}
+	return id
+}
+
+func Test_GetEntitlements(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	ebookID := testCreateEbook(t, ts, 1)
+
+	// only the ebooks of an order are entitled, not its printed books.
+	userID := testOrder(t, ts, ebookID, 2)
+
+	entitlements, err := ts.GetEntitlements(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetEntitlements(_, _) expected nil error, got = %v", err)
+	}
+	if len(entitlements) != 1 {
+		t.Fatalf("GetEntitlements(_, _) error, got = %v entitlements, want = %v", len(entitlements), 1)
+	}
+	got := entitlements[0]
+	if got.EditionID != ebookID || got.BookID != 1 || !got.Downloadable || got.FileKey != "ebooks/test" {
+		t.Fatalf("GetEntitlements(_, _) unexpected entitlement: %+v", got)
+	}
+
+	if _, err := ts.GetEntitlement(ctx, userID, got.ID); err != nil {
+		t.Fatalf("GetEntitlement(_, _, _) expected nil error, got = %v", err)
+	}
+
+	// an entitlement is only found for its owner.
+	otherUserID := testOrder(t, ts)
+	if _, err := ts.GetEntitlement(ctx, otherUserID, got.ID); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("GetEntitlement(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_RecordDownload(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testOrder(t, ts, testCreateEbook(t, ts, 1))
+	entitlements, err := ts.GetEntitlements(ctx, userID)
+	if err != nil || len(entitlements) != 1 {
+		t.Fatalf("unexpected error when getting entitlements: %v", err)
+	}
+	id := entitlements[0].ID
+
+	// a download older than the window does not count towards the limit.
+	if _, err := ts.db.Exec(
+		`INSERT INTO entitlement_downloads (entitlement_id, created_at) VALUES (?, '2000-01-01 00:00:00');`, id,
+	); err != nil {
+		t.Fatalf("unexpected error when creating download: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := ts.RecordDownload(ctx, id, 2, time.Hour); err != nil {
+			t.Fatalf("RecordDownload(_, _, _, _) expected nil error, got = %v", err)
+		}
+	}
+	if err := ts.RecordDownload(ctx, id, 2, time.Hour); !errors.Is(err, ErrDownloadLimitReached) {
+		t.Fatalf("RecordDownload(_, _, _, _) error, got = %v, want = %v", err, ErrDownloadLimitReached)
+	}
+
+	// refused downloads are not counted.
+	got, err := ts.GetEntitlementByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetEntitlementByID(_, _) expected nil error, got = %v", err)
+	}
+	if got.DownloadCount != 2 || got.LastDownloadedAt == nil {
+		t.Fatalf("RecordDownload(_, _, _, _) error, got count = %v, last = %v, want = %v", got.DownloadCount, got.LastDownloadedAt, 2)
+	}
+
+	if err := ts.RecordDownload(ctx, 100000, 2, time.Hour); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("RecordDownload(_, _, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
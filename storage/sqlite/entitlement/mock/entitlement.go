@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: entitlement.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockEntitlementStorage is a mock of EntitlementStorage interface.
+type MockEntitlementStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntitlementStorageMockRecorder
+}
+
+// MockEntitlementStorageMockRecorder is the mock recorder for MockEntitlementStorage.
+type MockEntitlementStorageMockRecorder struct {
+	mock *MockEntitlementStorage
+}
+
+// NewMockEntitlementStorage creates a new mock instance.
+func NewMockEntitlementStorage(ctrl *gomock.Controller) *MockEntitlementStorage {
+	mock := &MockEntitlementStorage{ctrl: ctrl}
+	mock.recorder = &MockEntitlementStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntitlementStorage) EXPECT() *MockEntitlementStorageMockRecorder {
+	return m.recorder
+}
+
+// GetEntitlement mocks base method.
+func (m *MockEntitlementStorage) GetEntitlement(ctx context.Context, userID, id int64) (*models.Entitlement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntitlement", ctx, userID, id)
+	ret0, _ := ret[0].(*models.Entitlement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntitlement indicates an expected call of GetEntitlement.
+func (mr *MockEntitlementStorageMockRecorder) GetEntitlement(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitlement", reflect.TypeOf((*MockEntitlementStorage)(nil).GetEntitlement), ctx, userID, id)
+}
+
+// GetEntitlementByID mocks base method.
+func (m *MockEntitlementStorage) GetEntitlementByID(ctx context.Context, id int64) (*models.Entitlement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntitlementByID", ctx, id)
+	ret0, _ := ret[0].(*models.Entitlement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntitlementByID indicates an expected call of GetEntitlementByID.
+func (mr *MockEntitlementStorageMockRecorder) GetEntitlementByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitlementByID", reflect.TypeOf((*MockEntitlementStorage)(nil).GetEntitlementByID), ctx, id)
+}
+
+// GetEntitlements mocks base method.
+func (m *MockEntitlementStorage) GetEntitlements(ctx context.Context, userID int64) ([]*models.Entitlement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntitlements", ctx, userID)
+	ret0, _ := ret[0].([]*models.Entitlement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntitlements indicates an expected call of GetEntitlements.
+func (mr *MockEntitlementStorageMockRecorder) GetEntitlements(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitlements", reflect.TypeOf((*MockEntitlementStorage)(nil).GetEntitlements), ctx, userID)
+}
+
+// RecordDownload mocks base method.
+func (m *MockEntitlementStorage) RecordDownload(ctx context.Context, id, limit int64, window time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDownload", ctx, id, limit, window)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDownload indicates an expected call of RecordDownload.
+func (mr *MockEntitlementStorageMockRecorder) RecordDownload(ctx, id, limit, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDownload", reflect.TypeOf((*MockEntitlementStorage)(nil).RecordDownload), ctx, id, limit, window)
+}
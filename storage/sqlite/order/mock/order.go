@@ -49,6 +49,21 @@ func (mr *MockOrderStorageMockRecorder) Create(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderStorage)(nil).Create), arg0, arg1, arg2)
 }
 
+// GetBookSales mocks base method.
+func (m *MockOrderStorage) GetBookSales(arg0 context.Context) (map[int64]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookSales", arg0)
+	ret0, _ := ret[0].(map[int64]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookSales indicates an expected call of GetBookSales.
+func (mr *MockOrderStorageMockRecorder) GetBookSales(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookSales", reflect.TypeOf((*MockOrderStorage)(nil).GetBookSales), arg0)
+}
+
 // GetOrderHistory mocks base method.
 func (m *MockOrderStorage) GetOrderHistory(arg0 context.Context, arg1 int64) ([]*models.OrderHistory, error) {
 	m.ctrl.T.Helper()
@@ -63,3 +78,18 @@ func (mr *MockOrderStorageMockRecorder) GetOrderHistory(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderHistory", reflect.TypeOf((*MockOrderStorage)(nil).GetOrderHistory), arg0, arg1)
 }
+
+// ReleasePreorders mocks base method.
+func (m *MockOrderStorage) ReleasePreorders(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleasePreorders", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleasePreorders indicates an expected call of ReleasePreorders.
+func (mr *MockOrderStorageMockRecorder) ReleasePreorders(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleasePreorders", reflect.TypeOf((*MockOrderStorage)(nil).ReleasePreorders), arg0)
+}
@@ -9,23 +9,37 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/entitlement"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/reservation"
 )
 
+// dateLayout is the layout of release dates, dates in it compare as text.
+const dateLayout = "2006-01-02"
+
 var (
 	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
 
-	ErrUserIDNotFound = errors.New("user id not found")
-	ErrBookIDNotFound = errors.New("book id not found")
+	ErrUserIDNotFound    = errors.New("user id not found")
+	ErrEditionIDNotFound = errors.New("edition id not found")
 )
 
 //go:generate mockgen -source=order.go -destination=mock/order.go -package=mock
 type OrderStorage interface {
 	// Create adds a new order for a user and allow them to order multiple
-	// books.
+	// books, committing the stock reservation of the order in the same
+	// transaction.
 	Create(context.Context, *models.Order, []*models.OrderItem) error
 
 	// GetOrderHistory fetches all the orders of a user.
 	GetOrderHistory(context.Context, int64) ([]*models.OrderHistory, error)
+
+	// ReleasePreorders moves the preordered items of released books that
+	// are in stock to ready to ship, returning how many were moved.
+	ReleasePreorders(context.Context) (int64, error)
+
+	// GetBookSales fetches the units sold of every book that was ordered,
+	// keyed by book id.
+	GetBookSales(context.Context) (map[int64]int64, error)
 }
 
 type Storage struct {
@@ -38,11 +52,19 @@ func NewStorage(db *sqlx.DB) *Storage {
 }
 
 // Create adds a new order for a user and allow them to order multiple
-// books.
+// books, committing the stock reservation of the order in the same
+// transaction. Orders placed without a reservation reserve and commit
+// their items at once, returning a *reservation.InsufficientStockError
+// when any of the books does not have enough stock left. Ordering an ebook
+// entitles the user to download it.
+//
+// Items of books that are not released yet are preordered, they neither
+// hold stock nor entitle the user to an ebook until ReleasePreorders moves
+// them to ready to ship.
 func (s *Storage) Create(ctx context.Context, order *models.Order, items []*models.OrderItem) error {
 	timeNow := time.Now().UTC()
 
-	tx, err := s.db.Beginx()
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %v", err)
 	}
@@ -79,8 +101,17 @@ func (s *Storage) Create(ctx context.Context, order *models.Order, items []*mode
 	}
 	order.ID = orderID
 
+	unreleased, err := getUnreleasedBookIDs(ctx, tx, items, timeNow.Format(dateLayout))
+	if err != nil {
+		return err
+	}
+
 	for _, item := range items {
 		item.OrderID = orderID
+		item.FulfillmentStatus = models.FulfillmentStatusReadyToShip
+		if unreleased[item.BookID] {
+			item.FulfillmentStatus = models.FulfillmentStatusPreordered
+		}
 		item.CreatedAt = timeNow
 		item.UpdatedAt = timeNow
 
@@ -89,14 +120,18 @@ func (s *Storage) Create(ctx context.Context, order *models.Order, items []*mode
 		itemFields := []string{
 			"order_id",
 			"book_id",
+			"edition_id",
 			"price",
 			"quantity",
+			"fulfillment_status",
 		}
 		itemValues := []string{
 			":order_id",
 			":book_id",
+			":edition_id",
 			":price",
 			":quantity",
+			":fulfillment_status",
 		}
 
 		res, err := tx.NamedExec(
@@ -105,7 +140,7 @@ func (s *Storage) Create(ctx context.Context, order *models.Order, items []*mode
 		)
 		if err != nil {
 			if strings.Contains(err.Error(), errForeignKeyConstraint) {
-				return ErrBookIDNotFound
+				return ErrEditionIDNotFound
 			}
 			return fmt.Errorf("failed to insert order item operation: %v", err)
 		}
@@ -117,7 +152,51 @@ func (s *Storage) Create(ctx context.Context, order *models.Order, items []*mode
 		item.ID = itemID
 	}
 
-	tx.Commit()
+	reservationItems := []*models.ReservationItem{}
+	for _, item := range items {
+		if item.FulfillmentStatus == models.FulfillmentStatusPreordered {
+			continue
+		}
+		reservationItems = append(reservationItems, &models.ReservationItem{
+			BookID:    item.BookID,
+			EditionID: item.EditionID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	// orders without a reservation hold their stock right away, so the
+	// stock is only ever decremented through a reservation. The hold is
+	// committed below within the same transaction, its expiry only has to
+	// outlive it. Orders of nothing but preordered items hold no stock.
+	if order.ReservationID == 0 && len(reservationItems) > 0 {
+		held, err := reservation.ReserveTx(ctx, tx, order.UserID, reservationItems, timeNow.Add(time.Minute))
+		if err != nil {
+			return err
+		}
+		order.ReservationID = held.ID
+		reservationItems = nil
+	}
+
+	if order.ReservationID != 0 {
+		if err := reservation.CommitTx(ctx, tx, order.UserID, order.ReservationID, reservationItems); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE orders SET reservation_id = ? WHERE id = ?;`,
+			order.ReservationID, order.ID,
+		); err != nil {
+			return fmt.Errorf("failed to set order reservation: %v", err)
+		}
+	}
+
+	if err := entitlement.GrantTx(ctx, tx, order.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
 
 	return nil
 }
@@ -128,8 +207,13 @@ func (s *Storage) GetOrderHistory(ctx context.Context, userID int64) ([]*models.
 SELECT 
 	o.id,
 	o.total,
+	oi.book_id,
+	COALESCE(oi.edition_id, 0) AS edition_id,
+	COALESCE(e.format, '') AS format,
 	oi.price,
 	oi.quantity,
+	oi.fulfillment_status,
+	COALESCE(b.release_date, '') AS release_date,
 	b.title,
 	b.author,
 	b.description
@@ -138,6 +222,8 @@ JOIN order_items oi
 	ON o.id = oi.order_id
 JOIN books b
 	ON oi.book_id = b.id
+LEFT JOIN editions e
+	ON oi.edition_id = e.id
 WHERE user_id = :user_id;
 `
 
@@ -173,21 +259,31 @@ WHERE user_id = :user_id;
 				Total: order.Total,
 				Items: []*models.OrderHistoryItem{
 					{
-						Price:       order.Price,
-						Quantity:    order.Quantity,
-						Title:       order.Title,
-						Author:      order.Author,
-						Description: order.Description,
+						BookID:            order.BookID,
+						EditionID:         order.EditionID,
+						Format:            order.Format,
+						Price:             order.Price,
+						Quantity:          order.Quantity,
+						FulfillmentStatus: order.FulfillmentStatus,
+						ReleaseDate:       order.ReleaseDate,
+						Title:             order.Title,
+						Author:            order.Author,
+						Description:       order.Description,
 					},
 				},
 			}
 		} else {
 			ordersMap[order.ID].Items = append(ordersMap[order.ID].Items, &models.OrderHistoryItem{
-				Price:       order.Price,
-				Quantity:    order.Quantity,
-				Title:       order.Title,
-				Author:      order.Author,
-				Description: order.Description,
+				BookID:            order.BookID,
+				EditionID:         order.EditionID,
+				Format:            order.Format,
+				Price:             order.Price,
+				Quantity:          order.Quantity,
+				FulfillmentStatus: order.FulfillmentStatus,
+				ReleaseDate:       order.ReleaseDate,
+				Title:             order.Title,
+				Author:            order.Author,
+				Description:       order.Description,
 			})
 		}
 	}
@@ -199,3 +295,148 @@ WHERE user_id = :user_id;
 
 	return orders, nil
 }
+
+// ReleasePreorders moves the preordered items of released books to ready to
+// ship, returning how many were moved. Items take the stock of their edition
+// as they are moved, in the order they were placed, there is no checkout to
+// hold it through a reservation. Items of an edition short of stock wait
+// for it to be restocked, later items of the edition wait behind them.
+func (s *Storage) ReleasePreorders(ctx context.Context) (int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+SELECT oi.id, oi.order_id, oi.book_id, oi.edition_id, oi.quantity
+FROM order_items oi
+JOIN books b
+	ON b.id = oi.book_id
+WHERE oi.fulfillment_status = ?
+	AND (b.release_date IS NULL OR b.release_date <= ?)
+ORDER BY oi.id;
+`
+
+	items := []*models.OrderItem{}
+	if err := tx.SelectContext(ctx, &items, query,
+		models.FulfillmentStatusPreordered, time.Now().UTC().Format(dateLayout),
+	); err != nil {
+		return 0, fmt.Errorf("failed to query preordered items: %v", err)
+	}
+
+	released := int64(0)
+	waiting := map[int64]bool{}
+	orderIDs := []int64{}
+	granted := map[int64]bool{}
+	for _, item := range items {
+		if waiting[item.EditionID] {
+			continue
+		}
+
+		stockStmt := `
+UPDATE editions
+SET stock = CASE WHEN format = 'ebook' THEN stock ELSE stock - :quantity END,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :edition_id AND (format = 'ebook' OR stock >= :quantity);
+`
+
+		res, err := tx.NamedExecContext(ctx, stockStmt, item)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decrement edition stock: %v", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get affected rows: %v", err)
+		}
+		if affected < 1 {
+			waiting[item.EditionID] = true
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE order_items SET fulfillment_status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;`,
+			models.FulfillmentStatusReadyToShip, item.ID,
+		); err != nil {
+			return 0, fmt.Errorf("failed to set order item fulfillment status: %v", err)
+		}
+		released++
+
+		if !granted[item.OrderID] {
+			granted[item.OrderID] = true
+			orderIDs = append(orderIDs, item.OrderID)
+		}
+	}
+
+	for _, orderID := range orderIDs {
+		if err := entitlement.GrantTx(ctx, tx, orderID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return released, nil
+}
+
+// getUnreleasedBookIDs returns which books of the given items are not
+// released by the given date.
+func getUnreleasedBookIDs(ctx context.Context, tx *sqlx.Tx, items []*models.OrderItem, today string) (map[int64]bool, error) {
+	unreleased := map[int64]bool{}
+	if len(items) < 1 {
+		return unreleased, nil
+	}
+
+	bookIDs := []int64{}
+	for _, item := range items {
+		bookIDs = append(bookIDs, item.BookID)
+	}
+
+	query, args, err := sqlx.In(`
+SELECT id
+FROM books
+WHERE id IN (?) AND release_date > ?;
+`, bookIDs, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build unreleased books query: %v", err)
+	}
+
+	ids := []int64{}
+	if err := tx.SelectContext(ctx, &ids, tx.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to query unreleased books: %v", err)
+	}
+
+	for _, id := range ids {
+		unreleased[id] = true
+	}
+
+	return unreleased, nil
+}
+
+// GetBookSales fetches the units sold of every book that was ordered, keyed
+// by book id, preordered items included. Books never ordered are left out.
+func (s *Storage) GetBookSales(ctx context.Context) (map[int64]int64, error) {
+	query := `
+SELECT book_id, SUM(quantity) AS units
+FROM order_items
+GROUP BY book_id;
+`
+
+	rows := []struct {
+		BookID int64 `db:"book_id"`
+		Units  int64 `db:"units"`
+	}{}
+	if err := s.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to query book sales: %v", err)
+	}
+
+	sales := map[int64]int64{}
+	for _, row := range rows {
+		sales[row.BookID] = row.Units
+	}
+
+	return sales, nil
+}
@@ -9,11 +9,14 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/wilsonangara/simple-online-book-store/storage/models"
 	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite/reservation"
 )
 
 func newTestStorage(tb testing.TB) (*Storage, func()) {
@@ -55,6 +58,7 @@ func Test_Create(t *testing.T) {
 			t.Fatalf("unexpected error when getting books: %v", err)
 		}
 		book := books[0]
+		editionID := testGetDefaultEditionID(t, ts.db, book.ID)
 
 		bookPriceFloat, err := strconv.ParseFloat(book.Price, 64)
 		if err != nil {
@@ -70,28 +74,239 @@ func Test_Create(t *testing.T) {
 		}
 		testItems := []*models.OrderItem{
 			{
-				BookID:   book.ID,
-				Price:    book.Price,
-				Quantity: validQuantity,
+				BookID:    book.ID,
+				EditionID: editionID,
+				Price:     book.Price,
+				Quantity:  validQuantity,
 			},
 		}
 
 		if err := ts.Create(ctx, testOrder, testItems); err != nil {
 			t.Fatalf("Create(_, _, _) expected nil error, got = %v", err)
 		}
+
+		// stock of the ordered book should be decremented.
+		var gotStock int64
+		if err := ts.db.Get(&gotStock, `SELECT stock FROM books WHERE id = ?`, book.ID); err != nil {
+			t.Fatalf("unexpected error when getting book stock: %v", err)
+		}
+		if wantStock := book.Stock - validQuantity; gotStock != wantStock {
+			t.Fatalf("Create(_, _, _) error, got stock = %v, want = %v", gotStock, wantStock)
+		}
+	})
+
+	t.Run("Ebook", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		testUser, err := testCreateUser(t, ts.db)
+		if err != nil {
+			t.Fatalf("unexpected error when creating dummy user: %v", err)
+		}
+
+		res, err := ts.db.Exec(`INSERT INTO editions (book_id, format, price) VALUES (1, 'ebook', '3.99');`)
+		if err != nil {
+			t.Fatalf("unexpected error when creating ebook edition: %v", err)
+		}
+		ebookID, err := res.LastInsertId()
+		if err != nil {
+			t.Fatalf("unexpected error when getting ebook edition id: %v", err)
+		}
+
+		// buying an ebook twice entitles the user to it only once.
+		for i := 0; i < 2; i++ {
+			testOrder := &models.Order{
+				UserID: testUser.ID,
+				Total:  "3.99",
+			}
+			testItems := []*models.OrderItem{
+				{
+					BookID:    1,
+					EditionID: ebookID,
+					Price:     "3.99",
+					Quantity:  1,
+				},
+			}
+			if err := ts.Create(ctx, testOrder, testItems); err != nil {
+				t.Fatalf("Create(_, _, _) expected nil error, got = %v", err)
+			}
+		}
+
+		var entitlements int64
+		if err := ts.db.Get(&entitlements,
+			`SELECT COUNT(*) FROM entitlements WHERE user_id = ? AND edition_id = ?;`,
+			testUser.ID, ebookID,
+		); err != nil {
+			t.Fatalf("unexpected error when counting entitlements: %v", err)
+		}
+		if entitlements != 1 {
+			t.Fatalf("Create(_, _, _) error, got = %v entitlements, want = %v", entitlements, 1)
+		}
+	})
+
+	t.Run("Preorder", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		testUser, err := testCreateUser(t, ts.db)
+		if err != nil {
+			t.Fatalf("unexpected error when creating dummy user: %v", err)
+		}
+
+		// book 2 is not released yet and has no stock, its ebook is
+		// preordered too.
+		testSetReleaseDate(t, ts.db, 2, time.Now().UTC().AddDate(0, 0, 7))
+		if _, err := ts.db.Exec(`UPDATE books SET stock = 0 WHERE id = 2;`); err != nil {
+			t.Fatalf("unexpected error when updating book stock: %v", err)
+		}
+		res, err := ts.db.Exec(`INSERT INTO editions (book_id, format, price) VALUES (2, 'ebook', '3.99');`)
+		if err != nil {
+			t.Fatalf("unexpected error when creating ebook edition: %v", err)
+		}
+		ebookID, err := res.LastInsertId()
+		if err != nil {
+			t.Fatalf("unexpected error when getting ebook edition id: %v", err)
+		}
+
+		testOrder := &models.Order{
+			UserID: testUser.ID,
+			Total:  "37.99",
+		}
+		testItems := []*models.OrderItem{
+			{BookID: 1, EditionID: 1, Price: "10.00", Quantity: 1},
+			{BookID: 2, EditionID: 2, Price: "12.00", Quantity: 2},
+			{BookID: 2, EditionID: ebookID, Price: "3.99", Quantity: 1},
+		}
+		if err := ts.Create(ctx, testOrder, testItems); err != nil {
+			t.Fatalf("Create(_, _, _) expected nil error, got = %v", err)
+		}
+
+		gotStatuses := []string{}
+		for _, item := range testItems {
+			gotStatuses = append(gotStatuses, item.FulfillmentStatus)
+		}
+		wantStatuses := []string{
+			models.FulfillmentStatusReadyToShip,
+			models.FulfillmentStatusPreordered,
+			models.FulfillmentStatusPreordered,
+		}
+		if diff := cmp.Diff(wantStatuses, gotStatuses); diff != "" {
+			t.Fatalf("Create(_, _, _) mismatch (-want+got):\n%s", diff)
+		}
+
+		// only the released book holds stock.
+		if got := testGetEditionStock(t, ts.db, 2); got != 0 {
+			t.Fatalf("Create(_, _, _) error, got stock = %v, want = %v", got, 0)
+		}
+		if got := testCountEntitlements(t, ts.db, testUser.ID); got != 0 {
+			t.Fatalf("Create(_, _, _) error, got = %v entitlements, want = %v", got, 0)
+		}
+	})
+
+	t.Run("SuccessWithReservation", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		// create dummy user
+		testUser, err := testCreateUser(t, ts.db)
+		if err != nil {
+			t.Fatalf("unexpected error when creating dummy user: %v", err)
+		}
+
+		books, err := testGetBooks(t, ts.db)
+		if err != nil || len(books) < 1 {
+			t.Fatalf("unexpected error when getting books: %v", err)
+		}
+		book := books[0]
+		editionID := testGetDefaultEditionID(t, ts.db, book.ID)
+
+		validQuantity := int64(2)
+
+		held, err := reservation.NewStorage(ts.db).Reserve(ctx, testUser.ID, []*models.ReservationItem{
+			{
+				BookID:    book.ID,
+				EditionID: editionID,
+				Quantity:  validQuantity,
+			},
+		}, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error when reserving books: %v", err)
+		}
+
+		testItems := []*models.OrderItem{
+			{
+				BookID:    book.ID,
+				EditionID: editionID,
+				Price:     book.Price,
+				Quantity:  validQuantity,
+			},
+		}
+
+		// the reservation must hold exactly the ordered items.
+		mismatchOrder := &models.Order{
+			UserID:        testUser.ID,
+			ReservationID: held.ID,
+			Total:         book.Price,
+		}
+		mismatchItems := []*models.OrderItem{
+			{
+				BookID:    book.ID,
+				EditionID: editionID,
+				Price:     book.Price,
+				Quantity:  validQuantity - 1,
+			},
+		}
+		if err := ts.Create(ctx, mismatchOrder, mismatchItems); !errors.Is(err, reservation.ErrReservationMismatch) {
+			t.Fatalf("Create(_, _, _) error, got = %v, want = %v", err, reservation.ErrReservationMismatch)
+		}
+
+		testOrder := &models.Order{
+			UserID:        testUser.ID,
+			ReservationID: held.ID,
+			Total:         book.Price,
+		}
+		if err := ts.Create(ctx, testOrder, testItems); err != nil {
+			t.Fatalf("Create(_, _, _) expected nil error, got = %v", err)
+		}
+
+		// committing the reservation must not decrement the stock again.
+		var gotStock int64
+		if err := ts.db.Get(&gotStock, `SELECT stock FROM books WHERE id = ?`, book.ID); err != nil {
+			t.Fatalf("unexpected error when getting book stock: %v", err)
+		}
+		if wantStock := book.Stock - validQuantity; gotStock != wantStock {
+			t.Fatalf("Create(_, _, _) error, got stock = %v, want = %v", gotStock, wantStock)
+		}
+
+		// a committed reservation cannot be used for another order.
+		secondOrder := &models.Order{
+			UserID:        testUser.ID,
+			ReservationID: held.ID,
+			Total:         book.Price,
+		}
+		if err := ts.Create(ctx, secondOrder, testItems); !errors.Is(err, reservation.ErrReservationNotFound) {
+			t.Fatalf("Create(_, _, _) error, got = %v, want = %v", err, reservation.ErrReservationNotFound)
+		}
 	})
 
 	t.Run("Failed", func(t *testing.T) {
 		t.Parallel()
 
 		notFoundUserID := int64(100000)
-		notFoundBookID := int64(100000)
+		notFoundEditionID := int64(100000)
 
 		tests := []struct {
-			name    string
-			userID  int64
-			bookID  int64
-			wantErr error
+			name      string
+			userID    int64
+			editionID int64
+			quantity  int64
+			wantErr   error
 		}{
 			{
 				name:    "UserIDNotFound",
@@ -99,9 +314,14 @@ func Test_Create(t *testing.T) {
 				wantErr: ErrUserIDNotFound,
 			},
 			{
-				name:    "BookIDNotFound",
-				bookID:  notFoundBookID,
-				wantErr: ErrBookIDNotFound,
+				name:      "EditionIDNotFound",
+				editionID: notFoundEditionID,
+				wantErr:   ErrEditionIDNotFound,
+			},
+			{
+				name:     "InsufficientStock",
+				quantity: int64(100000),
+				wantErr:  reservation.ErrInsufficientStock,
 			},
 		}
 
@@ -124,6 +344,7 @@ func Test_Create(t *testing.T) {
 					t.Fatalf("unexpected error when getting books: %v", err)
 				}
 				book := books[0]
+				editionID := testGetDefaultEditionID(t, ts.db, book.ID)
 
 				bookPriceFloat, err := strconv.ParseFloat(book.Price, 64)
 				if err != nil {
@@ -131,15 +352,18 @@ func Test_Create(t *testing.T) {
 				}
 
 				validQuantity := int64(1)
+				if tt.quantity != 0 {
+					validQuantity = tt.quantity
+				}
 				totalBookPrice := fmt.Sprintf("%.2f", float64(validQuantity)*bookPriceFloat)
 
 				testUserID := tt.userID
 				if testUserID == 0 {
 					testUserID = testUser.ID
 				}
-				testBookID := tt.bookID
-				if testBookID == 0 {
-					testBookID = book.ID
+				testEditionID := tt.editionID
+				if testEditionID == 0 {
+					testEditionID = editionID
 				}
 
 				testOrder := &models.Order{
@@ -148,9 +372,10 @@ func Test_Create(t *testing.T) {
 				}
 				testItems := []*models.OrderItem{
 					{
-						BookID:   testBookID,
-						Price:    book.Price,
-						Quantity: validQuantity,
+						BookID:    book.ID,
+						EditionID: testEditionID,
+						Price:     book.Price,
+						Quantity:  validQuantity,
 					},
 				}
 
@@ -158,6 +383,15 @@ func Test_Create(t *testing.T) {
 				if !errors.Is(err, tt.wantErr) {
 					t.Fatalf("Create(_, _, _) error, got = %v, want = %v", err, tt.wantErr)
 				}
+
+				// a failed order must not leave any stock decremented.
+				var gotStock int64
+				if err := ts.db.Get(&gotStock, `SELECT stock FROM books WHERE id = ?`, book.ID); err != nil {
+					t.Fatalf("unexpected error when getting book stock: %v", err)
+				}
+				if gotStock != book.Stock {
+					t.Fatalf("Create(_, _, _) error, got stock = %v, want = %v", gotStock, book.Stock)
+				}
 			})
 		}
 	})
@@ -185,6 +419,7 @@ func Test_GetOrderHistory(t *testing.T) {
 			t.Fatalf("unexpected error when getting books: %v", err)
 		}
 		book := books[0]
+		editionID := testGetDefaultEditionID(t, ts.db, book.ID)
 
 		bookPriceFloat, err := strconv.ParseFloat(book.Price, 64)
 		if err != nil {
@@ -200,9 +435,10 @@ func Test_GetOrderHistory(t *testing.T) {
 		}
 		testItems := []*models.OrderItem{
 			{
-				BookID:   book.ID,
-				Price:    book.Price,
-				Quantity: validQuantity,
+				BookID:    book.ID,
+				EditionID: editionID,
+				Price:     book.Price,
+				Quantity:  validQuantity,
 			},
 		}
 
@@ -221,9 +457,185 @@ func Test_GetOrderHistory(t *testing.T) {
 		if len(orders[0].Items) != 1 {
 			t.Fatalf("GetOrderHistory(_, _) error, got = %v, want = %v", len(orders[0].Items), 1)
 		}
+		if orders[0].Items[0].BookID != book.ID {
+			t.Fatalf("GetOrderHistory(_, _) error, got book id = %v, want = %v", orders[0].Items[0].BookID, book.ID)
+		}
+		if item := orders[0].Items[0]; item.EditionID != editionID || item.Format != models.EditionFormatPaperback {
+			t.Fatalf("GetOrderHistory(_, _) error, got edition = %v %v, want = %v %v", item.EditionID, item.Format, editionID, models.EditionFormatPaperback)
+		}
+
+		// archived books keep showing in the orders they were part of.
+		if _, err := ts.db.Exec(`UPDATE books SET archived_at = CURRENT_TIMESTAMP WHERE id = ?;`, book.ID); err != nil {
+			t.Fatalf("unexpected error when archiving book: %v", err)
+		}
+		orders, err = ts.GetOrderHistory(ctx, testUser.ID)
+		if err != nil {
+			t.Fatalf("GetOrderHistory(_, _) expected nil error, got = %v", err)
+		}
+		if len(orders) != 1 || len(orders[0].Items) != 1 || orders[0].Items[0].Title != book.Title {
+			t.Fatalf("GetOrderHistory(_, _) error, got = %+v, want archived book %q", orders, book.Title)
+		}
 	})
 }
 
+func Test_ReleasePreorders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	testSetReleaseDate(t, ts.db, 2, time.Now().UTC().AddDate(0, 0, 7))
+	res, err := ts.db.Exec(`INSERT INTO editions (book_id, format, price) VALUES (2, 'ebook', '3.99');`)
+	if err != nil {
+		t.Fatalf("unexpected error when creating ebook edition: %v", err)
+	}
+	ebookID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting ebook edition id: %v", err)
+	}
+
+	// two preorders of 2 copies each and one of the ebook.
+	users := []*models.User{}
+	for _, item := range []*models.OrderItem{
+		{BookID: 2, EditionID: 2, Price: "12.00", Quantity: 2},
+		{BookID: 2, EditionID: 2, Price: "12.00", Quantity: 2},
+		{BookID: 2, EditionID: ebookID, Price: "3.99", Quantity: 1},
+	} {
+		testUser, err := testCreateUser(t, ts.db)
+		if err != nil {
+			t.Fatalf("unexpected error when creating dummy user: %v", err)
+		}
+		users = append(users, testUser)
+
+		if err := ts.Create(ctx, &models.Order{UserID: testUser.ID, Total: item.Price}, []*models.OrderItem{item}); err != nil {
+			t.Fatalf("Create(_, _, _) expected nil error, got = %v", err)
+		}
+	}
+
+	if _, err := ts.db.Exec(`UPDATE books SET stock = 3 WHERE id = 2;`); err != nil {
+		t.Fatalf("unexpected error when updating book stock: %v", err)
+	}
+
+	// nothing ships before the release date.
+	released, err := ts.ReleasePreorders(ctx)
+	if err != nil {
+		t.Fatalf("ReleasePreorders(_) expected nil error, got = %v", err)
+	}
+	if released != 0 {
+		t.Fatalf("ReleasePreorders(_) error, got = %v, want = %v", released, 0)
+	}
+
+	// once released the first preorder and the ebook ship, the second
+	// waits for stock.
+	testSetReleaseDate(t, ts.db, 2, time.Now().UTC().AddDate(0, 0, -1))
+	released, err = ts.ReleasePreorders(ctx)
+	if err != nil {
+		t.Fatalf("ReleasePreorders(_) expected nil error, got = %v", err)
+	}
+	if released != 2 {
+		t.Fatalf("ReleasePreorders(_) error, got = %v, want = %v", released, 2)
+	}
+	if got := testGetEditionStock(t, ts.db, 2); got != 1 {
+		t.Fatalf("ReleasePreorders(_) error, got stock = %v, want = %v", got, 1)
+	}
+	if got := testCountEntitlements(t, ts.db, users[2].ID); got != 1 {
+		t.Fatalf("ReleasePreorders(_) error, got = %v entitlements, want = %v", got, 1)
+	}
+
+	if _, err := ts.db.Exec(`UPDATE books SET stock = 5 WHERE id = 2;`); err != nil {
+		t.Fatalf("unexpected error when updating book stock: %v", err)
+	}
+	released, err = ts.ReleasePreorders(ctx)
+	if err != nil {
+		t.Fatalf("ReleasePreorders(_) expected nil error, got = %v", err)
+	}
+	if released != 1 {
+		t.Fatalf("ReleasePreorders(_) error, got = %v, want = %v", released, 1)
+	}
+	if got := testGetEditionStock(t, ts.db, 2); got != 3 {
+		t.Fatalf("ReleasePreorders(_) error, got stock = %v, want = %v", got, 3)
+	}
+
+	orders, err := ts.GetOrderHistory(ctx, users[1].ID)
+	if err != nil {
+		t.Fatalf("GetOrderHistory(_, _) expected nil error, got = %v", err)
+	}
+	if got := orders[0].Items[0].FulfillmentStatus; got != models.FulfillmentStatusReadyToShip {
+		t.Fatalf("GetOrderHistory(_, _) error, got = %v, want = %v", got, models.FulfillmentStatusReadyToShip)
+	}
+}
+
+func Test_GetBookSales(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	sales, err := ts.GetBookSales(ctx)
+	if err != nil {
+		t.Fatalf("GetBookSales(_) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff(map[int64]int64{}, sales); diff != "" {
+		t.Fatalf("GetBookSales(_) mismatch (-want+got):\n%s", diff)
+	}
+
+	for _, items := range [][]*models.OrderItem{
+		{{BookID: 1, EditionID: 1, Price: "10.00", Quantity: 2}},
+		{
+			{BookID: 1, EditionID: 1, Price: "10.00", Quantity: 1},
+			{BookID: 3, EditionID: 3, Price: "11.20", Quantity: 1},
+		},
+	} {
+		testUser, err := testCreateUser(t, ts.db)
+		if err != nil {
+			t.Fatalf("unexpected error when creating dummy user: %v", err)
+		}
+		if err := ts.Create(ctx, &models.Order{UserID: testUser.ID, Total: "0.00"}, items); err != nil {
+			t.Fatalf("Create(_, _, _) expected nil error, got = %v", err)
+		}
+	}
+
+	sales, err = ts.GetBookSales(ctx)
+	if err != nil {
+		t.Fatalf("GetBookSales(_) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff(map[int64]int64{1: 3, 3: 1}, sales); diff != "" {
+		t.Fatalf("GetBookSales(_) mismatch (-want+got):\n%s", diff)
+	}
+}
+
+func testSetReleaseDate(t *testing.T, db *sqlx.DB, bookID int64, date time.Time) {
+	t.Helper()
+
+	if _, err := db.Exec(`UPDATE books SET release_date = ? WHERE id = ?;`, date.Format(dateLayout), bookID); err != nil {
+		t.Fatalf("unexpected error when setting release date: %v", err)
+	}
+}
+
+func testGetEditionStock(t *testing.T, db *sqlx.DB, editionID int64) int64 {
+	t.Helper()
+
+	var stock int64
+	if err := db.Get(&stock, `SELECT stock FROM editions WHERE id = ?;`, editionID); err != nil {
+		t.Fatalf("unexpected error when getting edition stock: %v", err)
+	}
+	return stock
+}
+
+func testCountEntitlements(t *testing.T, db *sqlx.DB, userID int64) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Get(&count, `SELECT COUNT(*) FROM entitlements WHERE user_id = ?;`, userID); err != nil {
+		t.Fatalf("unexpected error when counting entitlements: %v", err)
+	}
+	return count
+}
+
 func testCreateUser(t *testing.T, db *sqlx.DB) (*models.User, error) {
 	t.Helper()
 
@@ -266,9 +678,19 @@ func testCreateUser(t *testing.T, db *sqlx.DB) (*models.User, error) {
 	return createdUser, nil
 }
 
+func testGetDefaultEditionID(t *testing.T, db *sqlx.DB, bookID int64) int64 {
+	t.Helper()
+
+	var id int64
+	if err := db.Get(&id, `SELECT id FROM editions WHERE book_id = ? AND is_default`, bookID); err != nil {
+		t.Fatalf("unexpected error when getting default edition: %v", err)
+	}
+	return id
+}
+
 func testGetBooks(t *testing.T, db *sqlx.DB) ([]*models.Book, error) {
 	query := `
-	SELECT id, title, author, price, description
+	SELECT id, title, author, price, description, stock
 	FROM books
 	`
 
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: price.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockPriceStorage is a mock of PriceStorage interface.
+type MockPriceStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceStorageMockRecorder
+}
+
+// MockPriceStorageMockRecorder is the mock recorder for MockPriceStorage.
+type MockPriceStorageMockRecorder struct {
+	mock *MockPriceStorage
+}
+
+// NewMockPriceStorage creates a new mock instance.
+func NewMockPriceStorage(ctrl *gomock.Controller) *MockPriceStorage {
+	mock := &MockPriceStorage{ctrl: ctrl}
+	mock.recorder = &MockPriceStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceStorage) EXPECT() *MockPriceStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteScheduledPrice mocks base method.
+func (m *MockPriceStorage) DeleteScheduledPrice(ctx context.Context, bookID, priceID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledPrice", ctx, bookID, priceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduledPrice indicates an expected call of DeleteScheduledPrice.
+func (mr *MockPriceStorageMockRecorder) DeleteScheduledPrice(ctx, bookID, priceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledPrice", reflect.TypeOf((*MockPriceStorage)(nil).DeleteScheduledPrice), ctx, bookID, priceID)
+}
+
+// GetPriceHistory mocks base method.
+func (m *MockPriceStorage) GetPriceHistory(ctx context.Context, bookID int64) ([]*models.BookPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", ctx, bookID)
+	ret0, _ := ret[0].([]*models.BookPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockPriceStorageMockRecorder) GetPriceHistory(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockPriceStorage)(nil).GetPriceHistory), ctx, bookID)
+}
+
+// SchedulePrice mocks base method.
+func (m *MockPriceStorage) SchedulePrice(arg0 context.Context, arg1 *models.BookPrice) (*models.BookPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePrice", arg0, arg1)
+	ret0, _ := ret[0].(*models.BookPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePrice indicates an expected call of SchedulePrice.
+func (mr *MockPriceStorageMockRecorder) SchedulePrice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePrice", reflect.TypeOf((*MockPriceStorage)(nil).SchedulePrice), arg0, arg1)
+}
@@ -0,0 +1,164 @@
+package price
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+// timeLayout is how times are stored, the layout of CURRENT_TIMESTAMP so
+// stored times compare with it as text.
+const timeLayout = "2006-01-02 15:04:05"
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrBookIDNotFound        = errors.New("book id not found")
+	ErrPriceAlreadyEffective = errors.New("price already took effect")
+)
+
+//go:generate mockgen -source=price.go -destination=mock/price.go -package=mock
+type PriceStorage interface {
+	// GetPriceHistory fetches every price of a book, past and scheduled,
+	// the most recent first.
+	GetPriceHistory(ctx context.Context, bookID int64) ([]*models.BookPrice, error)
+
+	// SchedulePrice adds a price of a book over the given period. A price
+	// with an end takes precedence over open-ended prices while in effect,
+	// then the price with the latest start wins, so a promotion falls back
+	// to the price it overlapped once it ends.
+	SchedulePrice(context.Context, *models.BookPrice) (*models.BookPrice, error)
+
+	// DeleteScheduledPrice removes a price of a book that has not taken
+	// effect yet.
+	DeleteScheduledPrice(ctx context.Context, bookID, priceID int64) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around price storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+const priceColumns = `id, book_id, price, effective_from, effective_to, created_at`
+
+// GetPriceHistory fetches every price of a book, past and scheduled, the most
+// recent first.
+func (s *Storage) GetPriceHistory(ctx context.Context, bookID int64) ([]*models.BookPrice, error) {
+	query := `
+SELECT %s
+FROM book_prices
+WHERE book_id = ?
+ORDER BY effective_from DESC, id DESC;
+`
+
+	prices := []*models.BookPrice{}
+	if err := s.db.SelectContext(ctx, &prices, fmt.Sprintf(query, priceColumns), bookID); err != nil {
+		return nil, fmt.Errorf("failed to get price history: %v", err)
+	}
+
+	return prices, nil
+}
+
+// SchedulePrice adds a price of a book over the given period.
+func (s *Storage) SchedulePrice(ctx context.Context, price *models.BookPrice) (*models.BookPrice, error) {
+	stmt := `INSERT INTO book_prices(%s) VALUES(%s);`
+
+	// fields and values to be operated
+	fields := []string{
+		"book_id",
+		"price",
+		"effective_from",
+		"effective_to",
+	}
+	values := []string{
+		":book_id",
+		":price",
+		":effective_from",
+		":effective_to",
+	}
+
+	var effectiveTo *string
+	if price.EffectiveTo != nil {
+		to := price.EffectiveTo.UTC().Format(timeLayout)
+		effectiveTo = &to
+	}
+
+	res, err := s.db.NamedExecContext(ctx,
+		fmt.Sprintf(stmt, strings.Join(fields, ","), strings.Join(values, ",")),
+		map[string]interface{}{
+			"book_id":        price.BookID,
+			"price":          price.Price,
+			"effective_from": price.EffectiveFrom.UTC().Format(timeLayout),
+			"effective_to":   effectiveTo,
+		},
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), errForeignKeyConstraint) {
+			return nil, ErrBookIDNotFound
+		}
+		return nil, fmt.Errorf("failed to perform SchedulePrice operation: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inserted price id: %v", err)
+	}
+
+	var scheduled models.BookPrice
+	query := fmt.Sprintf(`SELECT %s FROM book_prices WHERE id = ?;`, priceColumns)
+	if err := s.db.GetContext(ctx, &scheduled, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get scheduled price: %v", err)
+	}
+
+	return &scheduled, nil
+}
+
+// DeleteScheduledPrice removes a price of a book that has not taken effect
+// yet, a price that did is part of the history of the book.
+func (s *Storage) DeleteScheduledPrice(ctx context.Context, bookID, priceID int64) error {
+	var effective bool
+	query := `
+SELECT effective_from <= CURRENT_TIMESTAMP
+FROM book_prices
+WHERE id = ? AND book_id = ?;
+`
+	if err := s.db.GetContext(ctx, &effective, query, priceID, bookID); err != nil {
+		if err == sql.ErrNoRows {
+			return sqlite.ErrNotFound
+		}
+		return fmt.Errorf("failed to get price: %v", err)
+	}
+	if effective {
+		return ErrPriceAlreadyEffective
+	}
+
+	stmt := `
+DELETE FROM book_prices
+WHERE id = ? AND book_id = ? AND effective_from > CURRENT_TIMESTAMP;
+`
+	res, err := s.db.ExecContext(ctx, stmt, priceID, bookID)
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduled price: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return ErrPriceAlreadyEffective
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_GetPriceHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	// changing the price of a book keeps the price it replaces.
+	if _, err := ts.db.Exec(`UPDATE books SET price = '12.00' WHERE id = 1;`); err != nil {
+		t.Fatalf("unexpected error when updating book price: %v", err)
+	}
+
+	prices, err := ts.GetPriceHistory(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetPriceHistory(_, _) expected nil error, got = %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("GetPriceHistory(_, _) error, got = %v prices, want = %v", len(prices), 2)
+	}
+	if prices[0].Price != "12.00" || prices[0].EffectiveTo != nil {
+		t.Fatalf("GetPriceHistory(_, _) unexpected current price: %+v", prices[0])
+	}
+	if prices[1].Price != "10.00" {
+		t.Fatalf("GetPriceHistory(_, _) unexpected previous price: %+v", prices[1])
+	}
+}
+
+func Test_SchedulePrice(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	from := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	to := from.Add(7 * 24 * time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		price, err := ts.SchedulePrice(ctx, &models.BookPrice{
+			BookID:        2,
+			Price:         "5.00",
+			EffectiveFrom: from,
+			EffectiveTo:   &to,
+		})
+		if err != nil {
+			t.Fatalf("SchedulePrice(_, _) expected nil error, got = %v", err)
+		}
+		if price.ID == 0 || !price.EffectiveFrom.Equal(from) || price.EffectiveTo == nil || !price.EffectiveTo.Equal(to) {
+			t.Fatalf("SchedulePrice(_, _) error, got = %+v", price)
+		}
+	})
+
+	t.Run("BookIDNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ts.SchedulePrice(ctx, &models.BookPrice{
+			BookID:        1000,
+			Price:         "5.00",
+			EffectiveFrom: from,
+		})
+		if !errors.Is(err, ErrBookIDNotFound) {
+			t.Fatalf("SchedulePrice(_, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+		}
+	})
+}
+
+func Test_DeleteScheduledPrice(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	scheduled, err := ts.SchedulePrice(ctx, &models.BookPrice{
+		BookID:        3,
+		Price:         "5.00",
+		EffectiveFrom: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error when SchedulePrice: %v", err)
+	}
+
+	if err := ts.DeleteScheduledPrice(ctx, 3, scheduled.ID); err != nil {
+		t.Fatalf("DeleteScheduledPrice(_, _, _) expected nil error, got = %v", err)
+	}
+	if err := ts.DeleteScheduledPrice(ctx, 3, scheduled.ID); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("DeleteScheduledPrice(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+
+	// the seeded price already took effect.
+	prices, err := ts.GetPriceHistory(ctx, 3)
+	if err != nil {
+		t.Fatalf("unexpected error when GetPriceHistory: %v", err)
+	}
+	if err := ts.DeleteScheduledPrice(ctx, 3, prices[0].ID); !errors.Is(err, ErrPriceAlreadyEffective) {
+		t.Fatalf("DeleteScheduledPrice(_, _, _) error, got = %v, want = %v", err, ErrPriceAlreadyEffective)
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: publisher.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockPublisherStorage is a mock of PublisherStorage interface.
+type MockPublisherStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherStorageMockRecorder
+}
+
+// MockPublisherStorageMockRecorder is the mock recorder for MockPublisherStorage.
+type MockPublisherStorageMockRecorder struct {
+	mock *MockPublisherStorage
+}
+
+// NewMockPublisherStorage creates a new mock instance.
+func NewMockPublisherStorage(ctrl *gomock.Controller) *MockPublisherStorage {
+	mock := &MockPublisherStorage{ctrl: ctrl}
+	mock.recorder = &MockPublisherStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisherStorage) EXPECT() *MockPublisherStorageMockRecorder {
+	return m.recorder
+}
+
+// AssignBook mocks base method.
+func (m *MockPublisherStorage) AssignBook(ctx context.Context, publisherID, bookID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignBook", ctx, publisherID, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignBook indicates an expected call of AssignBook.
+func (mr *MockPublisherStorageMockRecorder) AssignBook(ctx, publisherID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignBook", reflect.TypeOf((*MockPublisherStorage)(nil).AssignBook), ctx, publisherID, bookID)
+}
+
+// Create mocks base method.
+func (m *MockPublisherStorage) Create(arg0 context.Context, arg1 *models.Publisher) (*models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPublisherStorageMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPublisherStorage)(nil).Create), arg0, arg1)
+}
+
+// GetPublisherBySlug mocks base method.
+func (m *MockPublisherStorage) GetPublisherBySlug(arg0 context.Context, arg1 string) (*models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublisherBySlug", arg0, arg1)
+	ret0, _ := ret[0].(*models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublisherBySlug indicates an expected call of GetPublisherBySlug.
+func (mr *MockPublisherStorageMockRecorder) GetPublisherBySlug(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublisherBySlug", reflect.TypeOf((*MockPublisherStorage)(nil).GetPublisherBySlug), arg0, arg1)
+}
+
+// GetPublishers mocks base method.
+func (m *MockPublisherStorage) GetPublishers(arg0 context.Context) ([]*models.Publisher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishers", arg0)
+	ret0, _ := ret[0].([]*models.Publisher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublishers indicates an expected call of GetPublishers.
+func (mr *MockPublisherStorageMockRecorder) GetPublishers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishers", reflect.TypeOf((*MockPublisherStorage)(nil).GetPublishers), arg0)
+}
+
+// GetSales mocks base method.
+func (m *MockPublisherStorage) GetSales(ctx context.Context, from, to time.Time) ([]*models.PublisherSales, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSales", ctx, from, to)
+	ret0, _ := ret[0].([]*models.PublisherSales)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSales indicates an expected call of GetSales.
+func (mr *MockPublisherStorageMockRecorder) GetSales(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSales", reflect.TypeOf((*MockPublisherStorage)(nil).GetSales), ctx, from, to)
+}
+
+// UnassignBook mocks base method.
+func (m *MockPublisherStorage) UnassignBook(ctx context.Context, publisherID, bookID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignBook", ctx, publisherID, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignBook indicates an expected call of UnassignBook.
+func (mr *MockPublisherStorageMockRecorder) UnassignBook(ctx, publisherID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignBook", reflect.TypeOf((*MockPublisherStorage)(nil).UnassignBook), ctx, publisherID, bookID)
+}
@@ -0,0 +1,249 @@
+package publisher
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+// timeLayout is the layout of CURRENT_TIMESTAMP, times stored in it compare
+// as text.
+const timeLayout = "2006-01-02 15:04:05"
+
+var (
+	ErrSlugAlreadyExist = errors.New("slug already exist")
+	ErrBookIDNotFound   = errors.New("book id not found")
+)
+
+//go:generate mockgen -source=publisher.go -destination=mock/publisher.go -package=mock
+type PublisherStorage interface {
+	// GetPublishers fetches all publishers ordered by name.
+	GetPublishers(context.Context) ([]*models.Publisher, error)
+
+	// GetPublisherBySlug fetches a publisher by the given slug.
+	GetPublisherBySlug(context.Context, string) (*models.Publisher, error)
+
+	// Create adds a new publisher to our storage.
+	Create(context.Context, *models.Publisher) (*models.Publisher, error)
+
+	// AssignBook sets the publisher of a book.
+	AssignBook(ctx context.Context, publisherID, bookID int64) error
+
+	// UnassignBook removes the publisher of a book.
+	UnassignBook(ctx context.Context, publisherID, bookID int64) error
+
+	// GetSales fetches the sales of every publisher from orders placed
+	// since from and before to, zero times leave the range open.
+	GetSales(ctx context.Context, from, to time.Time) ([]*models.PublisherSales, error)
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around publisher storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// GetPublishers fetches all publishers ordered by name.
+func (s *Storage) GetPublishers(ctx context.Context) ([]*models.Publisher, error) {
+	query := `
+SELECT id, name, slug
+FROM publishers
+ORDER BY name, id;
+`
+
+	publishers := []*models.Publisher{}
+	if err := s.db.SelectContext(ctx, &publishers, query); err != nil {
+		return nil, fmt.Errorf("failed to query from publishers table: %v", err)
+	}
+
+	return publishers, nil
+}
+
+// GetPublisherBySlug fetches a publisher by the given slug.
+func (s *Storage) GetPublisherBySlug(ctx context.Context, slug string) (*models.Publisher, error) {
+	query := `
+SELECT id, name, slug
+FROM publishers
+WHERE slug = ?;
+`
+
+	var publisher models.Publisher
+	if err := s.db.GetContext(ctx, &publisher, query, slug); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to perform GetPublisherBySlug storage operation: %w", err)
+	}
+
+	return &publisher, nil
+}
+
+// Create adds a new publisher to our storage.
+func (s *Storage) Create(ctx context.Context, publisher *models.Publisher) (*models.Publisher, error) {
+	stmt := `INSERT INTO publishers(%s) VALUES(%s);`
+
+	// fields and values to be operated
+	fields := []string{
+		"name",
+		"slug",
+	}
+	values := []string{
+		":name",
+		":slug",
+	}
+
+	res, err := s.db.NamedExecContext(ctx,
+		fmt.Sprintf(stmt, strings.Join(fields, ","), strings.Join(values, ",")),
+		publisher,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return nil, ErrSlugAlreadyExist
+		}
+		return nil, fmt.Errorf("failed to perform Create operation: %w", err)
+	}
+
+	insertedID, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to Create publisher: %v", err)
+	}
+
+	createdPublisher := &models.Publisher{
+		ID:   insertedID,
+		Name: publisher.Name,
+		Slug: publisher.Slug,
+	}
+
+	return createdPublisher, nil
+}
+
+// AssignBook sets the publisher of a book, replacing the publisher it had.
+func (s *Storage) AssignBook(ctx context.Context, publisherID, bookID int64) error {
+	stmt := `
+UPDATE books
+SET publisher_id = :publisher_id,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :book_id;
+`
+
+	arg := map[string]interface{}{
+		"publisher_id": publisherID,
+		"book_id":      bookID,
+	}
+
+	res, err := s.db.NamedExecContext(ctx, stmt, arg)
+	if err != nil {
+		return fmt.Errorf("failed to perform AssignBook operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return ErrBookIDNotFound
+	}
+
+	return nil
+}
+
+// UnassignBook removes the publisher of a book.
+func (s *Storage) UnassignBook(ctx context.Context, publisherID, bookID int64) error {
+	stmt := `
+UPDATE books
+SET publisher_id = NULL,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :book_id AND publisher_id = :publisher_id;
+`
+
+	arg := map[string]interface{}{
+		"publisher_id": publisherID,
+		"book_id":      bookID,
+	}
+
+	res, err := s.db.NamedExecContext(ctx, stmt, arg)
+	if err != nil {
+		return fmt.Errorf("failed to perform UnassignBook operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetSales fetches the sales of every publisher from orders placed since
+// from and before to, zero times leave the range open. Sales are counted by
+// the publisher a book has now, publishers without sales are listed with
+// zero totals, the best selling first.
+func (s *Storage) GetSales(ctx context.Context, from, to time.Time) ([]*models.PublisherSales, error) {
+	query := `
+WITH sold AS (
+	SELECT oi.order_id, oi.book_id, oi.quantity,
+		CAST(ROUND(CAST(oi.price AS REAL) * 100) AS INTEGER) * oi.quantity AS cents
+	FROM order_items oi
+	JOIN orders o
+		ON o.id = oi.order_id
+	WHERE (:from = '' OR o.created_at >= :from)
+		AND (:to = '' OR o.created_at < :to)
+)
+SELECT p.id AS publisher_id, p.name, p.slug,
+	COUNT(DISTINCT sold.order_id) AS orders,
+	COALESCE(SUM(sold.quantity), 0) AS units,
+	COALESCE(SUM(sold.cents), 0) AS revenue_cents
+FROM publishers p
+LEFT JOIN books b
+	ON b.publisher_id = p.id
+LEFT JOIN sold
+	ON sold.book_id = b.id
+GROUP BY p.id
+ORDER BY revenue_cents DESC, p.name, p.id;
+`
+
+	stmt, err := s.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare GetSales statement: %w", err)
+	}
+	defer stmt.Close()
+
+	arg := map[string]interface{}{
+		"from": formatTime(from),
+		"to":   formatTime(to),
+	}
+
+	sales := []*models.PublisherSales{}
+	if err := stmt.SelectContext(ctx, &sales, arg); err != nil {
+		return nil, fmt.Errorf("failed to query publisher sales: %v", err)
+	}
+
+	for _, sale := range sales {
+		sale.Revenue = fmt.Sprintf("%d.%02d", sale.RevenueCents/100, sale.RevenueCents%100)
+	}
+
+	return sales, nil
+}
+
+// formatTime formats a time the way CURRENT_TIMESTAMP does, a zero time is
+// formatted as an empty string.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(timeLayout)
+}
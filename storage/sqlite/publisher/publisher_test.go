@@ -0,0 +1,217 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+// testCreatePublisher creates a publisher of the given books.
+func testCreatePublisher(t *testing.T, ts *Storage, name string, bookIDs ...int64) *models.Publisher {
+	t.Helper()
+
+	ctx := context.Background()
+
+	created, err := ts.Create(ctx, &models.Publisher{Name: name, Slug: genString()})
+	if err != nil {
+		t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+	}
+
+	for _, bookID := range bookIDs {
+		if err := ts.AssignBook(ctx, created.ID, bookID); err != nil {
+			t.Fatalf("AssignBook(_, _, _) expected nil error, got = %v", err)
+		}
+	}
+
+	return created
+}
+
+// testOrder records an order placed at the given time of a book at the
+// given price and quantity.
+func testOrder(t *testing.T, ts *Storage, createdAt string, bookID int64, price string, quantity int64) {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when creating user: %v", err)
+	}
+	userID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting user id: %v", err)
+	}
+
+	res, err = ts.db.Exec(`INSERT INTO orders (user_id, total, created_at) VALUES (?, '0.00', ?);`, userID, createdAt)
+	if err != nil {
+		t.Fatalf("unexpected error when creating order: %v", err)
+	}
+	orderID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting order id: %v", err)
+	}
+
+	if _, err := ts.db.Exec(
+		`INSERT INTO order_items (order_id, book_id, edition_id, price, quantity) VALUES (?, ?, ?, ?, ?);`,
+		orderID, bookID, bookID, price, quantity,
+	); err != nil {
+		t.Fatalf("unexpected error when creating order item: %v", err)
+	}
+}
+
+func Test_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	created, err := ts.Create(ctx, &models.Publisher{Name: "Penguin", Slug: "penguin"})
+	if err != nil {
+		t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+	}
+
+	got, err := ts.GetPublisherBySlug(ctx, "penguin")
+	if err != nil {
+		t.Fatalf("GetPublisherBySlug(_, _) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff(created, got); diff != "" {
+		t.Fatalf("GetPublisherBySlug(_, _) mismatch (-want+got):\n%s", diff)
+	}
+
+	publishers, err := ts.GetPublishers(ctx)
+	if err != nil {
+		t.Fatalf("GetPublishers(_) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff([]*models.Publisher{created}, publishers); diff != "" {
+		t.Fatalf("GetPublishers(_) mismatch (-want+got):\n%s", diff)
+	}
+
+	if _, err := ts.Create(ctx, &models.Publisher{Name: "Penguin Books", Slug: "penguin"}); !errors.Is(err, ErrSlugAlreadyExist) {
+		t.Fatalf("Create(_, _) error, got = %v, want = %v", err, ErrSlugAlreadyExist)
+	}
+	if _, err := ts.GetPublisherBySlug(ctx, genString()); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("GetPublisherBySlug(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_AssignBook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	first := testCreatePublisher(t, ts, "First", 1)
+	second := testCreatePublisher(t, ts, "Second")
+
+	// a book has a single publisher, assigning it again moves it.
+	if err := ts.AssignBook(ctx, second.ID, 1); err != nil {
+		t.Fatalf("AssignBook(_, _, _) expected nil error, got = %v", err)
+	}
+
+	var publisherID int64
+	if err := ts.db.Get(&publisherID, `SELECT publisher_id FROM books WHERE id = 1;`); err != nil {
+		t.Fatalf("unexpected error when getting publisher of book: %v", err)
+	}
+	if publisherID != second.ID {
+		t.Fatalf("AssignBook(_, _, _) error, got publisher = %v, want = %v", publisherID, second.ID)
+	}
+
+	if err := ts.AssignBook(ctx, second.ID, 100000); !errors.Is(err, ErrBookIDNotFound) {
+		t.Fatalf("AssignBook(_, _, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+	}
+
+	if err := ts.UnassignBook(ctx, first.ID, 1); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("UnassignBook(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+	if err := ts.UnassignBook(ctx, second.ID, 1); err != nil {
+		t.Fatalf("UnassignBook(_, _, _) expected nil error, got = %v", err)
+	}
+}
+
+func Test_GetSales(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	first := testCreatePublisher(t, ts, "First", 1, 2)
+	second := testCreatePublisher(t, ts, "Second", 3)
+	third := testCreatePublisher(t, ts, "Third")
+
+	testOrder(t, ts, "2024-01-10 10:00:00", 1, "10.10", 2)
+	testOrder(t, ts, "2024-02-10 10:00:00", 2, "0.10", 3)
+	testOrder(t, ts, "2024-02-10 10:00:00", 3, "5.00", 1)
+
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		want []*models.PublisherSales
+	}{
+		{
+			name: "AllTime",
+			want: []*models.PublisherSales{
+				{PublisherID: first.ID, Name: "First", Slug: first.Slug, Orders: 2, Units: 5, Revenue: "20.50", RevenueCents: 2050},
+				{PublisherID: second.ID, Name: "Second", Slug: second.Slug, Orders: 1, Units: 1, Revenue: "5.00", RevenueCents: 500},
+				{PublisherID: third.ID, Name: "Third", Slug: third.Slug, Revenue: "0.00"},
+			},
+		},
+		{
+			name: "Range",
+			from: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: []*models.PublisherSales{
+				{PublisherID: second.ID, Name: "Second", Slug: second.Slug, Orders: 1, Units: 1, Revenue: "5.00", RevenueCents: 500},
+				{PublisherID: first.ID, Name: "First", Slug: first.Slug, Orders: 1, Units: 3, Revenue: "0.30", RevenueCents: 30},
+				{PublisherID: third.ID, Name: "Third", Slug: third.Slug, Revenue: "0.00"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ts.GetSales(ctx, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetSales(_, _, _) expected nil error, got = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("GetSales(_, _, _) mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
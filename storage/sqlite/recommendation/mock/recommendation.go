@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recommendation.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecommendationStorage is a mock of RecommendationStorage interface.
+type MockRecommendationStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendationStorageMockRecorder
+}
+
+// MockRecommendationStorageMockRecorder is the mock recorder for MockRecommendationStorage.
+type MockRecommendationStorageMockRecorder struct {
+	mock *MockRecommendationStorage
+}
+
+// NewMockRecommendationStorage creates a new mock instance.
+func NewMockRecommendationStorage(ctrl *gomock.Controller) *MockRecommendationStorage {
+	mock := &MockRecommendationStorage{ctrl: ctrl}
+	mock.recorder = &MockRecommendationStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendationStorage) EXPECT() *MockRecommendationStorageMockRecorder {
+	return m.recorder
+}
+
+// GetRecommendedBookIDs mocks base method.
+func (m *MockRecommendationStorage) GetRecommendedBookIDs(ctx context.Context, bookIDs []int64, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendedBookIDs", ctx, bookIDs, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendedBookIDs indicates an expected call of GetRecommendedBookIDs.
+func (mr *MockRecommendationStorageMockRecorder) GetRecommendedBookIDs(ctx, bookIDs, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendedBookIDs", reflect.TypeOf((*MockRecommendationStorage)(nil).GetRecommendedBookIDs), ctx, bookIDs, limit)
+}
+
+// Refresh mocks base method.
+func (m *MockRecommendationStorage) Refresh(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockRecommendationStorageMockRecorder) Refresh(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockRecommendationStorage)(nil).Refresh), arg0)
+}
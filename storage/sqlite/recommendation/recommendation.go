@@ -0,0 +1,90 @@
+package recommendation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:generate mockgen -source=recommendation.go -destination=mock/recommendation.go -package=mock
+type RecommendationStorage interface {
+	// Refresh recomputes the co-purchase scores of every pair of books from
+	// the books ordered together.
+	Refresh(context.Context) error
+
+	// GetRecommendedBookIDs fetches the ids of the books most often bought
+	// together with any of the given books, excluding the given books.
+	GetRecommendedBookIDs(ctx context.Context, bookIDs []int64, limit int) ([]int64, error)
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around recommendation storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// Refresh recomputes the co-purchase scores of every pair of books from the
+// books ordered together, the score of a pair is the number of orders
+// containing both books.
+func (s *Storage) Refresh(ctx context.Context) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_recommendations;`); err != nil {
+		return fmt.Errorf("failed to clear book recommendations: %v", err)
+	}
+
+	stmt := `
+INSERT INTO book_recommendations (book_id, recommended_book_id, score)
+SELECT a.book_id, b.book_id, COUNT(DISTINCT a.order_id)
+FROM order_items a
+JOIN order_items b
+	ON a.order_id = b.order_id AND a.book_id <> b.book_id
+GROUP BY a.book_id, b.book_id;
+`
+
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to compute book recommendations: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// GetRecommendedBookIDs fetches the ids of the books most often bought
+// together with any of the given books, excluding the given books. The
+// scores of a book recommended by several of the given books add up.
+func (s *Storage) GetRecommendedBookIDs(ctx context.Context, bookIDs []int64, limit int) ([]int64, error) {
+	if len(bookIDs) < 1 {
+		return []int64{}, nil
+	}
+
+	query, args, err := sqlx.In(`
+SELECT recommended_book_id
+FROM book_recommendations
+WHERE book_id IN (?) AND recommended_book_id NOT IN (?)
+GROUP BY recommended_book_id
+ORDER BY SUM(score) DESC, recommended_book_id
+LIMIT ?;
+`, bookIDs, bookIDs, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GetRecommendedBookIDs query: %v", err)
+	}
+
+	ids := []int64{}
+	if err := s.db.SelectContext(ctx, &ids, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to query from book_recommendations table: %v", err)
+	}
+
+	return ids, nil
+}
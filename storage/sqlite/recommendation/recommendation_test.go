@@ -0,0 +1,145 @@
+package recommendation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_GetRecommendedBookIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testCreateUser(t, ts)
+	testCreateOrder(t, ts, userID, 1, 2)
+	testCreateOrder(t, ts, userID, 1, 2, 3)
+	testCreateOrder(t, ts, userID, 3)
+
+	// nothing is recommended until the scores are refreshed.
+	ids, err := ts.GetRecommendedBookIDs(ctx, []int64{1}, 10)
+	if err != nil {
+		t.Fatalf("GetRecommendedBookIDs(_, _, _) expected nil error, got = %v", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("GetRecommendedBookIDs(_, _, _) error, got = %v, want = %v", ids, []int64{})
+	}
+
+	if err := ts.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh(_) expected nil error, got = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		bookIDs []int64
+		limit   int
+		want    []int64
+	}{
+		{
+			name:    "SingleBook",
+			bookIDs: []int64{1},
+			limit:   10,
+			want:    []int64{2, 3},
+		},
+		{
+			name:    "Limit",
+			bookIDs: []int64{1},
+			limit:   1,
+			want:    []int64{2},
+		},
+		{
+			name:    "ExcludesGivenBooks",
+			bookIDs: []int64{2, 3},
+			limit:   10,
+			want:    []int64{1},
+		},
+		{
+			name:    "NoCoPurchase",
+			bookIDs: []int64{1000},
+			limit:   10,
+			want:    []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ts.GetRecommendedBookIDs(ctx, tt.bookIDs, tt.limit)
+			if err != nil {
+				t.Fatalf("GetRecommendedBookIDs(_, _, _) expected nil error, got = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("GetRecommendedBookIDs(_, _, _) mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testCreateUser(t *testing.T, ts *Storage) int64 {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when creating dummy user: %v", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting dummy user id: %v", err)
+	}
+	return id
+}
+
+func testCreateOrder(t *testing.T, ts *Storage, userID int64, bookIDs ...int64) {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO orders (user_id, total) VALUES (?, ?);`, userID, "10.00")
+	if err != nil {
+		t.Fatalf("unexpected error when creating dummy order: %v", err)
+	}
+
+	orderID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting dummy order id: %v", err)
+	}
+
+	for _, bookID := range bookIDs {
+		if _, err := ts.db.Exec(`INSERT INTO order_items (order_id, book_id, price, quantity) VALUES (?, ?, ?, ?);`,
+			orderID, bookID, "10.00", 1,
+		); err != nil {
+			t.Fatalf("unexpected error when creating dummy order item: %v", err)
+		}
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
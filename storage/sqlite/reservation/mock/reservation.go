@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reservation.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockReservationStorage is a mock of ReservationStorage interface.
+type MockReservationStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationStorageMockRecorder
+}
+
+// MockReservationStorageMockRecorder is the mock recorder for MockReservationStorage.
+type MockReservationStorageMockRecorder struct {
+	mock *MockReservationStorage
+}
+
+// NewMockReservationStorage creates a new mock instance.
+func NewMockReservationStorage(ctrl *gomock.Controller) *MockReservationStorage {
+	mock := &MockReservationStorage{ctrl: ctrl}
+	mock.recorder = &MockReservationStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservationStorage) EXPECT() *MockReservationStorageMockRecorder {
+	return m.recorder
+}
+
+// Commit mocks base method.
+func (m *MockReservationStorage) Commit(ctx context.Context, userID, reservationID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", ctx, userID, reservationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockReservationStorageMockRecorder) Commit(ctx, userID, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockReservationStorage)(nil).Commit), ctx, userID, reservationID)
+}
+
+// Release mocks base method.
+func (m *MockReservationStorage) Release(ctx context.Context, userID, reservationID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, userID, reservationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockReservationStorageMockRecorder) Release(ctx, userID, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockReservationStorage)(nil).Release), ctx, userID, reservationID)
+}
+
+// ReleaseExpired mocks base method.
+func (m *MockReservationStorage) ReleaseExpired(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpired", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpired indicates an expected call of ReleaseExpired.
+func (mr *MockReservationStorageMockRecorder) ReleaseExpired(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpired", reflect.TypeOf((*MockReservationStorage)(nil).ReleaseExpired), arg0)
+}
+
+// Reserve mocks base method.
+func (m *MockReservationStorage) Reserve(ctx context.Context, userID int64, items []*models.ReservationItem, ttl time.Duration) (*models.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, userID, items, ttl)
+	ret0, _ := ret[0].(*models.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockReservationStorageMockRecorder) Reserve(ctx, userID, items, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockReservationStorage)(nil).Reserve), ctx, userID, items, ttl)
+}
@@ -0,0 +1,451 @@
+package reservation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrUserIDNotFound      = errors.New("user id not found")
+	ErrEditionIDNotFound   = errors.New("edition id not found")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationExpired  = errors.New("reservation expired")
+	ErrReservationMismatch = errors.New("items do not match reservation")
+	ErrBookArchived        = errors.New("reserved book is archived")
+)
+
+// InsufficientStockError reports the editions that do not have enough stock
+// left to be reserved, and the books they are editions of.
+type InsufficientStockError struct {
+	BookIDs    []int64
+	EditionIDs []int64
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("%v for editions with ids: %v", ErrInsufficientStock, e.EditionIDs)
+}
+
+func (e *InsufficientStockError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+//go:generate mockgen -source=reservation.go -destination=mock/reservation.go -package=mock
+type ReservationStorage interface {
+	// Reserve holds the stock of the given editions for a user until the
+	// ttl expires.
+	Reserve(ctx context.Context, userID int64, items []*models.ReservationItem, ttl time.Duration) (*models.Reservation, error)
+
+	// Release gives the stock held by an active reservation of a user
+	// back.
+	Release(ctx context.Context, userID, reservationID int64) error
+
+	// Commit turns an active reservation of a user into a permanent stock
+	// decrement.
+	Commit(ctx context.Context, userID, reservationID int64) error
+
+	// ReleaseExpired releases every active reservation that has expired,
+	// returning the number of released reservations.
+	ReleaseExpired(context.Context) (int64, error)
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around reservation storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// Reserve holds the stock of the given editions for a user until the ttl
+// expires.
+func (s *Storage) Reserve(ctx context.Context, userID int64, items []*models.ReservationItem, ttl time.Duration) (*models.Reservation, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	reservation, err := ReserveTx(ctx, tx, userID, items, time.Now().UTC().Add(ttl))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return reservation, nil
+}
+
+// Release gives the stock held by an active reservation of a user back.
+func (s *Storage) Release(ctx context.Context, userID, reservationID int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := getActiveReservation(ctx, tx, userID, reservationID); err != nil {
+		return err
+	}
+
+	if err := releaseTx(ctx, tx, reservationID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// Commit turns an active reservation of a user into a permanent stock
+// decrement.
+func (s *Storage) Commit(ctx context.Context, userID, reservationID int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := CommitTx(ctx, tx, userID, reservationID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ReleaseExpired releases every active reservation that has expired,
+// returning the number of released reservations.
+func (s *Storage) ReleaseExpired(ctx context.Context) (int64, error) {
+	timeNow := time.Now().UTC()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+SELECT id
+FROM reservations
+WHERE status = ? AND expires_at <= ?
+`
+
+	ids := []int64{}
+	if err := tx.SelectContext(ctx, &ids, query, models.ReservationStatusActive, timeNow); err != nil {
+		return 0, fmt.Errorf("failed to query expired reservations: %v", err)
+	}
+
+	for _, id := range ids {
+		if err := releaseTx(ctx, tx, id, timeNow); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return int64(len(ids)), nil
+}
+
+// ReserveTx holds the stock of the given editions for a user until
+// expiresAt within the given transaction. The stock of an edition is only
+// decremented when there is enough of it left, it returns an
+// *InsufficientStockError listing every edition that does not. Ebooks never
+// run out of stock.
+func ReserveTx(ctx context.Context, tx *sqlx.Tx, userID int64, items []*models.ReservationItem, expiresAt time.Time) (*models.Reservation, error) {
+	timeNow := time.Now().UTC()
+
+	reservation := &models.Reservation{
+		UserID:    userID,
+		Status:    models.ReservationStatusActive,
+		ExpiresAt: expiresAt,
+		Items:     items,
+		CreatedAt: timeNow,
+		UpdatedAt: timeNow,
+	}
+
+	reservationStmt := `INSERT INTO reservations (%s) VALUES(%s);`
+
+	reservationFields := []string{
+		"user_id",
+		"status",
+		"expires_at",
+	}
+	reservationValues := []string{
+		":user_id",
+		":status",
+		":expires_at",
+	}
+
+	res, err := tx.NamedExecContext(ctx,
+		fmt.Sprintf(reservationStmt, strings.Join(reservationFields, ","), strings.Join(reservationValues, ",")),
+		reservation,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), errForeignKeyConstraint) {
+			return nil, ErrUserIDNotFound
+		}
+		return nil, fmt.Errorf("failed to insert reservation operation: %v", err)
+	}
+
+	reservationID, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation id: %v", err)
+	}
+	reservation.ID = reservationID
+
+	insufficientIDs := []int64{}
+	insufficientBookIDs := []int64{}
+	insufficientBooks := map[int64]bool{}
+	for _, item := range items {
+		item.ReservationID = reservationID
+		item.CreatedAt = timeNow
+		item.UpdatedAt = timeNow
+
+		itemStmt := `INSERT INTO reservation_items (%s) VALUES(%s);`
+
+		itemFields := []string{
+			"reservation_id",
+			"book_id",
+			"edition_id",
+			"quantity",
+		}
+		itemValues := []string{
+			":reservation_id",
+			":book_id",
+			":edition_id",
+			":quantity",
+		}
+
+		res, err := tx.NamedExecContext(ctx,
+			fmt.Sprintf(itemStmt, strings.Join(itemFields, ","), strings.Join(itemValues, ",")),
+			item,
+		)
+		if err != nil {
+			if strings.Contains(err.Error(), errForeignKeyConstraint) {
+				return nil, ErrEditionIDNotFound
+			}
+			return nil, fmt.Errorf("failed to insert reservation item operation: %v", err)
+		}
+
+		itemID, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inserted item id: %v", err)
+		}
+		item.ID = itemID
+
+		// only decrement the stock when there is enough of it left, so
+		// concurrent reservations can never hold more than we have.
+		stockStmt := `
+UPDATE editions
+SET stock = CASE WHEN format = 'ebook' THEN stock ELSE stock - :quantity END,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :edition_id AND (format = 'ebook' OR stock >= :quantity);
+`
+
+		res, err = tx.NamedExecContext(ctx, stockStmt, item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrement edition stock: %v", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get affected rows: %v", err)
+		}
+		if affected < 1 {
+			insufficientIDs = append(insufficientIDs, item.EditionID)
+			if !insufficientBooks[item.BookID] {
+				insufficientBooks[item.BookID] = true
+				insufficientBookIDs = append(insufficientBookIDs, item.BookID)
+			}
+		}
+	}
+
+	if len(insufficientIDs) > 0 {
+		return nil, &InsufficientStockError{
+			BookIDs:    insufficientBookIDs,
+			EditionIDs: insufficientIDs,
+		}
+	}
+
+	return reservation, nil
+}
+
+// CommitTx commits an active reservation of a user within the given
+// transaction, the held stock stays decremented. A reservation of a book
+// archived since it was made cannot be committed. When items is not nil it
+// must reserve exactly the same quantity of every edition as the
+// reservation.
+func CommitTx(ctx context.Context, tx *sqlx.Tx, userID, reservationID int64, items []*models.ReservationItem) error {
+	timeNow := time.Now().UTC()
+
+	reservation, err := getActiveReservation(ctx, tx, userID, reservationID)
+	if err != nil {
+		return err
+	}
+	if !reservation.ExpiresAt.After(timeNow) {
+		return ErrReservationExpired
+	}
+
+	// a book archived since it was reserved can no longer be ordered.
+	var archived int64
+	query := `
+SELECT COUNT(*)
+FROM reservation_items ri
+JOIN books b
+	ON b.id = ri.book_id
+WHERE ri.reservation_id = ? AND b.archived_at IS NOT NULL;
+`
+	if err := tx.GetContext(ctx, &archived, query, reservationID); err != nil {
+		return fmt.Errorf("failed to check archived books: %v", err)
+	}
+	if archived > 0 {
+		return ErrBookArchived
+	}
+
+	if items != nil {
+		reserved := []*models.ReservationItem{}
+		query = `
+SELECT book_id, edition_id, quantity
+FROM reservation_items
+WHERE reservation_id = ?
+`
+		if err := tx.SelectContext(ctx, &reserved, query, reservationID); err != nil {
+			return fmt.Errorf("failed to query reservation items: %v", err)
+		}
+
+		if !sameQuantities(reserved, items) {
+			return ErrReservationMismatch
+		}
+	}
+
+	stmt := `
+UPDATE reservations
+SET status = ?, updated_at = ?
+WHERE id = ? AND status = ?;
+`
+
+	res, err := tx.ExecContext(ctx, stmt,
+		models.ReservationStatusCommitted, timeNow, reservationID, models.ReservationStatusActive,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to commit reservation: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return ErrReservationNotFound
+	}
+
+	return nil
+}
+
+// releaseTx marks an active reservation as released and gives the stock it
+// held back to its editions.
+func releaseTx(ctx context.Context, tx *sqlx.Tx, reservationID int64, timeNow time.Time) error {
+	stmt := `
+UPDATE reservations
+SET status = ?, updated_at = ?
+WHERE id = ? AND status = ?;
+`
+
+	res, err := tx.ExecContext(ctx, stmt,
+		models.ReservationStatusReleased, timeNow, reservationID, models.ReservationStatusActive,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release reservation: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return ErrReservationNotFound
+	}
+
+	stockStmt := `
+UPDATE editions
+SET stock = stock + (
+		SELECT SUM(ri.quantity)
+		FROM reservation_items ri
+		WHERE ri.reservation_id = :reservation_id AND ri.edition_id = editions.id
+	),
+	updated_at = CURRENT_TIMESTAMP
+WHERE format <> 'ebook' AND id IN (
+	SELECT edition_id
+	FROM reservation_items
+	WHERE reservation_id = :reservation_id
+);
+`
+
+	arg := map[string]interface{}{
+		"reservation_id": reservationID,
+	}
+	if _, err := tx.NamedExecContext(ctx, stockStmt, arg); err != nil {
+		return fmt.Errorf("failed to restore edition stock: %v", err)
+	}
+
+	return nil
+}
+
+// getActiveReservation fetches an active reservation owned by the user.
+func getActiveReservation(ctx context.Context, tx *sqlx.Tx, userID, reservationID int64) (*models.Reservation, error) {
+	query := `
+SELECT id, user_id, status, expires_at
+FROM reservations
+WHERE id = ? AND user_id = ? AND status = ?
+`
+
+	var reservation models.Reservation
+	err := tx.GetContext(ctx, &reservation, query, reservationID, userID, models.ReservationStatusActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrReservationNotFound
+		}
+		return nil, fmt.Errorf("failed to get reservation: %v", err)
+	}
+
+	return &reservation, nil
+}
+
+// sameQuantities reports whether both item lists hold the same total
+// quantity of every edition.
+func sameQuantities(a, b []*models.ReservationItem) bool {
+	quantities := map[int64]int64{}
+	for _, item := range a {
+		quantities[item.EditionID] += item.Quantity
+	}
+	for _, item := range b {
+		quantities[item.EditionID] -= item.Quantity
+	}
+	for _, quantity := range quantities {
+		if quantity != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,320 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_Reserve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		userID := testCreateUser(t, ts)
+		bookID, editionID, stock := testGetEdition(t, ts)
+
+		reservation, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+			{
+				BookID:    bookID,
+				EditionID: editionID,
+				Quantity:  2,
+			},
+		}, time.Minute)
+		if err != nil {
+			t.Fatalf("Reserve(_, _, _, _) expected nil error, got = %v", err)
+		}
+		if reservation.Status != models.ReservationStatusActive {
+			t.Fatalf("Reserve(_, _, _, _) error, got status = %v, want = %v", reservation.Status, models.ReservationStatusActive)
+		}
+
+		if got := testGetStock(t, ts, editionID); got != stock-2 {
+			t.Fatalf("Reserve(_, _, _, _) error, got stock = %v, want = %v", got, stock-2)
+		}
+	})
+
+	t.Run("InsufficientStock", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		userID := testCreateUser(t, ts)
+		bookID, editionID, stock := testGetEdition(t, ts)
+
+		_, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+			{
+				BookID:    bookID,
+				EditionID: editionID,
+				Quantity:  stock + 1,
+			},
+		}, time.Minute)
+
+		var stockErr *InsufficientStockError
+		if !errors.As(err, &stockErr) {
+			t.Fatalf("Reserve(_, _, _, _) error, got = %v, want = %v", err, ErrInsufficientStock)
+		}
+		if len(stockErr.EditionIDs) != 1 || stockErr.EditionIDs[0] != editionID {
+			t.Fatalf("Reserve(_, _, _, _) error, got edition ids = %v, want = %v", stockErr.EditionIDs, []int64{editionID})
+		}
+		if len(stockErr.BookIDs) != 1 || stockErr.BookIDs[0] != bookID {
+			t.Fatalf("Reserve(_, _, _, _) error, got book ids = %v, want = %v", stockErr.BookIDs, []int64{bookID})
+		}
+
+		if got := testGetStock(t, ts, editionID); got != stock {
+			t.Fatalf("Reserve(_, _, _, _) error, got stock = %v, want = %v", got, stock)
+		}
+	})
+
+	t.Run("Ebook", func(t *testing.T) {
+		t.Parallel()
+
+		ts, teardown := newTestStorage(t)
+		t.Cleanup(teardown)
+
+		userID := testCreateUser(t, ts)
+		bookID, _, _ := testGetEdition(t, ts)
+
+		res, err := ts.db.Exec(`INSERT INTO editions (book_id, format, price) VALUES (?, 'ebook', '4.99');`, bookID)
+		if err != nil {
+			t.Fatalf("unexpected error when creating ebook edition: %v", err)
+		}
+		ebookID, err := res.LastInsertId()
+		if err != nil {
+			t.Fatalf("unexpected error when getting ebook edition id: %v", err)
+		}
+
+		// ebooks never run out of stock.
+		if _, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+			{
+				BookID:    bookID,
+				EditionID: ebookID,
+				Quantity:  100,
+			},
+		}, time.Minute); err != nil {
+			t.Fatalf("Reserve(_, _, _, _) expected nil error, got = %v", err)
+		}
+
+		if got := testGetStock(t, ts, ebookID); got != 0 {
+			t.Fatalf("Reserve(_, _, _, _) error, got stock = %v, want = %v", got, 0)
+		}
+	})
+}
+
+func Test_Release(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testCreateUser(t, ts)
+	otherUserID := testCreateUser(t, ts)
+	bookID, editionID, stock := testGetEdition(t, ts)
+
+	reservation, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+		{
+			BookID:    bookID,
+			EditionID: editionID,
+			Quantity:  3,
+		},
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error when reserving books: %v", err)
+	}
+
+	// a reservation can only be released by its owner.
+	if err := ts.Release(ctx, otherUserID, reservation.ID); !errors.Is(err, ErrReservationNotFound) {
+		t.Fatalf("Release(_, _, _) error, got = %v, want = %v", err, ErrReservationNotFound)
+	}
+
+	if err := ts.Release(ctx, userID, reservation.ID); err != nil {
+		t.Fatalf("Release(_, _, _) expected nil error, got = %v", err)
+	}
+
+	if got := testGetStock(t, ts, editionID); got != stock {
+		t.Fatalf("Release(_, _, _) error, got stock = %v, want = %v", got, stock)
+	}
+
+	// a released reservation cannot be released twice.
+	if err := ts.Release(ctx, userID, reservation.ID); !errors.Is(err, ErrReservationNotFound) {
+		t.Fatalf("Release(_, _, _) error, got = %v, want = %v", err, ErrReservationNotFound)
+	}
+}
+
+func Test_Commit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testCreateUser(t, ts)
+	bookID, editionID, stock := testGetEdition(t, ts)
+
+	reservation, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+		{
+			BookID:    bookID,
+			EditionID: editionID,
+			Quantity:  1,
+		},
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error when reserving books: %v", err)
+	}
+
+	if err := ts.Commit(ctx, userID, reservation.ID); err != nil {
+		t.Fatalf("Commit(_, _, _) expected nil error, got = %v", err)
+	}
+
+	// a committed reservation keeps its stock and cannot be released.
+	if err := ts.Release(ctx, userID, reservation.ID); !errors.Is(err, ErrReservationNotFound) {
+		t.Fatalf("Release(_, _, _) error, got = %v, want = %v", err, ErrReservationNotFound)
+	}
+	if got := testGetStock(t, ts, editionID); got != stock-1 {
+		t.Fatalf("Commit(_, _, _) error, got stock = %v, want = %v", got, stock-1)
+	}
+
+	expired, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+		{
+			BookID:    bookID,
+			EditionID: editionID,
+			Quantity:  1,
+		},
+	}, -time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error when reserving books: %v", err)
+	}
+
+	if err := ts.Commit(ctx, userID, expired.ID); !errors.Is(err, ErrReservationExpired) {
+		t.Fatalf("Commit(_, _, _) error, got = %v, want = %v", err, ErrReservationExpired)
+	}
+
+	// the book is archived after it was reserved.
+	archived, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+		{
+			BookID:    bookID,
+			EditionID: editionID,
+			Quantity:  1,
+		},
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error when reserving books: %v", err)
+	}
+	if _, err := ts.db.Exec(`UPDATE books SET archived_at = CURRENT_TIMESTAMP WHERE id = ?;`, bookID); err != nil {
+		t.Fatalf("unexpected error when archiving book: %v", err)
+	}
+
+	if err := ts.Commit(ctx, userID, archived.ID); !errors.Is(err, ErrBookArchived) {
+		t.Fatalf("Commit(_, _, _) error, got = %v, want = %v", err, ErrBookArchived)
+	}
+}
+
+func Test_ReleaseExpired(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testCreateUser(t, ts)
+	bookID, editionID, stock := testGetEdition(t, ts)
+
+	// reserve once with an expired hold and once with an active one.
+	for _, ttl := range []time.Duration{-time.Minute, time.Hour} {
+		if _, err := ts.Reserve(ctx, userID, []*models.ReservationItem{
+			{
+				BookID:    bookID,
+				EditionID: editionID,
+				Quantity:  2,
+			},
+		}, ttl); err != nil {
+			t.Fatalf("unexpected error when reserving books: %v", err)
+		}
+	}
+
+	released, err := ts.ReleaseExpired(ctx)
+	if err != nil {
+		t.Fatalf("ReleaseExpired(_) expected nil error, got = %v", err)
+	}
+	if released != 1 {
+		t.Fatalf("ReleaseExpired(_) error, got = %v, want = %v released", released, 1)
+	}
+
+	if got := testGetStock(t, ts, editionID); got != stock-2 {
+		t.Fatalf("ReleaseExpired(_) error, got stock = %v, want = %v", got, stock-2)
+	}
+}
+
+func testCreateUser(t *testing.T, ts *Storage) int64 {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when creating dummy user: %v", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting dummy user id: %v", err)
+	}
+	return id
+}
+
+func testGetEdition(t *testing.T, ts *Storage) (int64, int64, int64) {
+	t.Helper()
+
+	var edition models.Edition
+	if err := ts.db.Get(&edition, `SELECT id, book_id, stock FROM editions WHERE is_default LIMIT 1`); err != nil {
+		t.Fatalf("unexpected error when getting edition: %v", err)
+	}
+	return edition.BookID, edition.ID, edition.Stock
+}
+
+func testGetStock(t *testing.T, ts *Storage, editionID int64) int64 {
+	t.Helper()
+
+	var stock int64
+	if err := ts.db.Get(&stock, `SELECT stock FROM editions WHERE id = ?`, editionID); err != nil {
+		t.Fatalf("unexpected error when getting edition stock: %v", err)
+	}
+	return stock
+}
+
+func genString() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: review.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockReviewStorage is a mock of ReviewStorage interface.
+type MockReviewStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewStorageMockRecorder
+}
+
+// MockReviewStorageMockRecorder is the mock recorder for MockReviewStorage.
+type MockReviewStorageMockRecorder struct {
+	mock *MockReviewStorage
+}
+
+// NewMockReviewStorage creates a new mock instance.
+func NewMockReviewStorage(ctrl *gomock.Controller) *MockReviewStorage {
+	mock := &MockReviewStorage{ctrl: ctrl}
+	mock.recorder = &MockReviewStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewStorage) EXPECT() *MockReviewStorageMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReviewStorage) Create(arg0 context.Context, arg1 *models.Review) (*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReviewStorageMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReviewStorage)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockReviewStorage) Delete(ctx context.Context, bookID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, bookID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReviewStorageMockRecorder) Delete(ctx, bookID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReviewStorage)(nil).Delete), ctx, bookID, userID)
+}
+
+// GetReview mocks base method.
+func (m *MockReviewStorage) GetReview(ctx context.Context, bookID, userID int64) (*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReview", ctx, bookID, userID)
+	ret0, _ := ret[0].(*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReview indicates an expected call of GetReview.
+func (mr *MockReviewStorageMockRecorder) GetReview(ctx, bookID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReview", reflect.TypeOf((*MockReviewStorage)(nil).GetReview), ctx, bookID, userID)
+}
+
+// GetReviewsByBookID mocks base method.
+func (m *MockReviewStorage) GetReviewsByBookID(arg0 context.Context, arg1 int64) ([]*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByBookID", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByBookID indicates an expected call of GetReviewsByBookID.
+func (mr *MockReviewStorageMockRecorder) GetReviewsByBookID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByBookID", reflect.TypeOf((*MockReviewStorage)(nil).GetReviewsByBookID), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockReviewStorage) Update(arg0 context.Context, arg1 *models.Review) (*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReviewStorageMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReviewStorage)(nil).Update), arg0, arg1)
+}
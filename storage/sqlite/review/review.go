@@ -0,0 +1,189 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrReviewAlreadyExist = errors.New("review already exist")
+	ErrBookIDNotFound     = errors.New("book id not found")
+)
+
+//go:generate mockgen -source=review.go -destination=mock/review.go -package=mock
+type ReviewStorage interface {
+	// GetReviewsByBookID fetches all the reviews of a book, the most recent
+	// first.
+	GetReviewsByBookID(context.Context, int64) ([]*models.Review, error)
+
+	// GetReview fetches the review a user wrote for a book.
+	GetReview(ctx context.Context, bookID, userID int64) (*models.Review, error)
+
+	// Create adds the review of a user for a book, a user can only review a
+	// book once.
+	Create(context.Context, *models.Review) (*models.Review, error)
+
+	// Update changes the rating and body of the review a user wrote for a
+	// book.
+	Update(context.Context, *models.Review) (*models.Review, error)
+
+	// Delete removes the review a user wrote for a book.
+	Delete(ctx context.Context, bookID, userID int64) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around review storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// reviewColumns are the columns selected into the review model, a review is
+// a verified purchase when its reviewer has ordered the book.
+const reviewColumns = `r.id, r.book_id, r.user_id, r.rating, r.body, r.created_at, r.updated_at,
+	EXISTS (
+		SELECT 1
+		FROM orders o
+		JOIN order_items oi
+			ON o.id = oi.order_id
+		WHERE o.user_id = r.user_id AND oi.book_id = r.book_id
+	) AS verified_purchase`
+
+// GetReviewsByBookID fetches all the reviews of a book, the most recent
+// first.
+func (s *Storage) GetReviewsByBookID(ctx context.Context, bookID int64) ([]*models.Review, error) {
+	query := `
+SELECT %s
+FROM reviews r
+WHERE r.book_id = ?
+ORDER BY r.created_at DESC, r.id DESC;
+`
+
+	reviews := []*models.Review{}
+	if err := s.db.SelectContext(ctx, &reviews, fmt.Sprintf(query, reviewColumns), bookID); err != nil {
+		return nil, fmt.Errorf("failed to query from reviews table: %v", err)
+	}
+
+	return reviews, nil
+}
+
+// GetReview fetches the review a user wrote for a book.
+func (s *Storage) GetReview(ctx context.Context, bookID, userID int64) (*models.Review, error) {
+	query := `
+SELECT %s
+FROM reviews r
+WHERE r.book_id = ? AND r.user_id = ?;
+`
+
+	var review models.Review
+	if err := s.db.GetContext(ctx, &review, fmt.Sprintf(query, reviewColumns), bookID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %v", err)
+	}
+
+	return &review, nil
+}
+
+// Create adds the review of a user for a book, a user can only review a
+// book once.
+func (s *Storage) Create(ctx context.Context, review *models.Review) (*models.Review, error) {
+	stmt := `INSERT INTO reviews(%s) VALUES(%s);`
+
+	// fields and values to be operated
+	fields := []string{
+		"book_id",
+		"user_id",
+		"rating",
+		"body",
+	}
+	values := []string{
+		":book_id",
+		":user_id",
+		":rating",
+		":body",
+	}
+
+	if _, err := s.db.NamedExecContext(ctx,
+		fmt.Sprintf(stmt, strings.Join(fields, ","), strings.Join(values, ",")),
+		review,
+	); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return nil, ErrReviewAlreadyExist
+		}
+		if strings.Contains(err.Error(), errForeignKeyConstraint) {
+			return nil, ErrBookIDNotFound
+		}
+		return nil, fmt.Errorf("failed to perform Create operation: %w", err)
+	}
+
+	return s.GetReview(ctx, review.BookID, review.UserID)
+}
+
+// Update changes the rating and body of the review a user wrote for a book.
+func (s *Storage) Update(ctx context.Context, review *models.Review) (*models.Review, error) {
+	stmt := `
+UPDATE reviews
+SET rating = :rating, body = :body, updated_at = :updated_at
+WHERE book_id = :book_id AND user_id = :user_id;
+`
+
+	review.UpdatedAt = time.Now().UTC()
+
+	res, err := s.db.NamedExecContext(ctx, stmt, review)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform Update operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return nil, sqlite.ErrNotFound
+	}
+
+	return s.GetReview(ctx, review.BookID, review.UserID)
+}
+
+// Delete removes the review a user wrote for a book.
+func (s *Storage) Delete(ctx context.Context, bookID, userID int64) error {
+	stmt := `
+DELETE FROM reviews
+WHERE book_id = :book_id AND user_id = :user_id;
+`
+
+	arg := map[string]interface{}{
+		"book_id": bookID,
+		"user_id": userID,
+	}
+
+	res, err := s.db.NamedExecContext(ctx, stmt, arg)
+	if err != nil {
+		return fmt.Errorf("failed to perform Delete operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		userID := testCreateUser(t, ts)
+
+		review, err := ts.Create(ctx, &models.Review{
+			BookID: 1,
+			UserID: userID,
+			Rating: 4,
+			Body:   genString(),
+		})
+		if err != nil {
+			t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+		}
+		if review.ID == 0 || review.Rating != 4 {
+			t.Fatalf("Create(_, _) error, got = %+v", review)
+		}
+		if review.VerifiedPurchase {
+			t.Fatalf("Create(_, _) error, got verified purchase = %v, want = %v", review.VerifiedPurchase, false)
+		}
+	})
+
+	t.Run("VerifiedPurchase", func(t *testing.T) {
+		t.Parallel()
+
+		userID := testCreateUser(t, ts)
+		testCreateOrder(t, ts, userID, 2)
+
+		review, err := ts.Create(ctx, &models.Review{
+			BookID: 2,
+			UserID: userID,
+			Rating: 5,
+		})
+		if err != nil {
+			t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+		}
+		if !review.VerifiedPurchase {
+			t.Fatalf("Create(_, _) error, got verified purchase = %v, want = %v", review.VerifiedPurchase, true)
+		}
+	})
+
+	t.Run("ReviewAlreadyExist", func(t *testing.T) {
+		t.Parallel()
+
+		userID := testCreateUser(t, ts)
+		review := &models.Review{
+			BookID: 3,
+			UserID: userID,
+			Rating: 3,
+		}
+
+		if _, err := ts.Create(ctx, review); err != nil {
+			t.Fatalf("unexpected error when creating review: %v", err)
+		}
+
+		_, err := ts.Create(ctx, review)
+		if !errors.Is(err, ErrReviewAlreadyExist) {
+			t.Fatalf("Create(_, _) error, got = %v, want = %v", err, ErrReviewAlreadyExist)
+		}
+	})
+
+	t.Run("BookIDNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ts.Create(ctx, &models.Review{
+			BookID: 1000,
+			UserID: testCreateUser(t, ts),
+			Rating: 3,
+		})
+		if !errors.Is(err, ErrBookIDNotFound) {
+			t.Fatalf("Create(_, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+		}
+	})
+}
+
+func Test_GetReviewsByBookID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	for i := 0; i < 2; i++ {
+		if _, err := ts.Create(ctx, &models.Review{
+			BookID: 1,
+			UserID: testCreateUser(t, ts),
+			Rating: 5,
+		}); err != nil {
+			t.Fatalf("unexpected error when creating review: %v", err)
+		}
+	}
+
+	reviews, err := ts.GetReviewsByBookID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetReviewsByBookID(_, _) expected nil error, got = %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("GetReviewsByBookID(_, _) error, got = %v, want = %v reviews", len(reviews), 2)
+	}
+}
+
+func Test_Update(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testCreateUser(t, ts)
+	if _, err := ts.Create(ctx, &models.Review{
+		BookID: 1,
+		UserID: userID,
+		Rating: 2,
+	}); err != nil {
+		t.Fatalf("unexpected error when creating review: %v", err)
+	}
+
+	body := genString()
+	review, err := ts.Update(ctx, &models.Review{
+		BookID: 1,
+		UserID: userID,
+		Rating: 5,
+		Body:   body,
+	})
+	if err != nil {
+		t.Fatalf("Update(_, _) expected nil error, got = %v", err)
+	}
+	if review.Rating != 5 || review.Body != body {
+		t.Fatalf("Update(_, _) error, got = %+v", review)
+	}
+
+	_, err = ts.Update(ctx, &models.Review{
+		BookID: 2,
+		UserID: userID,
+		Rating: 5,
+	})
+	if !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("Update(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testCreateUser(t, ts)
+	if _, err := ts.Create(ctx, &models.Review{
+		BookID: 1,
+		UserID: userID,
+		Rating: 2,
+	}); err != nil {
+		t.Fatalf("unexpected error when creating review: %v", err)
+	}
+
+	if err := ts.Delete(ctx, 1, userID); err != nil {
+		t.Fatalf("Delete(_, _, _) expected nil error, got = %v", err)
+	}
+
+	if err := ts.Delete(ctx, 1, userID); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("Delete(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func testCreateUser(t *testing.T, ts *Storage) int64 {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when creating dummy user: %v", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting dummy user id: %v", err)
+	}
+	return id
+}
+
+func testCreateOrder(t *testing.T, ts *Storage, userID, bookID int64) {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO orders (user_id, total) VALUES (?, ?);`, userID, "10.00")
+	if err != nil {
+		t.Fatalf("unexpected error when creating dummy order: %v", err)
+	}
+
+	orderID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting dummy order id: %v", err)
+	}
+
+	if _, err := ts.db.Exec(`INSERT INTO order_items (order_id, book_id, price, quantity) VALUES (?, ?, ?, ?);`,
+		orderID, bookID, "10.00", 1,
+	); err != nil {
+		t.Fatalf("unexpected error when creating dummy order item: %v", err)
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
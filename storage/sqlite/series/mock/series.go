@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: series.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockSeriesStorage is a mock of SeriesStorage interface.
+type MockSeriesStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesStorageMockRecorder
+}
+
+// MockSeriesStorageMockRecorder is the mock recorder for MockSeriesStorage.
+type MockSeriesStorageMockRecorder struct {
+	mock *MockSeriesStorage
+}
+
+// NewMockSeriesStorage creates a new mock instance.
+func NewMockSeriesStorage(ctrl *gomock.Controller) *MockSeriesStorage {
+	mock := &MockSeriesStorage{ctrl: ctrl}
+	mock.recorder = &MockSeriesStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesStorage) EXPECT() *MockSeriesStorageMockRecorder {
+	return m.recorder
+}
+
+// AssignBook mocks base method.
+func (m *MockSeriesStorage) AssignBook(ctx context.Context, seriesID, bookID, position int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignBook", ctx, seriesID, bookID, position)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignBook indicates an expected call of AssignBook.
+func (mr *MockSeriesStorageMockRecorder) AssignBook(ctx, seriesID, bookID, position interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignBook", reflect.TypeOf((*MockSeriesStorage)(nil).AssignBook), ctx, seriesID, bookID, position)
+}
+
+// Create mocks base method.
+func (m *MockSeriesStorage) Create(arg0 context.Context, arg1 *models.Series) (*models.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*models.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSeriesStorageMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSeriesStorage)(nil).Create), arg0, arg1)
+}
+
+// GetNextInSeries mocks base method.
+func (m *MockSeriesStorage) GetNextInSeries(ctx context.Context, userID int64) ([]*models.SeriesEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextInSeries", ctx, userID)
+	ret0, _ := ret[0].([]*models.SeriesEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextInSeries indicates an expected call of GetNextInSeries.
+func (mr *MockSeriesStorageMockRecorder) GetNextInSeries(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextInSeries", reflect.TypeOf((*MockSeriesStorage)(nil).GetNextInSeries), ctx, userID)
+}
+
+// GetSeriesByID mocks base method.
+func (m *MockSeriesStorage) GetSeriesByID(arg0 context.Context, arg1 int64) (*models.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesByID indicates an expected call of GetSeriesByID.
+func (mr *MockSeriesStorageMockRecorder) GetSeriesByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesByID", reflect.TypeOf((*MockSeriesStorage)(nil).GetSeriesByID), arg0, arg1)
+}
+
+// GetSeriesEntries mocks base method.
+func (m *MockSeriesStorage) GetSeriesEntries(ctx context.Context, seriesID int64) ([]*models.SeriesEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesEntries", ctx, seriesID)
+	ret0, _ := ret[0].([]*models.SeriesEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesEntries indicates an expected call of GetSeriesEntries.
+func (mr *MockSeriesStorageMockRecorder) GetSeriesEntries(ctx, seriesID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesEntries", reflect.TypeOf((*MockSeriesStorage)(nil).GetSeriesEntries), ctx, seriesID)
+}
+
+// UnassignBook mocks base method.
+func (m *MockSeriesStorage) UnassignBook(ctx context.Context, seriesID, bookID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignBook", ctx, seriesID, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignBook indicates an expected call of UnassignBook.
+func (mr *MockSeriesStorageMockRecorder) UnassignBook(ctx, seriesID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignBook", reflect.TypeOf((*MockSeriesStorage)(nil).UnassignBook), ctx, seriesID, bookID)
+}
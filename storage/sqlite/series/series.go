@@ -0,0 +1,245 @@
+package series
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	ErrNameAlreadyExist = errors.New("series name already exist")
+	ErrBookIDNotFound   = errors.New("book id not found")
+	ErrPositionTaken    = errors.New("position is taken by another book of the series")
+)
+
+//go:generate mockgen -source=series.go -destination=mock/series.go -package=mock
+type SeriesStorage interface {
+	// GetSeriesByID fetches a series by the given id.
+	GetSeriesByID(context.Context, int64) (*models.Series, error)
+
+	// GetSeriesEntries fetches the books of a series that are not archived
+	// in reading order.
+	GetSeriesEntries(ctx context.Context, seriesID int64) ([]*models.SeriesEntry, error)
+
+	// GetNextInSeries fetches, for every series a user ordered books of, the
+	// first book the user has not ordered past the furthest one they did.
+	GetNextInSeries(ctx context.Context, userID int64) ([]*models.SeriesEntry, error)
+
+	// Create adds a new series to our storage.
+	Create(context.Context, *models.Series) (*models.Series, error)
+
+	// AssignBook places a book at a position of a series.
+	AssignBook(ctx context.Context, seriesID, bookID, position int64) error
+
+	// UnassignBook removes a book from a series.
+	UnassignBook(ctx context.Context, seriesID, bookID int64) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around series storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// GetSeriesByID fetches a series by the given id.
+func (s *Storage) GetSeriesByID(ctx context.Context, id int64) (*models.Series, error) {
+	query := `
+SELECT id, name, description
+FROM series
+WHERE id = ?;
+`
+
+	var series models.Series
+	if err := s.db.GetContext(ctx, &series, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sqlite.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to perform GetSeriesByID storage operation: %w", err)
+	}
+
+	return &series, nil
+}
+
+// GetSeriesEntries fetches the books of a series that are not archived in
+// reading order.
+func (s *Storage) GetSeriesEntries(ctx context.Context, seriesID int64) ([]*models.SeriesEntry, error) {
+	query := `
+SELECT series_id, id AS book_id, series_position
+FROM books
+WHERE series_id = ? AND archived_at IS NULL
+ORDER BY series_position;
+`
+
+	entries := []*models.SeriesEntry{}
+	if err := s.db.SelectContext(ctx, &entries, query, seriesID); err != nil {
+		return nil, fmt.Errorf("failed to query from books table: %v", err)
+	}
+
+	return entries, nil
+}
+
+// GetNextInSeries fetches, for every series a user ordered books of, the
+// first book the user has not ordered past the furthest one they did. Series
+// the user has nothing left to read of are left out.
+func (s *Storage) GetNextInSeries(ctx context.Context, userID int64) ([]*models.SeriesEntry, error) {
+	query := `
+WITH ordered AS (
+	SELECT DISTINCT oi.book_id
+	FROM order_items oi
+	JOIN orders o
+		ON o.id = oi.order_id
+	WHERE o.user_id = :user_id
+),
+furthest AS (
+	SELECT b.series_id, MAX(b.series_position) AS series_position
+	FROM books b
+	JOIN ordered
+		ON ordered.book_id = b.id
+	WHERE b.series_id IS NOT NULL
+	GROUP BY b.series_id
+)
+SELECT f.series_id, next.id AS book_id, next.series_position
+FROM furthest f
+JOIN books next
+	ON next.id = (
+		SELECT b.id
+		FROM books b
+		WHERE b.series_id = f.series_id
+			AND b.series_position > f.series_position
+			AND b.archived_at IS NULL
+			AND b.id NOT IN (SELECT book_id FROM ordered)
+		ORDER BY b.series_position
+		LIMIT 1
+	)
+ORDER BY f.series_id;
+`
+
+	stmt, err := s.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare GetNextInSeries statement: %w", err)
+	}
+	defer stmt.Close()
+
+	entries := []*models.SeriesEntry{}
+	if err := stmt.SelectContext(ctx, &entries, map[string]interface{}{"user_id": userID}); err != nil {
+		return nil, fmt.Errorf("failed to query next in series: %v", err)
+	}
+
+	return entries, nil
+}
+
+// Create adds a new series to our storage.
+func (s *Storage) Create(ctx context.Context, series *models.Series) (*models.Series, error) {
+	stmt := `INSERT INTO series(%s) VALUES(%s);`
+
+	// fields and values to be operated
+	fields := []string{
+		"name",
+		"description",
+	}
+	values := []string{
+		":name",
+		":description",
+	}
+
+	res, err := s.db.NamedExecContext(ctx,
+		fmt.Sprintf(stmt, strings.Join(fields, ","), strings.Join(values, ",")),
+		series,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return nil, ErrNameAlreadyExist
+		}
+		return nil, fmt.Errorf("failed to perform Create operation: %w", err)
+	}
+
+	insertedID, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to Create series: %v", err)
+	}
+
+	createdSeries := &models.Series{
+		ID:          insertedID,
+		Name:        series.Name,
+		Description: series.Description,
+	}
+
+	return createdSeries, nil
+}
+
+// AssignBook places a book at a position of a series, moving it out of the
+// series it was in before.
+func (s *Storage) AssignBook(ctx context.Context, seriesID, bookID, position int64) error {
+	stmt := `
+UPDATE books
+SET series_id = :series_id,
+	series_position = :series_position,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :book_id;
+`
+
+	arg := map[string]interface{}{
+		"series_id":       seriesID,
+		"series_position": position,
+		"book_id":         bookID,
+	}
+
+	res, err := s.db.NamedExecContext(ctx, stmt, arg)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return ErrPositionTaken
+		}
+		return fmt.Errorf("failed to perform AssignBook operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return ErrBookIDNotFound
+	}
+
+	return nil
+}
+
+// UnassignBook removes a book from a series.
+func (s *Storage) UnassignBook(ctx context.Context, seriesID, bookID int64) error {
+	stmt := `
+UPDATE books
+SET series_id = NULL,
+	series_position = NULL,
+	updated_at = CURRENT_TIMESTAMP
+WHERE id = :book_id AND series_id = :series_id;
+`
+
+	arg := map[string]interface{}{
+		"series_id": seriesID,
+		"book_id":   bookID,
+	}
+
+	res, err := s.db.NamedExecContext(ctx, stmt, arg)
+	if err != nil {
+		return fmt.Errorf("failed to perform UnassignBook operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
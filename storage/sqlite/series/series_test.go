@@ -0,0 +1,216 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+// testCreateSeries creates a series with the seeded books 1, 2 and 3 at
+// positions 1, 2 and 3.
+func testCreateSeries(t *testing.T, ts *Storage) *models.Series {
+	t.Helper()
+
+	ctx := context.Background()
+
+	created, err := ts.Create(ctx, &models.Series{Name: genString()})
+	if err != nil {
+		t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+	}
+
+	for position, bookID := range []int64{1, 2, 3} {
+		if err := ts.AssignBook(ctx, created.ID, bookID, int64(position+1)); err != nil {
+			t.Fatalf("AssignBook(_, _, _, _) expected nil error, got = %v", err)
+		}
+	}
+
+	return created
+}
+
+// testOrder records an order of the given books for a new user, returning
+// the id of the user.
+func testOrder(t *testing.T, ts *Storage, bookIDs ...int64) int64 {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when creating user: %v", err)
+	}
+	userID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting user id: %v", err)
+	}
+
+	res, err = ts.db.Exec(`INSERT INTO orders (user_id, total) VALUES (?, '0.00');`, userID)
+	if err != nil {
+		t.Fatalf("unexpected error when creating order: %v", err)
+	}
+	orderID, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting order id: %v", err)
+	}
+
+	for _, bookID := range bookIDs {
+		if _, err := ts.db.Exec(
+			`INSERT INTO order_items (order_id, book_id, edition_id, price, quantity) VALUES (?, ?, ?, '0.00', 1);`,
+			orderID, bookID, bookID,
+		); err != nil {
+			t.Fatalf("unexpected error when creating order item: %v", err)
+		}
+	}
+
+	return userID
+}
+
+func Test_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	created, err := ts.Create(ctx, &models.Series{Name: "Foundation", Description: "Psychohistory"})
+	if err != nil {
+		t.Fatalf("Create(_, _) expected nil error, got = %v", err)
+	}
+
+	got, err := ts.GetSeriesByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetSeriesByID(_, _) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff(created, got); diff != "" {
+		t.Fatalf("GetSeriesByID(_, _) mismatch (-want+got):\n%s", diff)
+	}
+
+	if _, err := ts.Create(ctx, &models.Series{Name: "Foundation"}); !errors.Is(err, ErrNameAlreadyExist) {
+		t.Fatalf("Create(_, _) error, got = %v, want = %v", err, ErrNameAlreadyExist)
+	}
+	if _, err := ts.GetSeriesByID(ctx, 100000); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("GetSeriesByID(_, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_AssignBook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	series := testCreateSeries(t, ts)
+
+	// archived books are left out of the reading order.
+	if _, err := ts.db.Exec(`UPDATE books SET archived_at = CURRENT_TIMESTAMP WHERE id = 2;`); err != nil {
+		t.Fatalf("unexpected error when archiving book: %v", err)
+	}
+
+	entries, err := ts.GetSeriesEntries(ctx, series.ID)
+	if err != nil {
+		t.Fatalf("GetSeriesEntries(_, _) expected nil error, got = %v", err)
+	}
+	want := []*models.SeriesEntry{
+		{SeriesID: series.ID, BookID: 1, Position: 1},
+		{SeriesID: series.ID, BookID: 3, Position: 3},
+	}
+	if diff := cmp.Diff(want, entries); diff != "" {
+		t.Fatalf("GetSeriesEntries(_, _) mismatch (-want+got):\n%s", diff)
+	}
+
+	if err := ts.AssignBook(ctx, series.ID, 3, 1); !errors.Is(err, ErrPositionTaken) {
+		t.Fatalf("AssignBook(_, _, _, _) error, got = %v, want = %v", err, ErrPositionTaken)
+	}
+	if err := ts.AssignBook(ctx, series.ID, 100000, 4); !errors.Is(err, ErrBookIDNotFound) {
+		t.Fatalf("AssignBook(_, _, _, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+	}
+
+	if err := ts.UnassignBook(ctx, series.ID, 1); err != nil {
+		t.Fatalf("UnassignBook(_, _, _) expected nil error, got = %v", err)
+	}
+	if err := ts.UnassignBook(ctx, series.ID, 1); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("UnassignBook(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func Test_GetNextInSeries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	series := testCreateSeries(t, ts)
+
+	tests := []struct {
+		name    string
+		ordered []int64
+		want    []*models.SeriesEntry
+	}{
+		{
+			name:    "FirstBook",
+			ordered: []int64{1},
+			want:    []*models.SeriesEntry{{SeriesID: series.ID, BookID: 2, Position: 2}},
+		},
+		{
+			// books skipped on the way are not suggested.
+			name:    "SkippedBook",
+			ordered: []int64{2},
+			want:    []*models.SeriesEntry{{SeriesID: series.ID, BookID: 3, Position: 3}},
+		},
+		{
+			name:    "FinishedSeries",
+			ordered: []int64{1, 3},
+			want:    []*models.SeriesEntry{},
+		},
+		{
+			name: "NoOrders",
+			want: []*models.SeriesEntry{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			userID := testOrder(t, ts, tt.ordered...)
+
+			got, err := ts.GetNextInSeries(ctx, userID)
+			if err != nil {
+				t.Fatalf("GetNextInSeries(_, _) expected nil error, got = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("GetNextInSeries(_, _) mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
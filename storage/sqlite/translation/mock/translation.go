@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: translation.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockTranslationStorage is a mock of TranslationStorage interface.
+type MockTranslationStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockTranslationStorageMockRecorder
+}
+
+// MockTranslationStorageMockRecorder is the mock recorder for MockTranslationStorage.
+type MockTranslationStorageMockRecorder struct {
+	mock *MockTranslationStorage
+}
+
+// NewMockTranslationStorage creates a new mock instance.
+func NewMockTranslationStorage(ctrl *gomock.Controller) *MockTranslationStorage {
+	mock := &MockTranslationStorage{ctrl: ctrl}
+	mock.recorder = &MockTranslationStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTranslationStorage) EXPECT() *MockTranslationStorageMockRecorder {
+	return m.recorder
+}
+
+// DeleteTranslation mocks base method.
+func (m *MockTranslationStorage) DeleteTranslation(ctx context.Context, bookID int64, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranslation", ctx, bookID, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTranslation indicates an expected call of DeleteTranslation.
+func (mr *MockTranslationStorageMockRecorder) DeleteTranslation(ctx, bookID, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranslation", reflect.TypeOf((*MockTranslationStorage)(nil).DeleteTranslation), ctx, bookID, locale)
+}
+
+// GetLocales mocks base method.
+func (m *MockTranslationStorage) GetLocales(arg0 context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocales", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocales indicates an expected call of GetLocales.
+func (mr *MockTranslationStorageMockRecorder) GetLocales(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocales", reflect.TypeOf((*MockTranslationStorage)(nil).GetLocales), arg0)
+}
+
+// GetTranslations mocks base method.
+func (m *MockTranslationStorage) GetTranslations(ctx context.Context, bookID int64) ([]*models.BookTranslation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTranslations", ctx, bookID)
+	ret0, _ := ret[0].([]*models.BookTranslation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTranslations indicates an expected call of GetTranslations.
+func (mr *MockTranslationStorageMockRecorder) GetTranslations(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTranslations", reflect.TypeOf((*MockTranslationStorage)(nil).GetTranslations), ctx, bookID)
+}
+
+// GetTranslationsByBookIDs mocks base method.
+func (m *MockTranslationStorage) GetTranslationsByBookIDs(ctx context.Context, locale string, bookIDs []int64) (map[int64]*models.BookTranslation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTranslationsByBookIDs", ctx, locale, bookIDs)
+	ret0, _ := ret[0].(map[int64]*models.BookTranslation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTranslationsByBookIDs indicates an expected call of GetTranslationsByBookIDs.
+func (mr *MockTranslationStorageMockRecorder) GetTranslationsByBookIDs(ctx, locale, bookIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTranslationsByBookIDs", reflect.TypeOf((*MockTranslationStorage)(nil).GetTranslationsByBookIDs), ctx, locale, bookIDs)
+}
+
+// SetTranslation mocks base method.
+func (m *MockTranslationStorage) SetTranslation(arg0 context.Context, arg1 *models.BookTranslation) (*models.BookTranslation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTranslation", arg0, arg1)
+	ret0, _ := ret[0].(*models.BookTranslation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTranslation indicates an expected call of SetTranslation.
+func (mr *MockTranslationStorageMockRecorder) SetTranslation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTranslation", reflect.TypeOf((*MockTranslationStorage)(nil).SetTranslation), arg0, arg1)
+}
@@ -0,0 +1,151 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrBookIDNotFound = errors.New("book id not found")
+)
+
+//go:generate mockgen -source=translation.go -destination=mock/translation.go -package=mock
+type TranslationStorage interface {
+	// GetLocales fetches every locale books are translated in.
+	GetLocales(context.Context) ([]string, error)
+
+	// GetTranslations fetches every translation of a book ordered by
+	// locale.
+	GetTranslations(ctx context.Context, bookID int64) ([]*models.BookTranslation, error)
+
+	// GetTranslationsByBookIDs fetches the translations of the given books
+	// in a locale by book id, books without one are left out.
+	GetTranslationsByBookIDs(ctx context.Context, locale string, bookIDs []int64) (map[int64]*models.BookTranslation, error)
+
+	// SetTranslation creates or replaces the translation of a book in its
+	// locale.
+	SetTranslation(context.Context, *models.BookTranslation) (*models.BookTranslation, error)
+
+	// DeleteTranslation removes the translation of a book in a locale.
+	DeleteTranslation(ctx context.Context, bookID int64, locale string) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around translation storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+const translationColumns = `book_id, locale, title, COALESCE(description, '') AS description, created_at, updated_at`
+
+// GetLocales fetches every locale books are translated in.
+func (s *Storage) GetLocales(ctx context.Context) ([]string, error) {
+	locales := []string{}
+	if err := s.db.SelectContext(ctx, &locales, `SELECT DISTINCT locale FROM book_translations ORDER BY locale;`); err != nil {
+		return nil, fmt.Errorf("failed to get locales: %v", err)
+	}
+
+	return locales, nil
+}
+
+// GetTranslations fetches every translation of a book ordered by locale.
+func (s *Storage) GetTranslations(ctx context.Context, bookID int64) ([]*models.BookTranslation, error) {
+	query := `
+SELECT %s
+FROM book_translations
+WHERE book_id = ?
+ORDER BY locale;
+`
+
+	translations := []*models.BookTranslation{}
+	if err := s.db.SelectContext(ctx, &translations, fmt.Sprintf(query, translationColumns), bookID); err != nil {
+		return nil, fmt.Errorf("failed to get book translations: %v", err)
+	}
+
+	return translations, nil
+}
+
+// GetTranslationsByBookIDs fetches the translations of the given books in a
+// locale by book id, books without one are left out.
+func (s *Storage) GetTranslationsByBookIDs(ctx context.Context, locale string, bookIDs []int64) (map[int64]*models.BookTranslation, error) {
+	translations := map[int64]*models.BookTranslation{}
+	if len(bookIDs) < 1 {
+		return translations, nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(`
+SELECT %s
+FROM book_translations
+WHERE locale = ? AND book_id IN (?);
+`, translationColumns), locale, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GetTranslationsByBookIDs query: %v", err)
+	}
+
+	rows := []*models.BookTranslation{}
+	if err := s.db.SelectContext(ctx, &rows, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get book translations: %v", err)
+	}
+	for _, t := range rows {
+		translations[t.BookID] = t
+	}
+
+	return translations, nil
+}
+
+// SetTranslation creates or replaces the translation of a book in its
+// locale, an empty description is stored as none.
+func (s *Storage) SetTranslation(ctx context.Context, translation *models.BookTranslation) (*models.BookTranslation, error) {
+	stmt := `
+INSERT INTO book_translations (book_id, locale, title, description)
+VALUES (?, ?, ?, NULLIF(?, ''))
+ON CONFLICT (book_id, locale) DO UPDATE SET
+	title = excluded.title,
+	description = excluded.description,
+	updated_at = CURRENT_TIMESTAMP;
+`
+	if _, err := s.db.ExecContext(ctx, stmt, translation.BookID, translation.Locale, translation.Title, translation.Description); err != nil {
+		if strings.Contains(err.Error(), errForeignKeyConstraint) {
+			return nil, ErrBookIDNotFound
+		}
+		return nil, fmt.Errorf("failed to set book translation: %v", err)
+	}
+
+	var set models.BookTranslation
+	query := fmt.Sprintf(`SELECT %s FROM book_translations WHERE book_id = ? AND locale = ?;`, translationColumns)
+	if err := s.db.GetContext(ctx, &set, query, translation.BookID, translation.Locale); err != nil {
+		return nil, fmt.Errorf("failed to get book translation: %v", err)
+	}
+
+	return &set, nil
+}
+
+// DeleteTranslation removes the translation of a book in a locale.
+func (s *Storage) DeleteTranslation(ctx context.Context, bookID int64, locale string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM book_translations WHERE book_id = ? AND locale = ?;`, bookID, locale)
+	if err != nil {
+		return fmt.Errorf("failed to delete book translation: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
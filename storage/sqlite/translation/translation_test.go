@@ -0,0 +1,134 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_SetTranslation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	got, err := ts.SetTranslation(ctx, &models.BookTranslation{BookID: 1, Locale: "fr", Title: "Un Petit Rien", Description: "Des habitudes"})
+	if err != nil {
+		t.Fatalf("SetTranslation(_, _) expected nil error, got = %v", err)
+	}
+	if got.Title != "Un Petit Rien" || got.Description != "Des habitudes" {
+		t.Fatalf("SetTranslation(_, _) error, got = %+v", got)
+	}
+
+	// setting it again replaces it, an empty description is none.
+	got, err = ts.SetTranslation(ctx, &models.BookTranslation{BookID: 1, Locale: "fr", Title: "Atomic Habits (FR)"})
+	if err != nil {
+		t.Fatalf("SetTranslation(_, _) expected nil error, got = %v", err)
+	}
+	if got.Title != "Atomic Habits (FR)" || got.Description != "" {
+		t.Fatalf("SetTranslation(_, _) error, got = %+v", got)
+	}
+	translations, err := ts.GetTranslations(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTranslations(_, _) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff([]*models.BookTranslation{got}, translations); diff != "" {
+		t.Fatalf("GetTranslations(_, _) mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := ts.SetTranslation(ctx, &models.BookTranslation{BookID: 1000, Locale: "fr", Title: genString()}); !errors.Is(err, ErrBookIDNotFound) {
+		t.Fatalf("SetTranslation(_, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+	}
+}
+
+func Test_GetTranslationsByBookIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	set := []*models.BookTranslation{}
+	for _, tr := range []*models.BookTranslation{
+		{BookID: 1, Locale: "fr", Title: "Un Petit Rien"},
+		{BookID: 2, Locale: "fr", Title: "Le Point de Bascule"},
+		{BookID: 1, Locale: "de", Title: "Die 1%-Methode"},
+	} {
+		got, err := ts.SetTranslation(ctx, tr)
+		if err != nil {
+			t.Fatalf("unexpected error when setting translation: %v", err)
+		}
+		set = append(set, got)
+	}
+
+	got, err := ts.GetTranslationsByBookIDs(ctx, "fr", []int64{1, 3})
+	if err != nil {
+		t.Fatalf("GetTranslationsByBookIDs(_, _, _) expected nil error, got = %v", err)
+	}
+	want := map[int64]*models.BookTranslation{
+		1: set[0],
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("GetTranslationsByBookIDs(_, _, _) mismatch (-want +got):\n%s", diff)
+	}
+
+	locales, err := ts.GetLocales(ctx)
+	if err != nil {
+		t.Fatalf("GetLocales(_) expected nil error, got = %v", err)
+	}
+	if diff := cmp.Diff([]string{"de", "fr"}, locales); diff != "" {
+		t.Fatalf("GetLocales(_) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_DeleteTranslation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	if _, err := ts.SetTranslation(ctx, &models.BookTranslation{BookID: 1, Locale: "fr", Title: genString()}); err != nil {
+		t.Fatalf("unexpected error when setting translation: %v", err)
+	}
+
+	if err := ts.DeleteTranslation(ctx, 1, "fr"); err != nil {
+		t.Fatalf("DeleteTranslation(_, _, _) expected nil error, got = %v", err)
+	}
+	if err := ts.DeleteTranslation(ctx, 1, "fr"); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("DeleteTranslation(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func genString() string {
+	return uuid.New().String()
+}
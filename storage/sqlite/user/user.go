@@ -46,7 +46,7 @@ func (s *Storage) GetUserByID(ctx context.Context, id int64) (*models.User, erro
 	}
 
 	query := `
-SELECT id, email, password
+SELECT id, email, password, is_admin
 FROM users
 WHERE id = :id
 `
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: wishlist.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/wilsonangara/simple-online-book-store/storage/models"
+)
+
+// MockWishlistStorage is a mock of WishlistStorage interface.
+type MockWishlistStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockWishlistStorageMockRecorder
+}
+
+// MockWishlistStorageMockRecorder is the mock recorder for MockWishlistStorage.
+type MockWishlistStorageMockRecorder struct {
+	mock *MockWishlistStorage
+}
+
+// NewMockWishlistStorage creates a new mock instance.
+func NewMockWishlistStorage(ctrl *gomock.Controller) *MockWishlistStorage {
+	mock := &MockWishlistStorage{ctrl: ctrl}
+	mock.recorder = &MockWishlistStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWishlistStorage) EXPECT() *MockWishlistStorageMockRecorder {
+	return m.recorder
+}
+
+// AddItem mocks base method.
+func (m *MockWishlistStorage) AddItem(ctx context.Context, userID, bookID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItem", ctx, userID, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddItem indicates an expected call of AddItem.
+func (mr *MockWishlistStorageMockRecorder) AddItem(ctx, userID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockWishlistStorage)(nil).AddItem), ctx, userID, bookID)
+}
+
+// GetItems mocks base method.
+func (m *MockWishlistStorage) GetItems(ctx context.Context, userID int64) ([]*models.WishlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, userID)
+	ret0, _ := ret[0].([]*models.WishlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockWishlistStorageMockRecorder) GetItems(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockWishlistStorage)(nil).GetItems), ctx, userID)
+}
+
+// RemoveItems mocks base method.
+func (m *MockWishlistStorage) RemoveItems(ctx context.Context, userID int64, bookIDs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItems", ctx, userID, bookIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveItems indicates an expected call of RemoveItems.
+func (mr *MockWishlistStorageMockRecorder) RemoveItems(ctx, userID, bookIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItems", reflect.TypeOf((*MockWishlistStorage)(nil).RemoveItems), ctx, userID, bookIDs)
+}
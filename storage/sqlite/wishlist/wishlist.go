@@ -0,0 +1,113 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/models"
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+var (
+	errForeignKeyConstraint = "FOREIGN KEY constraint failed"
+
+	ErrBookIDNotFound = errors.New("book id not found")
+)
+
+//go:generate mockgen -source=wishlist.go -destination=mock/wishlist.go -package=mock
+type WishlistStorage interface {
+	// GetItems fetches all the items in the wishlist of a user, the most
+	// recently added first.
+	GetItems(ctx context.Context, userID int64) ([]*models.WishlistItem, error)
+
+	// AddItem adds a book to the wishlist of a user.
+	AddItem(ctx context.Context, userID, bookID int64) error
+
+	// RemoveItems removes the given books from the wishlist of a user.
+	RemoveItems(ctx context.Context, userID int64, bookIDs []int64) error
+}
+
+type Storage struct {
+	db *sqlx.DB
+}
+
+// NewStorage creates a wrapper around wishlist storage.
+func NewStorage(db *sqlx.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// GetItems fetches all the items in the wishlist of a user, the most
+// recently added first.
+func (s *Storage) GetItems(ctx context.Context, userID int64) ([]*models.WishlistItem, error) {
+	query := `
+SELECT user_id, book_id, created_at
+FROM wishlist_items
+WHERE user_id = ?
+ORDER BY created_at DESC, book_id;
+`
+
+	items := []*models.WishlistItem{}
+	if err := s.db.SelectContext(ctx, &items, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to query from wishlist_items table: %v", err)
+	}
+
+	return items, nil
+}
+
+// AddItem adds a book to the wishlist of a user, adding a book that is
+// already in the wishlist is a no-op.
+func (s *Storage) AddItem(ctx context.Context, userID, bookID int64) error {
+	stmt := `
+INSERT OR IGNORE INTO wishlist_items (user_id, book_id)
+VALUES (:user_id, :book_id);
+`
+
+	arg := map[string]interface{}{
+		"user_id": userID,
+		"book_id": bookID,
+	}
+
+	if _, err := s.db.NamedExecContext(ctx, stmt, arg); err != nil {
+		if strings.Contains(err.Error(), errForeignKeyConstraint) {
+			return ErrBookIDNotFound
+		}
+		return fmt.Errorf("failed to perform AddItem operation: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveItems removes the given books from the wishlist of a user, it
+// returns sqlite.ErrNotFound when none of them were in the wishlist.
+func (s *Storage) RemoveItems(ctx context.Context, userID int64, bookIDs []int64) error {
+	if len(bookIDs) < 1 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`
+DELETE FROM wishlist_items
+WHERE user_id = ? AND book_id IN (?);
+`, userID, bookIDs)
+	if err != nil {
+		return fmt.Errorf("failed to build RemoveItems statement: %v", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, s.db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to perform RemoveItems operation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if affected < 1 {
+		return sqlite.ErrNotFound
+	}
+
+	return nil
+}
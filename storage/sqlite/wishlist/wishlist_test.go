@@ -0,0 +1,120 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/wilsonangara/simple-online-book-store/storage/sqlite"
+)
+
+func newTestStorage(tb testing.TB) (*Storage, func()) {
+	dir, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("unexpected error when getting working directory: %v", err)
+	}
+
+	testDB := filepath.Join(dir, genString())
+	pathToMigrationsDir := filepath.Join("..", "..", "migrations")
+
+	ts, err := sqlite.NewStorage(testDB, pathToMigrationsDir)
+	if err != nil {
+		tb.Fatalf("failed to create new test storage: %v", err)
+	}
+
+	return &Storage{db: ts.Database()}, ts.Teardown
+}
+
+func Test_AddItem(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		userID := testCreateUser(t, ts)
+
+		// adding the same book twice keeps a single item.
+		for i := 0; i < 2; i++ {
+			if err := ts.AddItem(ctx, userID, 1); err != nil {
+				t.Fatalf("AddItem(_, _, _) expected nil error, got = %v", err)
+			}
+		}
+
+		items, err := ts.GetItems(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error when getting wishlist items: %v", err)
+		}
+		if len(items) != 1 || items[0].BookID != 1 {
+			t.Fatalf("AddItem(_, _, _) error, got = %v items, want = %v", len(items), 1)
+		}
+	})
+
+	t.Run("BookIDNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		err := ts.AddItem(ctx, testCreateUser(t, ts), 1000)
+		if !errors.Is(err, ErrBookIDNotFound) {
+			t.Fatalf("AddItem(_, _, _) error, got = %v, want = %v", err, ErrBookIDNotFound)
+		}
+	})
+}
+
+func Test_RemoveItems(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ts, teardown := newTestStorage(t)
+	t.Cleanup(teardown)
+
+	userID := testCreateUser(t, ts)
+	for _, bookID := range []int64{1, 2, 3} {
+		if err := ts.AddItem(ctx, userID, bookID); err != nil {
+			t.Fatalf("unexpected error when adding wishlist item: %v", err)
+		}
+	}
+
+	if err := ts.RemoveItems(ctx, userID, []int64{1, 3}); err != nil {
+		t.Fatalf("RemoveItems(_, _, _) expected nil error, got = %v", err)
+	}
+
+	items, err := ts.GetItems(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error when getting wishlist items: %v", err)
+	}
+	if len(items) != 1 || items[0].BookID != 2 {
+		t.Fatalf("RemoveItems(_, _, _) error, got = %v items, want = %v", len(items), 1)
+	}
+
+	if err := ts.RemoveItems(ctx, userID, []int64{1}); !errors.Is(err, sqlite.ErrNotFound) {
+		t.Fatalf("RemoveItems(_, _, _) error, got = %v, want = %v", err, sqlite.ErrNotFound)
+	}
+}
+
+func testCreateUser(t *testing.T, ts *Storage) int64 {
+	t.Helper()
+
+	res, err := ts.db.Exec(`INSERT INTO users (email, password) VALUES (?, ?);`, genString(), genString())
+	if err != nil {
+		t.Fatalf("unexpected error when creating dummy user: %v", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("unexpected error when getting dummy user id: %v", err)
+	}
+	return id
+}
+
+func genString() string {
+	return uuid.New().String()
+}
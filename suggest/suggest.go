@@ -0,0 +1,209 @@
+package suggest
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Entry is a text to suggest, such as the title of a book or the name of an
+// author, weighted by its popularity.
+type Entry struct {
+	Kind       string
+	ID         int64
+	Text       string
+	Popularity int64
+}
+
+// Match is an entry suggested for a query, Distance is the number of typos
+// it took to match it.
+type Match struct {
+	Entry
+	Distance int
+}
+
+// key is a normalized text an entry is found by, every entry is keyed by
+// its whole text and by the rest of it from each of its words so a query
+// matches any word.
+type key struct {
+	text  string
+	entry int
+}
+
+// Index finds the entries starting with a query. An Index is immutable once
+// built so it can be shared between goroutines.
+type Index struct {
+	entries []Entry
+	keys    []key
+}
+
+// NewIndex builds an index over the given entries.
+func NewIndex(entries []Entry) *Index {
+	keys := []key{}
+	for i, e := range entries {
+		words := strings.Fields(normalize(e.Text))
+		for j := range words {
+			keys = append(keys, key{
+				text:  strings.Join(words[j:], " "),
+				entry: i,
+			})
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].text < keys[j].text
+	})
+
+	return &Index{
+		entries: entries,
+		keys:    keys,
+	}
+}
+
+// Suggest returns up to limit entries starting with the query, ignoring
+// case and punctuation. Entries within a few typos of the query are
+// suggested as well, longer queries tolerating more of them, though the
+// first letter has to be right. Exact matches come first, then the closest
+// ones, the most popular first.
+func (idx *Index) Suggest(query string, limit int) []Match {
+	q := normalize(query)
+	if q == "" {
+		return []Match{}
+	}
+
+	distances := map[int]int{}
+	for i := idx.search(q); i < len(idx.keys) && strings.HasPrefix(idx.keys[i].text, q); i++ {
+		distances[idx.keys[i].entry] = 0
+	}
+
+	if maxDistance := maxDistance(q); maxDistance > 0 {
+		first := string([]rune(q)[:1])
+		for i := idx.search(first); i < len(idx.keys) && strings.HasPrefix(idx.keys[i].text, first); i++ {
+			k := idx.keys[i]
+			if d, ok := distances[k.entry]; ok && d == 0 {
+				continue
+			}
+			d := prefixDistance(q, k.text)
+			if d > maxDistance {
+				continue
+			}
+			if best, ok := distances[k.entry]; !ok || d < best {
+				distances[k.entry] = d
+			}
+		}
+	}
+
+	matches := []Match{}
+	for i, d := range distances {
+		matches = append(matches, Match{
+			Entry:    idx.entries[i],
+			Distance: d,
+		})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		if len(a.Text) != len(b.Text) {
+			return len(a.Text) < len(b.Text)
+		}
+		if a.Text != b.Text {
+			return a.Text < b.Text
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.ID < b.ID
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// search returns the position of the first key not before text.
+func (idx *Index) search(text string) int {
+	return sort.Search(len(idx.keys), func(i int) bool {
+		return idx.keys[i].text >= text
+	})
+}
+
+// normalize lower-cases a text and joins its words with single spaces,
+// dropping punctuation.
+func normalize(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// maxDistance is the number of typos tolerated in a query, short queries
+// match too much to tolerate any.
+func maxDistance(q string) int {
+	switch n := len([]rune(q)); {
+	case n < 3:
+		return 0
+	case n < 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// prefixDistance is the smallest edit distance between q and any prefix of
+// text, counting insertions, deletions, substitutions and transpositions of
+// adjacent letters as one edit each.
+func prefixDistance(q, text string) int {
+	a, b := []rune(q), []rune(text)
+	// no prefix longer than the query by more than its tolerance can be
+	// closer than a shorter one.
+	if len(b) > len(a)+maxDistance(q) {
+		b = b[:len(a)+maxDistance(q)]
+	}
+
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	best := d[len(a)][0]
+	for _, v := range d[len(a)] {
+		if v < best {
+			best = v
+		}
+	}
+	return best
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,119 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	idx := NewIndex([]Entry{
+		{Kind: "title", ID: 1, Text: "Atomic Habits", Popularity: 3},
+		{Kind: "title", ID: 2, Text: "The Tipping Point", Popularity: 10},
+		{Kind: "title", ID: 3, Text: "Building a Second Brain"},
+		{Kind: "title", ID: 4, Text: "The Power of Habit", Popularity: 5},
+		{Kind: "author", ID: 1, Text: "James Clear", Popularity: 3},
+		{Kind: "author", ID: 2, Text: "Malcolm Gladwell", Popularity: 10},
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "Prefix",
+			query: "ato",
+			limit: 10,
+			want:  []string{"Atomic Habits"},
+		},
+		{
+			name:  "AnyWord",
+			query: "Second b",
+			limit: 10,
+			want:  []string{"Building a Second Brain"},
+		},
+		{
+			name:  "RankedByPopularity",
+			query: "hab",
+			limit: 10,
+			want:  []string{"The Power of Habit", "Atomic Habits"},
+		},
+		{
+			name:  "Authors",
+			query: "glad",
+			limit: 10,
+			want:  []string{"Malcolm Gladwell"},
+		},
+		{
+			name:  "Typo",
+			query: "atmoic",
+			limit: 10,
+			want:  []string{"Atomic Habits"},
+		},
+		{
+			name:  "ExactBeforeTypo",
+			query: "the p",
+			limit: 10,
+			want:  []string{"The Power of Habit", "The Tipping Point"},
+		},
+		{
+			name:  "ShortQueryNoTypo",
+			query: "ht",
+			limit: 10,
+			want:  []string{},
+		},
+		{
+			name:  "Limit",
+			query: "t",
+			limit: 1,
+			want:  []string{"The Tipping Point"},
+		},
+		{
+			name:  "Empty",
+			query: " ,",
+			limit: 10,
+			want:  []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := []string{}
+			for _, m := range idx.Suggest(tc.query, tc.limit) {
+				got = append(got, m.Text)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("Suggest() mismatch (-want+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrefixDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		q, text string
+		want    int
+	}{
+		{q: "atomic", text: "atomic habits", want: 0},
+		{q: "atmoic", text: "atomic habits", want: 1},
+		{q: "atomc", text: "atomic habits", want: 1},
+		{q: "tiping", text: "tipping point", want: 1},
+		{q: "habbits", text: "habits", want: 1},
+		{q: "xyz", text: "habits", want: 3},
+	}
+
+	for _, tc := range tests {
+		if got := prefixDistance(tc.q, tc.text); got != tc.want {
+			t.Fatalf("prefixDistance(%q, %q) = %v, want = %v", tc.q, tc.text, got, tc.want)
+		}
+	}
+}